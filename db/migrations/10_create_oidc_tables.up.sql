@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS oidc_flows(
+    state VARCHAR PRIMARY KEY,
+    provider VARCHAR NOT NULL,
+    code_verifier VARCHAR NOT NULL,
+    nonce VARCHAR NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS user_identities(
+    provider VARCHAR NOT NULL,
+    subject VARCHAR NOT NULL,
+    email VARCHAR NOT NULL,
+    user_id BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    CONSTRAINT fk_user_identity_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY(provider, subject)
+);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS transactions_not_scanned_idx;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS flags_dismissed,
+    DROP COLUMN IF EXISTS flags,
+    DROP COLUMN IF EXISTS scanned;
@@ -0,0 +1,6 @@
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS scanned BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS flags VARCHAR[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS flags_dismissed BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS transactions_not_scanned_idx ON transactions(id) WHERE NOT scanned;
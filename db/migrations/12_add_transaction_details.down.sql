@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS transaction_tags;
+DROP TABLE IF EXISTS tags;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS payee,
+    DROP COLUMN IF EXISTS description;
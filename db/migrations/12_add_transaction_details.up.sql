@@ -0,0 +1,21 @@
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS description TEXT,
+    ADD COLUMN IF NOT EXISTS payee VARCHAR(255);
+
+CREATE TABLE IF NOT EXISTS tags(
+    id BIGSERIAL PRIMARY KEY,
+    title VARCHAR(50) NOT NULL,
+    owner_id INT NOT NULL,
+    CONSTRAINT fk_tag_owner FOREIGN KEY(owner_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT uq_tag_owner_title UNIQUE(owner_id, title)
+);
+
+CREATE TABLE IF NOT EXISTS transaction_tags(
+    transaction_id BIGINT NOT NULL,
+    tag_id BIGINT NOT NULL,
+    PRIMARY KEY(transaction_id, tag_id),
+    CONSTRAINT fk_transaction_tag_transaction FOREIGN KEY(transaction_id) REFERENCES transactions(id) ON DELETE CASCADE,
+    CONSTRAINT fk_transaction_tag_tag FOREIGN KEY(tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS transaction_tags_tag_idx ON transaction_tags(tag_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS transactions_payee_trgm_idx;
+DROP INDEX IF EXISTS transactions_search_idx;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS search;
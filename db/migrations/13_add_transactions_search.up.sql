@@ -0,0 +1,10 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS search TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(payee, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS transactions_search_idx ON transactions USING GIN(search);
+CREATE INDEX IF NOT EXISTS transactions_payee_trgm_idx ON transactions USING GIN(payee gin_trgm_ops);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS attachments(
+    id BIGSERIAL PRIMARY KEY,
+    transaction_id BIGINT NOT NULL,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    thumbnail_key VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    CONSTRAINT fk_attachment_transaction FOREIGN KEY(transaction_id) REFERENCES transactions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS attachments_transaction_idx ON attachments(transaction_id);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS transaction_lines(
+    id BIGSERIAL PRIMARY KEY,
+    transaction_id BIGINT NOT NULL,
+    category_id INT,
+    amount NUMERIC NOT NULL,
+    CONSTRAINT fk_transaction_line_transaction FOREIGN KEY(transaction_id) REFERENCES transactions(id) ON DELETE CASCADE,
+    CONSTRAINT fk_transaction_line_category FOREIGN KEY(category_id) REFERENCES transaction_categories(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS transaction_lines_transaction_idx ON transaction_lines(transaction_id);
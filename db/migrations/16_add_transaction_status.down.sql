@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS transactions_pending_debit_idx;
+DROP INDEX IF EXISTS transactions_pending_credit_idx;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS status;
+
+DROP TYPE IF EXISTS transaction_status;
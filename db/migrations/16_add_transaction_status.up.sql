@@ -0,0 +1,7 @@
+CREATE TYPE transaction_status AS ENUM('pending', 'cleared', 'reconciled');
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS status transaction_status NOT NULL DEFAULT 'cleared';
+
+CREATE INDEX IF NOT EXISTS transactions_pending_credit_idx ON transactions(credit_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS transactions_pending_debit_idx ON transactions(debit_id) WHERE status = 'pending';
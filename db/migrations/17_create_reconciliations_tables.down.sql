@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS reconciliation_transactions;
+DROP TABLE IF EXISTS reconciliations;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS reconciliations(
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL,
+    statement_date DATE NOT NULL,
+    statement_balance NUMERIC NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    completed_at TIMESTAMP,
+    CONSTRAINT fk_reconciliation_account FOREIGN KEY(account_id) REFERENCES accounts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS reconciliations_account_idx ON reconciliations(account_id);
+-- Account has at most one reconciliation in progress
+CREATE UNIQUE INDEX IF NOT EXISTS reconciliations_open_idx ON reconciliations(account_id) WHERE completed_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS reconciliation_transactions(
+    reconciliation_id BIGINT NOT NULL,
+    transaction_id BIGINT NOT NULL,
+    PRIMARY KEY(reconciliation_id, transaction_id),
+    CONSTRAINT fk_reconciliation_transaction_reconciliation FOREIGN KEY(reconciliation_id) REFERENCES reconciliations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_reconciliation_transaction_transaction FOREIGN KEY(transaction_id) REFERENCES transactions(id) ON DELETE CASCADE
+);
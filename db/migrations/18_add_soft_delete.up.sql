@@ -0,0 +1,9 @@
+ALTER TABLE accounts
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+-- Deleted rows are looked up only for trash and purge
+CREATE INDEX IF NOT EXISTS accounts_deleted_idx ON accounts(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS transactions_deleted_idx ON transactions(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS transaction_templates(
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    type transaction_type NOT NULL,
+    amount NUMERIC,
+    category_id INT,
+    credit_id BIGINT,
+    debit_id BIGINT,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    owner_id INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    CONSTRAINT fk_transaction_template_category FOREIGN KEY(category_id) REFERENCES transaction_categories(id) ON DELETE SET NULL,
+    CONSTRAINT fk_transaction_template_credit FOREIGN KEY(credit_id) REFERENCES accounts(id) ON DELETE SET NULL,
+    CONSTRAINT fk_transaction_template_debit FOREIGN KEY(debit_id) REFERENCES accounts(id) ON DELETE SET NULL,
+    CONSTRAINT fk_transaction_template_owner FOREIGN KEY(owner_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT uq_transaction_template_owner_name UNIQUE(owner_id, name)
+);
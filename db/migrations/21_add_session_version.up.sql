@@ -0,0 +1,2 @@
+-- Version of session is changed by every login, refresh and reset, so access tokens of previous ones are rejected
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS access_tokens;
+DROP TYPE access_token_scope;
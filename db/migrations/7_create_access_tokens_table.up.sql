@@ -0,0 +1,13 @@
+CREATE TYPE access_token_scope AS ENUM('read', 'write');
+
+CREATE TABLE IF NOT EXISTS access_tokens(
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL,
+    token_hash VARCHAR UNIQUE NOT NULL,
+    scope access_token_scope NOT NULL,
+    expires_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    user_id BIGINT NOT NULL,
+    CONSTRAINT fk_access_token_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
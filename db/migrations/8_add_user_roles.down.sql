@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN IF EXISTS disabled;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
+DROP TYPE user_role;
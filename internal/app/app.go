@@ -13,6 +13,9 @@ import (
 	"github.com/lotostudio/financial-api/pkg/auth"
 	"github.com/lotostudio/financial-api/pkg/database"
 	"github.com/lotostudio/financial-api/pkg/hash"
+	"github.com/lotostudio/financial-api/pkg/limiter"
+	"github.com/lotostudio/financial-api/pkg/oidc"
+	"github.com/lotostudio/financial-api/pkg/storage"
 	log "github.com/sirupsen/logrus"
 	"net/http"
 	"os"
@@ -67,7 +70,31 @@ func Run(configPath string) {
 
 	// Utils
 	passwordHasher := hash.NewSHA1PasswordHasher(cfg.Auth.PasswordSalt)
-	tokenManager, err := auth.NewJWTManager(cfg.Auth.JWT.Key, cfg.Auth.AccessTokenTTL, cfg.Auth.RefreshTokenLength)
+	tokenManager, err := newTokenManager(cfg)
+
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	// Rate limits
+	var limits limiter.Store
+	var lockoutStore limiter.LockoutStore
+
+	if cfg.RateLimit.Store == "postgres" {
+		limits = limiter.NewPostgresStore(db.DB, cfg.RateLimit.LoginIP.Period, cfg.RateLimit.LoginEmail.Period,
+			cfg.RateLimit.API.Period)
+		lockoutStore = limiter.NewPostgresLockoutStore(db.DB, cfg.RateLimit.Lockout.Window)
+	} else {
+		limits = limiter.NewMemoryStore()
+		lockoutStore = limiter.NewMemoryLockoutStore(cfg.RateLimit.Lockout.Window)
+	}
+
+	lockout := limiter.NewLockout(lockoutStore, cfg.RateLimit.Lockout.Attempts, cfg.RateLimit.Lockout.Duration,
+		cfg.RateLimit.Lockout.MaxDuration)
+
+	// Files of attachments
+	blobs, err := newBlobStore(cfg.Attachments)
 
 	if err != nil {
 		log.Error(err)
@@ -76,8 +103,9 @@ func Run(configPath string) {
 
 	// Init handlers
 	repos := repo.NewRepos(db)
-	services := service.NewServices(repos, passwordHasher, tokenManager, cfg.Auth.AccessTokenTTL, cfg.Auth.RefreshTokenTTL, cfg.Account)
-	handlers := handler.NewHandler(services, tokenManager)
+	services := service.NewServices(repos, passwordHasher, tokenManager, cfg.Auth.AccessTokenTTL, cfg.Auth.RefreshTokenTTL,
+		cfg.Account, lockout, newOIDCProviders(cfg.OIDC), blobs, cfg.Attachments, cfg.Trash)
+	handlers := handler.NewHandler(services, tokenManager, limits)
 
 	// HTTP Server
 	srv := server.NewServer(cfg, handlers.Init(cfg))
@@ -89,12 +117,26 @@ func Run(configPath string) {
 
 	log.Info("Server started")
 
+	// Background jobs
+	jobs, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	if cfg.Anomalies.Interval > 0 {
+		go detectAnomalies(jobs, services.Anomalies, cfg.Anomalies.Interval)
+	}
+
+	if cfg.Trash.Interval > 0 && cfg.Trash.Retention > 0 {
+		go purgeTrash(jobs, services.Trash, cfg.Trash.Interval)
+	}
+
 	// Graceful Shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
 
 	<-quit
 
+	stopJobs()
+
 	const timeout = 5 * time.Second
 
 	ctx, shutdown := context.WithTimeout(context.Background(), timeout)
@@ -108,3 +150,95 @@ func Run(configPath string) {
 		log.Errorf("error occured on db connection close: %v", err)
 	}
 }
+
+// detectAnomalies checks new transactions for anomalies with given interval until context is done
+func detectAnomalies(ctx context.Context, anomalies service.Anomalies, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		flagged, err := anomalies.Detect(ctx)
+
+		if err != nil && !errors.Is(err, context.Canceled) {
+			log.Errorf("failed to detect anomalies: %v", err)
+		} else if flagged > 0 {
+			log.Infof("flagged %d unusual transactions", flagged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeTrash removes items kept in trash longer than retention period with given interval until context is done
+func purgeTrash(ctx context.Context, trash service.Trash, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := trash.Purge(ctx)
+
+		if err != nil && !errors.Is(err, context.Canceled) {
+			log.Errorf("failed to purge trash: %v", err)
+		} else if purged > 0 {
+			log.Infof("purged %d deleted accounts and transactions", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// newTokenManager creates token manager signing with key files if configured, otherwise with HMAC secret
+func newTokenManager(cfg *config.Config) (*auth.JWTManager, error) {
+	if cfg.Auth.JWT.KeyFile == "" {
+		return auth.NewJWTManager(cfg.Auth.JWT.Key, cfg.Auth.AccessTokenTTL, cfg.Auth.RefreshTokenLength)
+	}
+
+	keys, err := auth.LoadKeyRing(cfg.Auth.JWT.KeyFile, cfg.Auth.JWT.PreviousKeyFiles)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return auth.NewKeyRingJWTManager(keys, cfg.Auth.JWT.Issuer, cfg.Auth.JWT.Audience, cfg.Auth.AccessTokenTTL,
+		cfg.Auth.RefreshTokenLength)
+}
+
+// newBlobStore creates store of attachment files configured by name
+func newBlobStore(cfg config.Attachments) (storage.BlobStore, error) {
+	if cfg.Store == "s3" {
+		return storage.NewS3Store(storage.S3Config{
+			Endpoint:  cfg.S3.Endpoint,
+			Region:    cfg.S3.Region,
+			Bucket:    cfg.S3.Bucket,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+		}, &http.Client{Timeout: time.Minute})
+	}
+
+	return storage.NewLocalStore(cfg.Local.Path)
+}
+
+// newOIDCProviders creates OpenID Connect providers by names
+func newOIDCProviders(cfg config.OIDC) map[string]*oidc.Provider {
+	providers := make(map[string]*oidc.Provider, len(cfg.Providers))
+
+	for _, p := range cfg.Providers {
+		providers[p.Name] = oidc.NewProvider(oidc.Config{
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: os.ExpandEnv(p.ClientSecret),
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+		}, &http.Client{Timeout: 10 * time.Second})
+	}
+
+	return providers
+}
@@ -40,11 +40,115 @@ type Config struct {
 		RefreshTokenLength int           `yaml:"refresh-token-length" envconfig:"AUTH_REFRESH_TOKEN_LENGTH"`
 		PasswordSalt       string        `yaml:"password-salt" envconfig:"AUTH_PASSWORD_SALT"`
 		JWT                struct {
+			// HMAC secret, used only if key file is not set
 			Key string `yaml:"key" envconfig:"AUTH_JWT_KEY"`
+			// PEM file with RSA or Ed25519 private key used for signing
+			KeyFile string `yaml:"key-file" envconfig:"AUTH_JWT_KEY_FILE"`
+			// PEM files with previous keys still accepted for verifying
+			PreviousKeyFiles []string `yaml:"previous-key-files" envconfig:"AUTH_JWT_PREVIOUS_KEY_FILES"`
+			Issuer           string   `yaml:"issuer" envconfig:"AUTH_JWT_ISSUER"`
+			Audience         string   `yaml:"audience" envconfig:"AUTH_JWT_AUDIENCE"`
 		} `yaml:"jwt"`
 	} `yaml:"auth"`
 
 	Account Account `yaml:"account"`
+
+	RateLimit RateLimit `yaml:"rate-limit"`
+
+	OIDC OIDC `yaml:"oidc"`
+
+	Attachments Attachments `yaml:"attachments"`
+
+	Trash Trash `yaml:"trash"`
+
+	Anomalies struct {
+		// Period of checking new transactions for anomalies. Zero disables checking
+		Interval time.Duration `yaml:"interval" envconfig:"ANOMALIES_INTERVAL"`
+	} `yaml:"anomalies"`
+}
+
+// Attachments configures storage and limits of files attached to transactions
+type Attachments struct {
+	// Store of files: "local" or "s3"
+	Store string `yaml:"store" envconfig:"ATTACHMENTS_STORE"`
+	// Max size of file in bytes
+	MaxSize int64 `yaml:"max-size" envconfig:"ATTACHMENTS_MAX_SIZE"`
+	// Allowed MIME types, detected by content of file
+	Types []string `yaml:"types" envconfig:"ATTACHMENTS_TYPES"`
+	// Max width and height of image thumbnails in pixels. Zero disables thumbnails
+	ThumbnailSize int `yaml:"thumbnail-size" envconfig:"ATTACHMENTS_THUMBNAIL_SIZE"`
+
+	// Directory of files in local store
+	Local struct {
+		Path string `yaml:"path" envconfig:"ATTACHMENTS_LOCAL_PATH"`
+	} `yaml:"local"`
+
+	// Bucket of S3 compatible store
+	S3 struct {
+		Endpoint  string `yaml:"endpoint" envconfig:"ATTACHMENTS_S3_ENDPOINT"`
+		Region    string `yaml:"region" envconfig:"ATTACHMENTS_S3_REGION"`
+		Bucket    string `yaml:"bucket" envconfig:"ATTACHMENTS_S3_BUCKET"`
+		AccessKey string `yaml:"access-key" envconfig:"ATTACHMENTS_S3_ACCESS_KEY"`
+		SecretKey string `yaml:"secret-key" envconfig:"ATTACHMENTS_S3_SECRET_KEY"`
+	} `yaml:"s3"`
+}
+
+// Trash configures purging of deleted accounts and transactions
+type Trash struct {
+	// Period of keeping deleted items before purge. Zero keeps them forever
+	Retention time.Duration `yaml:"retention" envconfig:"TRASH_RETENTION"`
+	// Period of purging
+	Interval time.Duration `yaml:"interval" envconfig:"TRASH_INTERVAL"`
+}
+
+// OIDC configures OpenID Connect providers available for login
+type OIDC struct {
+	Providers []OIDCProvider `yaml:"providers" ignored:"true"`
+}
+
+type OIDCProvider struct {
+	// Name used in login URL, e.g. /auth/oidc/google/login
+	Name   string `yaml:"name"`
+	Issuer string `yaml:"issuer"`
+	// Client credentials, environment variables like ${GOOGLE_CLIENT_SECRET} are expanded
+	ClientID     string `yaml:"client-id"`
+	ClientSecret string `yaml:"client-secret"`
+	// Callback URL registered at provider, e.g. https://host/api/v1/auth/oidc/google/callback
+	RedirectURL string   `yaml:"redirect-url"`
+	Scopes      []string `yaml:"scopes"`
+}
+
+// RateLimit configures request limits and login lockout. Zero requests count disables limit
+type RateLimit struct {
+	// Store of limits state: "memory" or "postgres"
+	Store string `yaml:"store" envconfig:"RATE_LIMIT_STORE"`
+
+	// Login attempts limit per client IP
+	LoginIP struct {
+		Requests int           `yaml:"requests" envconfig:"RATE_LIMIT_LOGIN_IP_REQUESTS"`
+		Period   time.Duration `yaml:"period" envconfig:"RATE_LIMIT_LOGIN_IP_PERIOD"`
+	} `yaml:"login-ip"`
+
+	// Login attempts limit per email
+	LoginEmail struct {
+		Requests int           `yaml:"requests" envconfig:"RATE_LIMIT_LOGIN_EMAIL_REQUESTS"`
+		Period   time.Duration `yaml:"period" envconfig:"RATE_LIMIT_LOGIN_EMAIL_PERIOD"`
+	} `yaml:"login-email"`
+
+	// API requests limit per authenticated user
+	API struct {
+		Requests int           `yaml:"requests" envconfig:"RATE_LIMIT_API_REQUESTS"`
+		Period   time.Duration `yaml:"period" envconfig:"RATE_LIMIT_API_PERIOD"`
+	} `yaml:"api"`
+
+	// Progressive lockout after failed logins
+	Lockout struct {
+		Attempts    int           `yaml:"attempts" envconfig:"RATE_LIMIT_LOCKOUT_ATTEMPTS"`
+		Duration    time.Duration `yaml:"duration" envconfig:"RATE_LIMIT_LOCKOUT_DURATION"`
+		MaxDuration time.Duration `yaml:"max-duration" envconfig:"RATE_LIMIT_LOCKOUT_MAX_DURATION"`
+		// Failures are forgotten after this time without new failures
+		Window time.Duration `yaml:"window" envconfig:"RATE_LIMIT_LOCKOUT_WINDOW"`
+	} `yaml:"lockout"`
 }
 
 type Account struct {
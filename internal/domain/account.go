@@ -12,6 +12,11 @@ const (
 	Deposit = AccountType("deposit")
 )
 
+// IsLiability reports whether balance of account is owed money, not owned
+func (t AccountType) IsLiability() bool {
+	return t == Loan
+}
+
 type Account struct {
 	// Unique id
 	ID int64 `json:"id" binding:"required" db:"id" example:"1"`
@@ -19,6 +24,10 @@ type Account struct {
 	Title string `json:"title" binding:"required" db:"title" example:"Main savings"`
 	// Current amount of money
 	Balance float64 `json:"balance" binding:"required,gte=0" db:"balance" example:"123002.12"`
+	// Amount of money without pending transactions
+	ClearedBalance *float64 `json:"clearedBalance,omitempty" db:"cleared_balance" example:"120002.12"`
+	// Amount of money including pending transactions, same as balance
+	AvailableBalance *float64 `json:"availableBalance,omitempty" db:"available_balance" example:"123002.12"`
 	// Currency
 	Currency string `json:"currency" binding:"required" db:"currency" example:"KZT"`
 	// Type (different types have distinct data)
@@ -37,6 +46,8 @@ type Account struct {
 	// * For loans - loan interest
 	// * For deposits - deposit percentage
 	Rate *float32 `json:"rate,omitempty" binding:"omitempty,gt=0" db:"rate" example:"10.8"`
+	// Time of deletion, set only for accounts in trash
+	DeletedAt *time.Time `json:"deletedAt,omitempty" db:"deleted_at" format:"yyyy-MM-ddThh:mm:ss.ZZZ" example:"2022-04-02T10:12:45.499198Z"`
 } // @name Account
 
 type AccountToCreate struct {
@@ -84,9 +95,18 @@ type Balance struct {
 	// Date of balance
 	Date time.Time `json:"date" binding:"required" db:"date" format:"yyyy-MM-ddThh:mm:ss.ZZZ" example:"2022-01-15T00:00:00Z"`
 	// Amount of balance
-	Value int64 `json:"value" binding:"required" db:"value" example:"123002.12"`
+	Value float64 `json:"value" binding:"required" db:"value" example:"123002.12"`
 } // @name Balance
 
+type TypeBalance struct {
+	// Date of balance
+	Date time.Time `db:"date"`
+	// Type of accounts
+	Type AccountType `db:"type"`
+	// Sum of balances of accounts of type
+	Value float64 `db:"value"`
+}
+
 type Currency struct {
 	ID   int    `json:"id" binding:"required" db:"id" example:"1"`
 	Code string `json:"code" binding:"required,max=10" maxLength:"10" db:"code" example:"KZT"`
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+type Attachment struct {
+	// Unique ID
+	ID int64 `json:"id" binding:"required" db:"id" example:"1"`
+	// Transaction attached to
+	TransactionID int64 `json:"transactionId" binding:"required" db:"transaction_id" example:"1"`
+	// Original name of file
+	Filename string `json:"filename" binding:"required" db:"filename" example:"receipt.jpg"`
+	// MIME type detected by content
+	ContentType string `json:"contentType" binding:"required" db:"content_type" example:"image/jpeg"`
+	// Size in bytes
+	Size int64 `json:"size" binding:"required" db:"size" example:"204800"`
+	// Preview of image is available
+	HasThumbnail bool `json:"hasThumbnail" db:"has_thumbnail" example:"true"`
+	// Date of upload
+	CreatedAt time.Time `json:"createdAt" binding:"required" db:"created_at" example:"2021-09-01T12:00:00Z"`
+	// Key of file in blob store
+	Key string `json:"-" db:"key"`
+	// Key of thumbnail in blob store
+	ThumbnailKey *string `json:"-" db:"thumbnail_key"`
+} // @name Attachment
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+type AuditEntity string // @name AuditEntity
+
+// Audited entities
+const (
+	AuditAccount     = AuditEntity("account")
+	AuditTransaction = AuditEntity("transaction")
+	AuditUser        = AuditEntity("user")
+	AuditAccessToken = AuditEntity("access_token")
+)
+
+func (e AuditEntity) Validate() error {
+	if e != AuditAccount && e != AuditTransaction && e != AuditUser && e != AuditAccessToken {
+		return ErrInvalidAuditEntity
+	}
+
+	return nil
+}
+
+type AuditAction string // @name AuditAction
+
+// Audited actions
+const (
+	AuditCreate         = AuditAction("create")
+	AuditUpdate         = AuditAction("update")
+	AuditDelete         = AuditAction("delete")
+	AuditRestore        = AuditAction("restore")
+	AuditPurge          = AuditAction("purge")
+	AuditRegister       = AuditAction("register")
+	AuditLogin          = AuditAction("login")
+	AuditLoginFailure   = AuditAction("login_failure")
+	AuditRefresh        = AuditAction("refresh")
+	AuditPasswordChange = AuditAction("password_change")
+	AuditDisable        = AuditAction("disable")
+	AuditEnable         = AuditAction("enable")
+	AuditSessionsReset  = AuditAction("sessions_reset")
+)
+
+type AuditEntry struct {
+	// Unique ID
+	ID int64 `json:"id" binding:"required" db:"id" example:"1"`
+	// User whose data is changed
+	UserID int64 `json:"-" db:"user_id" swaggerignore:"true"`
+	// User made change, empty for changes of background jobs
+	ActorID *int64 `json:"actorId,omitempty" db:"actor_id" example:"1"`
+	// Personal access token used for change
+	AccessTokenID *int64 `json:"accessTokenId,omitempty" db:"access_token_id" example:"1"`
+	// ID of request made change
+	RequestID string `json:"requestId,omitempty" db:"request_id" example:"3f2b6c1e9a7d4e08"`
+	// IP address of client made change
+	IP string `json:"ip,omitempty" db:"ip" example:"127.0.0.1"`
+	// Changed entity
+	Entity AuditEntity `json:"entity" binding:"required" db:"entity" enums:"account,transaction,user,access_token" example:"account"`
+	// ID of changed entity
+	EntityID int64 `json:"entityId" binding:"required" db:"entity_id" example:"1"`
+	// Action made to entity
+	Action AuditAction `json:"action" binding:"required" db:"action" example:"update"`
+	// State of entity before change
+	Before json.RawMessage `json:"before,omitempty" db:"before" swaggertype:"object"`
+	// State of entity after change
+	After json.RawMessage `json:"after,omitempty" db:"after" swaggertype:"object"`
+	// Time of change
+	CreatedAt time.Time `json:"createdAt" binding:"required" db:"created_at" format:"yyyy-MM-ddThh:mm:ss.ZZZ" example:"2022-03-01T18:03:24.499198Z"`
+} // @name AuditEntry
+
+type AuditFilter struct {
+	UserID   int64
+	Entity   *AuditEntity
+	EntityID *int64
+	Action   *AuditAction
+	From     *time.Time
+	To       *time.Time
+	Limit    int
+	Offset   int
+}
+
+// Actor is author of changes made within request
+type Actor struct {
+	UserID        *int64
+	AccessTokenID *int64
+	RequestID     string
+	IP            string
+}
+
+type actorKey struct{}
+
+// WithActor returns context carrying actor of request
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns actor of request, empty actor is returned for background jobs
+func ActorFrom(ctx context.Context) Actor {
+	actor, _ := ctx.Value(actorKey{}).(Actor)
+
+	return actor
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -9,6 +10,12 @@ type Session struct {
 	RefreshToken string    `db:"refresh_token"`
 	ExpiresAt    time.Time `db:"expires_at"`
 	UserId       int64     `db:"user_id"`
+	Version      int64     `db:"version"`
+}
+
+// SID identifies current login of session in access tokens. It is changed by every login, refresh and reset
+func (s Session) SID() string {
+	return fmt.Sprintf("%d.%d", s.Id, s.Version)
 }
 
 func (s Session) Expired() bool {
@@ -18,6 +25,8 @@ func (s Session) Expired() bool {
 type SessionToUpdate struct {
 	RefreshToken string
 	ExpiresAt    time.Time
+	// Action recorded to audit log, either login or refresh
+	Action AuditAction
 }
 
 type Tokens struct {
@@ -28,3 +37,51 @@ type Tokens struct {
 	RefreshToken          string `json:"refreshToken" binding:"required" example:"refresh token"`
 	RefreshTokenExpiredAt int16  `json:"-"`
 } // @name Tokens
+
+// AccessTokenPrefix distinguishes personal access tokens from JWT access tokens
+const AccessTokenPrefix = "fa_"
+
+type AccessTokenScope string // @name AccessTokenScope
+
+// Access token scopes
+const (
+	ReadOnly  = AccessTokenScope("read")
+	ReadWrite = AccessTokenScope("write")
+)
+
+type AccessToken struct {
+	// Unique ID
+	ID int64 `json:"id" binding:"required" db:"id" example:"1"`
+	// Name to distinguish token
+	Name string `json:"name" binding:"required" db:"name" example:"Backup script"`
+	// Scope of operations
+	Scope  AccessTokenScope `json:"scope" binding:"required,oneof=read write" db:"scope" enums:"read,write" example:"read"`
+	Hash   string           `json:"-" db:"token_hash" swaggerignore:"true"`
+	UserId int64            `json:"-" db:"user_id" swaggerignore:"true"`
+	Role   Role             `json:"-" db:"-" swaggerignore:"true"`
+	// Time of expiration (never expires if empty)
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" db:"expires_at" format:"yyyy-MM-ddThh:mm:ss.ZZZ" example:"2022-09-01T00:00:00Z"`
+	// Time of last usage
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty" db:"last_used_at" format:"yyyy-MM-ddThh:mm:ss.ZZZ" example:"2022-03-01T18:03:24.499198Z"`
+	// Time of creation
+	CreatedAt time.Time `json:"createdAt" binding:"required" db:"created_at" format:"yyyy-MM-ddThh:mm:ss.ZZZ" example:"2022-03-01T18:03:24.499198Z"`
+} // @name AccessToken
+
+func (t AccessToken) Expired() bool {
+	return t.ExpiresAt != nil && t.ExpiresAt.Before(time.Now().UTC())
+}
+
+type AccessTokenToCreate struct {
+	// Name to distinguish token
+	Name string `json:"name" binding:"required,max=50" example:"Backup script"`
+	// Scope of operations
+	Scope AccessTokenScope `json:"scope" binding:"required,oneof=read write" enums:"read,write" example:"read"`
+	// Time of expiration (never expires if empty)
+	ExpiresAt *time.Time `json:"expiresAt" format:"yyyy-MM-ddThh:mm:ss.ZZZ" example:"2022-09-01T00:00:00Z"`
+} // @name AccessTokenToCreate
+
+type CreatedAccessToken struct {
+	AccessToken
+	// Secret token value. It is shown only once
+	Token string `json:"token" binding:"required" example:"fa_4f6d..."`
+} // @name CreatedAccessToken
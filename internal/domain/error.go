@@ -3,5 +3,10 @@ package domain
 import "errors"
 
 var (
-	ErrInvalidTransactionType = errors.New("invalid type of transaction")
+	ErrInvalidTransactionType   = errors.New("invalid type of transaction")
+	ErrInvalidTransactionStatus = errors.New("invalid status of transaction, use one of: pending, cleared, reconciled")
+	ErrInvalidInterval          = errors.New("invalid interval, use one of: day, week, month, year")
+	ErrInvalidStatsGroup        = errors.New("invalid grouping, use one of: category, type, account, month, weekday")
+	ErrInvalidBatchMode         = errors.New("invalid mode of batch, use one of: all_or_nothing, best_effort")
+	ErrInvalidAuditEntity       = errors.New("invalid audited entity, use one of: account, transaction, user, access_token")
 )
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+type RecurringTransaction struct {
+	// Type
+	Type TransactionType `json:"type" binding:"required" enums:"income,expense,transfer" example:"income"`
+	// Category, missing for transfers
+	Category *string `json:"category,omitempty" example:"salary"`
+	// Typical amount
+	Amount float64 `json:"amount" binding:"required" example:"250000"`
+	// Day of month
+	Day int `json:"day" binding:"required" example:"5"`
+	// Id of account transfer from
+	CreditId *int64 `json:"creditId,omitempty" example:"1"`
+	// Id of account transfer to
+	DebitId *int64 `json:"debitId,omitempty" example:"2"`
+} // @name RecurringTransaction
+
+type AccountForecast struct {
+	// Account information
+	Account Account `json:"account" binding:"required"`
+	// Average daily expenses by categories, not counting recurring ones
+	Spending map[string]float64 `json:"spending" binding:"required"`
+	// Projected balance for the end of every day
+	Balances []Balance `json:"balances" binding:"required"`
+	// First day balance of card or cash account is negative
+	NegativeAt *time.Time `json:"negativeAt,omitempty" format:"yyyy-MM-dd" example:"2022-05-14T00:00:00Z"`
+} // @name AccountForecast
+
+type Forecast struct {
+	// Transactions repeating every month, found in history
+	Recurring []RecurringTransaction `json:"recurring" binding:"required"`
+	// Forecasts of accounts
+	Accounts []AccountForecast `json:"accounts" binding:"required"`
+} // @name Forecast
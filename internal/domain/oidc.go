@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// OIDCFlow is pending authorization of user at OpenID Connect provider
+type OIDCFlow struct {
+	// Random value passed through provider to match callback with flow
+	State string `db:"state"`
+	// Name of provider
+	Provider string `db:"provider"`
+	// PKCE code verifier
+	CodeVerifier string `db:"code_verifier"`
+	// Random value bound to ID token
+	Nonce     string    `db:"nonce"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+func (f OIDCFlow) Expired() bool {
+	return f.ExpiresAt.Before(time.Now().UTC())
+}
+
+// ExternalIdentity is user authenticated by OpenID Connect provider
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	FirstName     string
+	LastName      string
+}
+
+// UserIdentity links user to identity at OpenID Connect provider
+type UserIdentity struct {
+	Provider  string    `db:"provider"`
+	Subject   string    `db:"subject"`
+	Email     string    `db:"email"`
+	UserId    int64     `db:"user_id"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type OIDCProviders struct {
+	// Names of configured providers
+	Providers []string `json:"providers" example:"google,keycloak"`
+} // @name OIDCProviders
@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+type Reconciliation struct {
+	// Unique ID
+	ID int64 `json:"id" binding:"required" db:"id" example:"1"`
+	// Reconciled account
+	AccountID int64 `json:"accountId" binding:"required" db:"account_id" example:"2"`
+	// End date of bank statement
+	StatementDate time.Time `json:"statementDate" binding:"required" db:"statement_date" format:"yyyy-MM-dd" example:"2022-03-31"`
+	// Closing balance of bank statement
+	StatementBalance float64 `json:"statementBalance" binding:"required" db:"statement_balance" example:"120500.5"`
+	// Time of start
+	CreatedAt time.Time `json:"createdAt" binding:"required" db:"created_at" format:"yyyy-MM-ddThh:mm:ss.ZZZ" example:"2022-04-02T10:12:45.499198Z"`
+	// Time of completion, missing while reconciliation is in progress
+	CompletedAt *time.Time `json:"completedAt,omitempty" db:"completed_at" format:"yyyy-MM-ddThh:mm:ss.ZZZ" example:"2022-04-02T10:20:11.124511Z"`
+} // @name Reconciliation
+
+type ReconciliationToCreate struct {
+	// End date of bank statement
+	StatementDate time.Time `json:"statementDate" binding:"required" format:"yyyy-MM-dd" example:"2022-03-31"`
+	// Closing balance of bank statement
+	StatementBalance *float64 `json:"statementBalance" binding:"required" example:"120500.5"`
+} // @name ReconciliationToCreate
+
+type ReconciliationTransaction struct {
+	Transaction
+	// Transaction is found in bank statement
+	Ticked bool `json:"ticked" example:"true"`
+} // @name ReconciliationTransaction
+
+type ReconciliationDetails struct {
+	Reconciliation
+	// Balance of account by books at end date of statement
+	BookBalance float64 `json:"bookBalance" binding:"required" example:"121000.5"`
+	// Book balance without transactions not ticked, expected to match statement balance
+	ClearedBalance float64 `json:"clearedBalance" binding:"required" example:"120300.5"`
+	// Statement balance minus cleared balance, reconciliation is completed when it is zero
+	Difference float64 `json:"difference" binding:"required" example:"200"`
+	// Transactions not reconciled before up to end date of statement
+	Transactions []ReconciliationTransaction `json:"transactions"`
+} // @name ReconciliationDetails
@@ -1,5 +1,54 @@
 package domain
 
+import "time"
+
+type Interval string // @name Interval
+
+// Intervals of grouping stats by periods
+const (
+	Day   = Interval("day")
+	Week  = Interval("week")
+	Month = Interval("month")
+	Year  = Interval("year")
+)
+
+func (i Interval) Validate() error {
+	if i != Day && i != Week && i != Month && i != Year {
+		return ErrInvalidInterval
+	}
+
+	return nil
+}
+
+// Periods returns count of periods of interval from period of one date up to period of another, both included.
+// Weeks start on Monday
+func (i Interval) Periods(from time.Time, to time.Time) int64 {
+	if to.Before(from) {
+		return 0
+	}
+
+	switch i {
+	case Day, Week:
+		from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+		to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+
+		if i == Week {
+			from = from.AddDate(0, 0, -(int(from.Weekday())+6)%7)
+			to = to.AddDate(0, 0, -(int(to.Weekday())+6)%7)
+
+			return (to.Unix()-from.Unix())/(7*24*60*60) + 1
+		}
+
+		return (to.Unix()-from.Unix())/(24*60*60) + 1
+	case Month:
+		return int64((to.Year()-from.Year())*12+int(to.Month())-int(from.Month())) + 1
+	case Year:
+		return int64(to.Year()-from.Year()) + 1
+	}
+
+	return 0
+}
+
 type Statement struct {
 	// Account information
 	Account Account `json:"account" binding:"required"`
@@ -7,6 +56,176 @@ type Statement struct {
 	BalanceIn Balance `json:"balanceIn" binding:"required"`
 	// Balance for end of period
 	BalanceOut Balance `json:"balanceOut" binding:"required"`
-	// Transactions for given period
-	Transactions []Transaction `json:"transactions" binding:"required"`
+	// Transactions for given period ordered by date
+	Transactions []StatementTransaction `json:"transactions" binding:"required"`
+	// Sums of listed transactions by types
+	Totals StatementTotals `json:"totals" binding:"required"`
+	// Whether opening balance with all transactions of period gives closing balance
+	Reconciled bool `json:"reconciled" binding:"required" example:"true"`
+	// Closing balance minus opening balance with all transactions of period
+	Discrepancy float64 `json:"discrepancy" binding:"required" example:"0"`
 } // @name Statement
+
+type StatementTransaction struct {
+	Transaction
+	// Balance of account after transaction
+	Balance float64 `json:"balance" binding:"required" example:"1500.5"`
+} // @name StatementTransaction
+
+type StatementTotals struct {
+	// Sum of incomes
+	Income float64 `json:"income" binding:"required" example:"1200.5"`
+	// Sum of expenses
+	Expense float64 `json:"expense" binding:"required" example:"800"`
+	// Sum of transfers to account
+	TransferIn float64 `json:"transferIn" binding:"required" example:"100"`
+	// Sum of transfers from account
+	TransferOut float64 `json:"transferOut" binding:"required" example:"50"`
+} // @name StatementTotals
+
+type ConsolidatedFilter struct {
+	OwnerId     int64
+	AccountIds  []int64
+	AccountType *AccountType
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	// Leave transfers between selected accounts out of totals
+	ExcludeInternal bool
+}
+
+type AccountBalances struct {
+	// Account information
+	Account Account `json:"account" binding:"required"`
+	// Balance for start of period
+	BalanceIn Balance `json:"balanceIn" binding:"required"`
+	// Balance for end of period
+	BalanceOut Balance `json:"balanceOut" binding:"required"`
+} // @name AccountBalances
+
+type ConsolidatedTransaction struct {
+	StatementTransaction
+	// Whether transaction is transfer between selected accounts
+	Internal bool `json:"internal" binding:"required" example:"false"`
+} // @name ConsolidatedTransaction
+
+type ConsolidatedStatement struct {
+	// Balances of selected accounts
+	Accounts []AccountBalances `json:"accounts" binding:"required"`
+	// Sum of balances of accounts for start of period
+	BalanceIn float64 `json:"balanceIn" binding:"required" example:"1500"`
+	// Sum of balances of accounts for end of period
+	BalanceOut float64 `json:"balanceOut" binding:"required" example:"1700"`
+	// Transactions of all accounts ordered by date, transfers between accounts are listed once.
+	// Balance of transaction is sum of balances of accounts after it
+	Transactions []ConsolidatedTransaction `json:"transactions" binding:"required"`
+	// Sums of transactions by types
+	Totals StatementTotals `json:"totals" binding:"required"`
+	// Whether opening balances with all transactions of period give closing balances
+	Reconciled bool `json:"reconciled" binding:"required" example:"true"`
+	// Closing balances minus opening balances with all transactions of period
+	Discrepancy float64 `json:"discrepancy" binding:"required" example:"0"`
+} // @name ConsolidatedStatement
+
+type SystemStats struct {
+	// Count of all users
+	Users int64 `json:"users" binding:"required" db:"users" example:"120"`
+	// Count of disabled users
+	DisabledUsers int64 `json:"disabledUsers" binding:"required" db:"disabled_users" example:"3"`
+	// Count of users with not expired sessions
+	ActiveSessions int64 `json:"activeSessions" binding:"required" db:"active_sessions" example:"40"`
+	// Count of all accounts
+	Accounts int64 `json:"accounts" binding:"required" db:"accounts" example:"310"`
+	// Count of all transactions
+	Transactions int64 `json:"transactions" binding:"required" db:"transactions" example:"10452"`
+} // @name SystemStats
+
+type CashFlow struct {
+	// Start of period
+	Period time.Time `json:"period" binding:"required" db:"period" format:"yyyy-MM-dd" example:"2021-09-01T00:00:00Z"`
+	// Sum of incomes
+	Income float64 `json:"income" binding:"required" db:"income" example:"1200.5"`
+	// Sum of expenses
+	Expense float64 `json:"expense" binding:"required" db:"expense" example:"800"`
+	// Sum of transfers to selected accounts
+	TransferIn float64 `json:"transferIn" binding:"required" db:"transfer_in" example:"100"`
+	// Sum of transfers from selected accounts
+	TransferOut float64 `json:"transferOut" binding:"required" db:"transfer_out" example:"50"`
+	// Change of money on selected accounts
+	Net float64 `json:"net" binding:"required" db:"net" example:"450.5"`
+} // @name CashFlow
+
+type NetWorth struct {
+	// Date of balances
+	Date time.Time `json:"date" binding:"required" format:"yyyy-MM-dd" example:"2021-09-30T00:00:00Z"`
+	// Sum of balances of cash, card and deposit accounts
+	Assets float64 `json:"assets" binding:"required" example:"15000"`
+	// Sum of balances of loan accounts
+	Liabilities float64 `json:"liabilities" binding:"required" example:"4000"`
+	// Assets minus liabilities
+	Total float64 `json:"total" binding:"required" example:"11000"`
+	// Sums of balances by account types
+	Types map[AccountType]float64 `json:"types" binding:"required"`
+} // @name NetWorth
+
+type CategoryTrend struct {
+	// Category of transactions
+	Category string `json:"category" binding:"required" example:"food"`
+	// Sum for selected period
+	Current float64 `json:"current" binding:"required" example:"1230"`
+	// Sum for previous period of the same length
+	Previous float64 `json:"previous" binding:"required" example:"1000"`
+	// Current minus previous sum
+	Change float64 `json:"change" binding:"required" example:"230"`
+	// Change in percents of previous sum, missing if previous sum is zero
+	ChangePercent *float64 `json:"changePercent,omitempty" example:"23"`
+	// Sum for the same period last year
+	LastYear float64 `json:"lastYear" binding:"required" example:"1100"`
+	// Current minus last year sum
+	LastYearChange float64 `json:"lastYearChange" binding:"required" example:"130"`
+	// Change in percents of last year sum, missing if last year sum is zero
+	LastYearChangePercent *float64 `json:"lastYearChangePercent,omitempty" example:"11.82"`
+	// Average monthly sum for 3 months up to end of period
+	Avg3Months float64 `json:"avg3Months" binding:"required" example:"1050"`
+	// Average monthly sum for 6 months up to end of period
+	Avg6Months float64 `json:"avg6Months" binding:"required" example:"990.5"`
+	// Average monthly sum for 12 months up to end of period
+	Avg12Months float64 `json:"avg12Months" binding:"required" example:"1010.25"`
+} // @name CategoryTrend
+
+type AccountInterest struct {
+	// Account information
+	Account Account `json:"account" binding:"required"`
+	// Interest earned on deposit or paid on loan, estimated by monthly rate on balance for start of every month
+	Interest float64 `json:"interest" binding:"required" example:"120.5"`
+} // @name AccountInterest
+
+type AnnualReport struct {
+	// Year of report
+	Year int `json:"year" binding:"required" example:"2021"`
+	// Sum of incomes
+	Income float64 `json:"income" binding:"required" example:"120000"`
+	// Sum of expenses
+	Expense float64 `json:"expense" binding:"required" example:"90000"`
+	// Percent of income not spent, missing if there is no income
+	SavingsRate *float64 `json:"savingsRate,omitempty" example:"25"`
+	// Sums of incomes by categories
+	IncomeByCategory []TransactionStat `json:"incomeByCategory" binding:"required"`
+	// Sums of expenses by categories
+	ExpenseByCategory []TransactionStat `json:"expenseByCategory" binding:"required"`
+	// Sums of transactions by months
+	Months []CashFlow `json:"months" binding:"required"`
+	// Incomes and expenses with largest amounts
+	LargestTransactions []Transaction `json:"largestTransactions" binding:"required"`
+	// Net worth for end of previous year
+	NetWorthStart NetWorth `json:"netWorthStart" binding:"required"`
+	// Net worth for end of year
+	NetWorthEnd NetWorth `json:"netWorthEnd" binding:"required"`
+	// Net worth for end of year minus net worth for end of previous year
+	NetWorthChange float64 `json:"netWorthChange" binding:"required" example:"30000"`
+	// Sum of interest earned on deposits
+	InterestEarned float64 `json:"interestEarned" binding:"required" example:"1200"`
+	// Sum of interest paid on loans
+	InterestPaid float64 `json:"interestPaid" binding:"required" example:"800"`
+	// Interest by deposit and loan accounts
+	Interest []AccountInterest `json:"interest" binding:"required"`
+} // @name AnnualReport
@@ -0,0 +1,64 @@
+package domain
+
+import "time"
+
+type TransactionTemplate struct {
+	// Unique ID
+	ID int64 `json:"id" binding:"required" db:"id" example:"1"`
+	// Name, unique among templates of user
+	Name string `json:"name" binding:"required" db:"name" example:"Coffee"`
+	// Type of transactions
+	Type TransactionType `json:"type" binding:"required" db:"type" enums:"income,expense,transfer" example:"expense"`
+	// Default amount
+	Amount *float64 `json:"amount,omitempty" db:"amount" example:"950"`
+	// Id of default category
+	CategoryId *int64 `json:"categoryId,omitempty" db:"category_id" example:"3"`
+	// Id of default account transfer from
+	CreditId *int64 `json:"creditId,omitempty" db:"credit_id" example:"1"`
+	// Id of default account transfer to
+	DebitId *int64 `json:"debitId,omitempty" db:"debit_id" example:"2"`
+	// Default tags
+	Tags    []string `json:"tags" binding:"required" db:"-" example:"coffee"`
+	OwnerId int64    `json:"-" db:"owner_id" swaggerignore:"true"`
+	// Time of creation
+	CreatedAt time.Time `json:"createdAt" binding:"required" db:"created_at" format:"yyyy-MM-ddThh:mm:ss.ZZZ" example:"2022-04-02T10:12:45.499198Z"`
+} // @name TransactionTemplate
+
+type TransactionTemplateToCreate struct {
+	// Name, unique among templates of user
+	Name string `json:"name" binding:"required,max=100" example:"Coffee"`
+	// Type of transactions
+	Type TransactionType `json:"type" binding:"required,oneof=income expense transfer" enums:"income,expense,transfer" example:"expense"`
+	// Default amount
+	Amount *float64 `json:"amount" binding:"omitempty,gte=0" example:"950"`
+	// Id of default category
+	CategoryId *int64 `json:"categoryId" example:"3"`
+	// Id of default account transfer from
+	CreditId *int64 `json:"creditId" example:"1"`
+	// Id of default account transfer to
+	DebitId *int64 `json:"debitId" example:"2"`
+	// Default tags, stored trimmed and in lower case
+	Tags []string `json:"tags" binding:"omitempty,max=20,dive,max=50" example:"coffee"`
+} // @name TransactionTemplateToCreate
+
+// TransactionTemplateToApply overrides defaults of template in created transaction
+type TransactionTemplateToApply struct {
+	// Amount, default amount of template if empty
+	Amount *float64 `json:"amount" binding:"omitempty,gte=0" example:"1100"`
+	// Date of creation, today if empty
+	CreatedAt *time.Time `json:"createdAt" format:"yyyy-MM-dd" example:"2022-04-02"`
+	// Status of settlement, cleared by default
+	Status TransactionStatus `json:"status,omitempty" binding:"omitempty,oneof=pending cleared" enums:"pending,cleared" example:"pending"`
+	// Notes
+	Description *string `json:"description" binding:"omitempty,max=1000" example:"Coffee with team"`
+	// Payee or merchant
+	Payee *string `json:"payee" binding:"omitempty,max=255" example:"Starbucks"`
+	// Tags, default tags of template if empty
+	Tags []string `json:"tags" binding:"omitempty,max=20,dive,max=50" example:"coffee,work"`
+	// Id of category, default category of template if empty
+	CategoryId *int64 `json:"categoryId" example:"3"`
+	// Id of account transfer from, default account of template if empty
+	CreditId *int64 `json:"creditId" example:"1"`
+	// Id of account transfer to, default account of template if empty
+	DebitId *int64 `json:"debitId" example:"2"`
+} // @name TransactionTemplateToApply
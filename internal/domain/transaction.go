@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // Transaction types
 const (
@@ -11,6 +14,38 @@ const (
 
 type TransactionType string // @name TransactionType
 
+// Flags of unusual transactions
+const (
+	// Amount is far above usual amounts of category
+	FlagUnusualAmount = TransactionFlag("unusual_amount")
+	// First expense in category
+	FlagNewCategory = TransactionFlag("new_category")
+	// Abnormal count of expenses in a day
+	FlagManyTransactions = TransactionFlag("many_transactions")
+)
+
+type TransactionFlag string // @name TransactionFlag
+
+// Transaction statuses
+const (
+	// Made, but not settled by bank yet
+	Pending = TransactionStatus("pending")
+	// Settled by bank
+	Cleared = TransactionStatus("cleared")
+	// Matched with bank statement, locked against changes
+	Reconciled = TransactionStatus("reconciled")
+)
+
+type TransactionStatus string // @name TransactionStatus
+
+func (s TransactionStatus) Validate() error {
+	if s != Pending && s != Cleared && s != Reconciled {
+		return ErrInvalidTransactionStatus
+	}
+
+	return nil
+}
+
 type TransactionCategory struct {
 	// Unique ID
 	ID int64 `json:"id"  binding:"required" db:"id" example:"1"`
@@ -31,12 +66,107 @@ type Transaction struct {
 	Category *string `json:"category,omitempty"`
 	// Date of creation
 	CreatedAt time.Time `json:"createdAt" binding:"required,date" db:"created_at" format:"yyyy-MM-dd" example:"2021-09-01"`
+	// Status of settlement
+	Status TransactionStatus `json:"status" db:"status" enums:"pending,cleared,reconciled" example:"cleared"`
+	// Notes
+	Description *string `json:"description,omitempty" db:"description" example:"Dinner with team"`
+	// Payee or merchant
+	Payee *string `json:"payee,omitempty" db:"payee" example:"Navat"`
+	// Tags ordered by name
+	Tags []string `json:"tags,omitempty" example:"work,restaurants"`
+	// Parts of split transaction with own categories, category of transaction itself is missing then
+	Lines []TransactionLine `json:"lines,omitempty"`
 	// Account transfer from
 	Credit *Account `json:"credit,omitempty" db:"credit"`
 	// Account transfer to
 	Debit *Account `json:"debit,omitempty" db:"debit"`
+	// Reasons transaction is considered unusual
+	Flags []TransactionFlag `json:"flags,omitempty" enums:"unusual_amount,new_category,many_transactions"`
+	// Flags are dismissed by user
+	FlagsDismissed bool `json:"flagsDismissed,omitempty"`
+	// Transaction is checked for anomalies
+	Scanned bool `json:"-"`
+	// Time of deletion, set only for transactions in trash
+	DeletedAt *time.Time `json:"deletedAt,omitempty" format:"yyyy-MM-ddThh:mm:ss.ZZZ" example:"2022-04-02T10:12:45.499198Z"`
 } // @name Transaction
 
+// HasCategory reports whether transaction or one of its lines has category with given title
+func (t Transaction) HasCategory(title string) bool {
+	if t.Category != nil && *t.Category == title {
+		return true
+	}
+
+	for _, line := range t.Lines {
+		if line.Category != nil && *line.Category == title {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasTag reports whether transaction has tag, which is compared in lower case as tags are stored
+func (t Transaction) HasTag(tag string) bool {
+	tag = strings.ToLower(tag)
+
+	for _, title := range t.Tags {
+		if title == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsFlagged reports whether transaction has flags not dismissed by user
+func (t Transaction) IsFlagged() bool {
+	return len(t.Flags) > 0 && !t.FlagsDismissed
+}
+
+// Parts returns lines of split transaction or transaction itself as the only line, so amounts are attributed to
+// categories the same way for split and plain transactions
+func (t Transaction) Parts() []TransactionLine {
+	if len(t.Lines) > 0 {
+		return t.Lines
+	}
+
+	return []TransactionLine{{Category: t.Category, Amount: t.Amount}}
+}
+
+// CategoryTitle returns title of category or distinct titles of line categories separated by commas
+func (t Transaction) CategoryTitle() string {
+	if t.Category != nil {
+		return *t.Category
+	}
+
+	titles := make([]string, 0, len(t.Lines))
+	seen := make(map[string]bool, len(t.Lines))
+
+	for _, line := range t.Lines {
+		if line.Category != nil && !seen[*line.Category] {
+			seen[*line.Category] = true
+			titles = append(titles, *line.Category)
+		}
+	}
+
+	return strings.Join(titles, ", ")
+}
+
+type TransactionLine struct {
+	// Category
+	Category *string `json:"category,omitempty" example:"groceries"`
+	// Part of transaction amount
+	Amount float64 `json:"amount" binding:"required" example:"830.5"`
+} // @name TransactionLine
+
+type TransactionSearchResult struct {
+	Transaction
+	// Relevance of transaction to query, higher is better
+	Rank float64 `json:"rank" binding:"required" example:"0.6"`
+	// Fragments of payee and description with matched words wrapped in <b> tags
+	Highlight string `json:"highlight" binding:"required" example:"Navat - <b>Dinner</b> with team"`
+} // @name TransactionSearchResult
+
 type TransactionsFilter struct {
 	AccountId   *int64
 	OwnerId     *int64
@@ -44,6 +174,15 @@ type TransactionsFilter struct {
 	Type        *TransactionType
 	CreatedFrom *time.Time
 	CreatedTo   *time.Time
+	// Transactions having tag
+	Tag *string
+	// Transactions of payee, case insensitive
+	Payee *string
+	// Part of description or payee, case insensitive
+	Text *string
+	// Only transactions with not dismissed flags or without them
+	Flagged *bool
+	Status  *TransactionStatus
 }
 
 type TransactionToCreate struct {
@@ -53,8 +192,97 @@ type TransactionToCreate struct {
 	Type TransactionType `json:"type" binding:"required,oneof=income expense transfer" enums:"income,expense,transfer" example:"income"`
 	// Date of creation
 	CreatedAt time.Time `json:"createdAt" binding:"required" db:"created_at" format:"yyyy-MM-dd" example:"2021-09-01"`
+	// Status of settlement, cleared by default
+	Status TransactionStatus `json:"status,omitempty" binding:"omitempty,oneof=pending cleared" db:"status" enums:"pending,cleared" example:"pending"`
+	// Notes
+	Description *string `json:"description,omitempty" binding:"omitempty,max=1000" db:"description" example:"Dinner with team"`
+	// Payee or merchant
+	Payee *string `json:"payee,omitempty" binding:"omitempty,max=255" db:"payee" example:"Navat"`
+	// Tags, stored trimmed and in lower case
+	Tags []string `json:"tags,omitempty" binding:"omitempty,max=20,dive,max=50" example:"work,restaurants"`
+	// Split of income or expense into categories, amounts must sum to amount of transaction.
+	// Category of transaction is not set then
+	Lines []TransactionLineToCreate `json:"lines,omitempty" binding:"omitempty,min=2,max=50,dive"`
 } // @name TransactionToCreate
 
+type TransactionLineToCreate struct {
+	// Id of category
+	CategoryId int64 `json:"categoryId" binding:"required" example:"3"`
+	// Part of transaction amount
+	Amount float64 `json:"amount" binding:"required,gt=0" example:"830.5"`
+} // @name TransactionLineToCreate
+
+// Modes of applying batch of transactions
+const (
+	// Batch is rejected if any item is invalid
+	AllOrNothing = BatchMode("all_or_nothing")
+	// Valid items are applied, invalid ones are skipped
+	BestEffort = BatchMode("best_effort")
+)
+
+type BatchMode string // @name BatchMode
+
+func (m BatchMode) Validate() error {
+	if m != AllOrNothing && m != BestEffort {
+		return ErrInvalidBatchMode
+	}
+
+	return nil
+}
+
+type TransactionBatchItem struct {
+	TransactionToCreate
+	// Id of category, missing for transfers and split transactions
+	CategoryId *int64 `json:"categoryId,omitempty" example:"3"`
+	// Id of account transfer from, required for expenses and transfers
+	CreditId *int64 `json:"creditId,omitempty" example:"1"`
+	// Id of account transfer to, required for incomes and transfers
+	DebitId *int64 `json:"debitId,omitempty" example:"2"`
+} // @name TransactionBatchItem
+
+type TransactionsToCreate struct {
+	// Mode of applying, all_or_nothing by default
+	Mode BatchMode `json:"mode,omitempty" enums:"all_or_nothing,best_effort" example:"all_or_nothing"`
+	// Transactions to create in given order
+	Items []TransactionBatchItem `json:"items" binding:"required,min=1,max=100,dive"`
+} // @name TransactionsToCreate
+
+type TransactionsToDelete struct {
+	// Mode of applying, all_or_nothing by default
+	Mode BatchMode `json:"mode,omitempty" enums:"all_or_nothing,best_effort" example:"best_effort"`
+	// Ids of transactions to delete
+	IDs []int64 `json:"ids" binding:"required,min=1,max=100" example:"4,5"`
+	// Delete reconciled transactions too
+	Unlock bool `json:"unlock,omitempty" example:"false"`
+} // @name TransactionsToDelete
+
+type TransactionStatusToUpdate struct {
+	// New status
+	Status TransactionStatus `json:"status" binding:"required,oneof=pending cleared reconciled" enums:"pending,cleared,reconciled" example:"cleared"`
+	// Allow changing status of reconciled transaction
+	Unlock bool `json:"unlock,omitempty" example:"false"`
+} // @name TransactionStatusToUpdate
+
+type TransactionBatchResult struct {
+	// Position of item in request
+	Index int `json:"index" example:"0"`
+	// Id of created transaction or of transaction to delete
+	ID int64 `json:"id,omitempty" example:"4"`
+	// Created transaction
+	Transaction *Transaction `json:"transaction,omitempty"`
+	// Reason item is not applied
+	Error string `json:"error,omitempty" example:"no account selected"`
+} // @name TransactionBatchResult
+
+type TransactionBatch struct {
+	// Count of applied items
+	Applied int `json:"applied" example:"1"`
+	// Count of invalid items
+	Failed int `json:"failed" example:"1"`
+	// Results of items in order of request
+	Results []TransactionBatchResult `json:"results"`
+} // @name TransactionBatch
+
 func (t TransactionType) Validate() error {
 	if t != Income && t != Expense && t != Transfer {
 		return ErrInvalidTransactionType
@@ -63,9 +291,36 @@ func (t TransactionType) Validate() error {
 	return nil
 }
 
+type StatsGroup string // @name StatsGroup
+
+// Groupings of transaction stats
+const (
+	GroupByCategory = StatsGroup("category")
+	GroupByType     = StatsGroup("type")
+	GroupByAccount  = StatsGroup("account")
+	GroupByMonth    = StatsGroup("month")
+	GroupByWeekday  = StatsGroup("weekday")
+)
+
+func (g StatsGroup) Validate() error {
+	if g != GroupByCategory && g != GroupByType && g != GroupByAccount && g != GroupByMonth && g != GroupByWeekday {
+		return ErrInvalidStatsGroup
+	}
+
+	return nil
+}
+
 type TransactionStat struct {
-	// Category
-	Category string `json:"category" binding:"required" db:"category" example:"food"`
-	// Count
-	Value int64 `json:"value" binding:"required" db:"value" example:"12"`
+	// Group: category, type, account title, month (yyyy-MM) or weekday
+	Group string `json:"group" binding:"required" db:"grp" example:"food"`
+	// Sum of amounts
+	Sum float64 `json:"sum" binding:"required" db:"sum" example:"1250.5"`
+	// Count of transactions
+	Count int64 `json:"count" binding:"required" db:"count" example:"12"`
+	// Average amount
+	Avg float64 `json:"avg" binding:"required" db:"avg" example:"104.21"`
+	// Minimal amount
+	Min float64 `json:"min" binding:"required" db:"min" example:"10"`
+	// Maximal amount
+	Max float64 `json:"max" binding:"required" db:"max" example:"300"`
 } // @name TransactionStat
@@ -0,0 +1,8 @@
+package domain
+
+type Trash struct {
+	// Deleted accounts, latest deleted first
+	Accounts []Account `json:"accounts"`
+	// Deleted transactions, latest deleted first
+	Transactions []Transaction `json:"transactions"`
+} // @name Trash
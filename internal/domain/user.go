@@ -1,5 +1,14 @@
 package domain
 
+type Role string // @name Role
+
+// User roles
+const (
+	RoleUser    = Role("user")
+	RoleAdmin   = Role("admin")
+	RoleSupport = Role("support")
+)
+
 type User struct {
 	// Unique id
 	ID int64 `json:"id" binding:"required" db:"id" example:"1"`
@@ -11,6 +20,10 @@ type User struct {
 	LastName string `json:"lastName" binding:"required,alpha" db:"last_name" example:"Sam"`
 	// Secret password
 	Password string `json:"-" binding:"omitempty,alphanum,min=8" db:"password" example:"qweqweqwe"`
+	// Role of user
+	Role Role `json:"role" binding:"required,oneof=user admin support" db:"role" enums:"user,admin,support" example:"user"`
+	// Disabled users can not log in
+	Disabled bool `json:"disabled" db:"disabled" example:"false"`
 } // @name User
 
 type UserToCreate struct {
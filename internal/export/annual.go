@@ -0,0 +1,234 @@
+package export
+
+import (
+	"fmt"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/pkg/pdf"
+	"github.com/lotostudio/financial-api/pkg/xlsx"
+	"io"
+	"strconv"
+)
+
+// AnnualReportFilename returns name of file for annual report in given format
+func AnnualReportFilename(report domain.AnnualReport, format string) string {
+	return fmt.Sprintf("annual-report-%d.%s", report.Year, format)
+}
+
+// annualSummary returns names and values of report totals
+func annualSummary(report domain.AnnualReport) [][2]string {
+	savingsRate := "-"
+
+	if report.SavingsRate != nil {
+		savingsRate = amount(*report.SavingsRate) + "%"
+	}
+
+	return [][2]string{
+		{"Income", amount(report.Income)},
+		{"Expense", amount(report.Expense)},
+		{"Savings rate", savingsRate},
+		{"Net worth at start", amount(report.NetWorthStart.Total)},
+		{"Net worth at end", amount(report.NetWorthEnd.Total)},
+		{"Net worth change", amount(report.NetWorthChange)},
+		{"Interest earned", amount(report.InterestEarned)},
+		{"Interest paid", amount(report.InterestPaid)},
+	}
+}
+
+// counterparty returns title of account of income or expense
+func counterparty(tx domain.Transaction) string {
+	if tx.Credit != nil {
+		return tx.Credit.Title
+	}
+
+	if tx.Debit != nil {
+		return tx.Debit.Title
+	}
+
+	return ""
+}
+
+// AnnualReportXLSX writes annual report as spreadsheet with sheets of summary, categories, months, largest
+// transactions and interest
+func AnnualReportXLSX(w io.Writer, report domain.AnnualReport) error {
+	book := xlsx.New()
+
+	summary := book.AddSheet("Summary")
+	summary.AddRow("Year", report.Year)
+
+	for _, line := range annualSummary(report) {
+		summary.AddRow(line[0], line[1])
+	}
+
+	categories := book.AddSheet("Categories")
+	categories.AddHeader("Type", "Category", "Sum", "Count", "Average", "Min", "Max")
+
+	for _, group := range []struct {
+		txType string
+		stats  []domain.TransactionStat
+	}{
+		{string(domain.Income), report.IncomeByCategory},
+		{string(domain.Expense), report.ExpenseByCategory},
+	} {
+		for _, st := range group.stats {
+			categories.AddRow(group.txType, st.Group, st.Sum, st.Count, st.Avg, st.Min, st.Max)
+		}
+	}
+
+	months := book.AddSheet("Months")
+	months.AddHeader("Month", "Income", "Expense", "Transfers in", "Transfers out", "Net")
+
+	for _, m := range report.Months {
+		months.AddRow(m.Period.Format("2006-01"), m.Income, m.Expense, m.TransferIn, m.TransferOut, m.Net)
+	}
+
+	largest := book.AddSheet("Largest transactions")
+	largest.AddHeader("Date", "Type", "Category", "Account", "Amount")
+
+	for _, tx := range report.LargestTransactions {
+		largest.AddRow(tx.CreatedAt.Format(dateLayout), string(tx.Type), tx.CategoryTitle(), counterparty(tx), tx.Amount)
+	}
+
+	interest := book.AddSheet("Interest")
+	interest.AddHeader("Account", "Type", "Rate", "Interest")
+
+	for _, i := range report.Interest {
+		var rate float64
+
+		if i.Account.Rate != nil {
+			rate = float64(*i.Account.Rate)
+		}
+
+		interest.AddRow(i.Account.Title, string(i.Account.Type), rate, i.Interest)
+	}
+
+	_, err := book.WriteTo(w)
+
+	return err
+}
+
+// Columns of PDF annual report tables
+var (
+	pdfCategoryColumns = []pdfColumn{{x: 40, width: 300}, {x: 450, width: 80, right: true},
+		{x: 555, width: 100, right: true}}
+	pdfMonthColumns = []pdfColumn{{x: 40, width: 100}, {x: 305, width: 80, right: true},
+		{x: 430, width: 80, right: true}, {x: 555, width: 80, right: true}}
+	pdfLargestColumns = []pdfColumn{{x: 40, width: 60}, {x: 100, width: 55}, {x: 155, width: 120},
+		{x: 275, width: 180}, {x: 555, width: 100, right: true}}
+	pdfInterestColumns = []pdfColumn{{x: 40, width: 200}, {x: 240, width: 100}, {x: 450, width: 60, right: true},
+		{x: 555, width: 100, right: true}}
+)
+
+// annualPDF draws sections of annual report one after another, starting new page when current one is full
+type annualPDF struct {
+	doc *pdf.Document
+	y   float64
+}
+
+// table draws titled table, repeating its header on every new page
+func (p *annualPDF) table(title string, columns []pdfColumn, header []string, rows [][]string) {
+	// Title, header and first row are kept on the same page
+	if p.y+4*pdfRow > p.doc.Height()-pdfMargin {
+		p.doc.AddPage()
+		p.y = pdfMargin
+	}
+
+	p.y += 2 * pdfRow
+	p.doc.Text(pdfMargin, p.y, pdf.Bold, 12, title)
+	p.y += pdfRow + 4
+
+	drawHeader := func() {
+		for i, c := range header {
+			cell(p.doc, columns[i], p.y, pdf.Bold, c)
+		}
+
+		p.doc.Line(pdfMargin, p.y+4, p.doc.Width()-pdfMargin, p.y+4, 0.5)
+		p.y += pdfRow + 2
+	}
+
+	drawHeader()
+
+	for _, r := range rows {
+		if p.y > p.doc.Height()-pdfMargin-pdfRow {
+			p.doc.AddPage()
+			p.y = pdfMargin + pdfRow
+			drawHeader()
+		}
+
+		for i, v := range r {
+			cell(p.doc, columns[i], p.y, pdf.Regular, v)
+		}
+
+		p.y += pdfRow
+	}
+}
+
+// AnnualReportPDF writes annual report as paginated A4 document with summary followed by tables of categories, months,
+// largest transactions and interest
+func AnnualReportPDF(w io.Writer, report domain.AnnualReport) error {
+	p := &annualPDF{doc: pdf.New()}
+	p.doc.AddPage()
+	p.y = pdfMargin + 16
+
+	p.doc.Text(pdfMargin, p.y, pdf.Bold, 16, "Annual report "+strconv.Itoa(report.Year))
+	p.y += 24
+
+	for _, line := range annualSummary(report) {
+		p.doc.Text(pdfMargin, p.y, pdf.Bold, 10, line[0])
+		p.doc.Text(pdfMargin+120, p.y, pdf.Regular, 10, line[1])
+		p.y += pdfRow
+	}
+
+	categoryRows := func(stats []domain.TransactionStat) [][]string {
+		rows := make([][]string, 0, len(stats))
+
+		for _, st := range stats {
+			rows = append(rows, []string{st.Group, strconv.FormatInt(st.Count, 10), amount(st.Sum)})
+		}
+
+		return rows
+	}
+
+	p.table("Income by category", pdfCategoryColumns, []string{"Category", "Count", "Sum"},
+		categoryRows(report.IncomeByCategory))
+	p.table("Expenses by category", pdfCategoryColumns, []string{"Category", "Count", "Sum"},
+		categoryRows(report.ExpenseByCategory))
+
+	months := make([][]string, 0, len(report.Months))
+
+	for _, m := range report.Months {
+		months = append(months, []string{m.Period.Format("2006-01"), amount(m.Income), amount(m.Expense),
+			amount(m.Net)})
+	}
+
+	p.table("Months", pdfMonthColumns, []string{"Month", "Income", "Expense", "Net"}, months)
+
+	largest := make([][]string, 0, len(report.LargestTransactions))
+
+	for _, tx := range report.LargestTransactions {
+		largest = append(largest, []string{tx.CreatedAt.Format(dateLayout), string(tx.Type), tx.CategoryTitle(),
+			counterparty(tx), amount(tx.Amount)})
+	}
+
+	p.table("Largest transactions", pdfLargestColumns, []string{"Date", "Type", "Category", "Account", "Amount"},
+		largest)
+
+	interest := make([][]string, 0, len(report.Interest))
+
+	for _, i := range report.Interest {
+		var rate string
+
+		if i.Account.Rate != nil {
+			rate = amount(float64(*i.Account.Rate)) + "%"
+		}
+
+		interest = append(interest, []string{i.Account.Title, string(i.Account.Type), rate, amount(i.Interest)})
+	}
+
+	p.table("Interest", pdfInterestColumns, []string{"Account", "Type", "Rate", "Interest"}, interest)
+
+	pageNumbers(p.doc)
+
+	_, err := p.doc.WriteTo(w)
+
+	return err
+}
@@ -0,0 +1,91 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testAnnualReport(transactions int) domain.AnnualReport {
+	card := domain.Account{ID: 2, Title: "Main card", Currency: "KZT", Type: domain.Card}
+	rate := float32(12)
+	deposit := domain.Account{ID: 3, Title: "Savings", Currency: "KZT", Type: domain.Deposit, Rate: &rate}
+	food := "food"
+	savingsRate := 25.0
+
+	report := domain.AnnualReport{
+		Year:              2021,
+		Income:            1200,
+		Expense:           900,
+		SavingsRate:       &savingsRate,
+		IncomeByCategory:  []domain.TransactionStat{{Group: "salary", Sum: 1200, Count: 12}},
+		ExpenseByCategory: []domain.TransactionStat{{Group: "food", Sum: 900, Count: 30}},
+		NetWorthStart:     domain.NetWorth{Total: 1000},
+		NetWorthEnd:       domain.NetWorth{Total: 1300},
+		NetWorthChange:    300,
+		InterestEarned:    120,
+		Interest:          []domain.AccountInterest{{Account: deposit, Interest: 120}},
+	}
+
+	for m := time.January; m <= time.December; m++ {
+		report.Months = append(report.Months, domain.CashFlow{Period: time.Date(2021, m, 1, 0, 0, 0, 0, time.UTC),
+			Income: 100, Expense: 75, Net: 25})
+	}
+
+	for i := 0; i < transactions; i++ {
+		report.LargestTransactions = append(report.LargestTransactions, domain.Transaction{ID: int64(i), Amount: 30,
+			Type: domain.Expense, Category: &food, CreatedAt: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+			Credit: &card})
+	}
+
+	return report
+}
+
+func TestAnnualReportXLSX(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, AnnualReportXLSX(&buf, testAnnualReport(10)))
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+
+	require.NoError(t, err)
+
+	sheets := 0
+
+	for _, f := range r.File {
+		if strings.HasPrefix(f.Name, "xl/worksheets/") {
+			sheets++
+		}
+	}
+
+	require.Equal(t, 5, sheets)
+}
+
+func TestAnnualReportPDF(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, AnnualReportPDF(&buf, testAnnualReport(80)))
+
+	out := buf.String()
+	texts := pdfText(t, out)
+
+	require.True(t, strings.HasPrefix(out, "%PDF-"))
+	require.Contains(t, texts, "Annual report 2021")
+	require.Contains(t, texts, "Expenses by category")
+	require.Contains(t, texts, "25.00%")
+	require.Contains(t, texts, "Savings")
+
+	pages := strings.Count(out, "/Type /Page ")
+
+	require.Greater(t, pages, 1)
+	require.Contains(t, texts, fmt.Sprintf("Page %d of %d", pages, pages))
+}
+
+func TestAnnualReportFilename(t *testing.T) {
+	require.Equal(t, "annual-report-2021.xlsx", AnnualReportFilename(testAnnualReport(0), XLSX))
+}
@@ -0,0 +1,256 @@
+// Package export renders domain entities as downloadable documents
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/pkg/pdf"
+	"github.com/lotostudio/financial-api/pkg/xlsx"
+	"io"
+	"strconv"
+)
+
+// Document formats
+const (
+	CSV  = "csv"
+	XLSX = "xlsx"
+	PDF  = "pdf"
+)
+
+// MIME types of formats
+var ContentTypes = map[string]string{
+	CSV:  "text/csv",
+	XLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	PDF:  "application/pdf",
+}
+
+const dateLayout = "2006-01-02"
+
+var statementColumns = []string{"Date", "Type", "Category", "Counterparty", "In", "Out", "Balance"}
+
+// statementRow is transaction of statement from side of its account
+type statementRow struct {
+	date         string
+	txType       string
+	category     string
+	counterparty string
+	in           float64
+	out          float64
+	balance      float64
+}
+
+func statementRows(st domain.Statement) []statementRow {
+	rows := make([]statementRow, 0, len(st.Transactions))
+
+	for _, tx := range st.Transactions {
+		row := statementRow{
+			date:     tx.CreatedAt.Format(dateLayout),
+			txType:   string(tx.Type),
+			category: tx.CategoryTitle(),
+			balance:  tx.Balance,
+		}
+
+		if tx.Debit != nil && tx.Debit.ID == st.Account.ID {
+			row.in = tx.Amount
+
+			if tx.Credit != nil {
+				row.counterparty = tx.Credit.Title
+			}
+		}
+
+		if tx.Credit != nil && tx.Credit.ID == st.Account.ID {
+			row.out = tx.Amount
+
+			if tx.Debit != nil {
+				row.counterparty = tx.Debit.Title
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// StatementFilename returns name of file for statement in given format
+func StatementFilename(st domain.Statement, format string) string {
+	return fmt.Sprintf("statement-%d-%s-%s.%s", st.Account.ID, st.BalanceIn.Date.Format(dateLayout),
+		st.BalanceOut.Date.Format(dateLayout), format)
+}
+
+// StatementCSV writes transactions of statement as CSV table
+func StatementCSV(w io.Writer, st domain.Statement) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(statementColumns); err != nil {
+		return err
+	}
+
+	for _, r := range statementRows(st) {
+		if err := cw.Write([]string{r.date, r.txType, r.category, r.counterparty,
+			amount(r.in), amount(r.out), amount(r.balance)}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// StatementXLSX writes statement as spreadsheet with summary followed by transactions table
+func StatementXLSX(w io.Writer, st domain.Statement) error {
+	book := xlsx.New()
+	sheet := book.AddSheet("Statement")
+
+	sheet.AddRow("Account", st.Account.Title)
+	sheet.AddRow("Type", string(st.Account.Type))
+	sheet.AddRow("Currency", st.Account.Currency)
+	sheet.AddRow("Period", st.BalanceIn.Date.Format(dateLayout)+" - "+st.BalanceOut.Date.Format(dateLayout))
+	sheet.AddRow("Opening balance", st.BalanceIn.Value)
+	sheet.AddRow("Closing balance", st.BalanceOut.Value)
+	sheet.AddRow()
+
+	header := make([]interface{}, len(statementColumns))
+
+	for i, c := range statementColumns {
+		header[i] = c
+	}
+
+	sheet.AddHeader(header...)
+
+	for _, r := range statementRows(st) {
+		sheet.AddRow(r.date, r.txType, r.category, r.counterparty, r.in, r.out, r.balance)
+	}
+
+	_, err := book.WriteTo(w)
+
+	return err
+}
+
+// Layout of PDF statement in points
+const (
+	pdfMargin   = 40.0
+	pdfRow      = 14.0
+	pdfFontSize = 9.0
+)
+
+// pdfColumn is position of PDF table column, amounts are aligned to the right edge of column
+type pdfColumn struct {
+	x     float64
+	width float64
+	right bool
+}
+
+// Columns of PDF statement
+var pdfColumns = []pdfColumn{
+	{x: 40, width: 60},
+	{x: 100, width: 55},
+	{x: 155, width: 95},
+	{x: 250, width: 110},
+	{x: 425, width: 65, right: true},
+	{x: 490, width: 65, right: true},
+	{x: 555, width: 65, right: true},
+}
+
+// StatementPDF writes statement as paginated A4 document with account header, balances and transactions table
+func StatementPDF(w io.Writer, st domain.Statement) error {
+	doc := pdf.New()
+	doc.AddPage()
+
+	y := pdfMargin + 16
+
+	doc.Text(pdfMargin, y, pdf.Bold, 16, "Account statement")
+
+	y += 24
+
+	summary := [][2]string{
+		{"Account", st.Account.Title},
+		{"Type", string(st.Account.Type)},
+	}
+
+	if st.Account.Number != nil {
+		summary = append(summary, [2]string{"Number", "**** " + *st.Account.Number})
+	}
+
+	summary = append(summary,
+		[2]string{"Currency", st.Account.Currency},
+		[2]string{"Period", st.BalanceIn.Date.Format(dateLayout) + " - " + st.BalanceOut.Date.Format(dateLayout)},
+		[2]string{"Opening balance", amount(st.BalanceIn.Value)},
+		[2]string{"Closing balance", amount(st.BalanceOut.Value)},
+	)
+
+	for _, line := range summary {
+		doc.Text(pdfMargin, y, pdf.Bold, 10, line[0])
+		doc.Text(pdfMargin+100, y, pdf.Regular, 10, line[1])
+		y += pdfRow
+	}
+
+	y += pdfRow
+
+	header := func() {
+		for i, c := range statementColumns {
+			cell(doc, pdfColumns[i], y, pdf.Bold, c)
+		}
+
+		doc.Line(pdfMargin, y+4, doc.Width()-pdfMargin, y+4, 0.5)
+		y += pdfRow + 2
+	}
+
+	header()
+
+	for _, r := range statementRows(st) {
+		if y > doc.Height()-pdfMargin-pdfRow {
+			doc.AddPage()
+			y = pdfMargin + pdfRow
+			header()
+		}
+
+		for i, v := range []string{r.date, r.txType, r.category, r.counterparty,
+			amount(r.in), amount(r.out), amount(r.balance)} {
+			cell(doc, pdfColumns[i], y, pdf.Regular, v)
+		}
+
+		y += pdfRow
+	}
+
+	pageNumbers(doc)
+
+	_, err := doc.WriteTo(w)
+
+	return err
+}
+
+// pageNumbers draws "Page i of n" at the bottom of every page
+func pageNumbers(doc *pdf.Document) {
+	for i := 0; i < doc.Pages(); i++ {
+		doc.SetPage(i)
+		doc.TextRight(doc.Width()-pdfMargin, doc.Height()-pdfMargin/2, pdf.Regular, 8,
+			fmt.Sprintf("Page %d of %d", i+1, doc.Pages()))
+	}
+}
+
+// cell draws value in column of PDF table, cutting it to column width
+func cell(doc *pdf.Document, c pdfColumn, y float64, font pdf.Font, value string) {
+	runes := []rune(value)
+
+	for len(runes) > 0 && pdf.TextWidth(font, pdfFontSize, string(runes)) > c.width-5 {
+		runes = runes[:len(runes)-1]
+	}
+
+	if len(runes) < len([]rune(value)) && len(runes) > 3 {
+		runes = append(runes[:len(runes)-3], []rune("...")...)
+	}
+
+	if c.right {
+		doc.TextRight(c.x, y, font, pdfFontSize, string(runes))
+	} else {
+		doc.Text(c.x, y, font, pdfFontSize, string(runes))
+	}
+}
+
+// amount formats money with two decimals
+func amount(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
@@ -0,0 +1,115 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+// pdfText returns text drawn in document, which is decoded by Unicode maps of fonts
+func pdfText(t *testing.T, out string) []string {
+	chars := make(map[string]string)
+
+	for _, m := range regexp.MustCompile(`(?m)^<([0-9A-F]{4})> <([0-9A-F]+)>$`).FindAllStringSubmatch(out, -1) {
+		b, err := hex.DecodeString(m[2])
+		require.NoError(t, err)
+
+		units := make([]uint16, len(b)/2)
+
+		for i := range units {
+			units[i] = uint16(b[i*2])<<8 | uint16(b[i*2+1])
+		}
+
+		chars[m[1]] = string(utf16.Decode(units))
+	}
+
+	var texts []string
+
+	for _, m := range regexp.MustCompile(`<([0-9A-F]*)> Tj`).FindAllStringSubmatch(out, -1) {
+		var sb strings.Builder
+
+		for i := 0; i+4 <= len(m[1]); i += 4 {
+			sb.WriteString(chars[m[1][i:i+4]])
+		}
+
+		texts = append(texts, sb.String())
+	}
+
+	return texts
+}
+
+func testStatement(transactions int) domain.Statement {
+	acc := domain.Account{ID: 2, Title: "Main card", Currency: "KZT", Type: domain.Card}
+	savings := domain.Account{ID: 3, Title: "Savings", Currency: "KZT", Type: domain.Deposit}
+	food := "food"
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	st := domain.Statement{
+		Account:    acc,
+		BalanceIn:  domain.Balance{Date: from, Value: 1000},
+		BalanceOut: domain.Balance{Date: from.AddDate(0, 0, 30), Value: 900},
+	}
+
+	for i := 0; i < transactions; i++ {
+		tx := domain.Transaction{ID: int64(i), Amount: 10, Type: domain.Expense, Category: &food, CreatedAt: from,
+			Credit: &acc}
+
+		if i%2 == 1 {
+			tx = domain.Transaction{ID: int64(i), Amount: 10, Type: domain.Transfer, CreatedAt: from, Credit: &savings,
+				Debit: &acc}
+		}
+
+		st.Transactions = append(st.Transactions, domain.StatementTransaction{Transaction: tx, Balance: float64(1000 - i)})
+	}
+
+	return st
+}
+
+func TestStatementCSV(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, StatementCSV(&buf, testStatement(2)))
+	require.Equal(t, "Date,Type,Category,Counterparty,In,Out,Balance\n"+
+		"2022-01-01,expense,food,,0.00,10.00,1000.00\n"+
+		"2022-01-01,transfer,,Savings,10.00,0.00,999.00\n", buf.String())
+}
+
+func TestStatementXLSX(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, StatementXLSX(&buf, testStatement(2)))
+
+	_, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+
+	require.NoError(t, err)
+}
+
+func TestStatementPDF(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, StatementPDF(&buf, testStatement(120)))
+
+	out := buf.String()
+	texts := pdfText(t, out)
+
+	require.True(t, strings.HasPrefix(out, "%PDF-"))
+	require.Contains(t, texts, "Account statement")
+	require.Contains(t, texts, "Main card")
+
+	pages := strings.Count(out, "/Type /Page ")
+
+	require.Greater(t, pages, 1)
+	require.Contains(t, texts, fmt.Sprintf("Page %d of %d", pages, pages))
+}
+
+func TestStatementFilename(t *testing.T) {
+	require.Equal(t, "statement-2-2022-01-01-2022-01-31.pdf", StatementFilename(testStatement(0), PDF))
+}
@@ -7,6 +7,7 @@ import (
 	v1 "github.com/lotostudio/financial-api/internal/handler/v1"
 	"github.com/lotostudio/financial-api/internal/service"
 	"github.com/lotostudio/financial-api/pkg/auth"
+	"github.com/lotostudio/financial-api/pkg/limiter"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"github.com/swaggo/gin-swagger/swaggerFiles"
 	"net/http"
@@ -15,16 +16,18 @@ import (
 type Handler struct {
 	services     *service.Services
 	tokenManager auth.TokenManager
+	limits       limiter.Store
 }
 
-func NewHandler(services *service.Services, tokenManager auth.TokenManager) *Handler {
+func NewHandler(services *service.Services, tokenManager auth.TokenManager, limits limiter.Store) *Handler {
 	return &Handler{
 		services:     services,
 		tokenManager: tokenManager,
+		limits:       limits,
 	}
 }
 
-func (h *Handler) Init(_ *config.Config) *gin.Engine {
+func (h *Handler) Init(cfg *config.Config) *gin.Engine {
 	// Init gin handler
 	router := gin.Default()
 
@@ -35,17 +38,22 @@ func (h *Handler) Init(_ *config.Config) *gin.Engine {
 		c.String(http.StatusOK, "pong")
 	})
 
+	// Public keys for verifying access tokens
+	router.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, h.tokenManager.JWKS())
+	})
+
 	// Enable CORS
 	router.Use(cors.Default())
 
 	// Init router
-	h.initAPI(router)
+	h.initAPI(router, cfg)
 
 	return router
 }
 
-func (h *Handler) initAPI(router *gin.Engine) {
-	handlerV1 := v1.NewHandler(h.services, h.tokenManager)
+func (h *Handler) initAPI(router *gin.Engine, cfg *config.Config) {
+	handlerV1 := v1.NewHandler(h.services, h.tokenManager, h.limits, cfg.RateLimit, cfg.Attachments.MaxSize)
 
 	api := router.Group("/api")
 	{
@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"encoding/json"
 	"github.com/lotostudio/financial-api/internal/config"
 	"github.com/lotostudio/financial-api/internal/service"
 	"github.com/lotostudio/financial-api/pkg/auth"
+	"github.com/lotostudio/financial-api/pkg/limiter"
 	"github.com/stretchr/testify/require"
 	"net/http"
 	"net/http/httptest"
@@ -14,7 +16,7 @@ import (
 func TestNewHandler(t *testing.T) {
 	tokenManager, _ := auth.NewJWTManager("key", 5*time.Second, 32)
 
-	h := NewHandler(&service.Services{}, tokenManager)
+	h := NewHandler(&service.Services{}, tokenManager, limiter.NewMemoryStore())
 
 	require.IsType(t, &Handler{}, h)
 }
@@ -22,7 +24,7 @@ func TestNewHandler(t *testing.T) {
 func TestNewHandler_Init(t *testing.T) {
 	tokenManager, _ := auth.NewJWTManager("key", 5*time.Second, 32)
 
-	h := NewHandler(&service.Services{}, tokenManager)
+	h := NewHandler(&service.Services{}, tokenManager, limiter.NewMemoryStore())
 
 	router := h.Init(&config.Config{})
 
@@ -37,3 +39,26 @@ func TestNewHandler_Init(t *testing.T) {
 
 	require.Equal(t, http.StatusOK, res.StatusCode)
 }
+
+func TestNewHandler_jwks(t *testing.T) {
+	tokenManager, _ := auth.NewJWTManager("key", 5*time.Second, 32)
+
+	h := NewHandler(&service.Services{}, tokenManager, limiter.NewMemoryStore())
+
+	router := h.Init(&config.Config{})
+
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/.well-known/jwks.json")
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	var jwks auth.JWKSet
+
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&jwks))
+	require.Empty(t, jwks.Keys)
+}
@@ -11,7 +11,7 @@ import (
 )
 
 func (h *Handler) initAccountsRoutes(api *gin.RouterGroup) {
-	accounts := api.Group("/accounts", h.userIdentity)
+	accounts := api.Group("/accounts", h.userIdentity, h.limitUser)
 	{
 		accounts.GET("", h.listAccounts)
 		accounts.GET("/grouped", h.listGropedAccounts)
@@ -32,6 +32,17 @@ func (h *Handler) initAccountsRoutes(api *gin.RouterGroup) {
 			{
 				transactions.GET("", h.listTransactionsOfAccount)
 			}
+
+			reconciliations := account.Group("/reconciliations")
+			{
+				reconciliations.GET("", h.listReconciliations)
+				reconciliations.POST("", h.createReconciliation)
+				reconciliations.GET("/:reconciliationId", h.getReconciliation)
+				reconciliations.DELETE("/:reconciliationId", h.deleteReconciliation)
+				reconciliations.POST("/:reconciliationId/complete", h.completeReconciliation)
+				reconciliations.PUT("/:reconciliationId/transactions/:transactionId", h.tickReconciliationTransaction)
+				reconciliations.DELETE("/:reconciliationId/transactions/:transactionId", h.untickReconciliationTransaction)
+			}
 		}
 	}
 
@@ -334,7 +345,7 @@ func (h *Handler) updateAccount(c *gin.Context) {
 
 // @Summary Delete account
 // @Tags accounts
-// @Description Delete account of user
+// @Description Move account of user to trash with its transactions. It can be restored until it is purged
 // @ID deleteAccount
 // @Security UsersAuth
 // @Accept json
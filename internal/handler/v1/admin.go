@@ -0,0 +1,186 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handler) initAdminRoutes(api *gin.RouterGroup) {
+	// Support users have read-only access to admin routes
+	admin := api.Group("/admin", h.userIdentity, h.limitUser, h.userRole(domain.RoleAdmin, domain.RoleSupport))
+	{
+		admin.GET("/stats", h.systemStats)
+
+		users := admin.Group("/users")
+		{
+			users.GET("", h.listUsers)
+
+			user := users.Group("/:id", h.userRole(domain.RoleAdmin))
+			{
+				user.POST("/disable", h.disableUser)
+				user.POST("/enable", h.enableUser)
+				user.DELETE("/sessions", h.resetUserSessions)
+			}
+		}
+	}
+}
+
+// @Summary List users
+// @Tags admin
+// @Description List all users
+// @ID listUsers
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Success 200 {array} domain.User "Operation finished successfully"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /admin/users [get]
+func (h *Handler) listUsers(c *gin.Context) {
+	users, err := h.s.Users.List(c.Request.Context())
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, users)
+}
+
+// @Summary Disable user
+// @Tags admin
+// @Description Disable user and reset his session
+// @ID disableUser
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Id of user"
+// @Success 200 {object} domain.User "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /admin/users/{id}/disable [post]
+func (h *Handler) disableUser(c *gin.Context) {
+	h.setUserDisabled(c, true)
+}
+
+// @Summary Enable user
+// @Tags admin
+// @Description Enable previously disabled user
+// @ID enableUser
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Id of user"
+// @Success 200 {object} domain.User "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /admin/users/{id}/enable [post]
+func (h *Handler) enableUser(c *gin.Context) {
+	h.setUserDisabled(c, false)
+}
+
+func (h *Handler) setUserDisabled(c *gin.Context, disabled bool) {
+	idString := c.Param("id")
+
+	if idString == "" {
+		newResponse(c, http.StatusBadRequest, "path param 'id' missing")
+		return
+	}
+
+	id, err := strconv.ParseInt(idString, 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'id' must be integer - "+err.Error())
+		return
+	}
+
+	user, err := h.s.Admin.SetDisabled(c.Request.Context(), id, disabled)
+
+	if errors.Is(err, repo.ErrUserNotFound) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// @Summary Reset user sessions
+// @Tags admin
+// @Description Invalidate refresh token of user
+// @ID resetUserSessions
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Id of user"
+// @Success 204 {null} nil "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /admin/users/{id}/sessions [delete]
+func (h *Handler) resetUserSessions(c *gin.Context) {
+	idString := c.Param("id")
+
+	if idString == "" {
+		newResponse(c, http.StatusBadRequest, "path param 'id' missing")
+		return
+	}
+
+	id, err := strconv.ParseInt(idString, 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'id' must be integer - "+err.Error())
+		return
+	}
+
+	err = h.s.Admin.ResetSessions(c.Request.Context(), id)
+
+	if errors.Is(err, repo.ErrUserNotFound) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary System stats
+// @Tags admin
+// @Description Overall statistics of system
+// @ID systemStats
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} domain.SystemStats "Operation finished successfully"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /admin/stats [get]
+func (h *Handler) systemStats(c *gin.Context) {
+	stats, err := h.s.Admin.Stats(c.Request.Context())
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
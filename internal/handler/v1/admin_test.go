@@ -0,0 +1,345 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"github.com/lotostudio/financial-api/internal/service"
+	mockService "github.com/lotostudio/financial-api/internal/service/mocks"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_listUsers(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockUsers)
+
+	users := []domain.User{
+		{
+			ID:        int64(1),
+			FirstName: "Sirius",
+			LastName:  "Sam",
+			Email:     "qweqweqwe@gmail.com",
+			Password:  "qweqweqwe",
+		},
+	}
+
+	setResponseBody := func(users []domain.User) string {
+		body, _ := json.Marshal(users)
+
+		return string(body)
+	}
+
+	tests := []struct {
+		name                 string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name: "ok",
+			mockBehaviour: func(s *mockService.MockUsers) {
+				s.EXPECT().List(context.Background()).Return(users, nil)
+			},
+			expectedCodeStatus:   200,
+			expectedResponseBody: setResponseBody(users),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			userService := mockService.NewMockUsers(c)
+			tt.mockBehaviour(userService)
+
+			services := &service.Services{Users: userService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/admin/users", handler.listUsers)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/admin/users", bytes.NewBufferString(""))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_disableUser(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockAdmin)
+
+	tests := []struct {
+		name                 string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name: "ok",
+			mockBehaviour: func(s *mockService.MockAdmin) {
+				s.EXPECT().SetDisabled(context.Background(), userID, true).Return(domain.User{
+					ID:       userID,
+					Role:     domain.RoleUser,
+					Disabled: true,
+				}, nil)
+			},
+			expectedCodeStatus:   200,
+			expectedResponseBody: `{"id":1,"email":"","firstName":"","lastName":"","role":"user","disabled":true}`,
+		},
+		{
+			name: "user not found",
+			mockBehaviour: func(s *mockService.MockAdmin) {
+				s.EXPECT().SetDisabled(context.Background(), userID, true).Return(domain.User{}, repo.ErrUserNotFound)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"user doesn't exists"}`,
+		},
+		{
+			name: "error",
+			mockBehaviour: func(s *mockService.MockAdmin) {
+				s.EXPECT().SetDisabled(context.Background(), userID, true).Return(domain.User{}, errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			aService := mockService.NewMockAdmin(c)
+			tt.mockBehaviour(aService)
+
+			services := &service.Services{Admin: aService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/admin/users/:id/disable", handler.disableUser)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", fmt.Sprintf("/admin/users/%d/disable", userID),
+				bytes.NewBufferString(""))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_enableUser(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockAdmin)
+
+	tests := []struct {
+		name                 string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name: "ok",
+			mockBehaviour: func(s *mockService.MockAdmin) {
+				s.EXPECT().SetDisabled(context.Background(), userID, false).Return(domain.User{
+					ID:   userID,
+					Role: domain.RoleUser,
+				}, nil)
+			},
+			expectedCodeStatus:   200,
+			expectedResponseBody: `{"id":1,"email":"","firstName":"","lastName":"","role":"user","disabled":false}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			aService := mockService.NewMockAdmin(c)
+			tt.mockBehaviour(aService)
+
+			services := &service.Services{Admin: aService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/admin/users/:id/enable", handler.enableUser)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", fmt.Sprintf("/admin/users/%d/enable", userID),
+				bytes.NewBufferString(""))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_resetUserSessions(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockAdmin)
+
+	tests := []struct {
+		name                 string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name: "ok",
+			mockBehaviour: func(s *mockService.MockAdmin) {
+				s.EXPECT().ResetSessions(context.Background(), userID).Return(nil)
+			},
+			expectedCodeStatus:   204,
+			expectedResponseBody: "",
+		},
+		{
+			name: "user not found",
+			mockBehaviour: func(s *mockService.MockAdmin) {
+				s.EXPECT().ResetSessions(context.Background(), userID).Return(repo.ErrUserNotFound)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"user doesn't exists"}`,
+		},
+		{
+			name: "error",
+			mockBehaviour: func(s *mockService.MockAdmin) {
+				s.EXPECT().ResetSessions(context.Background(), userID).Return(errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			aService := mockService.NewMockAdmin(c)
+			tt.mockBehaviour(aService)
+
+			services := &service.Services{Admin: aService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.DELETE("/admin/users/:id/sessions", handler.resetUserSessions)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", fmt.Sprintf("/admin/users/%d/sessions", userID),
+				bytes.NewBufferString(""))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_systemStats(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockAdmin)
+
+	tests := []struct {
+		name                 string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name: "ok",
+			mockBehaviour: func(s *mockService.MockAdmin) {
+				s.EXPECT().Stats(context.Background()).Return(domain.SystemStats{
+					Users:          10,
+					DisabledUsers:  1,
+					ActiveSessions: 4,
+					Accounts:       20,
+					Transactions:   300,
+				}, nil)
+			},
+			expectedCodeStatus:   200,
+			expectedResponseBody: `{"users":10,"disabledUsers":1,"activeSessions":4,"accounts":20,"transactions":300}`,
+		},
+		{
+			name: "error",
+			mockBehaviour: func(s *mockService.MockAdmin) {
+				s.EXPECT().Stats(context.Background()).Return(domain.SystemStats{}, errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			aService := mockService.NewMockAdmin(c)
+			tt.mockBehaviour(aService)
+
+			services := &service.Services{Admin: aService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/admin/stats", handler.systemStats)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/admin/stats", bytes.NewBufferString(""))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,323 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"github.com/lotostudio/financial-api/internal/service"
+	"github.com/lotostudio/financial-api/pkg/thumbnail"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+// Multipart request is larger than uploaded file by boundaries and headers of parts
+const multipartOverhead = 64 << 10
+
+// @Summary List attachments
+// @Tags attachments
+// @Description List files attached to transaction
+// @ID listAttachments
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Id of transaction"
+// @Success 200 {array} domain.Attachment "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /transactions/{id}/attachments [get]
+func (h *Handler) listAttachments(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'id' must be integer - "+err.Error())
+		return
+	}
+
+	attachments, err := h.s.Attachments.List(c.Request.Context(), id, userId)
+
+	if errors.Is(err, repo.ErrTransactionNotFound) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, service.ErrTransactionForbidden) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, attachments)
+}
+
+// @Summary Upload attachment
+// @Tags attachments
+// @Description Attach receipt photo or document to transaction. Type of file is detected by its content,
+// @Description thumbnail is created for images
+// @ID uploadAttachment
+// @Security UsersAuth
+// @Accept mpfd
+// @Produce json
+// @Param id path int64 true "Id of transaction"
+// @Param file formData file true "File to attach"
+// @Success 201 {object} domain.Attachment "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 413 {object} response "File is too large"
+// @Failure 415 {object} response "Type of file is not allowed"
+// @Failure 500 {object} response "Server error"
+// @Router /transactions/{id}/attachments [post]
+func (h *Handler) uploadAttachment(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'id' must be integer - "+err.Error())
+		return
+	}
+
+	if h.maxUploadSize > 0 {
+		limit := h.maxUploadSize + multipartOverhead
+
+		if c.Request.ContentLength > limit {
+			newResponse(c, http.StatusRequestEntityTooLarge, errUploadTooLarge.Error())
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+	}
+
+	fileHeader, err := c.FormFile("file")
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "form file 'file' is invalid - "+err.Error())
+		return
+	}
+
+	file, err := fileHeader.Open()
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	attachment, err := h.s.Attachments.Upload(c.Request.Context(), id, userId, fileHeader.Filename, file)
+
+	if errors.Is(err, repo.ErrTransactionNotFound) || errors.Is(err, service.ErrAttachmentEmpty) ||
+		errors.Is(err, service.ErrAttachmentImageInvalid) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, service.ErrTransactionForbidden) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if errors.Is(err, service.ErrAttachmentTooLarge) {
+		newResponse(c, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	}
+
+	if errors.Is(err, service.ErrAttachmentTypeNotAllowed) {
+		newResponse(c, http.StatusUnsupportedMediaType, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// @Summary Download attachment
+// @Tags attachments
+// @Description Download file attached to transaction or JPEG thumbnail of image
+// @ID downloadAttachment
+// @Security UsersAuth
+// @Produce octet-stream
+// @Param id path int64 true "Id of transaction"
+// @Param attachmentId path int64 true "Id of attachment"
+// @Param thumbnail query bool false "Download thumbnail instead of file"
+// @Success 200 {file} binary "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /transactions/{id}/attachments/{attachmentId} [get]
+func (h *Handler) downloadAttachment(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'id' must be integer - "+err.Error())
+		return
+	}
+
+	attachmentId, err := strconv.ParseInt(c.Param("attachmentId"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'attachmentId' must be integer - "+err.Error())
+		return
+	}
+
+	var thumb bool
+
+	if thumbString := c.Query("thumbnail"); thumbString != "" {
+		if thumb, err = strconv.ParseBool(thumbString); err != nil {
+			newResponse(c, http.StatusBadRequest, errThumbnailInvalid.Error())
+			return
+		}
+	}
+
+	attachment, r, err := h.s.Attachments.Open(c.Request.Context(), id, attachmentId, userId, thumb)
+
+	if errors.Is(err, repo.ErrTransactionNotFound) || errors.Is(err, repo.ErrAttachmentNotFound) ||
+		errors.Is(err, service.ErrThumbnailNotFound) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, service.ErrTransactionForbidden) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	size, contentType := attachment.Size, attachment.ContentType
+
+	// Size of thumbnail is not stored
+	if thumb {
+		size, contentType = -1, thumbnail.ContentType
+	}
+
+	c.DataFromReader(http.StatusOK, size, contentType, r, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// @Summary Delete attachment
+// @Tags attachments
+// @Description Delete file attached to transaction
+// @ID deleteAttachment
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Id of transaction"
+// @Param attachmentId path int64 true "Id of attachment"
+// @Success 204 {null} nil "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /transactions/{id}/attachments/{attachmentId} [delete]
+func (h *Handler) deleteAttachment(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'id' must be integer - "+err.Error())
+		return
+	}
+
+	attachmentId, err := strconv.ParseInt(c.Param("attachmentId"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'attachmentId' must be integer - "+err.Error())
+		return
+	}
+
+	err = h.s.Attachments.Delete(c.Request.Context(), id, attachmentId, userId)
+
+	if errors.Is(err, repo.ErrTransactionNotFound) || errors.Is(err, repo.ErrAttachmentNotFound) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, service.ErrTransactionForbidden) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
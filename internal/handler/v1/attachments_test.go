@@ -0,0 +1,486 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"github.com/lotostudio/financial-api/internal/service"
+	mockService "github.com/lotostudio/financial-api/internal/service/mocks"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	attachmentID = int64(7)
+)
+
+var testAttachment = domain.Attachment{
+	ID:            attachmentID,
+	TransactionID: transactionID,
+	Filename:      "чек.png",
+	ContentType:   "image/png",
+	Size:          4,
+	HasThumbnail:  true,
+	CreatedAt:     time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC),
+}
+
+func TestHandler_listAttachments(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockAttachments)
+
+	tests := []struct {
+		name                 string
+		id                   string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name: "ok",
+			id:   strconv.FormatInt(transactionID, 10),
+			mockBehaviour: func(s *mockService.MockAttachments) {
+				s.EXPECT().List(context.Background(), transactionID, userID).
+					Return([]domain.Attachment{testAttachment}, nil)
+			},
+			expectedCodeStatus: 200,
+			expectedResponseBody: `[{"id":7,"transactionId":5,"filename":"чек.png","contentType":"image/png","size":4,` +
+				`"hasThumbnail":true,"createdAt":"2022-03-01T12:00:00Z"}]`,
+		},
+		{
+			name:                 "invalid id",
+			id:                   "a",
+			mockBehaviour:        func(s *mockService.MockAttachments) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"path param 'id' must be integer - strconv.ParseInt: parsing \"a\": invalid syntax"}`,
+		},
+		{
+			name: "forbidden",
+			id:   strconv.FormatInt(transactionID, 10),
+			mockBehaviour: func(s *mockService.MockAttachments) {
+				s.EXPECT().List(context.Background(), transactionID, userID).Return(nil, service.ErrTransactionForbidden)
+			},
+			expectedCodeStatus:   403,
+			expectedResponseBody: `{"message":"transaction forbidden to access"}`,
+		},
+		{
+			name: "error",
+			id:   strconv.FormatInt(transactionID, 10),
+			mockBehaviour: func(s *mockService.MockAttachments) {
+				s.EXPECT().List(context.Background(), transactionID, userID).Return(nil, errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			aService := mockService.NewMockAttachments(c)
+			tt.mockBehaviour(aService)
+
+			services := &service.Services{Attachments: aService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/transactions/:id/attachments", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.listAttachments)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/transactions/"+tt.id+"/attachments", nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_uploadAttachment(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockAttachments)
+
+	multipartBody := func(field, filename, content string) (*bytes.Buffer, string) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, _ := writer.CreateFormFile(field, filename)
+		_, _ = part.Write([]byte(content))
+		_ = writer.Close()
+
+		return body, writer.FormDataContentType()
+	}
+
+	expectUpload := func(s *mockService.MockAttachments, err error) {
+		s.EXPECT().Upload(context.Background(), transactionID, userID, "чек.png", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _, _ int64, _ string, file io.Reader) (domain.Attachment, error) {
+				content, _ := io.ReadAll(file)
+
+				if string(content) != "data" {
+					return domain.Attachment{}, fmt.Errorf("unexpected content %q", content)
+				}
+
+				return testAttachment, err
+			})
+	}
+
+	tests := []struct {
+		name                 string
+		field                string
+		content              string
+		maxUploadSize        int64
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name:    "ok",
+			field:   "file",
+			content: "data",
+			mockBehaviour: func(s *mockService.MockAttachments) {
+				expectUpload(s, nil)
+			},
+			expectedCodeStatus: 201,
+			expectedResponseBody: `{"id":7,"transactionId":5,"filename":"чек.png","contentType":"image/png","size":4,` +
+				`"hasThumbnail":true,"createdAt":"2022-03-01T12:00:00Z"}`,
+		},
+		{
+			name:                 "missing file",
+			field:                "document",
+			content:              "data",
+			mockBehaviour:        func(s *mockService.MockAttachments) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"form file 'file' is invalid - http: no such file"}`,
+		},
+		{
+			name:                 "body too large",
+			field:                "file",
+			content:              strings.Repeat("a", multipartOverhead+20),
+			maxUploadSize:        10,
+			mockBehaviour:        func(s *mockService.MockAttachments) {},
+			expectedCodeStatus:   413,
+			expectedResponseBody: `{"message":"request body is too large"}`,
+		},
+		{
+			name:    "file too large",
+			field:   "file",
+			content: "data",
+			mockBehaviour: func(s *mockService.MockAttachments) {
+				expectUpload(s, service.ErrAttachmentTooLarge)
+			},
+			expectedCodeStatus:   413,
+			expectedResponseBody: `{"message":"attachment is too large"}`,
+		},
+		{
+			name:    "type not allowed",
+			field:   "file",
+			content: "data",
+			mockBehaviour: func(s *mockService.MockAttachments) {
+				expectUpload(s, service.ErrAttachmentTypeNotAllowed)
+			},
+			expectedCodeStatus:   415,
+			expectedResponseBody: `{"message":"type of attachment is not allowed"}`,
+		},
+		{
+			name:    "broken image",
+			field:   "file",
+			content: "data",
+			mockBehaviour: func(s *mockService.MockAttachments) {
+				expectUpload(s, service.ErrAttachmentImageInvalid)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"image of attachment can't be decoded"}`,
+		},
+		{
+			name:    "forbidden",
+			field:   "file",
+			content: "data",
+			mockBehaviour: func(s *mockService.MockAttachments) {
+				expectUpload(s, service.ErrTransactionForbidden)
+			},
+			expectedCodeStatus:   403,
+			expectedResponseBody: `{"message":"transaction forbidden to access"}`,
+		},
+		{
+			name:    "error",
+			field:   "file",
+			content: "data",
+			mockBehaviour: func(s *mockService.MockAttachments) {
+				expectUpload(s, errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			aService := mockService.NewMockAttachments(c)
+			tt.mockBehaviour(aService)
+
+			services := &service.Services{Attachments: aService}
+			handler := &Handler{
+				s:             services,
+				maxUploadSize: tt.maxUploadSize,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/transactions/:id/attachments", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.uploadAttachment)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			body, contentType := multipartBody(tt.field, "чек.png", tt.content)
+			req := httptest.NewRequest("POST", fmt.Sprintf("/transactions/%d/attachments", transactionID), body)
+			req.Header.Set("Content-Type", contentType)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_downloadAttachment(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockAttachments)
+
+	tests := []struct {
+		name                 string
+		query                string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedContentType  string
+		expectedDisposition  string
+		expectedResponseBody string
+	}{
+		{
+			name: "ok",
+			mockBehaviour: func(s *mockService.MockAttachments) {
+				s.EXPECT().Open(context.Background(), transactionID, attachmentID, userID, false).
+					Return(testAttachment, io.NopCloser(strings.NewReader("data")), nil)
+			},
+			expectedCodeStatus:   200,
+			expectedContentType:  "image/png",
+			expectedDisposition:  "attachment; filename*=utf-8''%D1%87%D0%B5%D0%BA.png",
+			expectedResponseBody: "data",
+		},
+		{
+			name:  "thumbnail",
+			query: "?thumbnail=true",
+			mockBehaviour: func(s *mockService.MockAttachments) {
+				s.EXPECT().Open(context.Background(), transactionID, attachmentID, userID, true).
+					Return(testAttachment, io.NopCloser(strings.NewReader("preview")), nil)
+			},
+			expectedCodeStatus:   200,
+			expectedContentType:  "image/jpeg",
+			expectedDisposition:  "attachment; filename*=utf-8''%D1%87%D0%B5%D0%BA.png",
+			expectedResponseBody: "preview",
+		},
+		{
+			name:                 "invalid thumbnail",
+			query:                "?thumbnail=yes",
+			mockBehaviour:        func(s *mockService.MockAttachments) {},
+			expectedCodeStatus:   400,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"message":"query param 'thumbnail' must be boolean"}`,
+		},
+		{
+			name:  "no thumbnail",
+			query: "?thumbnail=true",
+			mockBehaviour: func(s *mockService.MockAttachments) {
+				s.EXPECT().Open(context.Background(), transactionID, attachmentID, userID, true).
+					Return(domain.Attachment{}, nil, service.ErrThumbnailNotFound)
+			},
+			expectedCodeStatus:   400,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"message":"attachment doesn't have thumbnail"}`,
+		},
+		{
+			name: "not found",
+			mockBehaviour: func(s *mockService.MockAttachments) {
+				s.EXPECT().Open(context.Background(), transactionID, attachmentID, userID, false).
+					Return(domain.Attachment{}, nil, repo.ErrAttachmentNotFound)
+			},
+			expectedCodeStatus:   400,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"message":"attachment doesn't exists"}`,
+		},
+		{
+			name: "forbidden",
+			mockBehaviour: func(s *mockService.MockAttachments) {
+				s.EXPECT().Open(context.Background(), transactionID, attachmentID, userID, false).
+					Return(domain.Attachment{}, nil, service.ErrTransactionForbidden)
+			},
+			expectedCodeStatus:   403,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"message":"transaction forbidden to access"}`,
+		},
+		{
+			name: "error",
+			mockBehaviour: func(s *mockService.MockAttachments) {
+				s.EXPECT().Open(context.Background(), transactionID, attachmentID, userID, false).
+					Return(domain.Attachment{}, nil, errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			aService := mockService.NewMockAttachments(c)
+			tt.mockBehaviour(aService)
+
+			services := &service.Services{Attachments: aService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/transactions/:id/attachments/:attachmentId", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.downloadAttachment)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", fmt.Sprintf("/transactions/%d/attachments/%d%s", transactionID,
+				attachmentID, tt.query), nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expectedDisposition, w.Header().Get("Content-Disposition"))
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_deleteAttachment(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockAttachments)
+
+	tests := []struct {
+		name                 string
+		attachmentId         string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name:         "ok",
+			attachmentId: strconv.FormatInt(attachmentID, 10),
+			mockBehaviour: func(s *mockService.MockAttachments) {
+				s.EXPECT().Delete(context.Background(), transactionID, attachmentID, userID).Return(nil)
+			},
+			expectedCodeStatus:   204,
+			expectedResponseBody: "",
+		},
+		{
+			name:                 "invalid attachment id",
+			attachmentId:         "a",
+			mockBehaviour:        func(s *mockService.MockAttachments) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"path param 'attachmentId' must be integer - strconv.ParseInt: parsing \"a\": invalid syntax"}`,
+		},
+		{
+			name:         "not found",
+			attachmentId: strconv.FormatInt(attachmentID, 10),
+			mockBehaviour: func(s *mockService.MockAttachments) {
+				s.EXPECT().Delete(context.Background(), transactionID, attachmentID, userID).
+					Return(repo.ErrAttachmentNotFound)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"attachment doesn't exists"}`,
+		},
+		{
+			name:         "forbidden",
+			attachmentId: strconv.FormatInt(attachmentID, 10),
+			mockBehaviour: func(s *mockService.MockAttachments) {
+				s.EXPECT().Delete(context.Background(), transactionID, attachmentID, userID).
+					Return(service.ErrTransactionForbidden)
+			},
+			expectedCodeStatus:   403,
+			expectedResponseBody: `{"message":"transaction forbidden to access"}`,
+		},
+		{
+			name:         "error",
+			attachmentId: strconv.FormatInt(attachmentID, 10),
+			mockBehaviour: func(s *mockService.MockAttachments) {
+				s.EXPECT().Delete(context.Background(), transactionID, attachmentID, userID).
+					Return(errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			aService := mockService.NewMockAttachments(c)
+			tt.mockBehaviour(aService)
+
+			services := &service.Services{Attachments: aService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.DELETE("/transactions/:id/attachments/:attachmentId", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.deleteAttachment)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", fmt.Sprintf("/transactions/%d/attachments/%s", transactionID,
+				tt.attachmentId), nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/service"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// @Summary List audit log
+// @Tags users
+// @Description List changes of accounts, transactions, access tokens and auth events of authorized user, latest
+// @Description first. Entries are paginated by limit and offset
+// @ID listAudit
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param entity query string false "Changed entity" Enums(account, transaction, user, access_token)
+// @Param entityId query int64 false "ID of changed entity"
+// @Param action query string false "Action made to entity"
+// @Param dateFrom query string false "Date from (including)" Format(yyyy-MM-dd)
+// @Param dateTo query string false "Date to (including)" Format(yyyy-MM-dd)
+// @Param limit query int false "Count of entries, from 1 to 100" default(50)
+// @Param offset query int false "Count of entries to skip" default(0)
+// @Success 200 {array} domain.AuditEntry "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 500 {object} response "Server error"
+// @Router /users/me/audit [get]
+func (h *Handler) listAudit(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	filter := domain.AuditFilter{UserID: userId}
+
+	if entity := domain.AuditEntity(c.Query("entity")); entity != "" {
+		filter.Entity = &entity
+	}
+
+	if entityIdString := c.Query("entityId"); entityIdString != "" {
+		entityId, err := strconv.ParseInt(entityIdString, 10, 64)
+
+		if err != nil {
+			newResponse(c, http.StatusBadRequest, "query param 'entityId' must be integer - "+err.Error())
+			return
+		}
+
+		filter.EntityID = &entityId
+	}
+
+	if action := domain.AuditAction(c.Query("action")); action != "" {
+		filter.Action = &action
+	}
+
+	if dateFromString := c.Query("dateFrom"); dateFromString != "" {
+		dateFrom, err := time.Parse(layout, dateFromString)
+
+		if err != nil {
+			newResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		filter.From = &dateFrom
+	}
+
+	if dateToString := c.Query("dateTo"); dateToString != "" {
+		dateTo, err := time.Parse(layout, dateToString)
+
+		if err != nil {
+			newResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// Whole last day is included
+		dateTo = dateTo.AddDate(0, 0, 1)
+		filter.To = &dateTo
+	}
+
+	if filter.Limit, err = strconv.Atoi(c.DefaultQuery("limit", "50")); err != nil {
+		newResponse(c, http.StatusBadRequest, "query param 'limit' must be integer - "+err.Error())
+		return
+	}
+
+	if filter.Offset, err = strconv.Atoi(c.DefaultQuery("offset", "0")); err != nil {
+		newResponse(c, http.StatusBadRequest, "query param 'offset' must be integer - "+err.Error())
+		return
+	}
+
+	entries, err := h.s.Audit.List(c.Request.Context(), filter)
+
+	if errors.Is(err, domain.ErrInvalidAuditEntity) || errors.Is(err, service.ErrAuditLimitInvalid) ||
+		errors.Is(err, service.ErrAuditOffsetInvalid) || errors.Is(err, service.ErrAuditPeriodInvalid) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
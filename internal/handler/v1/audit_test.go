@@ -0,0 +1,131 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/service"
+	mockService "github.com/lotostudio/financial-api/internal/service/mocks"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestHandler_listAudit(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockAudit)
+
+	createdAt := time.Date(2022, 4, 2, 10, 0, 0, 0, time.UTC)
+	from := time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 4, 3, 0, 0, 0, 0, time.UTC)
+	entity := domain.AuditAccount
+	action := domain.AuditUpdate
+	entityId := accountID
+	actorId := userID
+
+	tests := []struct {
+		name                 string
+		query                string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name: "ok",
+			mockBehaviour: func(s *mockService.MockAudit) {
+				s.EXPECT().List(context.Background(), domain.AuditFilter{UserID: userID, Limit: 50}).
+					Return([]domain.AuditEntry{{
+						ID: 1, UserID: userID, ActorID: &actorId, RequestID: "req", IP: "127.0.0.1",
+						Entity: domain.AuditAccount, EntityID: accountID, Action: domain.AuditUpdate,
+						Before: json.RawMessage(`{"title":"Cash"}`), After: json.RawMessage(`{"title":"Wallet"}`),
+						CreatedAt: createdAt,
+					}}, nil)
+			},
+			expectedCodeStatus: 200,
+			expectedResponseBody: `[{"id":1,"actorId":1,"requestId":"req","ip":"127.0.0.1","entity":"account",` +
+				`"entityId":2,"action":"update","before":{"title":"Cash"},"after":{"title":"Wallet"},` +
+				`"createdAt":"2022-04-02T10:00:00Z"}]`,
+		},
+		{
+			name:  "filter",
+			query: "?entity=account&entityId=2&action=update&dateFrom=2022-04-01&dateTo=2022-04-02&limit=10&offset=20",
+			mockBehaviour: func(s *mockService.MockAudit) {
+				s.EXPECT().List(context.Background(), domain.AuditFilter{UserID: userID, Entity: &entity,
+					EntityID: &entityId, Action: &action, From: &from, To: &to, Limit: 10, Offset: 20}).
+					Return([]domain.AuditEntry{}, nil)
+			},
+			expectedCodeStatus:   200,
+			expectedResponseBody: `[]`,
+		},
+		{
+			name:                 "invalid entity id",
+			query:                "?entityId=a",
+			mockBehaviour:        func(s *mockService.MockAudit) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"query param 'entityId' must be integer - strconv.ParseInt: parsing \"a\": invalid syntax"}`,
+		},
+		{
+			name:                 "invalid offset",
+			query:                "?offset=a",
+			mockBehaviour:        func(s *mockService.MockAudit) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"query param 'offset' must be integer - strconv.Atoi: parsing \"a\": invalid syntax"}`,
+		},
+		{
+			name:  "invalid limit",
+			query: "?limit=500",
+			mockBehaviour: func(s *mockService.MockAudit) {
+				s.EXPECT().List(context.Background(), domain.AuditFilter{UserID: userID, Limit: 500}).
+					Return(nil, service.ErrAuditLimitInvalid)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"limit of audit entries must be from 1 to 100"}`,
+		},
+		{
+			name: "error",
+			mockBehaviour: func(s *mockService.MockAudit) {
+				s.EXPECT().List(context.Background(), domain.AuditFilter{UserID: userID, Limit: 50}).
+					Return(nil, errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auService := mockService.NewMockAudit(c)
+			tt.mockBehaviour(auService)
+
+			services := &service.Services{Audit: auService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/users/me/audit", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.listAudit)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/users/me/audit"+tt.query, nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
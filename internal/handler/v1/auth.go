@@ -8,14 +8,22 @@ import (
 	"github.com/lotostudio/financial-api/internal/service"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 func (h *Handler) initAuthRoutes(api *gin.RouterGroup) {
 	auth := api.Group("/auth")
 	{
 		auth.POST("/register", h.register)
-		auth.POST("/login", h.login)
+		auth.POST("/login", h.limitLoginIP, h.login)
 		auth.POST("/refresh", h.refresh)
+
+		oidc := auth.Group("/oidc")
+		{
+			oidc.GET("", h.listOIDCProviders)
+			oidc.GET("/:provider/login", h.limitLoginIP, h.oidcLogin)
+			oidc.GET("/:provider/callback", h.limitLoginIP, h.oidcCallback)
+		}
 	}
 }
 
@@ -62,9 +70,12 @@ func (h *Handler) register(c *gin.Context) {
 // @Param input body domain.UserToLogin true "Login credentials"
 // @Success 200 {object} domain.Tokens "Operation finished successfully"
 // @Failure 400 {object} response "Invalid request"
+// @Failure 403 {object} response "User disabled"
+// @Failure 429 {object} response "Too many login attempts"
 // @Failure 500 {object} response "Server error"
 // @Header 200 {int} Access-Token-TTL "Time to live of access token in seconds"
 // @Header 200 {int} Refresh-Token-TTL "Time to live of refresh token in seconds"
+// @Header 429 {int} Retry-After "Seconds to wait before the next attempt"
 // @Router /auth/login [post]
 func (h *Handler) login(c *gin.Context) {
 	var toLogin domain.UserToLogin
@@ -74,13 +85,29 @@ func (h *Handler) login(c *gin.Context) {
 		return
 	}
 
+	if !h.allow(c, "login-email:"+strings.ToLower(toLogin.Email), h.loginEmailLimit) {
+		return
+	}
+
 	tokens, err := h.s.Login(c.Request.Context(), toLogin)
 
+	var lockedErr *service.LoginLockedError
+
+	if errors.As(err, &lockedErr) {
+		tooManyRequests(c, err.Error(), lockedErr.RetryAfter)
+		return
+	}
+
 	if errors.Is(err, repo.ErrUserNotFound) {
 		newResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	if errors.Is(err, service.ErrUserDisabled) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
 	if err != nil {
 		newResponse(c, http.StatusInternalServerError, err.Error())
 		return
@@ -101,6 +128,7 @@ func (h *Handler) login(c *gin.Context) {
 // @Success 200 {object} domain.Tokens "Operation finished successfully"
 // @Failure 400 {object} response "Invalid request"
 // @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "User disabled"
 // @Failure 500 {object} response "Server error"
 // @Header 200 {int} Access-Token-TTL "Time to live of access token in seconds"
 // @Header 200 {int} Refresh-Token-TTL "Time to live of refresh token in seconds"
@@ -120,6 +148,11 @@ func (h *Handler) refresh(c *gin.Context) {
 		return
 	}
 
+	if errors.Is(err, service.ErrUserDisabled) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
 	if err != nil {
 		newResponse(c, http.StatusInternalServerError, err.Error())
 		return
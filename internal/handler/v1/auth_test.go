@@ -13,6 +13,7 @@ import (
 	mockService "github.com/lotostudio/financial-api/internal/service/mocks"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestHandler_register(t *testing.T) {
@@ -42,10 +43,11 @@ func TestHandler_register(t *testing.T) {
 					FirstName: "Sirius",
 					LastName:  "Sam",
 					Password:  "qweqweqwe",
+					Role:      domain.RoleUser,
 				}, nil)
 			},
 			statusCode:   201,
-			responseBody: `{"id":1,"email":"qweqweqwe@gmail.com","firstName":"Sirius","lastName":"Sam"}`,
+			responseBody: `{"id":1,"email":"qweqweqwe@gmail.com","firstName":"Sirius","lastName":"Sam","role":"user","disabled":false}`,
 		},
 		{
 			name:          "invalid request body",
@@ -165,6 +167,20 @@ func TestHandler_login(t *testing.T) {
 			statusCode:   400,
 			responseBody: `{"message":"user doesn't exists"}`,
 		},
+		{
+			name:        "locked",
+			requestBody: `{"email": "qweqweqwe@gmail.com", "password": "qweqweqwe"}`,
+			requestUser: domain.UserToLogin{
+				Email:    "qweqweqwe@gmail.com",
+				Password: "qweqweqwe",
+			},
+			mockBehaviour: func(s *mockService.MockAuth, user domain.UserToLogin) {
+				s.EXPECT().Login(context.Background(), user).Return(domain.Tokens{},
+					&service.LoginLockedError{RetryAfter: time.Minute})
+			},
+			statusCode:   429,
+			responseBody: `{"message":"too many failed login attempts"}`,
+		},
 		{
 			name:        "error",
 			requestBody: `{"email": "qweqweqwe@gmail.com", "password": "qweqweqwe"}`,
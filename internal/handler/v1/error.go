@@ -3,5 +3,15 @@ package v1
 import "errors"
 
 var (
-	errDateFiltersInvalid = errors.New("date filters are invalid. check 'dateFrom' and 'dateTo' params")
+	errDateFiltersInvalid  = errors.New("date filters are invalid. check 'dateFrom' and 'dateTo' params")
+	errAccessTokenReadOnly = errors.New("access token has read-only scope")
+	errRoleForbidden       = errors.New("user role forbidden to access")
+	errTooManyRequests     = errors.New("too many requests")
+	errStatsPeriodInvalid  = errors.New("period is invalid. check 'from' and 'to' params")
+	errFormatInvalid       = errors.New("invalid format")
+	errFormatNotAcceptable = errors.New("none of accepted content types is supported")
+	errFlaggedInvalid      = errors.New("query param 'flagged' must be boolean")
+	errThumbnailInvalid    = errors.New("query param 'thumbnail' must be boolean")
+	errUploadTooLarge      = errors.New("request body is too large")
+	errUnlockInvalid       = errors.New("query param 'unlock' must be boolean")
 )
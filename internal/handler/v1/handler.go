@@ -2,28 +2,49 @@ package v1
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/lotostudio/financial-api/internal/config"
 	"github.com/lotostudio/financial-api/internal/service"
 	"github.com/lotostudio/financial-api/pkg/auth"
+	"github.com/lotostudio/financial-api/pkg/limiter"
 )
 
 type Handler struct {
 	s   *service.Services
 	tkn auth.TokenManager
+
+	// Rate limiting, disabled if store is nil
+	limits          limiter.Store
+	loginIPLimit    limiter.Limit
+	loginEmailLimit limiter.Limit
+	apiLimit        limiter.Limit
+
+	// Max size of uploaded attachment, not limited if zero
+	maxUploadSize int64
 }
 
-func NewHandler(services *service.Services, tokenManager auth.TokenManager) *Handler {
+func NewHandler(services *service.Services, tokenManager auth.TokenManager, limits limiter.Store,
+	cfg config.RateLimit, maxUploadSize int64) *Handler {
 	return &Handler{
-		s:   services,
-		tkn: tokenManager,
+		s:               services,
+		tkn:             tokenManager,
+		limits:          limits,
+		loginIPLimit:    limiter.Every(cfg.LoginIP.Requests, cfg.LoginIP.Period),
+		loginEmailLimit: limiter.Every(cfg.LoginEmail.Requests, cfg.LoginEmail.Period),
+		apiLimit:        limiter.Every(cfg.API.Requests, cfg.API.Period),
+		maxUploadSize:   maxUploadSize,
 	}
 }
 
 func (h *Handler) Init(api *gin.RouterGroup) {
-	v1 := api.Group("/v1")
+	v1 := api.Group("/v1", h.requestActor)
 	{
 		h.initUsersRoutes(v1)
 		h.initAuthRoutes(v1)
 		h.initAccountsRoutes(v1)
 		h.initTransactionsRoutes(v1)
+		h.initTrashRoutes(v1)
+		h.initTemplatesRoutes(v1)
+		h.initStatsRoutes(v1)
+		h.initAdminRoutes(v1)
 	}
 }
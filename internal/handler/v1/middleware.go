@@ -1,48 +1,188 @@
 package v1
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"github.com/gin-gonic/gin"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"github.com/lotostudio/financial-api/internal/service"
+	"github.com/lotostudio/financial-api/pkg/limiter"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 )
 
 const (
 	authorizationHeader = "Authorization"
 	userCtx             = "userId"
+	roleCtx             = "userRole"
+	scopeCtx            = "tokenScope"
+	retryAfterHeader    = "Retry-After"
+	requestIDHeader     = "X-Request-ID"
+
+	// Max length of request ID given by client
+	requestIDMaxLength = 64
 )
 
+// requestActor puts actor of request to request context, so changes made within request are audited.
+// ID of request is taken from header or generated and returned in response header
+func (h *Handler) requestActor(c *gin.Context) {
+	requestID := c.GetHeader(requestIDHeader)
+
+	if requestID == "" || len(requestID) > requestIDMaxLength {
+		id := make([]byte, 16)
+
+		if _, err := rand.Read(id); err != nil {
+			newResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		requestID = hex.EncodeToString(id)
+	}
+
+	c.Header(requestIDHeader, requestID)
+	c.Request = c.Request.WithContext(domain.WithActor(c.Request.Context(), domain.Actor{
+		RequestID: requestID,
+		IP:        c.ClientIP(),
+	}))
+}
+
+// identifyActor adds authorized user and personal access token used to actor of request
+func identifyActor(c *gin.Context, userID int64, accessTokenID *int64) {
+	actor := domain.ActorFrom(c.Request.Context())
+	actor.UserID = &userID
+	actor.AccessTokenID = accessTokenID
+
+	c.Request = c.Request.WithContext(domain.WithActor(c.Request.Context(), actor))
+}
+
+// userIdentity authorizes user either by JWT access token or by personal access token.
+// Personal access tokens with read-only scope are allowed only for safe methods
 func (h *Handler) userIdentity(c *gin.Context) {
-	id, err := h.parseAuthHeader(c)
+	token, err := h.parseAuthHeaderToken(c)
 
 	if err != nil {
 		newResponse(c, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	c.Set(userCtx, id)
+	if !strings.HasPrefix(token, domain.AccessTokenPrefix) {
+		claims, err := h.tkn.Decode(token)
+
+		if err != nil {
+			newResponse(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		userId, err := strconv.ParseInt(claims.Subject, 10, 64)
+
+		if err != nil {
+			newResponse(c, http.StatusUnauthorized, "token subject must be integer - "+err.Error())
+			return
+		}
+
+		// Tokens of previous logins and reset sessions are rejected before expiration
+		err = h.s.Auth.CheckSession(c.Request.Context(), userId, claims.SessionID)
+
+		if errors.Is(err, service.ErrSessionRevoked) || errors.Is(err, repo.ErrSessionNotFound) {
+			newResponse(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		if err != nil {
+			newResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		c.Set(userCtx, claims.Subject)
+		c.Set(roleCtx, domain.Role(claims.Role))
+		identifyActor(c, userId, nil)
+		return
+	}
+
+	accessToken, err := h.s.AccessTokens.Authenticate(c.Request.Context(), token)
+
+	if errors.Is(err, repo.ErrAccessTokenNotFound) || errors.Is(err, service.ErrAccessTokenExpired) ||
+		errors.Is(err, service.ErrUserDisabled) {
+		newResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if accessToken.Scope == domain.ReadOnly && !isSafeMethod(c.Request.Method) {
+		newResponse(c, http.StatusForbidden, errAccessTokenReadOnly.Error())
+		return
+	}
+
+	c.Set(userCtx, strconv.FormatInt(accessToken.UserId, 10))
+	c.Set(roleCtx, accessToken.Role)
+	c.Set(scopeCtx, accessToken.Scope)
+	identifyActor(c, accessToken.UserId, &accessToken.ID)
 }
 
-func (h *Handler) parseAuthHeaderToken(c *gin.Context) (string, error) {
-	header := c.GetHeader(authorizationHeader)
+// userRole allows access only to users having one of given roles.
+// Must be used after userIdentity
+func (h *Handler) userRole(roles ...domain.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, _ := c.Get(roleCtx)
 
-	if header == "" {
-		return "", errors.New("empty auth header")
+		for _, r := range roles {
+			if role == r {
+				return
+			}
+		}
+
+		newResponse(c, http.StatusForbidden, errRoleForbidden.Error())
 	}
+}
 
-	headerParts := strings.Split(header, " ")
-	if len(headerParts) != 2 || headerParts[0] != "Bearer" {
-		return "", errors.New("invalid auth header")
+// limitLoginIP limits login attempts by client IP
+func (h *Handler) limitLoginIP(c *gin.Context) {
+	h.allow(c, "login-ip:"+c.ClientIP(), h.loginIPLimit)
+}
+
+// limitUser limits API requests by authenticated user.
+// Must be used after userIdentity
+func (h *Handler) limitUser(c *gin.Context) {
+	h.allow(c, "api:"+c.GetString(userCtx), h.apiLimit)
+}
+
+// allow takes token of given key from limits store. Aborts request with 429 status if limit exceeded
+func (h *Handler) allow(c *gin.Context, key string, limit limiter.Limit) bool {
+	if h.limits == nil || limit.Unlimited() {
+		return true
 	}
 
-	if len(headerParts[1]) == 0 {
-		return "", errors.New("token is empty")
+	wait, err := h.limits.Take(c.Request.Context(), key, limit)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return false
 	}
 
-	return headerParts[1], nil
+	if wait > 0 {
+		tooManyRequests(c, errTooManyRequests.Error(), wait)
+		return false
+	}
+
+	return true
+}
+
+// tooManyRequests aborts request with 429 status and Retry-After header in whole seconds
+func tooManyRequests(c *gin.Context, message string, retryAfter time.Duration) {
+	c.Header(retryAfterHeader, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	newResponse(c, http.StatusTooManyRequests, message)
 }
 
-func (h *Handler) parseAuthHeader(c *gin.Context) (string, error) {
+func (h *Handler) parseAuthHeaderToken(c *gin.Context) (string, error) {
 	header := c.GetHeader(authorizationHeader)
 
 	if header == "" {
@@ -58,5 +198,10 @@ func (h *Handler) parseAuthHeader(c *gin.Context) (string, error) {
 		return "", errors.New("token is empty")
 	}
 
-	return h.tkn.Decode(headerParts[1])
+	return headerParts[1], nil
+}
+
+// isSafeMethod reports whether request method does not modify resources
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
 }
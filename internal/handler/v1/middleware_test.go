@@ -0,0 +1,455 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"github.com/lotostudio/financial-api/internal/service"
+	mockService "github.com/lotostudio/financial-api/internal/service/mocks"
+	"github.com/lotostudio/financial-api/pkg/auth"
+	"github.com/lotostudio/financial-api/pkg/limiter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler_userIdentity(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockAccessTokens)
+
+	tokenManager, _ := auth.NewJWTManager("key", time.Hour, 32)
+
+	tests := []struct {
+		name                 string
+		method               string
+		header               string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "empty header",
+			method:               "GET",
+			mockBehaviour:        func(s *mockService.MockAccessTokens) {},
+			expectedCodeStatus:   401,
+			expectedResponseBody: `{"message":"empty auth header"}`,
+		},
+		{
+			name:   "access token read",
+			method: "GET",
+			header: "Bearer fa_token",
+			mockBehaviour: func(s *mockService.MockAccessTokens) {
+				s.EXPECT().Authenticate(context.Background(), "fa_token").Return(domain.AccessToken{
+					UserId: 2,
+					Scope:  domain.ReadOnly,
+				}, nil)
+			},
+			expectedCodeStatus:   200,
+			expectedResponseBody: "2",
+		},
+		{
+			name:   "access token read-only scope on mutation",
+			method: "POST",
+			header: "Bearer fa_token",
+			mockBehaviour: func(s *mockService.MockAccessTokens) {
+				s.EXPECT().Authenticate(context.Background(), "fa_token").Return(domain.AccessToken{
+					UserId: 2,
+					Scope:  domain.ReadOnly,
+				}, nil)
+			},
+			expectedCodeStatus:   403,
+			expectedResponseBody: `{"message":"access token has read-only scope"}`,
+		},
+		{
+			name:   "access token write scope on mutation",
+			method: "POST",
+			header: "Bearer fa_token",
+			mockBehaviour: func(s *mockService.MockAccessTokens) {
+				s.EXPECT().Authenticate(context.Background(), "fa_token").Return(domain.AccessToken{
+					UserId: 2,
+					Scope:  domain.ReadWrite,
+				}, nil)
+			},
+			expectedCodeStatus:   200,
+			expectedResponseBody: "2",
+		},
+		{
+			name:   "access token of disabled user",
+			method: "GET",
+			header: "Bearer fa_token",
+			mockBehaviour: func(s *mockService.MockAccessTokens) {
+				s.EXPECT().Authenticate(context.Background(), "fa_token").Return(domain.AccessToken{},
+					service.ErrUserDisabled)
+			},
+			expectedCodeStatus:   401,
+			expectedResponseBody: `{"message":"user is disabled"}`,
+		},
+		{
+			name:   "access token not found",
+			method: "GET",
+			header: "Bearer fa_token",
+			mockBehaviour: func(s *mockService.MockAccessTokens) {
+				s.EXPECT().Authenticate(context.Background(), "fa_token").Return(domain.AccessToken{},
+					repo.ErrAccessTokenNotFound)
+			},
+			expectedCodeStatus:   401,
+			expectedResponseBody: `{"message":"access token doesn't exists"}`,
+		},
+		{
+			name:   "access token expired",
+			method: "GET",
+			header: "Bearer fa_token",
+			mockBehaviour: func(s *mockService.MockAccessTokens) {
+				s.EXPECT().Authenticate(context.Background(), "fa_token").Return(domain.AccessToken{},
+					service.ErrAccessTokenExpired)
+			},
+			expectedCodeStatus:   401,
+			expectedResponseBody: `{"message":"access token expired"}`,
+		},
+		{
+			name:   "error",
+			method: "GET",
+			header: "Bearer fa_token",
+			mockBehaviour: func(s *mockService.MockAccessTokens) {
+				s.EXPECT().Authenticate(context.Background(), "fa_token").Return(domain.AccessToken{},
+					errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			tService := mockService.NewMockAccessTokens(c)
+			tt.mockBehaviour(tService)
+
+			services := &service.Services{AccessTokens: tService}
+			handler := &Handler{
+				s:   services,
+				tkn: tokenManager,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.Handle(tt.method, "/identity", handler.userIdentity, func(c *gin.Context) {
+				c.String(http.StatusOK, c.GetString(userCtx))
+			})
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, "/identity", bytes.NewBufferString(""))
+
+			if tt.header != "" {
+				req.Header.Set(authorizationHeader, tt.header)
+			}
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_userIdentityJWT(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockAuth)
+
+	tokenManager, _ := auth.NewJWTManager("key", time.Hour, 32)
+	jwtToken, _ := tokenManager.Issue(auth.Claims{Subject: "1", Role: string(domain.RoleUser), SessionID: "7.3"})
+
+	tests := []struct {
+		name                 string
+		header               string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "ok",
+			header: "Bearer " + jwtToken,
+			mockBehaviour: func(s *mockService.MockAuth) {
+				s.EXPECT().CheckSession(context.Background(), int64(1), "7.3").Return(nil)
+			},
+			expectedCodeStatus:   200,
+			expectedResponseBody: "1",
+		},
+		{
+			name:                 "invalid jwt",
+			header:               "Bearer qwe",
+			mockBehaviour:        func(s *mockService.MockAuth) {},
+			expectedCodeStatus:   401,
+			expectedResponseBody: `{"message":"token contains an invalid number of segments"}`,
+		},
+		{
+			name:   "session revoked",
+			header: "Bearer " + jwtToken,
+			mockBehaviour: func(s *mockService.MockAuth) {
+				s.EXPECT().CheckSession(context.Background(), int64(1), "7.3").Return(service.ErrSessionRevoked)
+			},
+			expectedCodeStatus:   401,
+			expectedResponseBody: `{"message":"session of token is revoked"}`,
+		},
+		{
+			name:   "error",
+			header: "Bearer " + jwtToken,
+			mockBehaviour: func(s *mockService.MockAuth) {
+				s.EXPECT().CheckSession(context.Background(), int64(1), "7.3").Return(errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			aService := mockService.NewMockAuth(c)
+			tt.mockBehaviour(aService)
+
+			services := &service.Services{Auth: aService}
+			handler := &Handler{
+				s:   services,
+				tkn: tokenManager,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/identity", handler.userIdentity, func(c *gin.Context) {
+				c.String(http.StatusOK, c.GetString(userCtx))
+			})
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/identity", bytes.NewBufferString(""))
+			req.Header.Set(authorizationHeader, tt.header)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_userRole(t *testing.T) {
+	tests := []struct {
+		name               string
+		role               interface{}
+		expectedCodeStatus int
+	}{
+		{
+			name:               "allowed",
+			role:               domain.RoleAdmin,
+			expectedCodeStatus: 200,
+		},
+		{
+			name:               "forbidden",
+			role:               domain.RoleUser,
+			expectedCodeStatus: 403,
+		},
+		{
+			name:               "no role",
+			expectedCodeStatus: 403,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &Handler{}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/admin", func(c *gin.Context) {
+				if tt.role != nil {
+					c.Set(roleCtx, tt.role)
+				}
+			}, handler.userRole(domain.RoleAdmin, domain.RoleSupport), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/admin", bytes.NewBufferString(""))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_limitUser(t *testing.T) {
+	handler := &Handler{
+		limits:   limiter.NewMemoryStore(),
+		apiLimit: limiter.Every(2, time.Minute),
+	}
+
+	// Init Endpoint
+	r := gin.New()
+	r.GET("/limited", func(c *gin.Context) {
+		c.Set(userCtx, c.GetHeader("User"))
+	}, handler.limitUser, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	request := func(user string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/limited", bytes.NewBufferString(""))
+		req.Header.Set("User", user)
+
+		r.ServeHTTP(w, req)
+
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, request("1").Code)
+	assert.Equal(t, http.StatusOK, request("1").Code)
+
+	w := request("1")
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "30", w.Header().Get(retryAfterHeader))
+	assert.Equal(t, `{"message":"too many requests"}`, w.Body.String())
+
+	// Other users have own limits
+	assert.Equal(t, http.StatusOK, request("2").Code)
+}
+
+func TestHandler_limitUserDisabled(t *testing.T) {
+	handler := &Handler{}
+
+	// Init Endpoint
+	r := gin.New()
+	r.GET("/limited", handler.limitUser, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for i := 0; i < 5; i++ {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/limited", bytes.NewBufferString(""))
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+}
+
+func TestHandler_requestActor(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestID string
+		generated bool
+	}{
+		{
+			name:      "given id",
+			requestID: "req-1",
+		},
+		{
+			name:      "generated id",
+			generated: true,
+		},
+		{
+			name:      "too long id",
+			requestID: strings.Repeat("a", requestIDMaxLength+1),
+			generated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &Handler{}
+
+			var actor domain.Actor
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/actor", handler.requestActor, func(c *gin.Context) {
+				actor = domain.ActorFrom(c.Request.Context())
+			})
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/actor", nil)
+			req.RemoteAddr = "10.0.0.1:1234"
+
+			if tt.requestID != "" {
+				req.Header.Set(requestIDHeader, tt.requestID)
+			}
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, 200, w.Code)
+			assert.Equal(t, actor.RequestID, w.Header().Get(requestIDHeader))
+			assert.Equal(t, "10.0.0.1", actor.IP)
+			assert.Equal(t, (*int64)(nil), actor.UserID)
+
+			if tt.generated {
+				assert.Equal(t, 32, len(actor.RequestID))
+			} else {
+				assert.Equal(t, tt.requestID, actor.RequestID)
+			}
+		})
+	}
+}
+
+func TestHandler_userIdentityActor(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	tService := mockService.NewMockAccessTokens(c)
+	tService.EXPECT().Authenticate(gomock.Any(), "fa_token").Return(domain.AccessToken{
+		ID:     4,
+		UserId: 2,
+		Scope:  domain.ReadWrite,
+	}, nil)
+
+	handler := &Handler{
+		s: &service.Services{AccessTokens: tService},
+	}
+
+	var actor domain.Actor
+
+	// Init Endpoint
+	r := gin.New()
+	r.POST("/identity", handler.requestActor, handler.userIdentity, func(c *gin.Context) {
+		actor = domain.ActorFrom(c.Request.Context())
+	})
+
+	// Create Request
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/identity", nil)
+	req.Header.Set(authorizationHeader, "Bearer fa_token")
+	req.Header.Set(requestIDHeader, "req-1")
+
+	// Make Request
+	r.ServeHTTP(w, req)
+
+	// Assert
+	userId, tokenId := int64(2), int64(4)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, domain.Actor{UserID: &userId, AccessTokenID: &tokenId, RequestID: "req-1", IP: "192.0.2.1"},
+		actor)
+}
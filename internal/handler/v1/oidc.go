@@ -0,0 +1,120 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"github.com/lotostudio/financial-api/internal/service"
+	"github.com/lotostudio/financial-api/pkg/oidc"
+	"net/http"
+	"strconv"
+)
+
+// @Summary List identity providers
+// @Tags auth
+// @Description List of OpenID Connect providers available for login
+// @ID listOIDCProviders
+// @Produce json
+// @Success 200 {object} domain.OIDCProviders "Operation finished successfully"
+// @Router /auth/oidc [get]
+func (h *Handler) listOIDCProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, domain.OIDCProviders{Providers: h.s.OIDC.Providers()})
+}
+
+// @Summary Login with identity provider
+// @Tags auth
+// @Description Redirects to authorization page of OpenID Connect provider
+// @ID oidcLogin
+// @Param provider path string true "Provider name"
+// @Success 302 "Redirect to provider"
+// @Failure 404 {object} response "Provider not found"
+// @Failure 429 {object} response "Too many login attempts"
+// @Failure 500 {object} response "Server error"
+// @Router /auth/oidc/{provider}/login [get]
+func (h *Handler) oidcLogin(c *gin.Context) {
+	url, err := h.s.OIDC.Begin(c.Request.Context(), c.Param("provider"))
+
+	if errors.Is(err, service.ErrOIDCProviderNotFound) {
+		newResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Redirect(http.StatusFound, url)
+}
+
+// @Summary Identity provider callback
+// @Tags auth
+// @Description Completes login with OpenID Connect provider. User is linked by verified email or created
+// @ID oidcCallback
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Param code query string true "Authorization code"
+// @Param state query string true "Authorization state"
+// @Success 200 {object} domain.Tokens "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Authorization at provider failed"
+// @Failure 403 {object} response "User disabled or email not verified"
+// @Failure 404 {object} response "Provider not found"
+// @Failure 429 {object} response "Too many login attempts"
+// @Failure 500 {object} response "Server error"
+// @Header 200 {int} Access-Token-TTL "Time to live of access token in seconds"
+// @Header 200 {int} Refresh-Token-TTL "Time to live of refresh token in seconds"
+// @Router /auth/oidc/{provider}/callback [get]
+func (h *Handler) oidcCallback(c *gin.Context) {
+	if errParam := c.Query("error"); errParam != "" {
+		newResponse(c, http.StatusUnauthorized, "authorization failed - "+errParam)
+		return
+	}
+
+	code, state := c.Query("code"), c.Query("state")
+
+	if code == "" || state == "" {
+		newResponse(c, http.StatusBadRequest, "query params 'code' and 'state' are required")
+		return
+	}
+
+	identity, err := h.s.OIDC.Complete(c.Request.Context(), c.Param("provider"), state, code)
+
+	if errors.Is(err, service.ErrOIDCProviderNotFound) {
+		newResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if errors.Is(err, repo.ErrOIDCFlowNotFound) || errors.Is(err, service.ErrOIDCFlowInvalid) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, oidc.ErrTokenExchange) || errors.Is(err, oidc.ErrInvalidToken) {
+		newResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	tokens, err := h.s.LoginIdentity(c.Request.Context(), identity)
+
+	if errors.Is(err, service.ErrUserDisabled) || errors.Is(err, service.ErrEmailNotVerified) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Header("Access-Token-TTL", strconv.Itoa(int(tokens.AccessTokenExpiredAt)))
+	c.Header("Refresh-Token-TTL", strconv.Itoa(int(tokens.RefreshTokenExpiredAt)))
+
+	c.JSON(http.StatusOK, tokens)
+}
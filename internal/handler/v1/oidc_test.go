@@ -0,0 +1,204 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"github.com/lotostudio/financial-api/internal/service"
+	mockService "github.com/lotostudio/financial-api/internal/service/mocks"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_listOIDCProviders(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	oidc := mockService.NewMockOIDC(c)
+	oidc.EXPECT().Providers().Return([]string{"google"})
+
+	handler := &Handler{s: &service.Services{OIDC: oidc}}
+
+	// Init Endpoint
+	r := gin.New()
+	r.GET("/oidc", handler.listOIDCProviders)
+
+	// Make Request
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/oidc", bytes.NewBufferString("")))
+
+	// Assert
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"providers":["google"]}`, w.Body.String())
+}
+
+func TestHandler_oidcLogin(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockOIDC)
+
+	tests := []struct {
+		name             string
+		provider         string
+		mockBehaviour    mockBehaviour
+		statusCode       int
+		expectedLocation string
+	}{
+		{
+			name:     "ok",
+			provider: "google",
+			mockBehaviour: func(s *mockService.MockOIDC) {
+				s.EXPECT().Begin(context.Background(), "google").Return("https://provider/authorize", nil)
+			},
+			statusCode:       302,
+			expectedLocation: "https://provider/authorize",
+		},
+		{
+			name:     "provider not found",
+			provider: "other",
+			mockBehaviour: func(s *mockService.MockOIDC) {
+				s.EXPECT().Begin(context.Background(), "other").Return("", service.ErrOIDCProviderNotFound)
+			},
+			statusCode: 404,
+		},
+		{
+			name:     "error",
+			provider: "google",
+			mockBehaviour: func(s *mockService.MockOIDC) {
+				s.EXPECT().Begin(context.Background(), "google").Return("", errors.New("general error"))
+			},
+			statusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			oidc := mockService.NewMockOIDC(c)
+			tt.mockBehaviour(oidc)
+
+			handler := &Handler{s: &service.Services{OIDC: oidc}}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/oidc/:provider/login", handler.oidcLogin)
+
+			// Make Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/oidc/"+tt.provider+"/login", bytes.NewBufferString(""))
+
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.statusCode, w.Code)
+			assert.Equal(t, tt.expectedLocation, w.Header().Get("Location"))
+		})
+	}
+}
+
+func TestHandler_oidcCallback(t *testing.T) {
+	type mockBehaviour func(o *mockService.MockOIDC, a *mockService.MockAuth)
+
+	identity := domain.ExternalIdentity{Provider: "google", Subject: "42", Email: "user@mail.com", EmailVerified: true}
+
+	tests := []struct {
+		name          string
+		query         string
+		mockBehaviour mockBehaviour
+		statusCode    int
+		responseBody  string
+	}{
+		{
+			name:  "ok",
+			query: "?code=code&state=state",
+			mockBehaviour: func(o *mockService.MockOIDC, a *mockService.MockAuth) {
+				o.EXPECT().Complete(context.Background(), "google", "state", "code").Return(identity, nil)
+				a.EXPECT().LoginIdentity(context.Background(), identity).Return(domain.Tokens{
+					AccessToken:  "token",
+					RefreshToken: "token",
+				}, nil)
+			},
+			statusCode:   200,
+			responseBody: `{"accessToken":"token","refreshToken":"token"}`,
+		},
+		{
+			name:          "provider error",
+			query:         "?error=access_denied&state=state",
+			mockBehaviour: func(o *mockService.MockOIDC, a *mockService.MockAuth) {},
+			statusCode:    401,
+			responseBody:  `{"message":"authorization failed - access_denied"}`,
+		},
+		{
+			name:          "no code",
+			query:         "?state=state",
+			mockBehaviour: func(o *mockService.MockOIDC, a *mockService.MockAuth) {},
+			statusCode:    400,
+			responseBody:  `{"message":"query params 'code' and 'state' are required"}`,
+		},
+		{
+			name:  "unknown state",
+			query: "?code=code&state=state",
+			mockBehaviour: func(o *mockService.MockOIDC, a *mockService.MockAuth) {
+				o.EXPECT().Complete(context.Background(), "google", "state", "code").Return(domain.ExternalIdentity{},
+					repo.ErrOIDCFlowNotFound)
+			},
+			statusCode:   400,
+			responseBody: `{"message":"authorization flow doesn't exists"}`,
+		},
+		{
+			name:  "email not verified",
+			query: "?code=code&state=state",
+			mockBehaviour: func(o *mockService.MockOIDC, a *mockService.MockAuth) {
+				o.EXPECT().Complete(context.Background(), "google", "state", "code").Return(identity, nil)
+				a.EXPECT().LoginIdentity(context.Background(), identity).Return(domain.Tokens{},
+					service.ErrEmailNotVerified)
+			},
+			statusCode:   403,
+			responseBody: `{"message":"email is not verified by provider"}`,
+		},
+		{
+			name:  "error",
+			query: "?code=code&state=state",
+			mockBehaviour: func(o *mockService.MockOIDC, a *mockService.MockAuth) {
+				o.EXPECT().Complete(context.Background(), "google", "state", "code").Return(domain.ExternalIdentity{},
+					errors.New("general error"))
+			},
+			statusCode:   500,
+			responseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			oidc := mockService.NewMockOIDC(c)
+			auth := mockService.NewMockAuth(c)
+			tt.mockBehaviour(oidc, auth)
+
+			handler := &Handler{s: &service.Services{OIDC: oidc, Auth: auth}}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/oidc/:provider/callback", handler.oidcCallback)
+
+			// Make Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/oidc/google/callback"+tt.query, bytes.NewBufferString(""))
+
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.statusCode, w.Code)
+			assert.Equal(t, tt.responseBody, w.Body.String())
+		})
+	}
+}
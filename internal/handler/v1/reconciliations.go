@@ -0,0 +1,433 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"github.com/lotostudio/financial-api/internal/service"
+	"net/http"
+	"strconv"
+)
+
+// @Summary List reconciliations
+// @Tags reconciliations
+// @Description List reconciliations of account with bank statements, latest statement first
+// @ID listReconciliations
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Id of account"
+// @Success 200 {array} domain.Reconciliation "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /accounts/{id}/reconciliations [get]
+func (h *Handler) listReconciliations(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	accountId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'id' must be integer - "+err.Error())
+		return
+	}
+
+	reconciliations, err := h.s.Reconciliations.List(c.Request.Context(), accountId, userId)
+
+	if errors.Is(err, repo.ErrAccountNotFound) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, service.ErrAccountForbidden) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, reconciliations)
+}
+
+// @Summary Start reconciliation
+// @Tags reconciliations
+// @Description Start reconciliation of account with bank statement by its end date and closing balance. Transactions
+// @Description not reconciled before up to end date are listed to tick ones found in statement. Account has one
+// @Description reconciliation in progress at most
+// @ID createReconciliation
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Id of account"
+// @Param input body domain.ReconciliationToCreate true "Bank statement info"
+// @Success 201 {object} domain.ReconciliationDetails "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /accounts/{id}/reconciliations [post]
+func (h *Handler) createReconciliation(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	accountId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'id' must be integer - "+err.Error())
+		return
+	}
+
+	var toCreate domain.ReconciliationToCreate
+
+	if err = c.ShouldBindJSON(&toCreate); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid request body - "+err.Error())
+		return
+	}
+
+	details, err := h.s.Reconciliations.Create(c.Request.Context(), accountId, userId, toCreate)
+
+	if errors.Is(err, repo.ErrAccountNotFound) || errors.Is(err, repo.ErrReconciliationInProgress) ||
+		errors.Is(err, service.ErrStatementDateInvalid) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, service.ErrAccountForbidden) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, details)
+}
+
+// @Summary Get reconciliation
+// @Tags reconciliations
+// @Description Get reconciliation with transactions to tick, book and cleared balances and difference of cleared
+// @Description balance with statement balance
+// @ID getReconciliation
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Id of account"
+// @Param reconciliationId path int64 true "Id of reconciliation"
+// @Success 200 {object} domain.ReconciliationDetails "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /accounts/{id}/reconciliations/{reconciliationId} [get]
+func (h *Handler) getReconciliation(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	accountId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'id' must be integer - "+err.Error())
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("reconciliationId"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'reconciliationId' must be integer - "+err.Error())
+		return
+	}
+
+	details, err := h.s.Reconciliations.Get(c.Request.Context(), accountId, id, userId)
+
+	if errors.Is(err, repo.ErrAccountNotFound) || errors.Is(err, repo.ErrReconciliationNotFound) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, service.ErrAccountForbidden) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, details)
+}
+
+// @Summary Tick transaction
+// @Tags reconciliations
+// @Description Mark transaction as found in bank statement
+// @ID tickReconciliationTransaction
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Id of account"
+// @Param reconciliationId path int64 true "Id of reconciliation"
+// @Param transactionId path int64 true "Id of transaction"
+// @Success 200 {object} domain.ReconciliationDetails "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /accounts/{id}/reconciliations/{reconciliationId}/transactions/{transactionId} [put]
+func (h *Handler) tickReconciliationTransaction(c *gin.Context) {
+	h.setReconciliationTransactionTicked(c, true)
+}
+
+// @Summary Untick transaction
+// @Tags reconciliations
+// @Description Mark transaction as missing in bank statement
+// @ID untickReconciliationTransaction
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Id of account"
+// @Param reconciliationId path int64 true "Id of reconciliation"
+// @Param transactionId path int64 true "Id of transaction"
+// @Success 200 {object} domain.ReconciliationDetails "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /accounts/{id}/reconciliations/{reconciliationId}/transactions/{transactionId} [delete]
+func (h *Handler) untickReconciliationTransaction(c *gin.Context) {
+	h.setReconciliationTransactionTicked(c, false)
+}
+
+func (h *Handler) setReconciliationTransactionTicked(c *gin.Context, ticked bool) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	accountId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'id' must be integer - "+err.Error())
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("reconciliationId"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'reconciliationId' must be integer - "+err.Error())
+		return
+	}
+
+	transactionId, err := strconv.ParseInt(c.Param("transactionId"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'transactionId' must be integer - "+err.Error())
+		return
+	}
+
+	var details domain.ReconciliationDetails
+
+	if ticked {
+		details, err = h.s.Reconciliations.Tick(c.Request.Context(), accountId, id, transactionId, userId)
+	} else {
+		details, err = h.s.Reconciliations.Untick(c.Request.Context(), accountId, id, transactionId, userId)
+	}
+
+	if errors.Is(err, repo.ErrAccountNotFound) || errors.Is(err, repo.ErrReconciliationNotFound) ||
+		errors.Is(err, service.ErrReconciliationCompleted) || errors.Is(err, service.ErrReconciliationTransactionInvalid) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, service.ErrAccountForbidden) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, details)
+}
+
+// @Summary Complete reconciliation
+// @Tags reconciliations
+// @Description Mark ticked transactions as reconciled if cleared balance matches statement balance. Reconciled
+// @Description transactions are locked against changes
+// @ID completeReconciliation
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Id of account"
+// @Param reconciliationId path int64 true "Id of reconciliation"
+// @Success 200 {object} domain.ReconciliationDetails "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /accounts/{id}/reconciliations/{reconciliationId}/complete [post]
+func (h *Handler) completeReconciliation(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	accountId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'id' must be integer - "+err.Error())
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("reconciliationId"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'reconciliationId' must be integer - "+err.Error())
+		return
+	}
+
+	details, err := h.s.Reconciliations.Complete(c.Request.Context(), accountId, id, userId)
+
+	if errors.Is(err, repo.ErrAccountNotFound) || errors.Is(err, repo.ErrReconciliationNotFound) ||
+		errors.Is(err, service.ErrReconciliationCompleted) || errors.Is(err, service.ErrReconciliationNotBalanced) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, service.ErrAccountForbidden) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, details)
+}
+
+// @Summary Cancel reconciliation
+// @Tags reconciliations
+// @Description Delete reconciliation in progress, statuses of transactions are kept
+// @ID deleteReconciliation
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Id of account"
+// @Param reconciliationId path int64 true "Id of reconciliation"
+// @Success 204 {null} nil "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /accounts/{id}/reconciliations/{reconciliationId} [delete]
+func (h *Handler) deleteReconciliation(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	accountId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'id' must be integer - "+err.Error())
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("reconciliationId"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'reconciliationId' must be integer - "+err.Error())
+		return
+	}
+
+	err = h.s.Reconciliations.Delete(c.Request.Context(), accountId, id, userId)
+
+	if errors.Is(err, repo.ErrAccountNotFound) || errors.Is(err, repo.ErrReconciliationNotFound) ||
+		errors.Is(err, service.ErrReconciliationCompleted) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, service.ErrAccountForbidden) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
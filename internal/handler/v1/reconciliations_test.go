@@ -0,0 +1,470 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"github.com/lotostudio/financial-api/internal/service"
+	mockService "github.com/lotostudio/financial-api/internal/service/mocks"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const (
+	reconciliationID = int64(3)
+)
+
+var testReconciliation = domain.Reconciliation{
+	ID:               reconciliationID,
+	AccountID:        accountID,
+	StatementDate:    time.Date(2022, 3, 31, 0, 0, 0, 0, time.UTC),
+	StatementBalance: 1100,
+	CreatedAt:        time.Date(2022, 4, 2, 10, 0, 0, 0, time.UTC),
+}
+
+var testReconciliationDetails = domain.ReconciliationDetails{
+	Reconciliation: testReconciliation,
+	BookBalance:    1000,
+	ClearedBalance: 1000,
+	Difference:     100,
+	Transactions:   []domain.ReconciliationTransaction{},
+}
+
+const testReconciliationDetailsJSON = `{"id":3,"accountId":2,"statementDate":"2022-03-31T00:00:00Z",` +
+	`"statementBalance":1100,"createdAt":"2022-04-02T10:00:00Z","bookBalance":1000,"clearedBalance":1000,` +
+	`"difference":100,"transactions":[]}`
+
+func TestHandler_listReconciliations(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockReconciliations)
+
+	tests := []struct {
+		name                 string
+		id                   string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name: "ok",
+			id:   strconv.FormatInt(accountID, 10),
+			mockBehaviour: func(s *mockService.MockReconciliations) {
+				s.EXPECT().List(context.Background(), accountID, userID).
+					Return([]domain.Reconciliation{testReconciliation}, nil)
+			},
+			expectedCodeStatus: 200,
+			expectedResponseBody: `[{"id":3,"accountId":2,"statementDate":"2022-03-31T00:00:00Z",` +
+				`"statementBalance":1100,"createdAt":"2022-04-02T10:00:00Z"}]`,
+		},
+		{
+			name:                 "invalid id",
+			id:                   "a",
+			mockBehaviour:        func(s *mockService.MockReconciliations) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"path param 'id' must be integer - strconv.ParseInt: parsing \"a\": invalid syntax"}`,
+		},
+		{
+			name: "forbidden",
+			id:   strconv.FormatInt(accountID, 10),
+			mockBehaviour: func(s *mockService.MockReconciliations) {
+				s.EXPECT().List(context.Background(), accountID, userID).Return(nil, service.ErrAccountForbidden)
+			},
+			expectedCodeStatus:   403,
+			expectedResponseBody: `{"message":"` + service.ErrAccountForbidden.Error() + `"}`,
+		},
+		{
+			name: "error",
+			id:   strconv.FormatInt(accountID, 10),
+			mockBehaviour: func(s *mockService.MockReconciliations) {
+				s.EXPECT().List(context.Background(), accountID, userID).Return(nil, errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			rService := mockService.NewMockReconciliations(c)
+			tt.mockBehaviour(rService)
+
+			services := &service.Services{Reconciliations: rService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/accounts/:id/reconciliations", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.listReconciliations)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/accounts/"+tt.id+"/reconciliations", nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_createReconciliation(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockReconciliations)
+
+	statementBalance := 1100.0
+	toCreate := domain.ReconciliationToCreate{
+		StatementDate:    time.Date(2022, 3, 31, 0, 0, 0, 0, time.UTC),
+		StatementBalance: &statementBalance,
+	}
+
+	tests := []struct {
+		name                 string
+		id                   string
+		body                 string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name: "ok",
+			id:   strconv.FormatInt(accountID, 10),
+			body: `{"statementDate":"2022-03-31T00:00:00Z","statementBalance":1100}`,
+			mockBehaviour: func(s *mockService.MockReconciliations) {
+				s.EXPECT().Create(context.Background(), accountID, userID, toCreate).
+					Return(testReconciliationDetails, nil)
+			},
+			expectedCodeStatus:   201,
+			expectedResponseBody: testReconciliationDetailsJSON,
+		},
+		{
+			name:               "missing balance",
+			id:                 strconv.FormatInt(accountID, 10),
+			body:               `{"statementDate":"2022-03-31T00:00:00Z"}`,
+			mockBehaviour:      func(s *mockService.MockReconciliations) {},
+			expectedCodeStatus: 400,
+			expectedResponseBody: `{"message":"invalid request body - Key: 'ReconciliationToCreate.StatementBalance' ` +
+				`Error:Field validation for 'StatementBalance' failed on the 'required' tag"}`,
+		},
+		{
+			name: "in progress",
+			id:   strconv.FormatInt(accountID, 10),
+			body: `{"statementDate":"2022-03-31T00:00:00Z","statementBalance":1100}`,
+			mockBehaviour: func(s *mockService.MockReconciliations) {
+				s.EXPECT().Create(context.Background(), accountID, userID, toCreate).
+					Return(domain.ReconciliationDetails{}, repo.ErrReconciliationInProgress)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"other reconciliation of account is in progress"}`,
+		},
+		{
+			name: "forbidden",
+			id:   strconv.FormatInt(accountID, 10),
+			body: `{"statementDate":"2022-03-31T00:00:00Z","statementBalance":1100}`,
+			mockBehaviour: func(s *mockService.MockReconciliations) {
+				s.EXPECT().Create(context.Background(), accountID, userID, toCreate).
+					Return(domain.ReconciliationDetails{}, service.ErrAccountForbidden)
+			},
+			expectedCodeStatus:   403,
+			expectedResponseBody: `{"message":"` + service.ErrAccountForbidden.Error() + `"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			rService := mockService.NewMockReconciliations(c)
+			tt.mockBehaviour(rService)
+
+			services := &service.Services{Reconciliations: rService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/accounts/:id/reconciliations", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.createReconciliation)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/accounts/"+tt.id+"/reconciliations",
+				bytes.NewBufferString(tt.body))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_tickReconciliationTransaction(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockReconciliations)
+
+	tests := []struct {
+		name                 string
+		transactionID        string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name:          "ok",
+			transactionID: strconv.FormatInt(transactionID, 10),
+			mockBehaviour: func(s *mockService.MockReconciliations) {
+				s.EXPECT().Tick(context.Background(), accountID, reconciliationID, transactionID, userID).
+					Return(testReconciliationDetails, nil)
+			},
+			expectedCodeStatus:   200,
+			expectedResponseBody: testReconciliationDetailsJSON,
+		},
+		{
+			name:                 "invalid transaction id",
+			transactionID:        "a",
+			mockBehaviour:        func(s *mockService.MockReconciliations) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"path param 'transactionId' must be integer - strconv.ParseInt: parsing \"a\": invalid syntax"}`,
+		},
+		{
+			name:          "transaction not listed",
+			transactionID: strconv.FormatInt(transactionID, 10),
+			mockBehaviour: func(s *mockService.MockReconciliations) {
+				s.EXPECT().Tick(context.Background(), accountID, reconciliationID, transactionID, userID).
+					Return(domain.ReconciliationDetails{}, service.ErrReconciliationTransactionInvalid)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"transaction is not among transactions to reconcile"}`,
+		},
+		{
+			name:          "completed",
+			transactionID: strconv.FormatInt(transactionID, 10),
+			mockBehaviour: func(s *mockService.MockReconciliations) {
+				s.EXPECT().Tick(context.Background(), accountID, reconciliationID, transactionID, userID).
+					Return(domain.ReconciliationDetails{}, service.ErrReconciliationCompleted)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"reconciliation is completed"}`,
+		},
+		{
+			name:          "error",
+			transactionID: strconv.FormatInt(transactionID, 10),
+			mockBehaviour: func(s *mockService.MockReconciliations) {
+				s.EXPECT().Tick(context.Background(), accountID, reconciliationID, transactionID, userID).
+					Return(domain.ReconciliationDetails{}, errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			rService := mockService.NewMockReconciliations(c)
+			tt.mockBehaviour(rService)
+
+			services := &service.Services{Reconciliations: rService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.PUT("/accounts/:id/reconciliations/:reconciliationId/transactions/:transactionId", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.tickReconciliationTransaction)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", "/accounts/"+strconv.FormatInt(accountID, 10)+"/reconciliations/"+
+				strconv.FormatInt(reconciliationID, 10)+"/transactions/"+tt.transactionID, nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_completeReconciliation(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockReconciliations)
+
+	tests := []struct {
+		name                 string
+		id                   string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name: "ok",
+			id:   strconv.FormatInt(reconciliationID, 10),
+			mockBehaviour: func(s *mockService.MockReconciliations) {
+				s.EXPECT().Complete(context.Background(), accountID, reconciliationID, userID).
+					Return(testReconciliationDetails, nil)
+			},
+			expectedCodeStatus:   200,
+			expectedResponseBody: testReconciliationDetailsJSON,
+		},
+		{
+			name:                 "invalid id",
+			id:                   "a",
+			mockBehaviour:        func(s *mockService.MockReconciliations) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"path param 'reconciliationId' must be integer - strconv.ParseInt: parsing \"a\": invalid syntax"}`,
+		},
+		{
+			name: "not balanced",
+			id:   strconv.FormatInt(reconciliationID, 10),
+			mockBehaviour: func(s *mockService.MockReconciliations) {
+				s.EXPECT().Complete(context.Background(), accountID, reconciliationID, userID).
+					Return(testReconciliationDetails, service.ErrReconciliationNotBalanced)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"statement balance differs from cleared balance"}`,
+		},
+		{
+			name: "not found",
+			id:   strconv.FormatInt(reconciliationID, 10),
+			mockBehaviour: func(s *mockService.MockReconciliations) {
+				s.EXPECT().Complete(context.Background(), accountID, reconciliationID, userID).
+					Return(domain.ReconciliationDetails{}, repo.ErrReconciliationNotFound)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"reconciliation doesn't exists"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			rService := mockService.NewMockReconciliations(c)
+			tt.mockBehaviour(rService)
+
+			services := &service.Services{Reconciliations: rService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/accounts/:id/reconciliations/:reconciliationId/complete", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.completeReconciliation)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/accounts/"+strconv.FormatInt(accountID, 10)+
+				"/reconciliations/"+tt.id+"/complete", nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_deleteReconciliation(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockReconciliations)
+
+	tests := []struct {
+		name                 string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name: "ok",
+			mockBehaviour: func(s *mockService.MockReconciliations) {
+				s.EXPECT().Delete(context.Background(), accountID, reconciliationID, userID).Return(nil)
+			},
+			expectedCodeStatus:   204,
+			expectedResponseBody: ``,
+		},
+		{
+			name: "completed",
+			mockBehaviour: func(s *mockService.MockReconciliations) {
+				s.EXPECT().Delete(context.Background(), accountID, reconciliationID, userID).
+					Return(service.ErrReconciliationCompleted)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"reconciliation is completed"}`,
+		},
+		{
+			name: "forbidden",
+			mockBehaviour: func(s *mockService.MockReconciliations) {
+				s.EXPECT().Delete(context.Background(), accountID, reconciliationID, userID).
+					Return(service.ErrAccountForbidden)
+			},
+			expectedCodeStatus:   403,
+			expectedResponseBody: `{"message":"` + service.ErrAccountForbidden.Error() + `"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			rService := mockService.NewMockReconciliations(c)
+			tt.mockBehaviour(rService)
+
+			services := &service.Services{Reconciliations: rService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.DELETE("/accounts/:id/reconciliations/:reconciliationId", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.deleteReconciliation)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", "/accounts/"+strconv.FormatInt(accountID, 10)+
+				"/reconciliations/"+strconv.FormatInt(reconciliationID, 10), nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
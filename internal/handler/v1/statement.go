@@ -1,32 +1,56 @@
 package v1
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/export"
 	"github.com/lotostudio/financial-api/internal/repo"
 	"github.com/lotostudio/financial-api/internal/service"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 // @Summary Get statement
 // @Tags statement
-// @Description List transactions
+// @Description Transactions of account ordered by date with balance after each one and totals by types.
+// @Description Statement is reconciled if opening balance with all transactions of period gives closing balance
+// @Description Statement is rendered as JSON, CSV, XLSX or PDF by 'format' param or 'Accept' header
 // @ID getStatement
 // @Security UsersAuth
 // @Accept json
 // @Produce json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce application/pdf
 // @Param id path int true "Id of account"
 // @Param category query string false "Category of transaction"
 // @Param type query string false "Type of transaction"
-// @Param dateFrom query string false "Start date (yyyy-MM-dd)"
-// @Param dateTo query string false "End date (yyyy-MM-dd)"
-// @Success 200 {array} domain.Transaction "Operation finished successfully"
+// @Param dateFrom query string true "Start date (yyyy-MM-dd)"
+// @Param dateTo query string true "End date (yyyy-MM-dd)"
+// @Param format query string false "Format of statement" Enums(json, csv, xlsx, pdf)
+// @Success 200 {object} domain.Statement "Operation finished successfully"
 // @Failure 400 {object} response "Invalid request"
 // @Failure 401 {object} response "Invalid authorization"
+// @Failure 406 {object} response "Unsupported content type"
 // @Failure 500 {object} response "Server error"
-// @Router /stats/statement [get]
+// @Router /accounts/{id}/statement [get]
 func (h *Handler) getStatement(c *gin.Context) {
+	format, err := exportFormat(c, export.CSV, export.XLSX, export.PDF)
+
+	if errors.Is(err, errFormatNotAcceptable) {
+		newResponse(c, http.StatusNotAcceptable, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	filter, err := h.parseTransactionsFilter(c)
 
 	if err != nil {
@@ -34,6 +58,12 @@ func (h *Handler) getStatement(c *gin.Context) {
 		return
 	}
 
+	// Statement is made for period, so both dates are required
+	if filter.CreatedFrom == nil || filter.CreatedTo == nil || filter.CreatedTo.Before(*filter.CreatedFrom) {
+		newResponse(c, http.StatusBadRequest, errDateFiltersInvalid.Error())
+		return
+	}
+
 	userIdString, ok := c.Get("userId")
 
 	if !ok {
@@ -90,5 +120,74 @@ func (h *Handler) getStatement(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, stat)
+	if format == jsonFormat {
+		c.JSON(http.StatusOK, stat)
+		return
+	}
+
+	writeStatement(c, stat, format)
+}
+
+const jsonFormat = "json"
+
+// Get export format from 'format' query param or from 'Accept' header, JSON is default. Only given formats and JSON
+// are supported
+func exportFormat(c *gin.Context, formats ...string) (string, error) {
+	if format := c.Query("format"); format != "" {
+		if format == jsonFormat {
+			return format, nil
+		}
+
+		for _, f := range formats {
+			if f == format {
+				return format, nil
+			}
+		}
+
+		return "", fmt.Errorf("%w, use one of: %s", errFormatInvalid, strings.Join(append([]string{jsonFormat},
+			formats...), ", "))
+	}
+
+	offered := []string{gin.MIMEJSON}
+
+	for _, f := range formats {
+		offered = append(offered, export.ContentTypes[f])
+	}
+
+	negotiated := c.NegotiateFormat(offered...)
+
+	if negotiated == gin.MIMEJSON {
+		return jsonFormat, nil
+	}
+
+	for _, f := range formats {
+		if export.ContentTypes[f] == negotiated {
+			return f, nil
+		}
+	}
+
+	return "", errFormatNotAcceptable
+}
+
+// Render statement as file of given format
+func writeStatement(c *gin.Context, stat domain.Statement, format string) {
+	var buf bytes.Buffer
+	var err error
+
+	switch format {
+	case export.CSV:
+		err = export.StatementCSV(&buf, stat)
+	case export.XLSX:
+		err = export.StatementXLSX(&buf, stat)
+	case export.PDF:
+		err = export.StatementPDF(&buf, stat)
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, export.StatementFilename(stat, format)))
+	c.Data(http.StatusOK, export.ContentTypes[format], buf.Bytes())
 }
@@ -22,6 +22,7 @@ func TestHandler_getStatement(t *testing.T) {
 
 	tests := []struct {
 		name                 string
+		query                string
 		mockBehaviour        mockBehaviour
 		expectedCodeStatus   int
 		expectedResponseBody string
@@ -33,7 +34,7 @@ func TestHandler_getStatement(t *testing.T) {
 				s.EXPECT().Statement(context.Background(), gomock.Any()).Return(domain.Statement{}, nil)
 			},
 			expectedCodeStatus:   200,
-			expectedResponseBody: `{"account":{"id":0,"title":"","balance":0,"currency":"","type":"","createdAt":"0001-01-01T00:00:00Z"},"balanceIn":{"date":"0001-01-01T00:00:00Z","value":0},"balanceOut":{"date":"0001-01-01T00:00:00Z","value":0},"transactions":null}`,
+			expectedResponseBody: `{"account":{"id":0,"title":"","balance":0,"currency":"","type":"","createdAt":"0001-01-01T00:00:00Z"},"balanceIn":{"date":"0001-01-01T00:00:00Z","value":0},"balanceOut":{"date":"0001-01-01T00:00:00Z","value":0},"transactions":null,"totals":{"income":0,"expense":0,"transferIn":0,"transferOut":0},"reconciled":false,"discrepancy":0}`,
 		},
 		{
 			name: "not found",
@@ -68,6 +69,20 @@ func TestHandler_getStatement(t *testing.T) {
 			expectedCodeStatus:   500,
 			expectedResponseBody: `{"message":"general error"}`,
 		},
+		{
+			name:                 "no dates",
+			query:                "?category=food",
+			mockBehaviour:        func(s *mockService.MockStats, a *mockService.MockAccounts) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"date filters are invalid. check 'dateFrom' and 'dateTo' params"}`,
+		},
+		{
+			name:                 "reversed dates",
+			query:                "?dateFrom=2022-01-31&dateTo=2022-01-01",
+			mockBehaviour:        func(s *mockService.MockStats, a *mockService.MockAccounts) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"date filters are invalid. check 'dateFrom' and 'dateTo' params"}`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -80,6 +95,10 @@ func TestHandler_getStatement(t *testing.T) {
 			aService := mockService.NewMockAccounts(c)
 			tt.mockBehaviour(tService, aService)
 
+			if tt.query == "" {
+				tt.query = "?dateFrom=2022-01-01&dateTo=2022-01-31"
+			}
+
 			services := &service.Services{Stats: tService, Accounts: aService}
 			handler := &Handler{
 				s: services,
@@ -93,7 +112,7 @@ func TestHandler_getStatement(t *testing.T) {
 
 			// Create Request
 			w := httptest.NewRecorder()
-			req := httptest.NewRequest("GET", fmt.Sprintf("/accounts/%d/statement", accountID),
+			req := httptest.NewRequest("GET", fmt.Sprintf("/accounts/%d/statement%s", accountID, tt.query),
 				bytes.NewBufferString(""))
 
 			// Make Request
@@ -105,3 +124,99 @@ func TestHandler_getStatement(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_getStatementFormats(t *testing.T) {
+	tests := []struct {
+		name                string
+		query               string
+		accept              string
+		callService         bool
+		expectedCodeStatus  int
+		expectedContentType string
+	}{
+		{
+			name:                "default",
+			callService:         true,
+			expectedCodeStatus:  200,
+			expectedContentType: "application/json; charset=utf-8",
+		},
+		{
+			name:                "csv param",
+			query:               "&format=csv",
+			accept:              "application/json",
+			callService:         true,
+			expectedCodeStatus:  200,
+			expectedContentType: "text/csv",
+		},
+		{
+			name:                "xlsx header",
+			accept:              "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			callService:         true,
+			expectedCodeStatus:  200,
+			expectedContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		},
+		{
+			name:                "pdf header",
+			accept:              "application/pdf, */*;q=0.8",
+			callService:         true,
+			expectedCodeStatus:  200,
+			expectedContentType: "application/pdf",
+		},
+		{
+			name:                "invalid format",
+			query:               "&format=doc",
+			expectedCodeStatus:  400,
+			expectedContentType: "application/json; charset=utf-8",
+		},
+		{
+			name:                "not acceptable",
+			accept:              "image/png",
+			expectedCodeStatus:  406,
+			expectedContentType: "application/json; charset=utf-8",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			tService := mockService.NewMockStats(c)
+			aService := mockService.NewMockAccounts(c)
+
+			if tt.callService {
+				aService.EXPECT().Get(context.Background(), accountID, userID).Return(domain.Account{}, nil)
+				tService.EXPECT().Statement(context.Background(), gomock.Any()).Return(domain.Statement{}, nil)
+			}
+
+			services := &service.Services{Stats: tService, Accounts: aService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/accounts/:id/statement", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.getStatement)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET",
+				fmt.Sprintf("/accounts/%d/statement?dateFrom=2022-01-01&dateTo=2022-01-31%s", accountID, tt.query),
+				bytes.NewBufferString(""))
+
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+		})
+	}
+}
@@ -0,0 +1,506 @@
+package v1
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/export"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"github.com/lotostudio/financial-api/internal/service"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func (h *Handler) initStatsRoutes(api *gin.RouterGroup) {
+	stats := api.Group("/stats", h.userIdentity, h.limitUser)
+	{
+		stats.GET("/cashflow", h.cashFlow)
+		stats.GET("/net-worth", h.netWorth)
+		stats.GET("/statement", h.consolidatedStatement)
+		stats.GET("/trends", h.trends)
+		stats.GET("/forecast", h.forecast)
+		stats.GET("/annual/:year", h.annualReport)
+	}
+}
+
+// @Summary Cash flow
+// @Tags stats
+// @Description Sums of incomes, expenses and transfers by periods. Periods without transactions have zero sums.
+// @Description Transfers are incoming or outgoing relative to selected account or to all accounts of user
+// @ID cashFlow
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param from query string true "Start date (yyyy-MM-dd)"
+// @Param to query string true "End date (yyyy-MM-dd)"
+// @Param interval query string false "Grouping interval" Enums(day, week, month, year) default(month)
+// @Param accountId query int false "Id of account"
+// @Param category query string false "Category of transaction"
+// @Param type query string false "Type of transaction"
+// @Success 200 {array} domain.CashFlow "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /stats/cashflow [get]
+func (h *Handler) cashFlow(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	filter, err := h.parseStatsFilter(c)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filter.OwnerId = &userId
+
+	if filter.AccountId != nil && !h.checkAccountAccess(c, *filter.AccountId, userId) {
+		return
+	}
+
+	interval := domain.Interval(c.DefaultQuery("interval", string(domain.Month)))
+
+	flows, err := h.s.Stats.CashFlow(c.Request.Context(), filter, interval)
+
+	if errors.Is(err, domain.ErrInvalidInterval) || errors.Is(err, service.ErrStatsPeriodsLimited) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, flows)
+}
+
+// @Summary Net worth
+// @Tags stats
+// @Description Balances of user's accounts on last day of every period, carrying last known balance forward.
+// @Description Cash, card and deposit accounts are assets, loans are liabilities
+// @ID netWorth
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param from query string true "Start date (yyyy-MM-dd)"
+// @Param to query string true "End date (yyyy-MM-dd)"
+// @Param interval query string false "Grouping interval" Enums(day, week, month, year) default(month)
+// @Success 200 {array} domain.NetWorth "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 500 {object} response "Server error"
+// @Router /stats/net-worth [get]
+func (h *Handler) netWorth(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	from, to, err := parseStatsPeriod(c)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	interval := domain.Interval(c.DefaultQuery("interval", string(domain.Month)))
+
+	worths, err := h.s.Stats.NetWorth(c.Request.Context(), userId, from, to, interval)
+
+	if errors.Is(err, domain.ErrInvalidInterval) || errors.Is(err, service.ErrStatsPeriodsLimited) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, worths)
+}
+
+// @Summary Consolidated statement
+// @Tags stats
+// @Description Balances of several accounts and their transactions ordered by date. Accounts are selected by ids
+// @Description or by type and must have the same currency. Transfers between selected accounts are listed once
+// @ID consolidatedStatement
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param from query string true "Start date (yyyy-MM-dd)"
+// @Param to query string true "End date (yyyy-MM-dd)"
+// @Param accountId query []int false "Ids of accounts" collectionFormat(multi)
+// @Param accountType query string false "Type of accounts, used if ids are not passed" Enums(card, cash, loan, deposit)
+// @Param excludeInternal query bool false "Leave transfers between selected accounts out of totals"
+// @Success 200 {object} domain.ConsolidatedStatement "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /stats/statement [get]
+func (h *Handler) consolidatedStatement(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	from, to, err := parseStatsPeriod(c)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filter := domain.ConsolidatedFilter{
+		OwnerId:     userId,
+		CreatedFrom: from,
+		CreatedTo:   to,
+	}
+
+	for _, accountIdString := range c.QueryArray("accountId") {
+		accountId, err := strconv.ParseInt(accountIdString, 10, 64)
+
+		if err != nil {
+			newResponse(c, http.StatusBadRequest, "query param 'accountId' must be integer - "+err.Error())
+			return
+		}
+
+		filter.AccountIds = append(filter.AccountIds, accountId)
+	}
+
+	if accountType := domain.AccountType(c.Query("accountType")); accountType != "" && len(filter.AccountIds) == 0 {
+		filter.AccountType = &accountType
+	}
+
+	if excludeInternal := c.Query("excludeInternal"); excludeInternal != "" {
+		filter.ExcludeInternal, err = strconv.ParseBool(excludeInternal)
+
+		if err != nil {
+			newResponse(c, http.StatusBadRequest, "query param 'excludeInternal' must be boolean - "+err.Error())
+			return
+		}
+	}
+
+	stat, err := h.s.Stats.ConsolidatedStatement(c.Request.Context(), filter)
+
+	if errors.Is(err, service.ErrAccountForbidden) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if errors.Is(err, service.ErrNoAccountSelected) || errors.Is(err, service.ErrAccountsHaveDifferenceCurrencies) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, stat)
+}
+
+// @Summary Spending trends
+// @Tags stats
+// @Description Sums of categories for period compared with previous period of the same length and with the same
+// @Description period last year, with rolling monthly averages for 3, 6 and 12 months up to end of period
+// @ID trends
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param from query string true "Start date (yyyy-MM-dd)"
+// @Param to query string true "End date (yyyy-MM-dd)"
+// @Param accountId query int false "Id of account"
+// @Param category query string false "Category of transaction"
+// @Param type query string false "Type of transaction" Enums(income, expense, transfer) default(expense)
+// @Success 200 {array} domain.CategoryTrend "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /stats/trends [get]
+func (h *Handler) trends(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	filter, err := h.parseStatsFilter(c)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filter.OwnerId = &userId
+
+	if filter.Type == nil {
+		expense := domain.Expense
+		filter.Type = &expense
+	}
+
+	if filter.AccountId != nil && !h.checkAccountAccess(c, *filter.AccountId, userId) {
+		return
+	}
+
+	trends, err := h.s.Stats.Trends(c.Request.Context(), filter)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, trends)
+}
+
+// @Summary Forecast
+// @Tags stats
+// @Description Daily balances of accounts for next months projected from current balances, transactions repeating
+// @Description every month, loan payments and average daily expenses. Marks first day card or cash account goes negative
+// @ID forecast
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param months query int false "Count of months" minimum(1) maximum(12) default(3)
+// @Success 200 {object} domain.Forecast "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 500 {object} response "Server error"
+// @Router /stats/forecast [get]
+func (h *Handler) forecast(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	months, err := strconv.Atoi(c.DefaultQuery("months", "3"))
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "query param 'months' must be integer - "+err.Error())
+		return
+	}
+
+	forecast, err := h.s.Forecast.Forecast(c.Request.Context(), userId, time.Now(), months)
+
+	if errors.Is(err, service.ErrForecastMonthsInvalid) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, forecast)
+}
+
+// @Summary Annual report
+// @Tags stats
+// @Description Summary of year: incomes and expenses by categories and months, largest transactions, change of net
+// @Description worth, interest earned on deposits and paid on loans, savings rate. Downloadable as XLSX or PDF
+// @ID annualReport
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce application/pdf
+// @Param year path int true "Year"
+// @Param format query string false "Format of report" Enums(json, xlsx, pdf)
+// @Success 200 {object} domain.AnnualReport "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 406 {object} response "Unsupported content type"
+// @Failure 500 {object} response "Server error"
+// @Router /stats/annual/{year} [get]
+func (h *Handler) annualReport(c *gin.Context) {
+	format, err := exportFormat(c, export.XLSX, export.PDF)
+
+	if errors.Is(err, errFormatNotAcceptable) {
+		newResponse(c, http.StatusNotAcceptable, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	year, err := strconv.Atoi(c.Param("year"))
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'year' must be integer - "+err.Error())
+		return
+	}
+
+	report, err := h.s.Stats.AnnualReport(c.Request.Context(), userId, year)
+
+	if errors.Is(err, service.ErrReportYearInvalid) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if format == jsonFormat {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	var buf bytes.Buffer
+
+	if format == export.XLSX {
+		err = export.AnnualReportXLSX(&buf, report)
+	} else {
+		err = export.AnnualReportPDF(&buf, report)
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, export.AnnualReportFilename(report, format)))
+	c.Data(http.StatusOK, export.ContentTypes[format], buf.Bytes())
+}
+
+// Parse query params of stats to domain.TransactionsFilter. Period params 'from' and 'to' are required
+func (h *Handler) parseStatsFilter(c *gin.Context) (domain.TransactionsFilter, error) {
+	filter, err := h.parseTransactionsFilter(c)
+
+	if err != nil {
+		return filter, err
+	}
+
+	from, to, err := parseStatsPeriod(c)
+
+	if err != nil {
+		return filter, err
+	}
+
+	filter.CreatedFrom = &from
+	filter.CreatedTo = &to
+
+	if accountIdString := c.Query("accountId"); accountIdString != "" {
+		accountId, err := strconv.ParseInt(accountIdString, 10, 64)
+
+		if err != nil {
+			return filter, errors.New("query param 'accountId' must be integer - " + err.Error())
+		}
+
+		filter.AccountId = &accountId
+	}
+
+	return filter, nil
+}
+
+// Parse required query params 'from' and 'to' of stats period
+func parseStatsPeriod(c *gin.Context) (time.Time, time.Time, error) {
+	from, err := time.Parse(layout, c.Query("from"))
+
+	if err != nil {
+		return from, from, errStatsPeriodInvalid
+	}
+
+	to, err := time.Parse(layout, c.Query("to"))
+
+	if err != nil || to.Before(from) {
+		return from, to, errStatsPeriodInvalid
+	}
+
+	return from, to, nil
+}
+
+// checkAccountAccess checks that account exists and belongs to user. Writes error response otherwise
+func (h *Handler) checkAccountAccess(c *gin.Context, accountId int64, userId int64) bool {
+	_, err := h.s.Accounts.Get(c.Request.Context(), accountId, userId)
+
+	if errors.Is(err, service.ErrAccountForbidden) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return false
+	}
+
+	if errors.Is(err, repo.ErrAccountNotFound) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,764 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/service"
+	mockService "github.com/lotostudio/financial-api/internal/service/mocks"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestHandler_cashFlow(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockStats, a *mockService.MockAccounts)
+
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 2, 28, 0, 0, 0, 0, time.UTC)
+	ownerID := int64(userID)
+	accID := int64(accountID)
+
+	tests := []struct {
+		name                 string
+		query                string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "ok",
+			query: "?from=2022-01-01&to=2022-02-28",
+			mockBehaviour: func(s *mockService.MockStats, a *mockService.MockAccounts) {
+				s.EXPECT().CashFlow(context.Background(), domain.TransactionsFilter{
+					OwnerId:     &ownerID,
+					CreatedFrom: &from,
+					CreatedTo:   &to,
+				}, domain.Month).Return([]domain.CashFlow{
+					{Period: from, Income: 100, Expense: 40, Net: 60},
+					{Period: from.AddDate(0, 1, 0)},
+				}, nil)
+			},
+			expectedCodeStatus: 200,
+			expectedResponseBody: `[{"period":"2022-01-01T00:00:00Z","income":100,"expense":40,"transferIn":0,"transferOut":0,"net":60},` +
+				`{"period":"2022-02-01T00:00:00Z","income":0,"expense":0,"transferIn":0,"transferOut":0,"net":0}]`,
+		},
+		{
+			name:  "ok by account",
+			query: "?from=2022-01-01&to=2022-02-28&interval=week&accountId=2",
+			mockBehaviour: func(s *mockService.MockStats, a *mockService.MockAccounts) {
+				a.EXPECT().Get(context.Background(), accID, ownerID).Return(domain.Account{}, nil)
+				s.EXPECT().CashFlow(context.Background(), domain.TransactionsFilter{
+					OwnerId:     &ownerID,
+					AccountId:   &accID,
+					CreatedFrom: &from,
+					CreatedTo:   &to,
+				}, domain.Week).Return([]domain.CashFlow{}, nil)
+			},
+			expectedCodeStatus:   200,
+			expectedResponseBody: `[]`,
+		},
+		{
+			name:                 "no period",
+			query:                "?from=2022-01-01",
+			mockBehaviour:        func(s *mockService.MockStats, a *mockService.MockAccounts) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"period is invalid. check 'from' and 'to' params"}`,
+		},
+		{
+			name:                 "reversed period",
+			query:                "?from=2022-03-01&to=2022-02-28",
+			mockBehaviour:        func(s *mockService.MockStats, a *mockService.MockAccounts) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"period is invalid. check 'from' and 'to' params"}`,
+		},
+		{
+			name:  "account forbidden",
+			query: "?from=2022-01-01&to=2022-02-28&accountId=2",
+			mockBehaviour: func(s *mockService.MockStats, a *mockService.MockAccounts) {
+				a.EXPECT().Get(context.Background(), accID, ownerID).Return(domain.Account{}, service.ErrAccountForbidden)
+			},
+			expectedCodeStatus:   403,
+			expectedResponseBody: `{"message":"account forbidden to access"}`,
+		},
+		{
+			name:  "invalid interval",
+			query: "?from=2022-01-01&to=2022-02-28&interval=hour",
+			mockBehaviour: func(s *mockService.MockStats, a *mockService.MockAccounts) {
+				s.EXPECT().CashFlow(context.Background(), gomock.Any(), domain.Interval("hour")).Return(nil,
+					domain.ErrInvalidInterval)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"invalid interval, use one of: day, week, month, year"}`,
+		},
+		{
+			name:  "too many periods",
+			query: "?from=2000-01-01&to=2022-02-28&interval=day",
+			mockBehaviour: func(s *mockService.MockStats, a *mockService.MockAccounts) {
+				s.EXPECT().CashFlow(context.Background(), gomock.Any(), domain.Day).Return(nil,
+					service.ErrStatsPeriodsLimited)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"too many periods, use longer interval or shorter date range"}`,
+		},
+		{
+			name:  "error",
+			query: "?from=2022-01-01&to=2022-02-28",
+			mockBehaviour: func(s *mockService.MockStats, a *mockService.MockAccounts) {
+				s.EXPECT().CashFlow(context.Background(), gomock.Any(), domain.Month).Return(nil,
+					errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			sService := mockService.NewMockStats(c)
+			aService := mockService.NewMockAccounts(c)
+			tt.mockBehaviour(sService, aService)
+
+			services := &service.Services{Stats: sService, Accounts: aService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/stats/cashflow", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.cashFlow)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/stats/cashflow"+tt.query, bytes.NewBufferString(""))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_netWorth(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockStats)
+
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 2, 28, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		query                string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "ok",
+			query: "?from=2022-01-01&to=2022-02-28",
+			mockBehaviour: func(s *mockService.MockStats) {
+				s.EXPECT().NetWorth(context.Background(), int64(userID), from, to, domain.Month).Return([]domain.NetWorth{
+					{
+						Date:        time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC),
+						Assets:      1000,
+						Liabilities: 400,
+						Total:       600,
+						Types:       map[domain.AccountType]float64{domain.Card: 1000, domain.Loan: 400},
+					},
+				}, nil)
+			},
+			expectedCodeStatus: 200,
+			expectedResponseBody: `[{"date":"2022-01-31T00:00:00Z","assets":1000,"liabilities":400,"total":600,` +
+				`"types":{"card":1000,"loan":400}}]`,
+		},
+		{
+			name:                 "no period",
+			query:                "?to=2022-02-28",
+			mockBehaviour:        func(s *mockService.MockStats) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"period is invalid. check 'from' and 'to' params"}`,
+		},
+		{
+			name:  "invalid interval",
+			query: "?from=2022-01-01&to=2022-02-28&interval=hour",
+			mockBehaviour: func(s *mockService.MockStats) {
+				s.EXPECT().NetWorth(context.Background(), int64(userID), from, to, domain.Interval("hour")).Return(nil,
+					domain.ErrInvalidInterval)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"invalid interval, use one of: day, week, month, year"}`,
+		},
+		{
+			name:  "too many periods",
+			query: "?from=2022-01-01&to=2022-02-28&interval=day",
+			mockBehaviour: func(s *mockService.MockStats) {
+				s.EXPECT().NetWorth(context.Background(), int64(userID), from, to, domain.Day).Return(nil,
+					service.ErrStatsPeriodsLimited)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"too many periods, use longer interval or shorter date range"}`,
+		},
+		{
+			name:  "error",
+			query: "?from=2022-01-01&to=2022-02-28&interval=week",
+			mockBehaviour: func(s *mockService.MockStats) {
+				s.EXPECT().NetWorth(context.Background(), int64(userID), from, to, domain.Week).Return(nil,
+					errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			sService := mockService.NewMockStats(c)
+			tt.mockBehaviour(sService)
+
+			services := &service.Services{Stats: sService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/stats/net-worth", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.netWorth)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/stats/net-worth"+tt.query, bytes.NewBufferString(""))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_consolidatedStatement(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockStats)
+
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC)
+	card := domain.Card
+
+	tests := []struct {
+		name                 string
+		query                string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "ok by ids",
+			query: "?from=2022-01-01&to=2022-01-31&accountId=2&accountId=5&accountType=card&excludeInternal=true",
+			mockBehaviour: func(s *mockService.MockStats) {
+				s.EXPECT().ConsolidatedStatement(context.Background(), domain.ConsolidatedFilter{
+					OwnerId:         userID,
+					AccountIds:      []int64{2, 5},
+					CreatedFrom:     from,
+					CreatedTo:       to,
+					ExcludeInternal: true,
+				}).Return(domain.ConsolidatedStatement{
+					Accounts:     []domain.AccountBalances{},
+					BalanceIn:    100,
+					BalanceOut:   100,
+					Transactions: []domain.ConsolidatedTransaction{},
+					Reconciled:   true,
+				}, nil)
+			},
+			expectedCodeStatus: 200,
+			expectedResponseBody: `{"accounts":[],"balanceIn":100,"balanceOut":100,"transactions":[],` +
+				`"totals":{"income":0,"expense":0,"transferIn":0,"transferOut":0},"reconciled":true,"discrepancy":0}`,
+		},
+		{
+			name:  "ok by type",
+			query: "?from=2022-01-01&to=2022-01-31&accountType=card",
+			mockBehaviour: func(s *mockService.MockStats) {
+				s.EXPECT().ConsolidatedStatement(context.Background(), domain.ConsolidatedFilter{
+					OwnerId:     userID,
+					AccountType: &card,
+					CreatedFrom: from,
+					CreatedTo:   to,
+				}).Return(domain.ConsolidatedStatement{}, nil)
+			},
+			expectedCodeStatus: 200,
+			expectedResponseBody: `{"accounts":null,"balanceIn":0,"balanceOut":0,"transactions":null,` +
+				`"totals":{"income":0,"expense":0,"transferIn":0,"transferOut":0},"reconciled":false,"discrepancy":0}`,
+		},
+		{
+			name:                 "invalid account id",
+			query:                "?from=2022-01-01&to=2022-01-31&accountId=two",
+			mockBehaviour:        func(s *mockService.MockStats) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"query param 'accountId' must be integer - strconv.ParseInt: parsing \"two\": invalid syntax"}`,
+		},
+		{
+			name:                 "invalid exclude internal",
+			query:                "?from=2022-01-01&to=2022-01-31&accountId=2&excludeInternal=maybe",
+			mockBehaviour:        func(s *mockService.MockStats) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"query param 'excludeInternal' must be boolean - strconv.ParseBool: parsing \"maybe\": invalid syntax"}`,
+		},
+		{
+			name:                 "no period",
+			query:                "?accountId=2",
+			mockBehaviour:        func(s *mockService.MockStats) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"period is invalid. check 'from' and 'to' params"}`,
+		},
+		{
+			name:  "no accounts",
+			query: "?from=2022-01-01&to=2022-01-31",
+			mockBehaviour: func(s *mockService.MockStats) {
+				s.EXPECT().ConsolidatedStatement(context.Background(), gomock.Any()).Return(domain.ConsolidatedStatement{},
+					service.ErrNoAccountSelected)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"no account selected"}`,
+		},
+		{
+			name:  "different currencies",
+			query: "?from=2022-01-01&to=2022-01-31&accountId=2&accountId=5",
+			mockBehaviour: func(s *mockService.MockStats) {
+				s.EXPECT().ConsolidatedStatement(context.Background(), gomock.Any()).Return(domain.ConsolidatedStatement{},
+					service.ErrAccountsHaveDifferenceCurrencies)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"accounts have different currencies"}`,
+		},
+		{
+			name:  "forbidden",
+			query: "?from=2022-01-01&to=2022-01-31&accountId=2",
+			mockBehaviour: func(s *mockService.MockStats) {
+				s.EXPECT().ConsolidatedStatement(context.Background(), gomock.Any()).Return(domain.ConsolidatedStatement{},
+					service.ErrAccountForbidden)
+			},
+			expectedCodeStatus:   403,
+			expectedResponseBody: `{"message":"account forbidden to access"}`,
+		},
+		{
+			name:  "error",
+			query: "?from=2022-01-01&to=2022-01-31&accountId=2",
+			mockBehaviour: func(s *mockService.MockStats) {
+				s.EXPECT().ConsolidatedStatement(context.Background(), gomock.Any()).Return(domain.ConsolidatedStatement{},
+					errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			sService := mockService.NewMockStats(c)
+			tt.mockBehaviour(sService)
+
+			services := &service.Services{Stats: sService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/stats/statement", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.consolidatedStatement)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/stats/statement"+tt.query, bytes.NewBufferString(""))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_trends(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockStats, a *mockService.MockAccounts)
+
+	from := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 3, 31, 0, 0, 0, 0, time.UTC)
+	ownerID := int64(userID)
+	accID := int64(accountID)
+	expense := domain.Expense
+	income := domain.Income
+	percent := 23.0
+
+	tests := []struct {
+		name                 string
+		query                string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "ok",
+			query: "?from=2022-03-01&to=2022-03-31",
+			mockBehaviour: func(s *mockService.MockStats, a *mockService.MockAccounts) {
+				s.EXPECT().Trends(context.Background(), domain.TransactionsFilter{
+					OwnerId:     &ownerID,
+					Type:        &expense,
+					CreatedFrom: &from,
+					CreatedTo:   &to,
+				}).Return([]domain.CategoryTrend{
+					{Category: "food", Current: 123, Previous: 100, Change: 23, ChangePercent: &percent},
+				}, nil)
+			},
+			expectedCodeStatus: 200,
+			expectedResponseBody: `[{"category":"food","current":123,"previous":100,"change":23,"changePercent":23,` +
+				`"lastYear":0,"lastYearChange":0,"avg3Months":0,"avg6Months":0,"avg12Months":0}]`,
+		},
+		{
+			name:  "ok by account and type",
+			query: "?from=2022-03-01&to=2022-03-31&accountId=2&type=income",
+			mockBehaviour: func(s *mockService.MockStats, a *mockService.MockAccounts) {
+				a.EXPECT().Get(context.Background(), accID, ownerID).Return(domain.Account{}, nil)
+				s.EXPECT().Trends(context.Background(), domain.TransactionsFilter{
+					OwnerId:     &ownerID,
+					AccountId:   &accID,
+					Type:        &income,
+					CreatedFrom: &from,
+					CreatedTo:   &to,
+				}).Return([]domain.CategoryTrend{}, nil)
+			},
+			expectedCodeStatus:   200,
+			expectedResponseBody: `[]`,
+		},
+		{
+			name:                 "no period",
+			query:                "?from=2022-03-01",
+			mockBehaviour:        func(s *mockService.MockStats, a *mockService.MockAccounts) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"period is invalid. check 'from' and 'to' params"}`,
+		},
+		{
+			name:  "account forbidden",
+			query: "?from=2022-03-01&to=2022-03-31&accountId=2",
+			mockBehaviour: func(s *mockService.MockStats, a *mockService.MockAccounts) {
+				a.EXPECT().Get(context.Background(), accID, ownerID).Return(domain.Account{}, service.ErrAccountForbidden)
+			},
+			expectedCodeStatus:   403,
+			expectedResponseBody: `{"message":"account forbidden to access"}`,
+		},
+		{
+			name:  "error",
+			query: "?from=2022-03-01&to=2022-03-31",
+			mockBehaviour: func(s *mockService.MockStats, a *mockService.MockAccounts) {
+				s.EXPECT().Trends(context.Background(), gomock.Any()).Return(nil, errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			sService := mockService.NewMockStats(c)
+			aService := mockService.NewMockAccounts(c)
+			tt.mockBehaviour(sService, aService)
+
+			services := &service.Services{Stats: sService, Accounts: aService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/stats/trends", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.trends)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/stats/trends"+tt.query, bytes.NewBufferString(""))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_forecast(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockForecast)
+
+	tests := []struct {
+		name                 string
+		query                string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "ok",
+			query: "",
+			mockBehaviour: func(s *mockService.MockForecast) {
+				s.EXPECT().Forecast(context.Background(), int64(userID), gomock.Any(), 3).Return(domain.Forecast{
+					Recurring: []domain.RecurringTransaction{},
+					Accounts:  []domain.AccountForecast{},
+				}, nil)
+			},
+			expectedCodeStatus:   200,
+			expectedResponseBody: `{"recurring":[],"accounts":[]}`,
+		},
+		{
+			name:  "ok with months",
+			query: "?months=6",
+			mockBehaviour: func(s *mockService.MockForecast) {
+				s.EXPECT().Forecast(context.Background(), int64(userID), gomock.Any(), 6).Return(domain.Forecast{
+					Recurring: []domain.RecurringTransaction{},
+					Accounts:  []domain.AccountForecast{},
+				}, nil)
+			},
+			expectedCodeStatus:   200,
+			expectedResponseBody: `{"recurring":[],"accounts":[]}`,
+		},
+		{
+			name:                 "invalid months",
+			query:                "?months=a",
+			mockBehaviour:        func(s *mockService.MockForecast) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"query param 'months' must be integer - strconv.Atoi: parsing \"a\": invalid syntax"}`,
+		},
+		{
+			name:  "months out of range",
+			query: "?months=13",
+			mockBehaviour: func(s *mockService.MockForecast) {
+				s.EXPECT().Forecast(context.Background(), int64(userID), gomock.Any(), 13).
+					Return(domain.Forecast{}, service.ErrForecastMonthsInvalid)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"months of forecast must be from 1 to 12"}`,
+		},
+		{
+			name:  "error",
+			query: "",
+			mockBehaviour: func(s *mockService.MockForecast) {
+				s.EXPECT().Forecast(context.Background(), int64(userID), gomock.Any(), 3).
+					Return(domain.Forecast{}, errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			fService := mockService.NewMockForecast(c)
+			tt.mockBehaviour(fService)
+
+			services := &service.Services{Forecast: fService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/stats/forecast", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.forecast)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/stats/forecast"+tt.query, bytes.NewBufferString(""))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_annualReport(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockStats)
+
+	report := domain.AnnualReport{
+		Year:                2021,
+		IncomeByCategory:    []domain.TransactionStat{},
+		ExpenseByCategory:   []domain.TransactionStat{},
+		Months:              []domain.CashFlow{},
+		LargestTransactions: []domain.Transaction{},
+		Interest:            []domain.AccountInterest{},
+	}
+
+	tests := []struct {
+		name                 string
+		year                 string
+		query                string
+		accept               string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedContentType  string
+		expectedResponseBody string
+	}{
+		{
+			name: "ok",
+			year: "2021",
+			mockBehaviour: func(s *mockService.MockStats) {
+				s.EXPECT().AnnualReport(context.Background(), int64(userID), 2021).Return(report, nil)
+			},
+			expectedCodeStatus:  200,
+			expectedContentType: "application/json; charset=utf-8",
+			expectedResponseBody: `{"year":2021,"income":0,"expense":0,"incomeByCategory":[],"expenseByCategory":[],` +
+				`"months":[],"largestTransactions":[],` +
+				`"netWorthStart":{"date":"0001-01-01T00:00:00Z","assets":0,"liabilities":0,"total":0,"types":null},` +
+				`"netWorthEnd":{"date":"0001-01-01T00:00:00Z","assets":0,"liabilities":0,"total":0,"types":null},` +
+				`"netWorthChange":0,"interestEarned":0,"interestPaid":0,"interest":[]}`,
+		},
+		{
+			name:  "xlsx",
+			year:  "2021",
+			query: "?format=xlsx",
+			mockBehaviour: func(s *mockService.MockStats) {
+				s.EXPECT().AnnualReport(context.Background(), int64(userID), 2021).Return(report, nil)
+			},
+			expectedCodeStatus:  200,
+			expectedContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		},
+		{
+			name:   "pdf header",
+			year:   "2021",
+			accept: "application/pdf",
+			mockBehaviour: func(s *mockService.MockStats) {
+				s.EXPECT().AnnualReport(context.Background(), int64(userID), 2021).Return(report, nil)
+			},
+			expectedCodeStatus:  200,
+			expectedContentType: "application/pdf",
+		},
+		{
+			name:                 "unsupported format",
+			year:                 "2021",
+			query:                "?format=csv",
+			mockBehaviour:        func(s *mockService.MockStats) {},
+			expectedCodeStatus:   400,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"message":"invalid format, use one of: json, xlsx, pdf"}`,
+		},
+		{
+			name:                 "not acceptable",
+			year:                 "2021",
+			accept:               "text/csv",
+			mockBehaviour:        func(s *mockService.MockStats) {},
+			expectedCodeStatus:   406,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"message":"none of accepted content types is supported"}`,
+		},
+		{
+			name:                 "invalid year",
+			year:                 "last",
+			mockBehaviour:        func(s *mockService.MockStats) {},
+			expectedCodeStatus:   400,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"message":"path param 'year' must be integer - strconv.Atoi: parsing \"last\": invalid syntax"}`,
+		},
+		{
+			name: "year out of range",
+			year: "0",
+			mockBehaviour: func(s *mockService.MockStats) {
+				s.EXPECT().AnnualReport(context.Background(), int64(userID), 0).
+					Return(domain.AnnualReport{}, service.ErrReportYearInvalid)
+			},
+			expectedCodeStatus:   400,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"message":"year of report is invalid"}`,
+		},
+		{
+			name: "error",
+			year: "2021",
+			mockBehaviour: func(s *mockService.MockStats) {
+				s.EXPECT().AnnualReport(context.Background(), int64(userID), 2021).
+					Return(domain.AnnualReport{}, errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			sService := mockService.NewMockStats(c)
+			tt.mockBehaviour(sService)
+
+			services := &service.Services{Stats: sService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/stats/annual/:year", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.annualReport)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/stats/annual/"+tt.year+tt.query, bytes.NewBufferString(""))
+
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+
+			if tt.expectedResponseBody != "" {
+				assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+			}
+		})
+	}
+}
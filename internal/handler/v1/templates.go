@@ -0,0 +1,370 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"github.com/lotostudio/financial-api/internal/service"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handler) initTemplatesRoutes(api *gin.RouterGroup) {
+	templates := api.Group("/templates", h.userIdentity, h.limitUser)
+	{
+		templates.GET("", h.listTemplates)
+		templates.POST("", h.createTemplate)
+		templates.GET("/:id", h.getTemplate)
+		templates.PUT("/:id", h.updateTemplate)
+		templates.DELETE("/:id", h.deleteTemplate)
+		templates.POST("/:id/apply", h.applyTemplate)
+	}
+}
+
+// @Summary List templates
+// @Tags templates
+// @Description List transaction templates of user by name
+// @ID listTemplates
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Success 200 {array} domain.TransactionTemplate "Operation finished successfully"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 500 {object} response "Server error"
+// @Router /templates [get]
+func (h *Handler) listTemplates(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	templates, err := h.s.TransactionTemplates.List(c.Request.Context(), userId)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// @Summary Create template
+// @Tags templates
+// @Description Create transaction template with default category, accounts, amount and tags. Defaults may be left
+// @Description empty and given when template is applied
+// @ID createTemplate
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param input body domain.TransactionTemplateToCreate true "Template info"
+// @Success 201 {object} domain.TransactionTemplate "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /templates [post]
+func (h *Handler) createTemplate(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	var toCreate domain.TransactionTemplateToCreate
+
+	if err = c.ShouldBindJSON(&toCreate); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid request body - "+err.Error())
+		return
+	}
+
+	template, err := h.s.TransactionTemplates.Create(c.Request.Context(), toCreate, userId)
+
+	if errors.Is(err, repo.ErrTransactionTemplateAlreadyExists) || errors.Is(err, repo.ErrAccountNotFound) ||
+		errors.Is(err, repo.ErrTransactionCategoryNotFound) ||
+		errors.Is(err, service.ErrTransactionAndCategoryTypesMismatch) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, service.ErrCreditAccountForbidden) || errors.Is(err, service.ErrDebitAccountForbidden) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// @Summary Retrieve template
+// @Tags templates
+// @Description Retrieve transaction template
+// @ID getTemplate
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Id of template"
+// @Success 200 {object} domain.TransactionTemplate "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /templates/{id} [get]
+func (h *Handler) getTemplate(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'id' must be integer - "+err.Error())
+		return
+	}
+
+	template, err := h.s.TransactionTemplates.Get(c.Request.Context(), id, userId)
+
+	if errors.Is(err, repo.ErrTransactionTemplateNotFound) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, service.ErrTransactionTemplateForbidden) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// @Summary Update template
+// @Tags templates
+// @Description Replace transaction template
+// @ID updateTemplate
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Id of template"
+// @Param input body domain.TransactionTemplateToCreate true "Template info"
+// @Success 200 {object} domain.TransactionTemplate "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /templates/{id} [put]
+func (h *Handler) updateTemplate(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'id' must be integer - "+err.Error())
+		return
+	}
+
+	var toUpdate domain.TransactionTemplateToCreate
+
+	if err = c.ShouldBindJSON(&toUpdate); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid request body - "+err.Error())
+		return
+	}
+
+	template, err := h.s.TransactionTemplates.Update(c.Request.Context(), id, toUpdate, userId)
+
+	if errors.Is(err, repo.ErrTransactionTemplateNotFound) || errors.Is(err, repo.ErrTransactionTemplateAlreadyExists) ||
+		errors.Is(err, repo.ErrAccountNotFound) || errors.Is(err, repo.ErrTransactionCategoryNotFound) ||
+		errors.Is(err, service.ErrTransactionAndCategoryTypesMismatch) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, service.ErrTransactionTemplateForbidden) || errors.Is(err, service.ErrCreditAccountForbidden) ||
+		errors.Is(err, service.ErrDebitAccountForbidden) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// @Summary Delete template
+// @Tags templates
+// @Description Delete transaction template. Transactions created from it are kept
+// @ID deleteTemplate
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Id of template"
+// @Success 204 {null} nil "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /templates/{id} [delete]
+func (h *Handler) deleteTemplate(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'id' must be integer - "+err.Error())
+		return
+	}
+
+	err = h.s.TransactionTemplates.Delete(c.Request.Context(), id, userId)
+
+	if errors.Is(err, repo.ErrTransactionTemplateNotFound) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, service.ErrTransactionTemplateForbidden) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Apply template
+// @Tags templates
+// @Description Create transaction from template. Given values override defaults of template, transaction is dated
+// @Description today unless date is given. Transaction is checked as in creation of transaction
+// @ID applyTemplate
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Id of template"
+// @Param input body domain.TransactionTemplateToApply false "Overrides of template"
+// @Success 201 {object} domain.Transaction "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /templates/{id}/apply [post]
+func (h *Handler) applyTemplate(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'id' must be integer - "+err.Error())
+		return
+	}
+
+	var toApply domain.TransactionTemplateToApply
+
+	// Template is applied as is without body
+	if c.Request.ContentLength != 0 {
+		if err = c.ShouldBindJSON(&toApply); err != nil {
+			newResponse(c, http.StatusBadRequest, "invalid request body - "+err.Error())
+			return
+		}
+	}
+
+	transaction, err := h.s.TransactionTemplates.Apply(c.Request.Context(), id, toApply, userId)
+
+	if errors.Is(err, repo.ErrTransactionTemplateNotFound) || errors.Is(err, service.ErrTemplateAmountMissing) ||
+		errors.Is(err, service.ErrTransactionAndCategoryTypesMismatch) || errors.Is(err, service.ErrNoCategorySelected) ||
+		errors.Is(err, repo.ErrTransactionCategoryNotFound) || errors.Is(err, service.ErrNoAccountSelected) ||
+		errors.Is(err, repo.ErrAccountNotFound) || errors.Is(err, service.ErrAccountsHaveDifferenceCurrencies) ||
+		errors.Is(err, repo.ErrAccountNotEnoughBalance) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, service.ErrTransactionTemplateForbidden) || errors.Is(err, service.ErrCreditAccountForbidden) ||
+		errors.Is(err, service.ErrDebitAccountForbidden) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, transaction)
+}
@@ -0,0 +1,384 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"github.com/lotostudio/financial-api/internal/service"
+	mockService "github.com/lotostudio/financial-api/internal/service/mocks"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const templateID = int64(7)
+
+func TestHandler_createTemplate(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockTransactionTemplates)
+
+	createdAt := time.Date(2022, 4, 2, 10, 0, 0, 0, time.UTC)
+	amount := 950.0
+	creditId := int64(1)
+
+	tests := []struct {
+		name                 string
+		body                 string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name: "ok",
+			body: `{"name":"Coffee","type":"expense","amount":950,"creditId":1,"tags":["coffee"]}`,
+			mockBehaviour: func(s *mockService.MockTransactionTemplates) {
+				s.EXPECT().Create(context.Background(), domain.TransactionTemplateToCreate{Name: "Coffee",
+					Type: domain.Expense, Amount: &amount, CreditId: &creditId, Tags: []string{"coffee"}}, userID).
+					Return(domain.TransactionTemplate{ID: templateID, Name: "Coffee", Type: domain.Expense,
+						Amount: &amount, CreditId: &creditId, Tags: []string{"coffee"}, CreatedAt: createdAt}, nil)
+			},
+			expectedCodeStatus: 201,
+			expectedResponseBody: `{"id":7,"name":"Coffee","type":"expense","amount":950,"creditId":1,` +
+				`"tags":["coffee"],"createdAt":"2022-04-02T10:00:00Z"}`,
+		},
+		{
+			name:                 "invalid body",
+			body:                 `{`,
+			mockBehaviour:        func(s *mockService.MockTransactionTemplates) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"invalid request body - unexpected EOF"}`,
+		},
+		{
+			name: "already exists",
+			body: `{"name":"Coffee","type":"expense"}`,
+			mockBehaviour: func(s *mockService.MockTransactionTemplates) {
+				s.EXPECT().Create(context.Background(), gomock.Any(), userID).
+					Return(domain.TransactionTemplate{}, repo.ErrTransactionTemplateAlreadyExists)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"transaction template with this name already exists"}`,
+		},
+		{
+			name: "account forbidden",
+			body: `{"name":"Coffee","type":"expense","creditId":1}`,
+			mockBehaviour: func(s *mockService.MockTransactionTemplates) {
+				s.EXPECT().Create(context.Background(), gomock.Any(), userID).
+					Return(domain.TransactionTemplate{}, service.ErrCreditAccountForbidden)
+			},
+			expectedCodeStatus:   403,
+			expectedResponseBody: `{"message":"` + service.ErrCreditAccountForbidden.Error() + `"}`,
+		},
+		{
+			name: "error",
+			body: `{"name":"Coffee","type":"expense"}`,
+			mockBehaviour: func(s *mockService.MockTransactionTemplates) {
+				s.EXPECT().Create(context.Background(), gomock.Any(), userID).
+					Return(domain.TransactionTemplate{}, errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			ttService := mockService.NewMockTransactionTemplates(c)
+			tt.mockBehaviour(ttService)
+
+			services := &service.Services{TransactionTemplates: ttService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/templates", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.createTemplate)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/templates", bytes.NewBufferString(tt.body))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getTemplate(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockTransactionTemplates)
+
+	createdAt := time.Date(2022, 4, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		id                   string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name: "ok",
+			id:   strconv.FormatInt(templateID, 10),
+			mockBehaviour: func(s *mockService.MockTransactionTemplates) {
+				s.EXPECT().Get(context.Background(), templateID, userID).Return(domain.TransactionTemplate{
+					ID: templateID, Name: "Bus", Type: domain.Expense, Tags: []string{}, CreatedAt: createdAt}, nil)
+			},
+			expectedCodeStatus: 200,
+			expectedResponseBody: `{"id":7,"name":"Bus","type":"expense","tags":[],` +
+				`"createdAt":"2022-04-02T10:00:00Z"}`,
+		},
+		{
+			name:                 "invalid id",
+			id:                   "a",
+			mockBehaviour:        func(s *mockService.MockTransactionTemplates) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"path param 'id' must be integer - strconv.ParseInt: parsing \"a\": invalid syntax"}`,
+		},
+		{
+			name: "not found",
+			id:   strconv.FormatInt(templateID, 10),
+			mockBehaviour: func(s *mockService.MockTransactionTemplates) {
+				s.EXPECT().Get(context.Background(), templateID, userID).
+					Return(domain.TransactionTemplate{}, repo.ErrTransactionTemplateNotFound)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"transaction template doesn't exists"}`,
+		},
+		{
+			name: "forbidden",
+			id:   strconv.FormatInt(templateID, 10),
+			mockBehaviour: func(s *mockService.MockTransactionTemplates) {
+				s.EXPECT().Get(context.Background(), templateID, userID).
+					Return(domain.TransactionTemplate{}, service.ErrTransactionTemplateForbidden)
+			},
+			expectedCodeStatus:   403,
+			expectedResponseBody: `{"message":"transaction template forbidden to access"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			ttService := mockService.NewMockTransactionTemplates(c)
+			tt.mockBehaviour(ttService)
+
+			services := &service.Services{TransactionTemplates: ttService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/templates/:id", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.getTemplate)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/templates/"+tt.id, nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_deleteTemplate(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockTransactionTemplates)
+
+	tests := []struct {
+		name                 string
+		id                   string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name: "ok",
+			id:   strconv.FormatInt(templateID, 10),
+			mockBehaviour: func(s *mockService.MockTransactionTemplates) {
+				s.EXPECT().Delete(context.Background(), templateID, userID).Return(nil)
+			},
+			expectedCodeStatus:   204,
+			expectedResponseBody: ``,
+		},
+		{
+			name: "forbidden",
+			id:   strconv.FormatInt(templateID, 10),
+			mockBehaviour: func(s *mockService.MockTransactionTemplates) {
+				s.EXPECT().Delete(context.Background(), templateID, userID).
+					Return(service.ErrTransactionTemplateForbidden)
+			},
+			expectedCodeStatus:   403,
+			expectedResponseBody: `{"message":"transaction template forbidden to access"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			ttService := mockService.NewMockTransactionTemplates(c)
+			tt.mockBehaviour(ttService)
+
+			services := &service.Services{TransactionTemplates: ttService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.DELETE("/templates/:id", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.deleteTemplate)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", "/templates/"+tt.id, nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_applyTemplate(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockTransactionTemplates)
+
+	amount := 1100.0
+	createdAt := time.Date(2022, 4, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		id                   string
+		body                 string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name: "without body",
+			id:   strconv.FormatInt(templateID, 10),
+			mockBehaviour: func(s *mockService.MockTransactionTemplates) {
+				s.EXPECT().Apply(context.Background(), templateID, domain.TransactionTemplateToApply{}, userID).
+					Return(domain.Transaction{ID: transactionID, Amount: 950, Type: domain.Expense,
+						CreatedAt: createdAt, Status: domain.Cleared}, nil)
+			},
+			expectedCodeStatus: 201,
+			expectedResponseBody: `{"id":5,"amount":950,"type":"expense","createdAt":"2022-04-02T00:00:00Z",` +
+				`"status":"cleared"}`,
+		},
+		{
+			name: "with overrides",
+			id:   strconv.FormatInt(templateID, 10),
+			body: `{"amount":1100}`,
+			mockBehaviour: func(s *mockService.MockTransactionTemplates) {
+				s.EXPECT().Apply(context.Background(), templateID, domain.TransactionTemplateToApply{Amount: &amount},
+					userID).Return(domain.Transaction{ID: transactionID, Amount: amount, Type: domain.Expense,
+					CreatedAt: createdAt, Status: domain.Cleared}, nil)
+			},
+			expectedCodeStatus: 201,
+			expectedResponseBody: `{"id":5,"amount":1100,"type":"expense","createdAt":"2022-04-02T00:00:00Z",` +
+				`"status":"cleared"}`,
+		},
+		{
+			name:                 "invalid body",
+			id:                   strconv.FormatInt(templateID, 10),
+			body:                 `{`,
+			mockBehaviour:        func(s *mockService.MockTransactionTemplates) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"invalid request body - unexpected EOF"}`,
+		},
+		{
+			name: "amount missing",
+			id:   strconv.FormatInt(templateID, 10),
+			mockBehaviour: func(s *mockService.MockTransactionTemplates) {
+				s.EXPECT().Apply(context.Background(), templateID, domain.TransactionTemplateToApply{}, userID).
+					Return(domain.Transaction{}, service.ErrTemplateAmountMissing)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"amount is set neither in template nor in request"}`,
+		},
+		{
+			name: "forbidden",
+			id:   strconv.FormatInt(templateID, 10),
+			mockBehaviour: func(s *mockService.MockTransactionTemplates) {
+				s.EXPECT().Apply(context.Background(), templateID, domain.TransactionTemplateToApply{}, userID).
+					Return(domain.Transaction{}, service.ErrTransactionTemplateForbidden)
+			},
+			expectedCodeStatus:   403,
+			expectedResponseBody: `{"message":"transaction template forbidden to access"}`,
+		},
+		{
+			name: "error",
+			id:   strconv.FormatInt(templateID, 10),
+			mockBehaviour: func(s *mockService.MockTransactionTemplates) {
+				s.EXPECT().Apply(context.Background(), templateID, domain.TransactionTemplateToApply{}, userID).
+					Return(domain.Transaction{}, errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			ttService := mockService.NewMockTransactionTemplates(c)
+			tt.mockBehaviour(ttService)
+
+			services := &service.Services{TransactionTemplates: ttService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/templates/:id/apply", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.applyTemplate)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/templates/"+tt.id+"/apply", bytes.NewBufferString(tt.body))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"github.com/lotostudio/financial-api/internal/service"
+	"net/http"
+	"strconv"
+)
+
+// @Summary List access tokens
+// @Tags users
+// @Description List personal access tokens of authorized user
+// @ID listAccessTokens
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Success 200 {array} domain.AccessToken "Operation finished successfully"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 500 {object} response "Server error"
+// @Router /users/me/tokens [get]
+func (h *Handler) listAccessTokens(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	tokens, err := h.s.AccessTokens.List(c.Request.Context(), userId)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// @Summary Create access token
+// @Tags users
+// @Description Create personal access token for scripts and integrations.
+// @Description Raw value of token is returned only once
+// @ID createAccessToken
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param input body domain.AccessTokenToCreate true "Token info"
+// @Success 201 {object} domain.CreatedAccessToken "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 500 {object} response "Server error"
+// @Router /users/me/tokens [post]
+func (h *Handler) createAccessToken(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	var toCreate domain.AccessTokenToCreate
+
+	if err = c.ShouldBindJSON(&toCreate); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid request body - "+err.Error())
+		return
+	}
+
+	token, err := h.s.AccessTokens.Create(c.Request.Context(), toCreate, userId)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, token)
+}
+
+// @Summary Delete access token
+// @Tags users
+// @Description Revoke personal access token of authorized user
+// @ID deleteAccessToken
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Id of access token"
+// @Success 204 {null} nil "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /users/me/tokens/{id} [delete]
+func (h *Handler) deleteAccessToken(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	idString := c.Param("id")
+
+	if idString == "" {
+		newResponse(c, http.StatusBadRequest, "path param 'id' missing")
+		return
+	}
+
+	id, err := strconv.ParseInt(idString, 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'id' must be integer - "+err.Error())
+		return
+	}
+
+	err = h.s.AccessTokens.Delete(c.Request.Context(), id, userId)
+
+	if errors.Is(err, service.ErrAccessTokenForbidden) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if errors.Is(err, repo.ErrAccessTokenNotFound) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,244 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"github.com/lotostudio/financial-api/internal/service"
+	mockService "github.com/lotostudio/financial-api/internal/service/mocks"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const tokenID = int64(4)
+
+func TestHandler_listAccessTokens(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockAccessTokens)
+
+	tests := []struct {
+		name                 string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name: "ok",
+			mockBehaviour: func(s *mockService.MockAccessTokens) {
+				s.EXPECT().List(context.Background(), userID).Return([]domain.AccessToken{
+					{ID: tokenID, Name: "script", Scope: domain.ReadOnly, Hash: "hash", UserId: userID},
+				}, nil)
+			},
+			expectedCodeStatus:   200,
+			expectedResponseBody: `[{"id":4,"name":"script","scope":"read","createdAt":"0001-01-01T00:00:00Z"}]`,
+		},
+		{
+			name: "error",
+			mockBehaviour: func(s *mockService.MockAccessTokens) {
+				s.EXPECT().List(context.Background(), userID).Return(nil, errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			tService := mockService.NewMockAccessTokens(c)
+			tt.mockBehaviour(tService)
+
+			services := &service.Services{AccessTokens: tService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/users/me/tokens", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.listAccessTokens)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/users/me/tokens", bytes.NewBufferString(""))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_createAccessToken(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockAccessTokens)
+
+	expiresAt := time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		requestBody          string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "ok",
+			requestBody: `{"name":"script","scope":"write","expiresAt":"2022-09-01T00:00:00Z"}`,
+			mockBehaviour: func(s *mockService.MockAccessTokens) {
+				s.EXPECT().Create(context.Background(), domain.AccessTokenToCreate{
+					Name:      "script",
+					Scope:     domain.ReadWrite,
+					ExpiresAt: &expiresAt,
+				}, userID).Return(domain.CreatedAccessToken{
+					AccessToken: domain.AccessToken{ID: tokenID, Name: "script", Scope: domain.ReadWrite, ExpiresAt: &expiresAt},
+					Token:       "fa_token",
+				}, nil)
+			},
+			expectedCodeStatus:   201,
+			expectedResponseBody: `{"id":4,"name":"script","scope":"write","expiresAt":"2022-09-01T00:00:00Z","createdAt":"0001-01-01T00:00:00Z","token":"fa_token"}`,
+		},
+		{
+			name:                 "invalid scope",
+			requestBody:          `{"name":"script","scope":"admin"}`,
+			mockBehaviour:        func(s *mockService.MockAccessTokens) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"invalid request body - Key: 'AccessTokenToCreate.Scope' Error:Field validation for 'Scope' failed on the 'oneof' tag"}`,
+		},
+		{
+			name:        "error",
+			requestBody: `{"name":"script","scope":"read"}`,
+			mockBehaviour: func(s *mockService.MockAccessTokens) {
+				s.EXPECT().Create(context.Background(), domain.AccessTokenToCreate{
+					Name:  "script",
+					Scope: domain.ReadOnly,
+				}, userID).Return(domain.CreatedAccessToken{}, errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			tService := mockService.NewMockAccessTokens(c)
+			tt.mockBehaviour(tService)
+
+			services := &service.Services{AccessTokens: tService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/users/me/tokens", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.createAccessToken)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/users/me/tokens", bytes.NewBufferString(tt.requestBody))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_deleteAccessToken(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockAccessTokens)
+
+	tests := []struct {
+		name                 string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name: "ok",
+			mockBehaviour: func(s *mockService.MockAccessTokens) {
+				s.EXPECT().Delete(context.Background(), tokenID, userID).Return(nil)
+			},
+			expectedCodeStatus:   204,
+			expectedResponseBody: "",
+		},
+		{
+			name: "forbidden",
+			mockBehaviour: func(s *mockService.MockAccessTokens) {
+				s.EXPECT().Delete(context.Background(), tokenID, userID).Return(service.ErrAccessTokenForbidden)
+			},
+			expectedCodeStatus:   403,
+			expectedResponseBody: `{"message":"access token forbidden to access"}`,
+		},
+		{
+			name: "not found",
+			mockBehaviour: func(s *mockService.MockAccessTokens) {
+				s.EXPECT().Delete(context.Background(), tokenID, userID).Return(repo.ErrAccessTokenNotFound)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"access token doesn't exists"}`,
+		},
+		{
+			name: "error",
+			mockBehaviour: func(s *mockService.MockAccessTokens) {
+				s.EXPECT().Delete(context.Background(), tokenID, userID).Return(errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			tService := mockService.NewMockAccessTokens(c)
+			tt.mockBehaviour(tService)
+
+			services := &service.Services{AccessTokens: tService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.DELETE("/users/me/tokens/:id", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.deleteAccessToken)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", fmt.Sprintf("/users/me/tokens/%d", tokenID), bytes.NewBufferString(""))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
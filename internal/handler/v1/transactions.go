@@ -17,11 +17,24 @@ const (
 )
 
 func (h *Handler) initTransactionsRoutes(api *gin.RouterGroup) {
-	transactions := api.Group("/transactions", h.userIdentity)
+	transactions := api.Group("/transactions", h.userIdentity, h.limitUser)
 	{
 		transactions.GET("", h.listTransactions)
+		transactions.GET("/search", h.searchTransactions)
 		transactions.POST("", h.createTransaction)
+		transactions.POST("/batch", h.createTransactions)
+		transactions.DELETE("/batch", h.deleteTransactions)
 		transactions.DELETE("/:id", h.deleteTransaction)
+		transactions.PUT("/:id/status", h.setTransactionStatus)
+		transactions.POST("/:id/dismiss", h.dismissTransactionFlags)
+
+		attachments := transactions.Group("/:id/attachments")
+		{
+			attachments.GET("", h.listAttachments)
+			attachments.POST("", h.uploadAttachment)
+			attachments.GET("/:attachmentId", h.downloadAttachment)
+			attachments.DELETE("/:attachmentId", h.deleteAttachment)
+		}
 
 		stats := transactions.Group("/stats")
 		{
@@ -51,6 +64,11 @@ func (h *Handler) initTransactionsRoutes(api *gin.RouterGroup) {
 // @Param type query string false "Type of transaction"
 // @Param dateFrom query string false "Start date (yyyy-MM-dd). Combined with dateTo"
 // @Param dateTo query string false "End date (yyyy-MM-dd). Combined with dateFrom"
+// @Param tag query string false "Tag of transaction"
+// @Param payee query string false "Payee of transaction"
+// @Param text query string false "Part of description or payee"
+// @Param flagged query bool false "Only unusual transactions with not dismissed flags or only usual ones"
+// @Param status query string false "Status of transaction: pending, cleared or reconciled"
 // @Success 200 {array} domain.Transaction "Operation finished successfully"
 // @Failure 400 {object} response "Invalid request"
 // @Failure 401 {object} response "Invalid authorization"
@@ -90,9 +108,95 @@ func (h *Handler) listTransactions(c *gin.Context) {
 	c.JSON(http.StatusOK, transactions)
 }
 
+// @Summary Search transactions
+// @Tags transactions
+// @Description Full-text search by words of description and payee, part of payee name is enough.
+// @Description Results are ordered by relevance and have matched words highlighted
+// @ID searchTransactions
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param q query string true "Search query"
+// @Param limit query int false "Count of results" minimum(1) maximum(100) default(50)
+// @Param accountId query int false "Id of account"
+// @Param category query string false "Category of transaction"
+// @Param type query string false "Type of transaction"
+// @Param dateFrom query string false "Start date (yyyy-MM-dd). Combined with dateTo"
+// @Param dateTo query string false "End date (yyyy-MM-dd). Combined with dateFrom"
+// @Param tag query string false "Tag of transaction"
+// @Param payee query string false "Payee of transaction"
+// @Param flagged query bool false "Only unusual transactions with not dismissed flags or only usual ones"
+// @Param status query string false "Status of transaction: pending, cleared or reconciled"
+// @Success 200 {array} domain.TransactionSearchResult "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /transactions/search [get]
+func (h *Handler) searchTransactions(c *gin.Context) {
+	filter, err := h.parseTransactionsFilter(c)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	filter.OwnerId = &userId
+
+	if accountIdString := c.Query("accountId"); accountIdString != "" {
+		accountId, err := strconv.ParseInt(accountIdString, 10, 64)
+
+		if err != nil {
+			newResponse(c, http.StatusBadRequest, "query param 'accountId' must be integer - "+err.Error())
+			return
+		}
+
+		if !h.checkAccountAccess(c, accountId, userId) {
+			return
+		}
+
+		filter.AccountId = &accountId
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "query param 'limit' must be integer - "+err.Error())
+		return
+	}
+
+	results, err := h.s.Transactions.Search(c.Request.Context(), c.Query("q"), filter, limit)
+
+	if errors.Is(err, service.ErrSearchQueryEmpty) || errors.Is(err, service.ErrSearchLimitInvalid) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
 // @Summary Transaction stats
 // @Tags transactions
-// @Description Transaction stats
+// @Description Sum, count, average, minimal and maximal amount of transactions by groups
 // @ID transactionStats
 // @Security UsersAuth
 // @Accept json
@@ -101,6 +205,7 @@ func (h *Handler) listTransactions(c *gin.Context) {
 // @Param type query string false "Type of transaction"
 // @Param dateFrom query string false "Start date (yyyy-MM-dd). Combined with dateTo"
 // @Param dateTo query string false "End date (yyyy-MM-dd). Combined with dateFrom"
+// @Param groupBy query string false "Grouping" Enums(category, type, account, month, weekday) default(category)
 // @Success 200 {array} domain.TransactionStat "Operation finished successfully"
 // @Failure 400 {object} response "Invalid request"
 // @Failure 401 {object} response "Invalid authorization"
@@ -130,7 +235,14 @@ func (h *Handler) transactionStats(c *gin.Context) {
 
 	filter.OwnerId = &userId
 
-	stats, err := h.s.Transactions.Stats(c.Request.Context(), filter)
+	groupBy := domain.StatsGroup(c.DefaultQuery("groupBy", string(domain.GroupByCategory)))
+
+	stats, err := h.s.Transactions.Stats(c.Request.Context(), filter, groupBy)
+
+	if errors.Is(err, domain.ErrInvalidStatsGroup) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	if err != nil {
 		newResponse(c, http.StatusInternalServerError, err.Error())
@@ -152,6 +264,11 @@ func (h *Handler) transactionStats(c *gin.Context) {
 // @Param type query string false "Type of transaction"
 // @Param dateFrom query string false "Start date (yyyy-MM-dd)"
 // @Param dateTo query string false "End date (yyyy-MM-dd)"
+// @Param tag query string false "Tag of transaction"
+// @Param payee query string false "Payee of transaction"
+// @Param text query string false "Part of description or payee"
+// @Param flagged query bool false "Only unusual transactions with not dismissed flags or only usual ones"
+// @Param status query string false "Status of transaction: pending, cleared or reconciled"
 // @Success 200 {array} domain.Transaction "Operation finished successfully"
 // @Failure 400 {object} response "Invalid request"
 // @Failure 401 {object} response "Invalid authorization"
@@ -237,11 +354,13 @@ func (h *Handler) parseTransactionsFilter(c *gin.Context) (domain.TransactionsFi
 
 	_type := domain.TransactionType(c.Query("type"))
 
-	if err := _type.Validate(); _type != "" && err != nil {
-		return filter, err
-	}
+	if _type != "" {
+		if err := _type.Validate(); err != nil {
+			return filter, err
+		}
 
-	filter.Type = &_type
+		filter.Type = &_type
+	}
 
 	dateFromString := c.Query("dateFrom")
 
@@ -267,6 +386,40 @@ func (h *Handler) parseTransactionsFilter(c *gin.Context) (domain.TransactionsFi
 		filter.CreatedTo = &dateTo
 	}
 
+	if tag := c.Query("tag"); tag != "" {
+		filter.Tag = &tag
+	}
+
+	if payee := c.Query("payee"); payee != "" {
+		filter.Payee = &payee
+	}
+
+	if text := c.Query("text"); text != "" {
+		filter.Text = &text
+	}
+
+	flaggedString := c.Query("flagged")
+
+	if flaggedString != "" {
+		flagged, err := strconv.ParseBool(flaggedString)
+
+		if err != nil {
+			return filter, errFlaggedInvalid
+		}
+
+		filter.Flagged = &flagged
+	}
+
+	status := domain.TransactionStatus(c.Query("status"))
+
+	if status != "" {
+		if err := status.Validate(); err != nil {
+			return filter, err
+		}
+
+		filter.Status = &status
+	}
+
 	// Filters by date period both must be null or not null at the same time
 	if (filter.CreatedFrom != nil && filter.CreatedTo == nil) || (filter.CreatedFrom == nil && filter.CreatedTo != nil) {
 		return filter, errDateFiltersInvalid
@@ -355,7 +508,9 @@ func (h *Handler) createTransaction(c *gin.Context) {
 
 	transaction, err := h.s.Transactions.Create(c.Request.Context(), toCreate, userId, categoryID, creditID, debitID)
 
-	if errors.Is(err, service.ErrTransactionAndCategoryTypesMismatch) {
+	if errors.Is(err, service.ErrTransactionAndCategoryTypesMismatch) ||
+		errors.Is(err, service.ErrTransactionLinesSumMismatch) || errors.Is(err, service.ErrTransferSplit) ||
+		errors.Is(err, service.ErrSplitTransactionCategory) || errors.Is(err, service.ErrNoCategorySelected) {
 		newResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -388,14 +543,73 @@ func (h *Handler) createTransaction(c *gin.Context) {
 	c.JSON(http.StatusCreated, transaction)
 }
 
+// @Summary Create transactions
+// @Tags transactions
+// @Description Create up to 100 transactions at once. Items are checked as in single creation, with categories and
+// @Description accounts passed in items, and saved in one database transaction. In all_or_nothing mode nothing is
+// @Description saved if any item is invalid, in best_effort mode invalid items are skipped. Result of every item is
+// @Description returned in order of request
+// @ID createTransactions
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param input body domain.TransactionsToCreate true "Transactions info"
+// @Success 200 {object} domain.TransactionBatch "Operation finished successfully"
+// @Failure 400 {object} domain.TransactionBatch "Batch is rejected"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 500 {object} response "Server error"
+// @Router /transactions/batch [post]
+func (h *Handler) createTransactions(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	var toCreate domain.TransactionsToCreate
+
+	if err = c.ShouldBindJSON(&toCreate); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid request body - "+err.Error())
+		return
+	}
+
+	batch, err := h.s.Transactions.CreateBatch(c.Request.Context(), toCreate, userId)
+
+	if errors.Is(err, service.ErrBatchRejected) {
+		c.JSON(http.StatusBadRequest, batch)
+		return
+	}
+
+	if errors.Is(err, domain.ErrInvalidBatchMode) || errors.Is(err, repo.ErrAccountNotEnoughBalance) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, batch)
+}
+
 // @Summary Delete transaction
 // @Tags transactions
-// @Description Delete transaction
+// @Description Move transaction to trash and revert its effect on balances. It can be restored until it is purged
 // @ID deleteTransaction
 // @Security UsersAuth
 // @Accept json
 // @Produce json
 // @Param id path int64 true "Id of transaction"
+// @Param unlock query bool false "Delete transaction even if it is reconciled"
 // @Success 204 {null} nil "Operation finished successfully"
 // @Failure 400 {object} response "Invalid request"
 // @Failure 401 {object} response "Invalid authorization"
@@ -431,8 +645,18 @@ func (h *Handler) deleteTransaction(c *gin.Context) {
 		return
 	}
 
-	if err = h.s.Transactions.Delete(c.Request.Context(), id, userId); err != nil {
-		if err == repo.ErrTransactionNotFound || err == repo.ErrAccountNotEnoughBalance {
+	var unlock bool
+
+	if unlockString := c.Query("unlock"); unlockString != "" {
+		if unlock, err = strconv.ParseBool(unlockString); err != nil {
+			newResponse(c, http.StatusBadRequest, errUnlockInvalid.Error())
+			return
+		}
+	}
+
+	if err = h.s.Transactions.Delete(c.Request.Context(), id, userId, unlock); err != nil {
+		if err == repo.ErrTransactionNotFound || err == repo.ErrAccountNotEnoughBalance ||
+			err == service.ErrTransactionReconciled {
 			newResponse(c, http.StatusBadRequest, err.Error())
 			return
 		}
@@ -449,6 +673,187 @@ func (h *Handler) deleteTransaction(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// @Summary Delete transactions
+// @Tags transactions
+// @Description Move up to 100 transactions to trash at once in one database transaction. Reconciled transactions are deleted
+// @Description only if unlock is set. In all_or_nothing mode nothing is deleted if any transaction is missing, forbidden
+// @Description or locked, in best_effort mode such transactions are skipped.
+// @Description Result of every transaction is returned in order of request
+// @ID deleteTransactions
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param input body domain.TransactionsToDelete true "Ids of transactions"
+// @Success 200 {object} domain.TransactionBatch "Operation finished successfully"
+// @Failure 400 {object} domain.TransactionBatch "Batch is rejected"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 500 {object} response "Server error"
+// @Router /transactions/batch [delete]
+func (h *Handler) deleteTransactions(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	var toDelete domain.TransactionsToDelete
+
+	if err = c.ShouldBindJSON(&toDelete); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid request body - "+err.Error())
+		return
+	}
+
+	batch, err := h.s.Transactions.DeleteBatch(c.Request.Context(), toDelete, userId)
+
+	if errors.Is(err, service.ErrBatchRejected) {
+		c.JSON(http.StatusBadRequest, batch)
+		return
+	}
+
+	if errors.Is(err, domain.ErrInvalidBatchMode) || errors.Is(err, repo.ErrAccountNotEnoughBalance) ||
+		errors.Is(err, repo.ErrTransactionNotFound) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, batch)
+}
+
+// @Summary Set transaction status
+// @Tags transactions
+// @Description Change status of transaction: pending, cleared or reconciled. Reconciled transaction is locked, its
+// @Description status is changed only if unlock is set
+// @ID setTransactionStatus
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Id of transaction"
+// @Param input body domain.TransactionStatusToUpdate true "Status info"
+// @Success 204 {null} nil "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /transactions/{id}/status [put]
+func (h *Handler) setTransactionStatus(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'id' must be integer - "+err.Error())
+		return
+	}
+
+	var toUpdate domain.TransactionStatusToUpdate
+
+	if err = c.ShouldBindJSON(&toUpdate); err != nil {
+		newResponse(c, http.StatusBadRequest, "invalid request body - "+err.Error())
+		return
+	}
+
+	err = h.s.Transactions.SetStatus(c.Request.Context(), id, userId, toUpdate)
+
+	if errors.Is(err, repo.ErrTransactionNotFound) || errors.Is(err, service.ErrTransactionReconciled) ||
+		errors.Is(err, domain.ErrInvalidTransactionStatus) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, service.ErrTransactionForbidden) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Dismiss transaction flags
+// @Tags transactions
+// @Description Dismiss flags of unusual transaction, it is not listed as flagged anymore
+// @ID dismissTransactionFlags
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Id of transaction"
+// @Success 204 {null} nil "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /transactions/{id}/dismiss [post]
+func (h *Handler) dismissTransactionFlags(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'id' must be integer - "+err.Error())
+		return
+	}
+
+	err = h.s.Transactions.DismissFlags(c.Request.Context(), id, userId)
+
+	if errors.Is(err, repo.ErrTransactionNotFound) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, service.ErrTransactionForbidden) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // @Summary List transaction categories
 // @Tags transactions
 // @Description List transaction categories
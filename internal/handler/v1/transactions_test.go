@@ -15,6 +15,7 @@ import (
 	mockService "github.com/lotostudio/financial-api/internal/service/mocks"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -41,8 +42,14 @@ func TestHandler_listTransactions(t *testing.T) {
 		return string(body)
 	}
 
+	ownerID := int64(userID)
+	flagged := true
+	tag, payee, text := "work", "Navat", "dinner"
+	status := domain.Pending
+
 	tests := []struct {
 		name                 string
+		query                string
 		mockBehaviour        mockBehaviour
 		expectedCodeStatus   int
 		expectedResponseBody string
@@ -55,6 +62,58 @@ func TestHandler_listTransactions(t *testing.T) {
 			expectedCodeStatus:   200,
 			expectedResponseBody: setResponseBody(transactions),
 		},
+		{
+			name:  "ok flagged",
+			query: "?flagged=true",
+			mockBehaviour: func(s *mockService.MockTransactions) {
+				s.EXPECT().List(context.Background(), domain.TransactionsFilter{
+					OwnerId: &ownerID,
+					Flagged: &flagged,
+				}).Return(transactions, nil)
+			},
+			expectedCodeStatus:   200,
+			expectedResponseBody: setResponseBody(transactions),
+		},
+		{
+			name:  "ok by tag, payee and text",
+			query: "?tag=work&payee=Navat&text=dinner",
+			mockBehaviour: func(s *mockService.MockTransactions) {
+				s.EXPECT().List(context.Background(), domain.TransactionsFilter{
+					OwnerId: &ownerID,
+					Tag:     &tag,
+					Payee:   &payee,
+					Text:    &text,
+				}).Return(transactions, nil)
+			},
+			expectedCodeStatus:   200,
+			expectedResponseBody: setResponseBody(transactions),
+		},
+		{
+			name:                 "invalid flagged",
+			query:                "?flagged=maybe",
+			mockBehaviour:        func(s *mockService.MockTransactions) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"query param 'flagged' must be boolean"}`,
+		},
+		{
+			name:  "ok by status",
+			query: "?status=pending",
+			mockBehaviour: func(s *mockService.MockTransactions) {
+				s.EXPECT().List(context.Background(), domain.TransactionsFilter{
+					OwnerId: &ownerID,
+					Status:  &status,
+				}).Return(transactions, nil)
+			},
+			expectedCodeStatus:   200,
+			expectedResponseBody: setResponseBody(transactions),
+		},
+		{
+			name:                 "invalid status",
+			query:                "?status=lost",
+			mockBehaviour:        func(s *mockService.MockTransactions) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"invalid status of transaction, use one of: pending, cleared, reconciled"}`,
+		},
 		{
 			name: "error",
 			mockBehaviour: func(s *mockService.MockTransactions) {
@@ -87,7 +146,7 @@ func TestHandler_listTransactions(t *testing.T) {
 
 			// Create Request
 			w := httptest.NewRecorder()
-			req := httptest.NewRequest("GET", "/transactions", bytes.NewBufferString(""))
+			req := httptest.NewRequest("GET", "/transactions"+tt.query, bytes.NewBufferString(""))
 
 			// Make Request
 			r.ServeHTTP(w, req)
@@ -104,12 +163,20 @@ func TestHandler_transactionStats(t *testing.T) {
 
 	stats := []domain.TransactionStat{
 		{
-			Category: "food",
-			Value:    123,
+			Group: "food",
+			Sum:   123.5,
+			Count: 2,
+			Avg:   61.75,
+			Min:   23.5,
+			Max:   100,
 		},
 		{
-			Category: "family",
-			Value:    1000,
+			Group: "family",
+			Sum:   1000,
+			Count: 1,
+			Avg:   1000,
+			Min:   1000,
+			Max:   1000,
 		},
 	}
 
@@ -121,6 +188,7 @@ func TestHandler_transactionStats(t *testing.T) {
 
 	tests := []struct {
 		name                 string
+		query                string
 		mockBehaviour        mockBehaviour
 		expectedCodeStatus   int
 		expectedResponseBody string
@@ -128,15 +196,34 @@ func TestHandler_transactionStats(t *testing.T) {
 		{
 			name: "ok",
 			mockBehaviour: func(s *mockService.MockTransactions) {
-				s.EXPECT().Stats(context.Background(), gomock.Any()).Return(stats, nil)
+				s.EXPECT().Stats(context.Background(), gomock.Any(), domain.GroupByCategory).Return(stats, nil)
 			},
 			expectedCodeStatus:   200,
 			expectedResponseBody: setResponseBody(stats),
 		},
+		{
+			name:  "ok by weekday",
+			query: "?groupBy=weekday",
+			mockBehaviour: func(s *mockService.MockTransactions) {
+				s.EXPECT().Stats(context.Background(), gomock.Any(), domain.GroupByWeekday).Return(stats, nil)
+			},
+			expectedCodeStatus:   200,
+			expectedResponseBody: setResponseBody(stats),
+		},
+		{
+			name:  "invalid group",
+			query: "?groupBy=year",
+			mockBehaviour: func(s *mockService.MockTransactions) {
+				s.EXPECT().Stats(context.Background(), gomock.Any(), domain.StatsGroup("year")).Return(nil,
+					domain.ErrInvalidStatsGroup)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"invalid grouping, use one of: category, type, account, month, weekday"}`,
+		},
 		{
 			name: "error",
 			mockBehaviour: func(s *mockService.MockTransactions) {
-				s.EXPECT().Stats(context.Background(), gomock.Any()).Return(stats, errors.New("general error"))
+				s.EXPECT().Stats(context.Background(), gomock.Any(), gomock.Any()).Return(stats, errors.New("general error"))
 			},
 			expectedCodeStatus:   500,
 			expectedResponseBody: `{"message":"general error"}`,
@@ -165,7 +252,7 @@ func TestHandler_transactionStats(t *testing.T) {
 
 			// Create Request
 			w := httptest.NewRecorder()
-			req := httptest.NewRequest("GET", "/transactions/stats", bytes.NewBufferString(""))
+			req := httptest.NewRequest("GET", "/transactions/stats"+tt.query, bytes.NewBufferString(""))
 
 			// Make Request
 			r.ServeHTTP(w, req)
@@ -305,6 +392,16 @@ func TestHandler_createTransaction(t *testing.T) {
 		CreatedAt: date,
 	}
 
+	description, payee := "Dinner with team", "Navat"
+	detailedToCreate := domain.TransactionToCreate{
+		Amount:      100,
+		Type:        domain.Expense,
+		CreatedAt:   date,
+		Description: &description,
+		Payee:       &payee,
+		Tags:        []string{"work", "restaurants"},
+	}
+
 	created := domain.Transaction{
 		ID:        1,
 		Amount:    100,
@@ -369,6 +466,27 @@ func TestHandler_createTransaction(t *testing.T) {
 			expectedCodeStatus:   201,
 			expectedResponseBody: setResponseBody(created),
 		},
+		{
+			name: "ok - description, payee and tags",
+			requestBody: fmt.Sprintf(`{"amount":100,"type":"expense","createdAt":"%s","description":"Dinner with team",`+
+				`"payee":"Navat","tags":["work","restaurants"]}`, dateString),
+			categoryId:      *categoryId,
+			creditId:        *creditId,
+			requestToCreate: detailedToCreate,
+			mockBehaviour: func(s *mockService.MockTransactions) {
+				s.EXPECT().Create(context.Background(), detailedToCreate, userID, categoryId, creditId, nil).Return(created, nil)
+			},
+			expectedCodeStatus:   201,
+			expectedResponseBody: setResponseBody(created),
+		},
+		{
+			name: "too long tag",
+			requestBody: fmt.Sprintf(`{"amount":100,"type":"expense","createdAt":"%s","tags":["%s"]}`, dateString,
+				strings.Repeat("a", 51)),
+			mockBehaviour:        func(s *mockService.MockTransactions) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"invalid request body - Key: 'TransactionToCreate.Tags[0]' Error:Field validation for 'Tags[0]' failed on the 'max' tag"}`,
+		},
 		{
 			name:                 "invalid request body",
 			requestBody:          `{"amount":100,"type":"transfer"}`,
@@ -389,6 +507,19 @@ func TestHandler_createTransaction(t *testing.T) {
 			expectedCodeStatus:   400,
 			expectedResponseBody: `{"message":"type of transaction and category does not match"}`,
 		},
+		{
+			name:            "lines sum mismatch",
+			requestBody:     fmt.Sprintf(`{"amount":100,"type":"income","createdAt":"%s"}`, dateString),
+			categoryId:      *categoryId,
+			debitId:         *debitId,
+			requestToCreate: toCreate,
+			mockBehaviour: func(s *mockService.MockTransactions) {
+				s.EXPECT().Create(context.Background(), toCreate, userID, categoryId, nil, debitId).
+					Return(created, service.ErrTransactionLinesSumMismatch)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"sum of lines does not match amount of transaction"}`,
+		},
 		{
 			name:            "forbidden",
 			requestBody:     fmt.Sprintf(`{"amount":100,"type":"income","createdAt":"%s"}`, dateString),
@@ -465,11 +596,138 @@ func TestHandler_createTransaction(t *testing.T) {
 	}
 }
 
+func TestHandler_createTransactions(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockTransactions, toCreate domain.TransactionsToCreate)
+
+	createdAt, _ := time.Parse("2006-01-02", "2022-03-01")
+	categoryId, creditId := int64(3), int64(accountID)
+
+	toCreate := domain.TransactionsToCreate{
+		Mode: domain.BestEffort,
+		Items: []domain.TransactionBatchItem{
+			{
+				TransactionToCreate: domain.TransactionToCreate{Amount: 100, Type: domain.Expense, CreatedAt: createdAt},
+				CategoryId:          &categoryId,
+				CreditId:            &creditId,
+			},
+			{
+				TransactionToCreate: domain.TransactionToCreate{Amount: 50, Type: domain.Expense, CreatedAt: createdAt},
+			},
+		},
+	}
+	requestBody := `{"mode":"best_effort","items":[` +
+		`{"amount":100,"type":"expense","createdAt":"2022-03-01T00:00:00Z","categoryId":3,"creditId":2},` +
+		`{"amount":50,"type":"expense","createdAt":"2022-03-01T00:00:00Z"}]}`
+
+	tests := []struct {
+		name                 string
+		requestBody          string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "ok",
+			requestBody: requestBody,
+			mockBehaviour: func(s *mockService.MockTransactions, toCreate domain.TransactionsToCreate) {
+				s.EXPECT().CreateBatch(context.Background(), toCreate, userID).Return(domain.TransactionBatch{
+					Applied: 1,
+					Failed:  1,
+					Results: []domain.TransactionBatchResult{
+						{Index: 0, ID: transactionID, Transaction: &domain.Transaction{ID: transactionID, Status: domain.Pending}},
+						{Index: 1, Error: service.ErrNoAccountSelected.Error()},
+					},
+				}, nil)
+			},
+			expectedCodeStatus: 200,
+			expectedResponseBody: `{"applied":1,"failed":1,"results":[{"index":0,"id":5,"transaction":{"id":5,` +
+				`"amount":0,"type":"","createdAt":"0001-01-01T00:00:00Z","status":"pending"}},{"index":1,"error":"no account selected"}]}`,
+		},
+		{
+			name:        "rejected",
+			requestBody: requestBody,
+			mockBehaviour: func(s *mockService.MockTransactions, toCreate domain.TransactionsToCreate) {
+				s.EXPECT().CreateBatch(context.Background(), toCreate, userID).Return(domain.TransactionBatch{
+					Failed: 1,
+					Results: []domain.TransactionBatchResult{
+						{Index: 0},
+						{Index: 1, Error: service.ErrNoAccountSelected.Error()},
+					},
+				}, service.ErrBatchRejected)
+			},
+			expectedCodeStatus: 400,
+			expectedResponseBody: `{"applied":0,"failed":1,"results":[{"index":0},` +
+				`{"index":1,"error":"no account selected"}]}`,
+		},
+		{
+			name:                 "empty items",
+			requestBody:          `{"items":[]}`,
+			mockBehaviour:        func(s *mockService.MockTransactions, toCreate domain.TransactionsToCreate) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"invalid request body - Key: 'TransactionsToCreate.Items' Error:Field validation for 'Items' failed on the 'min' tag"}`,
+		},
+		{
+			name:        "invalid mode",
+			requestBody: requestBody,
+			mockBehaviour: func(s *mockService.MockTransactions, toCreate domain.TransactionsToCreate) {
+				s.EXPECT().CreateBatch(context.Background(), toCreate, userID).
+					Return(domain.TransactionBatch{}, domain.ErrInvalidBatchMode)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"invalid mode of batch, use one of: all_or_nothing, best_effort"}`,
+		},
+		{
+			name:        "error",
+			requestBody: requestBody,
+			mockBehaviour: func(s *mockService.MockTransactions, toCreate domain.TransactionsToCreate) {
+				s.EXPECT().CreateBatch(context.Background(), toCreate, userID).
+					Return(domain.TransactionBatch{}, errors.New("default error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"default error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			tService := mockService.NewMockTransactions(c)
+			tt.mockBehaviour(tService, toCreate)
+
+			services := &service.Services{Transactions: tService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/transactions/batch", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.createTransactions)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/transactions/batch", bytes.NewBufferString(tt.requestBody))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+		})
+	}
+}
+
 func TestHandler_deleteTransaction(t *testing.T) {
 	type mockBehaviour func(s *mockService.MockTransactions)
 
 	tests := []struct {
 		name                 string
+		query                string
 		mockBehaviour        mockBehaviour
 		expectedCodeStatus   int
 		expectedResponseBody string
@@ -477,15 +735,40 @@ func TestHandler_deleteTransaction(t *testing.T) {
 		{
 			name: "ok",
 			mockBehaviour: func(s *mockService.MockTransactions) {
-				s.EXPECT().Delete(context.Background(), transactionID, userID).Return(nil)
+				s.EXPECT().Delete(context.Background(), transactionID, userID, false).Return(nil)
+			},
+			expectedCodeStatus:   204,
+			expectedResponseBody: "",
+		},
+		{
+			name:  "ok - unlocked",
+			query: "?unlock=true",
+			mockBehaviour: func(s *mockService.MockTransactions) {
+				s.EXPECT().Delete(context.Background(), transactionID, userID, true).Return(nil)
 			},
 			expectedCodeStatus:   204,
 			expectedResponseBody: "",
 		},
+		{
+			name:                 "invalid unlock",
+			query:                "?unlock=maybe",
+			mockBehaviour:        func(s *mockService.MockTransactions) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"query param 'unlock' must be boolean"}`,
+		},
+		{
+			name: "reconciled",
+			mockBehaviour: func(s *mockService.MockTransactions) {
+				s.EXPECT().Delete(context.Background(), transactionID, userID, false).
+					Return(service.ErrTransactionReconciled)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"transaction is reconciled, unlock it to change"}`,
+		},
 		{
 			name: "access to account forbidden",
 			mockBehaviour: func(s *mockService.MockTransactions) {
-				s.EXPECT().Delete(context.Background(), transactionID, userID).Return(service.ErrTransactionForbidden)
+				s.EXPECT().Delete(context.Background(), transactionID, userID, false).Return(service.ErrTransactionForbidden)
 			},
 			expectedCodeStatus:   403,
 			expectedResponseBody: `{"message":"transaction forbidden to access"}`,
@@ -493,7 +776,7 @@ func TestHandler_deleteTransaction(t *testing.T) {
 		{
 			name: "account not found",
 			mockBehaviour: func(s *mockService.MockTransactions) {
-				s.EXPECT().Delete(context.Background(), transactionID, userID).Return(repo.ErrTransactionNotFound)
+				s.EXPECT().Delete(context.Background(), transactionID, userID, false).Return(repo.ErrTransactionNotFound)
 			},
 			expectedCodeStatus:   400,
 			expectedResponseBody: `{"message":"transaction doesn't exists"}`,
@@ -501,7 +784,7 @@ func TestHandler_deleteTransaction(t *testing.T) {
 		{
 			name: "error",
 			mockBehaviour: func(s *mockService.MockTransactions) {
-				s.EXPECT().Delete(context.Background(), transactionID, userID).Return(errors.New("general error"))
+				s.EXPECT().Delete(context.Background(), transactionID, userID, false).Return(errors.New("general error"))
 			},
 			expectedCodeStatus:   500,
 			expectedResponseBody: `{"message":"general error"}`,
@@ -530,7 +813,295 @@ func TestHandler_deleteTransaction(t *testing.T) {
 
 			// Create Request
 			w := httptest.NewRecorder()
-			req := httptest.NewRequest("DELETE", fmt.Sprintf("/transactions/%d", transactionID), bytes.NewBufferString(""))
+			req := httptest.NewRequest("DELETE", fmt.Sprintf("/transactions/%d", transactionID)+tt.query,
+				bytes.NewBufferString(""))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_setTransactionStatus(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockTransactions)
+
+	tests := []struct {
+		name                 string
+		requestBody          string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "ok",
+			requestBody: `{"status":"cleared"}`,
+			mockBehaviour: func(s *mockService.MockTransactions) {
+				s.EXPECT().SetStatus(context.Background(), transactionID, userID,
+					domain.TransactionStatusToUpdate{Status: domain.Cleared}).Return(nil)
+			},
+			expectedCodeStatus:   204,
+			expectedResponseBody: "",
+		},
+		{
+			name:                 "invalid status",
+			requestBody:          `{"status":"lost"}`,
+			mockBehaviour:        func(s *mockService.MockTransactions) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"invalid request body - Key: 'TransactionStatusToUpdate.Status' Error:Field validation for 'Status' failed on the 'oneof' tag"}`,
+		},
+		{
+			name:        "reconciled",
+			requestBody: `{"status":"pending"}`,
+			mockBehaviour: func(s *mockService.MockTransactions) {
+				s.EXPECT().SetStatus(context.Background(), transactionID, userID,
+					domain.TransactionStatusToUpdate{Status: domain.Pending}).Return(service.ErrTransactionReconciled)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"transaction is reconciled, unlock it to change"}`,
+		},
+		{
+			name:        "forbidden",
+			requestBody: `{"status":"pending","unlock":true}`,
+			mockBehaviour: func(s *mockService.MockTransactions) {
+				s.EXPECT().SetStatus(context.Background(), transactionID, userID,
+					domain.TransactionStatusToUpdate{Status: domain.Pending, Unlock: true}).
+					Return(service.ErrTransactionForbidden)
+			},
+			expectedCodeStatus:   403,
+			expectedResponseBody: `{"message":"transaction forbidden to access"}`,
+		},
+		{
+			name:        "error",
+			requestBody: `{"status":"reconciled"}`,
+			mockBehaviour: func(s *mockService.MockTransactions) {
+				s.EXPECT().SetStatus(context.Background(), transactionID, userID,
+					domain.TransactionStatusToUpdate{Status: domain.Reconciled}).Return(errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			tService := mockService.NewMockTransactions(c)
+			tt.mockBehaviour(tService)
+
+			services := &service.Services{Transactions: tService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.PUT("/transactions/:id/status", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.setTransactionStatus)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", fmt.Sprintf("/transactions/%d/status", transactionID),
+				bytes.NewBufferString(tt.requestBody))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_deleteTransactions(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockTransactions, toDelete domain.TransactionsToDelete)
+
+	toDelete := domain.TransactionsToDelete{IDs: []int64{transactionID, transactionID + 1}}
+
+	tests := []struct {
+		name                 string
+		requestBody          string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "ok",
+			requestBody: `{"ids":[5,6]}`,
+			mockBehaviour: func(s *mockService.MockTransactions, toDelete domain.TransactionsToDelete) {
+				s.EXPECT().DeleteBatch(context.Background(), toDelete, userID).Return(domain.TransactionBatch{
+					Applied: 2,
+					Results: []domain.TransactionBatchResult{{Index: 0, ID: 5}, {Index: 1, ID: 6}},
+				}, nil)
+			},
+			expectedCodeStatus:   200,
+			expectedResponseBody: `{"applied":2,"failed":0,"results":[{"index":0,"id":5},{"index":1,"id":6}]}`,
+		},
+		{
+			name:        "rejected",
+			requestBody: `{"ids":[5,6]}`,
+			mockBehaviour: func(s *mockService.MockTransactions, toDelete domain.TransactionsToDelete) {
+				s.EXPECT().DeleteBatch(context.Background(), toDelete, userID).Return(domain.TransactionBatch{
+					Failed: 1,
+					Results: []domain.TransactionBatchResult{
+						{Index: 0, ID: 5},
+						{Index: 1, ID: 6, Error: service.ErrTransactionForbidden.Error()},
+					},
+				}, service.ErrBatchRejected)
+			},
+			expectedCodeStatus: 400,
+			expectedResponseBody: `{"applied":0,"failed":1,"results":[{"index":0,"id":5},` +
+				`{"index":1,"id":6,"error":"transaction forbidden to access"}]}`,
+		},
+		{
+			name:        "not enough balance",
+			requestBody: `{"ids":[5,6]}`,
+			mockBehaviour: func(s *mockService.MockTransactions, toDelete domain.TransactionsToDelete) {
+				s.EXPECT().DeleteBatch(context.Background(), toDelete, userID).
+					Return(domain.TransactionBatch{}, repo.ErrAccountNotEnoughBalance)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"account doesn't have enough balance"}`,
+		},
+		{
+			name:                 "missing ids",
+			requestBody:          `{"mode":"best_effort"}`,
+			mockBehaviour:        func(s *mockService.MockTransactions, toDelete domain.TransactionsToDelete) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"invalid request body - Key: 'TransactionsToDelete.IDs' Error:Field validation for 'IDs' failed on the 'required' tag"}`,
+		},
+		{
+			name:        "error",
+			requestBody: `{"ids":[5,6]}`,
+			mockBehaviour: func(s *mockService.MockTransactions, toDelete domain.TransactionsToDelete) {
+				s.EXPECT().DeleteBatch(context.Background(), toDelete, userID).
+					Return(domain.TransactionBatch{}, errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			tService := mockService.NewMockTransactions(c)
+			tt.mockBehaviour(tService, toDelete)
+
+			services := &service.Services{Transactions: tService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.DELETE("/transactions/batch", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.deleteTransactions)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", "/transactions/batch", bytes.NewBufferString(tt.requestBody))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_dismissTransactionFlags(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockTransactions)
+
+	tests := []struct {
+		name                 string
+		id                   string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name: "ok",
+			id:   strconv.FormatInt(transactionID, 10),
+			mockBehaviour: func(s *mockService.MockTransactions) {
+				s.EXPECT().DismissFlags(context.Background(), transactionID, userID).Return(nil)
+			},
+			expectedCodeStatus:   204,
+			expectedResponseBody: "",
+		},
+		{
+			name:                 "invalid id",
+			id:                   "a",
+			mockBehaviour:        func(s *mockService.MockTransactions) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"path param 'id' must be integer - strconv.ParseInt: parsing \"a\": invalid syntax"}`,
+		},
+		{
+			name: "transaction forbidden",
+			id:   strconv.FormatInt(transactionID, 10),
+			mockBehaviour: func(s *mockService.MockTransactions) {
+				s.EXPECT().DismissFlags(context.Background(), transactionID, userID).Return(service.ErrTransactionForbidden)
+			},
+			expectedCodeStatus:   403,
+			expectedResponseBody: `{"message":"transaction forbidden to access"}`,
+		},
+		{
+			name: "transaction not found",
+			id:   strconv.FormatInt(transactionID, 10),
+			mockBehaviour: func(s *mockService.MockTransactions) {
+				s.EXPECT().DismissFlags(context.Background(), transactionID, userID).Return(repo.ErrTransactionNotFound)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"transaction doesn't exists"}`,
+		},
+		{
+			name: "error",
+			id:   strconv.FormatInt(transactionID, 10),
+			mockBehaviour: func(s *mockService.MockTransactions) {
+				s.EXPECT().DismissFlags(context.Background(), transactionID, userID).Return(errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			tService := mockService.NewMockTransactions(c)
+			tt.mockBehaviour(tService)
+
+			services := &service.Services{Transactions: tService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/transactions/:id/dismiss", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.dismissTransactionFlags)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/transactions/"+tt.id+"/dismiss", bytes.NewBufferString(""))
 
 			// Make Request
 			r.ServeHTTP(w, req)
@@ -720,3 +1291,127 @@ func TestHandler_listTransactionTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_searchTransactions(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockTransactions, a *mockService.MockAccounts)
+
+	ownerID, accID := int64(userID), int64(accountID)
+	expense := domain.Expense
+	from := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 5, 31, 0, 0, 0, 0, time.UTC)
+	payee := "Pharmacy 24"
+
+	results := []domain.TransactionSearchResult{
+		{
+			Transaction: domain.Transaction{ID: 1, Amount: 12.5, Type: domain.Expense, Payee: &payee,
+				CreatedAt: time.Date(2022, 4, 2, 0, 0, 0, 0, time.UTC), Status: domain.Cleared},
+			Rank:      0.8,
+			Highlight: "<b>Pharmacy</b> 24",
+		},
+	}
+
+	tests := []struct {
+		name                 string
+		query                string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "ok",
+			query: "?q=pharmacy",
+			mockBehaviour: func(s *mockService.MockTransactions, a *mockService.MockAccounts) {
+				s.EXPECT().Search(context.Background(), "pharmacy", domain.TransactionsFilter{OwnerId: &ownerID}, 50).
+					Return(results, nil)
+			},
+			expectedCodeStatus: 200,
+			expectedResponseBody: `[{"id":1,"amount":12.5,"type":"expense","createdAt":"2022-04-02T00:00:00Z",` +
+				`"status":"cleared","payee":"Pharmacy 24","rank":0.8,"highlight":"\u003cb\u003ePharmacy\u003c/b\u003e 24"}]`,
+		},
+		{
+			name:  "ok with filters",
+			query: "?q=pharmacy&accountId=2&type=expense&dateFrom=2022-03-01&dateTo=2022-05-31&limit=10",
+			mockBehaviour: func(s *mockService.MockTransactions, a *mockService.MockAccounts) {
+				a.EXPECT().Get(context.Background(), accID, ownerID).Return(domain.Account{}, nil)
+				s.EXPECT().Search(context.Background(), "pharmacy", domain.TransactionsFilter{
+					OwnerId:     &ownerID,
+					AccountId:   &accID,
+					Type:        &expense,
+					CreatedFrom: &from,
+					CreatedTo:   &to,
+				}, 10).Return([]domain.TransactionSearchResult{}, nil)
+			},
+			expectedCodeStatus:   200,
+			expectedResponseBody: `[]`,
+		},
+		{
+			name:  "account forbidden",
+			query: "?q=pharmacy&accountId=2",
+			mockBehaviour: func(s *mockService.MockTransactions, a *mockService.MockAccounts) {
+				a.EXPECT().Get(context.Background(), accID, ownerID).Return(domain.Account{}, service.ErrAccountForbidden)
+			},
+			expectedCodeStatus:   403,
+			expectedResponseBody: `{"message":"account forbidden to access"}`,
+		},
+		{
+			name:                 "invalid limit",
+			query:                "?q=pharmacy&limit=all",
+			mockBehaviour:        func(s *mockService.MockTransactions, a *mockService.MockAccounts) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"query param 'limit' must be integer - strconv.Atoi: parsing \"all\": invalid syntax"}`,
+		},
+		{
+			name:  "empty query",
+			query: "",
+			mockBehaviour: func(s *mockService.MockTransactions, a *mockService.MockAccounts) {
+				s.EXPECT().Search(context.Background(), "", gomock.Any(), 50).Return(nil, service.ErrSearchQueryEmpty)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"search query is empty"}`,
+		},
+		{
+			name:  "error",
+			query: "?q=pharmacy",
+			mockBehaviour: func(s *mockService.MockTransactions, a *mockService.MockAccounts) {
+				s.EXPECT().Search(context.Background(), "pharmacy", gomock.Any(), 50).
+					Return(nil, errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			tService := mockService.NewMockTransactions(c)
+			aService := mockService.NewMockAccounts(c)
+			tt.mockBehaviour(tService, aService)
+
+			services := &service.Services{Transactions: tService, Accounts: aService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/transactions/search", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.searchTransactions)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/transactions/search"+tt.query, bytes.NewBufferString(""))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
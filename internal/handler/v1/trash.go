@@ -0,0 +1,170 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"github.com/lotostudio/financial-api/internal/service"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handler) initTrashRoutes(api *gin.RouterGroup) {
+	trash := api.Group("/trash", h.userIdentity, h.limitUser)
+	{
+		trash.GET("", h.listTrash)
+		trash.POST("/accounts/:id/restore", h.restoreAccount)
+		trash.POST("/transactions/:id/restore", h.restoreTransaction)
+	}
+}
+
+// @Summary List trash
+// @Tags trash
+// @Description List deleted accounts and transactions of user, latest deleted first. Transactions of deleted accounts
+// @Description are not listed, they are restored with account
+// @ID listTrash
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} domain.Trash "Operation finished successfully"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 500 {object} response "Server error"
+// @Router /trash [get]
+func (h *Handler) listTrash(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	trash, err := h.s.Trash.List(c.Request.Context(), userId)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, trash)
+}
+
+// @Summary Restore account
+// @Tags trash
+// @Description Take account out of trash with its transactions
+// @ID restoreAccount
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Id of account"
+// @Success 204 {null} nil "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /trash/accounts/{id}/restore [post]
+func (h *Handler) restoreAccount(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'id' must be integer - "+err.Error())
+		return
+	}
+
+	err = h.s.Trash.RestoreAccount(c.Request.Context(), id, userId)
+
+	if errors.Is(err, repo.ErrAccountNotFound) || errors.Is(err, service.ErrAccountCountLimited) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, service.ErrAccountForbidden) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Restore transaction
+// @Tags trash
+// @Description Take transaction out of trash and apply it to balances of accounts again. Deleted accounts of
+// @Description transaction must be restored first
+// @ID restoreTransaction
+// @Security UsersAuth
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Id of transaction"
+// @Success 204 {null} nil "Operation finished successfully"
+// @Failure 400 {object} response "Invalid request"
+// @Failure 401 {object} response "Invalid authorization"
+// @Failure 403 {object} response "Invalid access"
+// @Failure 500 {object} response "Server error"
+// @Router /trash/transactions/{id}/restore [post]
+func (h *Handler) restoreTransaction(c *gin.Context) {
+	userIdString, ok := c.Get("userId")
+
+	if !ok {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	userId, err := strconv.ParseInt(userIdString.(string), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, "user not found")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	if err != nil {
+		newResponse(c, http.StatusBadRequest, "path param 'id' must be integer - "+err.Error())
+		return
+	}
+
+	err = h.s.Trash.RestoreTransaction(c.Request.Context(), id, userId)
+
+	if errors.Is(err, repo.ErrTransactionNotFound) || errors.Is(err, repo.ErrTransactionOwnerNotFound) ||
+		errors.Is(err, repo.ErrTransactionAccountDeleted) || errors.Is(err, repo.ErrAccountNotEnoughBalance) {
+		newResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, service.ErrTransactionForbidden) {
+		newResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if err != nil {
+		newResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,276 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"github.com/lotostudio/financial-api/internal/service"
+	mockService "github.com/lotostudio/financial-api/internal/service/mocks"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestHandler_listTrash(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockTrash)
+
+	deletedAt := time.Date(2022, 4, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name: "ok",
+			mockBehaviour: func(s *mockService.MockTrash) {
+				s.EXPECT().List(context.Background(), userID).Return(domain.Trash{
+					Accounts: []domain.Account{{ID: accountID, Title: "Cash", Balance: 100, Currency: "KZT",
+						Type: domain.Cash, CreatedAt: deletedAt, DeletedAt: &deletedAt}},
+					Transactions: []domain.Transaction{},
+				}, nil)
+			},
+			expectedCodeStatus: 200,
+			expectedResponseBody: `{"accounts":[{"id":2,"title":"Cash","balance":100,"currency":"KZT","type":"cash",` +
+				`"createdAt":"2022-04-02T10:00:00Z","deletedAt":"2022-04-02T10:00:00Z"}],"transactions":[]}`,
+		},
+		{
+			name: "error",
+			mockBehaviour: func(s *mockService.MockTrash) {
+				s.EXPECT().List(context.Background(), userID).Return(domain.Trash{}, errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			trService := mockService.NewMockTrash(c)
+			tt.mockBehaviour(trService)
+
+			services := &service.Services{Trash: trService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/trash", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.listTrash)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/trash", nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_restoreAccount(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockTrash)
+
+	tests := []struct {
+		name                 string
+		id                   string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name: "ok",
+			id:   strconv.FormatInt(accountID, 10),
+			mockBehaviour: func(s *mockService.MockTrash) {
+				s.EXPECT().RestoreAccount(context.Background(), accountID, userID).Return(nil)
+			},
+			expectedCodeStatus:   204,
+			expectedResponseBody: ``,
+		},
+		{
+			name:                 "invalid id",
+			id:                   "a",
+			mockBehaviour:        func(s *mockService.MockTrash) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"path param 'id' must be integer - strconv.ParseInt: parsing \"a\": invalid syntax"}`,
+		},
+		{
+			name: "not in trash",
+			id:   strconv.FormatInt(accountID, 10),
+			mockBehaviour: func(s *mockService.MockTrash) {
+				s.EXPECT().RestoreAccount(context.Background(), accountID, userID).Return(repo.ErrAccountNotFound)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"account doesn't exists"}`,
+		},
+		{
+			name: "limit",
+			id:   strconv.FormatInt(accountID, 10),
+			mockBehaviour: func(s *mockService.MockTrash) {
+				s.EXPECT().RestoreAccount(context.Background(), accountID, userID).Return(service.ErrAccountCountLimited)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"account count of this type reached limit"}`,
+		},
+		{
+			name: "forbidden",
+			id:   strconv.FormatInt(accountID, 10),
+			mockBehaviour: func(s *mockService.MockTrash) {
+				s.EXPECT().RestoreAccount(context.Background(), accountID, userID).Return(service.ErrAccountForbidden)
+			},
+			expectedCodeStatus:   403,
+			expectedResponseBody: `{"message":"account forbidden to access"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			trService := mockService.NewMockTrash(c)
+			tt.mockBehaviour(trService)
+
+			services := &service.Services{Trash: trService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/trash/accounts/:id/restore", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.restoreAccount)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/trash/accounts/"+tt.id+"/restore", nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_restoreTransaction(t *testing.T) {
+	type mockBehaviour func(s *mockService.MockTrash)
+
+	tests := []struct {
+		name                 string
+		id                   string
+		mockBehaviour        mockBehaviour
+		expectedCodeStatus   int
+		expectedResponseBody string
+	}{
+		{
+			name: "ok",
+			id:   strconv.FormatInt(transactionID, 10),
+			mockBehaviour: func(s *mockService.MockTrash) {
+				s.EXPECT().RestoreTransaction(context.Background(), transactionID, userID).Return(nil)
+			},
+			expectedCodeStatus:   204,
+			expectedResponseBody: ``,
+		},
+		{
+			name:                 "invalid id",
+			id:                   "a",
+			mockBehaviour:        func(s *mockService.MockTrash) {},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"path param 'id' must be integer - strconv.ParseInt: parsing \"a\": invalid syntax"}`,
+		},
+		{
+			name: "account deleted",
+			id:   strconv.FormatInt(transactionID, 10),
+			mockBehaviour: func(s *mockService.MockTrash) {
+				s.EXPECT().RestoreTransaction(context.Background(), transactionID, userID).
+					Return(repo.ErrTransactionAccountDeleted)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"account of transaction is deleted, restore it first"}`,
+		},
+		{
+			name: "not enough balance",
+			id:   strconv.FormatInt(transactionID, 10),
+			mockBehaviour: func(s *mockService.MockTrash) {
+				s.EXPECT().RestoreTransaction(context.Background(), transactionID, userID).
+					Return(repo.ErrAccountNotEnoughBalance)
+			},
+			expectedCodeStatus:   400,
+			expectedResponseBody: `{"message":"account doesn't have enough balance"}`,
+		},
+		{
+			name: "forbidden",
+			id:   strconv.FormatInt(transactionID, 10),
+			mockBehaviour: func(s *mockService.MockTrash) {
+				s.EXPECT().RestoreTransaction(context.Background(), transactionID, userID).
+					Return(service.ErrTransactionForbidden)
+			},
+			expectedCodeStatus:   403,
+			expectedResponseBody: `{"message":"transaction forbidden to access"}`,
+		},
+		{
+			name: "error",
+			id:   strconv.FormatInt(transactionID, 10),
+			mockBehaviour: func(s *mockService.MockTrash) {
+				s.EXPECT().RestoreTransaction(context.Background(), transactionID, userID).
+					Return(errors.New("general error"))
+			},
+			expectedCodeStatus:   500,
+			expectedResponseBody: `{"message":"general error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			trService := mockService.NewMockTrash(c)
+			tt.mockBehaviour(trService)
+
+			services := &service.Services{Trash: trService}
+			handler := &Handler{
+				s: services,
+			}
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/trash/transactions/:id/restore", func(c *gin.Context) {
+				c.Set(userCtx, strconv.FormatInt(userID, 10))
+			}, handler.restoreTransaction)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/trash/transactions/"+tt.id+"/restore", nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCodeStatus, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, w.Body.String())
+		})
+	}
+}
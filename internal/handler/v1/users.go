@@ -10,36 +10,22 @@ import (
 func (h *Handler) initUsersRoutes(api *gin.RouterGroup) {
 	users := api.Group("/users")
 	{
-		users.GET("", h.listUsers)
-
-		me := users.Group("/me", h.userIdentity)
+		me := users.Group("/me", h.userIdentity, h.limitUser)
 		{
 			me.GET("", h.getMe)
 			me.PATCH("", h.partialUpdateMe)
+			me.GET("/audit", h.listAudit)
+
+			tokens := me.Group("/tokens")
+			{
+				tokens.GET("", h.listAccessTokens)
+				tokens.POST("", h.createAccessToken)
+				tokens.DELETE("/:id", h.deleteAccessToken)
+			}
 		}
 	}
 }
 
-// @Summary List users
-// @Tags users
-// @Description List all users
-// @ID listUsers
-// @Accept json
-// @Produce json
-// @Success 200 {array} domain.User "Operation finished successfully"
-// @Failure 500 {object} response "Server error"
-// @Router /users [get]
-func (h *Handler) listUsers(c *gin.Context) {
-	users, err := h.s.Users.List(c.Request.Context())
-
-	if err != nil {
-		newResponse(c, http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	c.JSON(http.StatusOK, users)
-}
-
 // @Summary Retrieve me
 // @Tags users
 // @Description Retrieve authorized user
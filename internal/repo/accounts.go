@@ -19,18 +19,25 @@ func newAccountsRepo(db *sqlx.DB) *AccountsRepo {
 	}
 }
 
+// Balance of account a without pending transactions
+const accountClearedBalance = `a.balance - coalesce((
+	SELECT sum(CASE WHEN t.debit_id = a.id THEN t.amount ELSE -t.amount END)
+	FROM transactions t
+	WHERE t.status = 'pending' AND t.deleted_at IS NULL AND (t.credit_id = a.id OR t.debit_id = a.id)), 0)`
+
 func (r *AccountsRepo) List(ctx context.Context, userID int64) ([]domain.Account, error) {
 	accounts := make([]domain.Account, 0)
 
 	if err := r.db.SelectContext(ctx, &accounts, `
-	SELECT a.id, a.title, a.balance, cur.code currency, a.type, a.created_at, 
+	SELECT a.id, a.title, a.balance, `+accountClearedBalance+` AS cleared_balance, a.balance AS available_balance,
+	       cur.code currency, a.type, a.created_at, 
 	       coalesce(l.term, d.term) AS term, coalesce(l.rate, d.rate) AS rate, c.number
 	FROM accounts a 
     LEFT JOIN loans l ON a.id = l.account_id 
     LEFT JOIN deposits d ON a.id = d.account_id
 	LEFT JOIN cards c ON a.id = c.account_id
 	JOIN currencies cur ON a.currency_id = cur.id
-	WHERE a.owner_id = $1`, userID); err != nil {
+	WHERE a.owner_id = $1 AND a.deleted_at IS NULL`, userID); err != nil {
 		return nil, err
 	}
 
@@ -47,7 +54,7 @@ func (r *AccountsRepo) CountByTypes(ctx context.Context, userID int64, _type dom
 	query, args, err := sqlx.In(`
 	SELECT count(*)
 	FROM accounts a
-	WHERE a.owner_id = ? AND a.type IN (?)`, userID, aTypes)
+	WHERE a.owner_id = ? AND a.type IN (?) AND a.deleted_at IS NULL`, userID, aTypes)
 
 	if err != nil {
 		return 0, err
@@ -87,6 +94,10 @@ func (r *AccountsRepo) Create(ctx context.Context, toCreate domain.AccountToCrea
 		return account, err
 	}
 
+	// New account doesn't have pending transactions
+	balance := account.Balance
+	account.ClearedBalance, account.AvailableBalance = &balance, &balance
+
 	if account.Type == domain.Loan {
 		row = tx.QueryRowContext(ctx,
 			`INSERT INTO loans(term, rate, account_id) VALUES ($1, $2, $3) RETURNING term, rate`,
@@ -133,6 +144,10 @@ func (r *AccountsRepo) Create(ctx context.Context, toCreate domain.AccountToCrea
 		return account, err
 	}
 
+	if err = audit(ctx, tx, domain.AuditAccount, domain.AuditCreate, nil, account.ID); err != nil {
+		return account, err
+	}
+
 	return account, tx.Commit()
 }
 
@@ -140,14 +155,15 @@ func (r *AccountsRepo) Get(ctx context.Context, id int64) (domain.Account, error
 	var account domain.Account
 
 	if err := r.db.GetContext(ctx, &account, `
-	SELECT a.id, a.title, a.balance, cur.code currency, a.type, a.owner_id, a.created_at, 
+	SELECT a.id, a.title, a.balance, `+accountClearedBalance+` AS cleared_balance, a.balance AS available_balance,
+	       cur.code currency, a.type, a.owner_id, a.created_at, 
 	       coalesce(l.term, d.term) AS term, coalesce(l.rate, d.rate) AS rate, c.number
 	FROM accounts a 
     LEFT JOIN loans l ON a.id = l.account_id 
     LEFT JOIN deposits d ON a.id = d.account_id 
 	LEFT JOIN cards c ON a.id = c.account_id 
 	JOIN currencies cur ON a.currency_id = cur.id 
-	WHERE a.id = $1`, id); err != nil {
+	WHERE a.id = $1 AND a.deleted_at IS NULL`, id); err != nil {
 		if err == sql.ErrNoRows {
 			return domain.Account{}, ErrAccountNotFound
 		}
@@ -167,10 +183,18 @@ func (r *AccountsRepo) Update(ctx context.Context, toUpdate domain.AccountToUpda
 		return account, err
 	}
 
+	before, err := auditStates(ctx, tx, domain.AuditAccount, id)
+
+	if err != nil {
+		return account, err
+	}
+
 	row := tx.QueryRowContext(ctx, `UPDATE accounts a SET title = $1, balance = $2 WHERE a.id = $3 
-	RETURNING a.id, a.title, a.balance, a.type, a.created_at`, toUpdate.Title, toUpdate.Balance, id)
+	RETURNING a.id, a.title, a.balance, `+accountClearedBalance+`, a.balance, a.type, a.created_at`,
+		toUpdate.Title, toUpdate.Balance, id)
 
-	if err = row.Scan(&account.ID, &account.Title, &account.Balance, &account.Type, &account.CreatedAt); err != nil {
+	if err = row.Scan(&account.ID, &account.Title, &account.Balance, &account.ClearedBalance,
+		&account.AvailableBalance, &account.Type, &account.CreatedAt); err != nil {
 		if err := tx.Rollback(); err != nil {
 			return account, err
 		}
@@ -217,13 +241,47 @@ func (r *AccountsRepo) Update(ctx context.Context, toUpdate domain.AccountToUpda
 		}
 	}
 
+	if err = audit(ctx, tx, domain.AuditAccount, domain.AuditUpdate, before, id); err != nil {
+		return account, err
+	}
+
 	return account, tx.Commit()
 }
 
+// Delete moves account to trash. Its transactions and balances are kept until account is purged
 func (r *AccountsRepo) Delete(ctx context.Context, id int64) error {
-	_, err := r.db.ExecContext(ctx, "DELETE FROM accounts WHERE id = $1", id)
+	tx, err := r.db.Begin()
 
-	return err
+	if err != nil {
+		return err
+	}
+
+	before, err := auditStates(ctx, tx, domain.AuditAccount, id)
+
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, "UPDATE accounts SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL", id)
+
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	// Account already in trash is not deleted again
+	if count, _ := res.RowsAffected(); count == 0 {
+		return tx.Rollback()
+	}
+
+	if err = audit(ctx, tx, domain.AuditAccount, domain.AuditDelete, before, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 type AccountTypesRepo struct {
@@ -274,6 +332,35 @@ func (r *BalancesRepo) Get(ctx context.Context, accountID int64, date time.Time)
 	return b, nil
 }
 
+// History returns sums of balances of owner's accounts by types on last day of every period between from and to.
+// Balance of account on date is its last known balance, zero before first one
+func (r *BalancesRepo) History(ctx context.Context, ownerID int64, from time.Time, to time.Time,
+	interval domain.Interval) ([]domain.TypeBalance, error) {
+	balances := make([]domain.TypeBalance, 0)
+
+	if err := r.db.SelectContext(ctx, &balances, `
+	WITH dates AS (
+		SELECT least(p + ('1 ' || $1)::interval - interval '1 day', $3::timestamp)::date AS date
+		FROM generate_series(date_trunc($1, $2::timestamp), date_trunc($1, $3::timestamp), ('1 ' || $1)::interval) p
+	)
+	SELECT d.date, a.type, coalesce(sum(b.value), 0) AS value
+	FROM dates d
+	CROSS JOIN accounts a
+	LEFT JOIN LATERAL (
+		SELECT value
+		FROM balances
+		WHERE account_id = a.id AND date <= d.date
+		ORDER BY date DESC LIMIT 1
+	) b ON true
+	WHERE a.owner_id = $4 AND a.deleted_at IS NULL
+	GROUP BY d.date, a.type
+	ORDER BY d.date, a.type`, interval, from, to, ownerID); err != nil {
+		return nil, err
+	}
+
+	return balances, nil
+}
+
 // updateBalance actualize account balance into balances table with recent value
 func updateBalance(ctx context.Context, tx *sql.Tx, id int64, balance float64) error {
 	// update new entry in balances table for today
@@ -0,0 +1,87 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"github.com/lotostudio/financial-api/internal/domain"
+)
+
+type AttachmentsRepo struct {
+	db *sqlx.DB
+}
+
+func newAttachmentsRepo(db *sqlx.DB) *AttachmentsRepo {
+	return &AttachmentsRepo{
+		db: db,
+	}
+}
+
+func (r *AttachmentsRepo) List(ctx context.Context, transactionID int64) ([]domain.Attachment, error) {
+	attachments := make([]domain.Attachment, 0)
+
+	if err := r.db.SelectContext(ctx, &attachments, `
+	SELECT a.id, a.transaction_id, a.filename, a.content_type, a.size, a.key, a.thumbnail_key,
+		a.thumbnail_key IS NOT NULL AS has_thumbnail, a.created_at
+	FROM attachments a
+	WHERE a.transaction_id = $1
+	ORDER BY a.created_at, a.id`, transactionID); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func (r *AttachmentsRepo) Create(ctx context.Context, toCreate domain.Attachment) (domain.Attachment, error) {
+	var attachment domain.Attachment
+
+	if err := r.db.GetContext(ctx, &attachment, `
+	INSERT INTO attachments(transaction_id, filename, content_type, size, key, thumbnail_key)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING id, transaction_id, filename, content_type, size, key, thumbnail_key,
+		thumbnail_key IS NOT NULL AS has_thumbnail, created_at`,
+		toCreate.TransactionID, toCreate.Filename, toCreate.ContentType, toCreate.Size, toCreate.Key,
+		toCreate.ThumbnailKey); err != nil {
+		return attachment, err
+	}
+
+	return attachment, nil
+}
+
+func (r *AttachmentsRepo) Get(ctx context.Context, id int64) (domain.Attachment, error) {
+	var attachment domain.Attachment
+
+	if err := r.db.GetContext(ctx, &attachment, `
+	SELECT a.id, a.transaction_id, a.filename, a.content_type, a.size, a.key, a.thumbnail_key,
+		a.thumbnail_key IS NOT NULL AS has_thumbnail, a.created_at
+	FROM attachments a
+	WHERE a.id = $1`, id); err != nil {
+		if err == sql.ErrNoRows {
+			return attachment, ErrAttachmentNotFound
+		}
+
+		return attachment, err
+	}
+
+	return attachment, nil
+}
+
+func (r *AttachmentsRepo) Delete(ctx context.Context, id int64) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM attachments WHERE id = $1", id)
+
+	if err != nil {
+		return err
+	}
+
+	count, err := res.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return ErrAttachmentNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,243 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"strings"
+)
+
+type AuditRepo struct {
+	db *sqlx.DB
+}
+
+func newAuditRepo(db *sqlx.DB) *AuditRepo {
+	return &AuditRepo{
+		db: db,
+	}
+}
+
+// List returns entries of audit log of user by filter, latest first
+func (r *AuditRepo) List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	entries := make([]domain.AuditEntry, 0)
+
+	conditions := []string{"l.user_id = $1"}
+	args := []interface{}{filter.UserID}
+	argId := 2
+
+	if filter.Entity != nil {
+		conditions = append(conditions, fmt.Sprintf("l.entity = $%d", argId))
+		args = append(args, *filter.Entity)
+		argId++
+	}
+
+	if filter.EntityID != nil {
+		conditions = append(conditions, fmt.Sprintf("l.entity_id = $%d", argId))
+		args = append(args, *filter.EntityID)
+		argId++
+	}
+
+	if filter.Action != nil {
+		conditions = append(conditions, fmt.Sprintf("l.action = $%d", argId))
+		args = append(args, *filter.Action)
+		argId++
+	}
+
+	if filter.From != nil {
+		conditions = append(conditions, fmt.Sprintf("l.created_at >= $%d", argId))
+		args = append(args, *filter.From)
+		argId++
+	}
+
+	if filter.To != nil {
+		conditions = append(conditions, fmt.Sprintf("l.created_at < $%d", argId))
+		args = append(args, *filter.To)
+		argId++
+	}
+
+	query := fmt.Sprintf(`
+	SELECT l.id, l.user_id, l.actor_id, l.access_token_id, l.request_id, l.ip, l.entity, l.entity_id, l.action,
+	       l.before, l.after, l.created_at
+	FROM audit_log l
+	WHERE %s
+	ORDER BY l.created_at DESC, l.id DESC
+	LIMIT $%d OFFSET $%d`, strings.Join(conditions, " AND "), argId, argId+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	if err := r.db.SelectContext(ctx, &entries, query, args...); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// CreateLoginFailure records failed login of user by email. Nothing is recorded for unknown email
+func (r *AuditRepo) CreateLoginFailure(ctx context.Context, email string) error {
+	actor := domain.ActorFrom(ctx)
+
+	_, err := r.db.ExecContext(ctx, `
+	INSERT INTO audit_log(user_id, actor_id, access_token_id, request_id, ip, entity, entity_id, action)
+	SELECT u.id, $2, $3, $4, $5, $6, u.id, $7
+	FROM users u
+	WHERE lower(u.email) = lower($1)`,
+		email, actor.UserID, actor.AccessTokenID, actor.RequestID, actor.IP, domain.AuditUser, domain.AuditLoginFailure)
+
+	return err
+}
+
+// auditState is state of audited entity saved to audit log
+type auditState struct {
+	owner *int64
+	data  *string
+}
+
+// Queries of states of audited entities by IDs with owners of entities. Secrets are never saved to audit log
+var auditStateQueries = map[domain.AuditEntity]string{
+	domain.AuditAccount: `
+	SELECT a.id, a.owner_id, to_jsonb(a) || jsonb_build_object('term', coalesce(l.term, d.term),
+	       'rate', coalesce(l.rate, d.rate), 'number', c.number)
+	FROM accounts a
+	LEFT JOIN loans l ON a.id = l.account_id
+	LEFT JOIN deposits d ON a.id = d.account_id
+	LEFT JOIN cards c ON a.id = c.account_id
+	WHERE a.id = ANY($1)`,
+	domain.AuditTransaction: `
+	SELECT t.id, coalesce(cr.owner_id, db.owner_id), (to_jsonb(t) - 'search') || jsonb_build_object(
+	       'tags', (SELECT coalesce(jsonb_agg(tg.title ORDER BY tg.title), '[]')
+	                FROM transaction_tags tt JOIN tags tg ON tt.tag_id = tg.id WHERE tt.transaction_id = t.id),
+	       'lines', (SELECT coalesce(jsonb_agg(to_jsonb(l) - 'transaction_id' ORDER BY l.id), '[]')
+	                 FROM transaction_lines l WHERE l.transaction_id = t.id))
+	FROM transactions t
+	LEFT JOIN accounts cr ON t.credit_id = cr.id
+	LEFT JOIN accounts db ON t.debit_id = db.id
+	WHERE t.id = ANY($1)`,
+	domain.AuditUser: `
+	SELECT u.id, u.id, to_jsonb(u) - 'password'
+	FROM users u
+	WHERE u.id = ANY($1)`,
+	domain.AuditAccessToken: `
+	SELECT t.id, t.user_id, to_jsonb(t) - 'token_hash'
+	FROM access_tokens t
+	WHERE t.id = ANY($1)`,
+}
+
+// auditStates returns states of audited entities by IDs, entities not found are left out.
+// Rolls back transaction on error
+func auditStates(ctx context.Context, tx *sql.Tx, entity domain.AuditEntity, ids ...int64) (map[int64]auditState,
+	error) {
+	states := make(map[int64]auditState, len(ids))
+
+	rows, err := tx.QueryContext(ctx, auditStateQueries[entity], pq.Array(ids))
+
+	if err == nil {
+		for rows.Next() {
+			var id int64
+			var state auditState
+
+			if err = rows.Scan(&id, &state.owner, &state.data); err != nil {
+				break
+			}
+
+			states[id] = state
+		}
+
+		_ = rows.Close()
+
+		if err == nil {
+			err = rows.Err()
+		}
+	}
+
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	return states, nil
+}
+
+// audit writes entries of action to audit log in database transaction of change, one for every entity by IDs. States
+// after change are read here, states before change must be read by caller with auditStates, nil for created entities.
+// Rolls back transaction on error
+func audit(ctx context.Context, tx *sql.Tx, entity domain.AuditEntity, action domain.AuditAction,
+	before map[int64]auditState, ids ...int64) error {
+	after, err := auditStates(ctx, tx, entity, ids...)
+
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		// Removed entity has only state before change
+		owner := after[id].owner
+
+		if owner == nil {
+			owner = before[id].owner
+		}
+
+		if owner == nil {
+			continue
+		}
+
+		if err = createAuditEntry(ctx, tx, *owner, entity, id, action, before[id].data, after[id].data); err != nil {
+			if err := tx.Rollback(); err != nil {
+				return err
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// execer executes queries either in database transaction or out of it
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// createAuditEntry writes entry of audit log by actor of context
+func createAuditEntry(ctx context.Context, e execer, userID int64, entity domain.AuditEntity, entityID int64,
+	action domain.AuditAction, before *string, after *string) error {
+	actor := domain.ActorFrom(ctx)
+
+	_, err := e.ExecContext(ctx, `
+	INSERT INTO audit_log(user_id, actor_id, access_token_id, request_id, ip, entity, entity_id, action, before, after)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		userID, actor.UserID, actor.AccessTokenID, actor.RequestID, actor.IP, entity, entityID, action, before, after)
+
+	return err
+}
+
+// selectIDs returns IDs selected by query in database transaction
+func selectIDs(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]int64, error) {
+	ids := make([]int64, 0)
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var id int64
+
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
@@ -7,14 +7,28 @@ var (
 	ErrUserNotFound      = errors.New("user doesn't exists")
 	ErrSessionNotFound   = errors.New("session doesn't exists")
 
+	ErrAccessTokenNotFound = errors.New("access token doesn't exists")
+
+	ErrOIDCFlowNotFound     = errors.New("authorization flow doesn't exists")
+	ErrUserIdentityNotFound = errors.New("user identity doesn't exists")
+
 	ErrCurrencyNotFound = errors.New("currency doesn't exists")
 
 	ErrTransactionNotFound         = errors.New("transaction doesn't exists")
 	ErrTransactionOwnerNotFound    = errors.New("transaction owner doesn't exists")
 	ErrTransactionCategoryNotFound = errors.New("transaction category doesn't exists")
+	ErrTransactionAccountDeleted   = errors.New("account of transaction is deleted, restore it first")
+
+	ErrTransactionTemplateNotFound      = errors.New("transaction template doesn't exists")
+	ErrTransactionTemplateAlreadyExists = errors.New("transaction template with this name already exists")
 
 	ErrAccountNotFound         = errors.New("account doesn't exists")
 	ErrAccountNotEnoughBalance = errors.New("account doesn't have enough balance")
 
 	ErrBalanceNotFound = errors.New("balance doesn't exists")
+
+	ErrAttachmentNotFound = errors.New("attachment doesn't exists")
+
+	ErrReconciliationNotFound   = errors.New("reconciliation doesn't exists")
+	ErrReconciliationInProgress = errors.New("other reconciliation of account is in progress")
 )
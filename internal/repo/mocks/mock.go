@@ -81,6 +81,21 @@ func (mr *MockUsersMockRecorder) GetByCredentials(ctx, email, password interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCredentials", reflect.TypeOf((*MockUsers)(nil).GetByCredentials), ctx, email, password)
 }
 
+// GetByEmail mocks base method.
+func (m *MockUsers) GetByEmail(ctx context.Context, email string) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByEmail", ctx, email)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByEmail indicates an expected call of GetByEmail.
+func (mr *MockUsersMockRecorder) GetByEmail(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmail", reflect.TypeOf((*MockUsers)(nil).GetByEmail), ctx, email)
+}
+
 // List mocks base method.
 func (m *MockUsers) List(ctx context.Context) ([]domain.User, error) {
 	m.ctrl.T.Helper()
@@ -96,6 +111,21 @@ func (mr *MockUsersMockRecorder) List(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUsers)(nil).List), ctx)
 }
 
+// SetDisabled mocks base method.
+func (m *MockUsers) SetDisabled(ctx context.Context, id int64, disabled bool) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDisabled", ctx, id, disabled)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetDisabled indicates an expected call of SetDisabled.
+func (mr *MockUsersMockRecorder) SetDisabled(ctx, id, disabled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDisabled", reflect.TypeOf((*MockUsers)(nil).SetDisabled), ctx, id, disabled)
+}
+
 // UpdatePassword mocks base method.
 func (m *MockUsers) UpdatePassword(ctx context.Context, userID int64, toUpdate domain.UserToUpdate) (domain.User, error) {
 	m.ctrl.T.Helper()
@@ -163,6 +193,35 @@ func (mr *MockSessionsMockRecorder) GetByToken(ctx, token interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByToken", reflect.TypeOf((*MockSessions)(nil).GetByToken), ctx, token)
 }
 
+// GetByUser mocks base method.
+func (m *MockSessions) GetByUser(ctx context.Context, userID int64) (domain.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUser", ctx, userID)
+	ret0, _ := ret[0].(domain.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUser indicates an expected call of GetByUser.
+func (mr *MockSessionsMockRecorder) GetByUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUser", reflect.TypeOf((*MockSessions)(nil).GetByUser), ctx, userID)
+}
+
+// Reset mocks base method.
+func (m *MockSessions) Reset(ctx context.Context, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockSessionsMockRecorder) Reset(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockSessions)(nil).Reset), ctx, userID)
+}
+
 // Update mocks base method.
 func (m *MockSessions) Update(ctx context.Context, toUpdate domain.SessionToUpdate, userID int64) (domain.Session, error) {
 	m.ctrl.T.Helper()
@@ -178,6 +237,221 @@ func (mr *MockSessionsMockRecorder) Update(ctx, toUpdate, userID interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSessions)(nil).Update), ctx, toUpdate, userID)
 }
 
+// MockAccessTokens is a mock of AccessTokens interface.
+type MockAccessTokens struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessTokensMockRecorder
+}
+
+// MockAccessTokensMockRecorder is the mock recorder for MockAccessTokens.
+type MockAccessTokensMockRecorder struct {
+	mock *MockAccessTokens
+}
+
+// NewMockAccessTokens creates a new mock instance.
+func NewMockAccessTokens(ctrl *gomock.Controller) *MockAccessTokens {
+	mock := &MockAccessTokens{ctrl: ctrl}
+	mock.recorder = &MockAccessTokensMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccessTokens) EXPECT() *MockAccessTokensMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAccessTokens) Create(ctx context.Context, toCreate domain.AccessTokenToCreate, hash string, userID int64) (domain.AccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, toCreate, hash, userID)
+	ret0, _ := ret[0].(domain.AccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAccessTokensMockRecorder) Create(ctx, toCreate, hash, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAccessTokens)(nil).Create), ctx, toCreate, hash, userID)
+}
+
+// Delete mocks base method.
+func (m *MockAccessTokens) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAccessTokensMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAccessTokens)(nil).Delete), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockAccessTokens) Get(ctx context.Context, id int64) (domain.AccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(domain.AccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAccessTokensMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAccessTokens)(nil).Get), ctx, id)
+}
+
+// GetByHash mocks base method.
+func (m *MockAccessTokens) GetByHash(ctx context.Context, hash string) (domain.AccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByHash", ctx, hash)
+	ret0, _ := ret[0].(domain.AccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByHash indicates an expected call of GetByHash.
+func (mr *MockAccessTokensMockRecorder) GetByHash(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByHash", reflect.TypeOf((*MockAccessTokens)(nil).GetByHash), ctx, hash)
+}
+
+// List mocks base method.
+func (m *MockAccessTokens) List(ctx context.Context, userID int64) ([]domain.AccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID)
+	ret0, _ := ret[0].([]domain.AccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAccessTokensMockRecorder) List(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAccessTokens)(nil).List), ctx, userID)
+}
+
+// UpdateLastUsed mocks base method.
+func (m *MockAccessTokens) UpdateLastUsed(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLastUsed", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLastUsed indicates an expected call of UpdateLastUsed.
+func (mr *MockAccessTokensMockRecorder) UpdateLastUsed(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastUsed", reflect.TypeOf((*MockAccessTokens)(nil).UpdateLastUsed), ctx, id)
+}
+
+// MockOIDCFlows is a mock of OIDCFlows interface.
+type MockOIDCFlows struct {
+	ctrl     *gomock.Controller
+	recorder *MockOIDCFlowsMockRecorder
+}
+
+// MockOIDCFlowsMockRecorder is the mock recorder for MockOIDCFlows.
+type MockOIDCFlowsMockRecorder struct {
+	mock *MockOIDCFlows
+}
+
+// NewMockOIDCFlows creates a new mock instance.
+func NewMockOIDCFlows(ctrl *gomock.Controller) *MockOIDCFlows {
+	mock := &MockOIDCFlows{ctrl: ctrl}
+	mock.recorder = &MockOIDCFlowsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOIDCFlows) EXPECT() *MockOIDCFlowsMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockOIDCFlows) Create(ctx context.Context, flow domain.OIDCFlow) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, flow)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOIDCFlowsMockRecorder) Create(ctx, flow interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOIDCFlows)(nil).Create), ctx, flow)
+}
+
+// Pop mocks base method.
+func (m *MockOIDCFlows) Pop(ctx context.Context, state string) (domain.OIDCFlow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pop", ctx, state)
+	ret0, _ := ret[0].(domain.OIDCFlow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Pop indicates an expected call of Pop.
+func (mr *MockOIDCFlowsMockRecorder) Pop(ctx, state interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pop", reflect.TypeOf((*MockOIDCFlows)(nil).Pop), ctx, state)
+}
+
+// MockUserIdentities is a mock of UserIdentities interface.
+type MockUserIdentities struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserIdentitiesMockRecorder
+}
+
+// MockUserIdentitiesMockRecorder is the mock recorder for MockUserIdentities.
+type MockUserIdentitiesMockRecorder struct {
+	mock *MockUserIdentities
+}
+
+// NewMockUserIdentities creates a new mock instance.
+func NewMockUserIdentities(ctrl *gomock.Controller) *MockUserIdentities {
+	mock := &MockUserIdentities{ctrl: ctrl}
+	mock.recorder = &MockUserIdentitiesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserIdentities) EXPECT() *MockUserIdentitiesMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockUserIdentities) Create(ctx context.Context, identity domain.UserIdentity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockUserIdentitiesMockRecorder) Create(ctx, identity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserIdentities)(nil).Create), ctx, identity)
+}
+
+// Get mocks base method.
+func (m *MockUserIdentities) Get(ctx context.Context, provider, subject string) (domain.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, provider, subject)
+	ret0, _ := ret[0].(domain.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockUserIdentitiesMockRecorder) Get(ctx, provider, subject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUserIdentities)(nil).Get), ctx, provider, subject)
+}
+
 // MockCurrencies is a mock of Currencies interface.
 type MockCurrencies struct {
 	ctrl     *gomock.Controller
@@ -409,6 +683,21 @@ func (m *MockTransactions) EXPECT() *MockTransactionsMockRecorder {
 	return m.recorder
 }
 
+// CashFlow mocks base method.
+func (m *MockTransactions) CashFlow(ctx context.Context, filter domain.TransactionsFilter, interval domain.Interval) ([]domain.CashFlow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CashFlow", ctx, filter, interval)
+	ret0, _ := ret[0].([]domain.CashFlow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CashFlow indicates an expected call of CashFlow.
+func (mr *MockTransactionsMockRecorder) CashFlow(ctx, filter, interval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CashFlow", reflect.TypeOf((*MockTransactions)(nil).CashFlow), ctx, filter, interval)
+}
+
 // Create mocks base method.
 func (m *MockTransactions) Create(ctx context.Context, toCreate domain.TransactionToCreate, categoryId, creditId, debitId *int64) (domain.Transaction, error) {
 	m.ctrl.T.Helper()
@@ -424,6 +713,21 @@ func (mr *MockTransactionsMockRecorder) Create(ctx, toCreate, categoryId, credit
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransactions)(nil).Create), ctx, toCreate, categoryId, creditId, debitId)
 }
 
+// CreateBatch mocks base method.
+func (m *MockTransactions) CreateBatch(ctx context.Context, items []domain.TransactionBatchItem) ([]domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, items)
+	ret0, _ := ret[0].([]domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockTransactionsMockRecorder) CreateBatch(ctx, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockTransactions)(nil).CreateBatch), ctx, items)
+}
+
 // Delete mocks base method.
 func (m *MockTransactions) Delete(ctx context.Context, id int64) error {
 	m.ctrl.T.Helper()
@@ -438,6 +742,34 @@ func (mr *MockTransactionsMockRecorder) Delete(ctx, id interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTransactions)(nil).Delete), ctx, id)
 }
 
+// DeleteBatch mocks base method.
+func (m *MockTransactions) DeleteBatch(ctx context.Context, ids []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBatch", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBatch indicates an expected call of DeleteBatch.
+func (mr *MockTransactionsMockRecorder) DeleteBatch(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBatch", reflect.TypeOf((*MockTransactions)(nil).DeleteBatch), ctx, ids)
+}
+
+// DismissFlags mocks base method.
+func (m *MockTransactions) DismissFlags(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DismissFlags", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DismissFlags indicates an expected call of DismissFlags.
+func (mr *MockTransactionsMockRecorder) DismissFlags(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DismissFlags", reflect.TypeOf((*MockTransactions)(nil).DismissFlags), ctx, id)
+}
+
 // GetOwner mocks base method.
 func (m *MockTransactions) GetOwner(ctx context.Context, id int64) (int64, error) {
 	m.ctrl.T.Helper()
@@ -453,6 +785,21 @@ func (mr *MockTransactionsMockRecorder) GetOwner(ctx, id interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOwner", reflect.TypeOf((*MockTransactions)(nil).GetOwner), ctx, id)
 }
 
+// GetStatus mocks base method.
+func (m *MockTransactions) GetStatus(ctx context.Context, id int64) (domain.TransactionStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatus", ctx, id)
+	ret0, _ := ret[0].(domain.TransactionStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatus indicates an expected call of GetStatus.
+func (mr *MockTransactionsMockRecorder) GetStatus(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*MockTransactions)(nil).GetStatus), ctx, id)
+}
+
 // List mocks base method.
 func (m *MockTransactions) List(ctx context.Context, filter domain.TransactionsFilter) ([]domain.Transaction, error) {
 	m.ctrl.T.Helper()
@@ -468,70 +815,625 @@ func (mr *MockTransactionsMockRecorder) List(ctx, filter interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTransactions)(nil).List), ctx, filter)
 }
 
-// Stats mocks base method.
-func (m *MockTransactions) Stats(ctx context.Context, filter domain.TransactionsFilter) ([]domain.TransactionStat, error) {
+// ListUnscannedOwners mocks base method.
+func (m *MockTransactions) ListUnscannedOwners(ctx context.Context, to time.Time) ([]int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Stats", ctx, filter)
-	ret0, _ := ret[0].([]domain.TransactionStat)
+	ret := m.ctrl.Call(m, "ListUnscannedOwners", ctx, to)
+	ret0, _ := ret[0].([]int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Stats indicates an expected call of Stats.
-func (mr *MockTransactionsMockRecorder) Stats(ctx, filter interface{}) *gomock.Call {
+// ListUnscannedOwners indicates an expected call of ListUnscannedOwners.
+func (mr *MockTransactionsMockRecorder) ListUnscannedOwners(ctx, to interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockTransactions)(nil).Stats), ctx, filter)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnscannedOwners", reflect.TypeOf((*MockTransactions)(nil).ListUnscannedOwners), ctx, to)
 }
 
-// MockTransactionCategories is a mock of TransactionCategories interface.
-type MockTransactionCategories struct {
-	ctrl     *gomock.Controller
-	recorder *MockTransactionCategoriesMockRecorder
+// Search mocks base method.
+func (m *MockTransactions) Search(ctx context.Context, query string, filter domain.TransactionsFilter, limit int) ([]domain.TransactionSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query, filter, limit)
+	ret0, _ := ret[0].([]domain.TransactionSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// MockTransactionCategoriesMockRecorder is the mock recorder for MockTransactionCategories.
-type MockTransactionCategoriesMockRecorder struct {
-	mock *MockTransactionCategories
+// Search indicates an expected call of Search.
+func (mr *MockTransactionsMockRecorder) Search(ctx, query, filter, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockTransactions)(nil).Search), ctx, query, filter, limit)
 }
 
-// NewMockTransactionCategories creates a new mock instance.
-func NewMockTransactionCategories(ctrl *gomock.Controller) *MockTransactionCategories {
-	mock := &MockTransactionCategories{ctrl: ctrl}
-	mock.recorder = &MockTransactionCategoriesMockRecorder{mock}
-	return mock
+// SetFlags mocks base method.
+func (m *MockTransactions) SetFlags(ctx context.Context, flags map[int64][]domain.TransactionFlag) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFlags", ctx, flags)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockTransactionCategories) EXPECT() *MockTransactionCategoriesMockRecorder {
-	return m.recorder
+// SetFlags indicates an expected call of SetFlags.
+func (mr *MockTransactionsMockRecorder) SetFlags(ctx, flags interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFlags", reflect.TypeOf((*MockTransactions)(nil).SetFlags), ctx, flags)
 }
 
-// Get mocks base method.
-func (m *MockTransactionCategories) Get(ctx context.Context, id int64) (domain.TransactionCategory, error) {
+// SetScannedBefore mocks base method.
+func (m *MockTransactions) SetScannedBefore(ctx context.Context, ownerID int64, before time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", ctx, id)
-	ret0, _ := ret[0].(domain.TransactionCategory)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "SetScannedBefore", ctx, ownerID, before)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// Get indicates an expected call of Get.
-func (mr *MockTransactionCategoriesMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+// SetScannedBefore indicates an expected call of SetScannedBefore.
+func (mr *MockTransactionsMockRecorder) SetScannedBefore(ctx, ownerID, before interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTransactionCategories)(nil).Get), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetScannedBefore", reflect.TypeOf((*MockTransactions)(nil).SetScannedBefore), ctx, ownerID, before)
 }
 
-// List mocks base method.
-func (m *MockTransactionCategories) List(ctx context.Context) ([]domain.TransactionCategory, error) {
+// SetStatus mocks base method.
+func (m *MockTransactions) SetStatus(ctx context.Context, id int64, status domain.TransactionStatus) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx)
-	ret0, _ := ret[0].([]domain.TransactionCategory)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "SetStatus", ctx, id, status)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// List indicates an expected call of List.
-func (mr *MockTransactionCategoriesMockRecorder) List(ctx interface{}) *gomock.Call {
+// SetStatus indicates an expected call of SetStatus.
+func (mr *MockTransactionsMockRecorder) SetStatus(ctx, id, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStatus", reflect.TypeOf((*MockTransactions)(nil).SetStatus), ctx, id, status)
+}
+
+// Stats mocks base method.
+func (m *MockTransactions) Stats(ctx context.Context, filter domain.TransactionsFilter, groupBy domain.StatsGroup) ([]domain.TransactionStat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats", ctx, filter, groupBy)
+	ret0, _ := ret[0].([]domain.TransactionStat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockTransactionsMockRecorder) Stats(ctx, filter, groupBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockTransactions)(nil).Stats), ctx, filter, groupBy)
+}
+
+// MockAttachments is a mock of Attachments interface.
+type MockAttachments struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttachmentsMockRecorder
+}
+
+// MockAttachmentsMockRecorder is the mock recorder for MockAttachments.
+type MockAttachmentsMockRecorder struct {
+	mock *MockAttachments
+}
+
+// NewMockAttachments creates a new mock instance.
+func NewMockAttachments(ctrl *gomock.Controller) *MockAttachments {
+	mock := &MockAttachments{ctrl: ctrl}
+	mock.recorder = &MockAttachmentsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttachments) EXPECT() *MockAttachmentsMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAttachments) Create(ctx context.Context, toCreate domain.Attachment) (domain.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, toCreate)
+	ret0, _ := ret[0].(domain.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAttachmentsMockRecorder) Create(ctx, toCreate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAttachments)(nil).Create), ctx, toCreate)
+}
+
+// Delete mocks base method.
+func (m *MockAttachments) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAttachmentsMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAttachments)(nil).Delete), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockAttachments) Get(ctx context.Context, id int64) (domain.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(domain.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAttachmentsMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAttachments)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockAttachments) List(ctx context.Context, transactionID int64) ([]domain.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, transactionID)
+	ret0, _ := ret[0].([]domain.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAttachmentsMockRecorder) List(ctx, transactionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAttachments)(nil).List), ctx, transactionID)
+}
+
+// MockReconciliations is a mock of Reconciliations interface.
+type MockReconciliations struct {
+	ctrl     *gomock.Controller
+	recorder *MockReconciliationsMockRecorder
+}
+
+// MockReconciliationsMockRecorder is the mock recorder for MockReconciliations.
+type MockReconciliationsMockRecorder struct {
+	mock *MockReconciliations
+}
+
+// NewMockReconciliations creates a new mock instance.
+func NewMockReconciliations(ctrl *gomock.Controller) *MockReconciliations {
+	mock := &MockReconciliations{ctrl: ctrl}
+	mock.recorder = &MockReconciliationsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReconciliations) EXPECT() *MockReconciliationsMockRecorder {
+	return m.recorder
+}
+
+// Complete mocks base method.
+func (m *MockReconciliations) Complete(ctx context.Context, id int64) (domain.Reconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, id)
+	ret0, _ := ret[0].(domain.Reconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockReconciliationsMockRecorder) Complete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockReconciliations)(nil).Complete), ctx, id)
+}
+
+// Create mocks base method.
+func (m *MockReconciliations) Create(ctx context.Context, accountID int64, toCreate domain.ReconciliationToCreate) (domain.Reconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, accountID, toCreate)
+	ret0, _ := ret[0].(domain.Reconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockReconciliationsMockRecorder) Create(ctx, accountID, toCreate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReconciliations)(nil).Create), ctx, accountID, toCreate)
+}
+
+// Delete mocks base method.
+func (m *MockReconciliations) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockReconciliationsMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockReconciliations)(nil).Delete), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockReconciliations) Get(ctx context.Context, id int64) (domain.Reconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(domain.Reconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockReconciliationsMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockReconciliations)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockReconciliations) List(ctx context.Context, accountID int64) ([]domain.Reconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, accountID)
+	ret0, _ := ret[0].([]domain.Reconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockReconciliationsMockRecorder) List(ctx, accountID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockReconciliations)(nil).List), ctx, accountID)
+}
+
+// ListTransactions mocks base method.
+func (m *MockReconciliations) ListTransactions(ctx context.Context, id int64) ([]domain.ReconciliationTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactions", ctx, id)
+	ret0, _ := ret[0].([]domain.ReconciliationTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransactions indicates an expected call of ListTransactions.
+func (mr *MockReconciliationsMockRecorder) ListTransactions(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactions", reflect.TypeOf((*MockReconciliations)(nil).ListTransactions), ctx, id)
+}
+
+// Tick mocks base method.
+func (m *MockReconciliations) Tick(ctx context.Context, id, transactionID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tick", ctx, id, transactionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Tick indicates an expected call of Tick.
+func (mr *MockReconciliationsMockRecorder) Tick(ctx, id, transactionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tick", reflect.TypeOf((*MockReconciliations)(nil).Tick), ctx, id, transactionID)
+}
+
+// Untick mocks base method.
+func (m *MockReconciliations) Untick(ctx context.Context, id, transactionID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Untick", ctx, id, transactionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Untick indicates an expected call of Untick.
+func (mr *MockReconciliationsMockRecorder) Untick(ctx, id, transactionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Untick", reflect.TypeOf((*MockReconciliations)(nil).Untick), ctx, id, transactionID)
+}
+
+// MockTrash is a mock of Trash interface.
+type MockTrash struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrashMockRecorder
+}
+
+// MockTrashMockRecorder is the mock recorder for MockTrash.
+type MockTrashMockRecorder struct {
+	mock *MockTrash
+}
+
+// NewMockTrash creates a new mock instance.
+func NewMockTrash(ctrl *gomock.Controller) *MockTrash {
+	mock := &MockTrash{ctrl: ctrl}
+	mock.recorder = &MockTrashMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrash) EXPECT() *MockTrashMockRecorder {
+	return m.recorder
+}
+
+// GetAccount mocks base method.
+func (m *MockTrash) GetAccount(ctx context.Context, id int64) (domain.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccount", ctx, id)
+	ret0, _ := ret[0].(domain.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccount indicates an expected call of GetAccount.
+func (mr *MockTrashMockRecorder) GetAccount(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockTrash)(nil).GetAccount), ctx, id)
+}
+
+// GetTransactionOwner mocks base method.
+func (m *MockTrash) GetTransactionOwner(ctx context.Context, id int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionOwner", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionOwner indicates an expected call of GetTransactionOwner.
+func (mr *MockTrashMockRecorder) GetTransactionOwner(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionOwner", reflect.TypeOf((*MockTrash)(nil).GetTransactionOwner), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockTrash) List(ctx context.Context, userID int64) (domain.Trash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID)
+	ret0, _ := ret[0].(domain.Trash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTrashMockRecorder) List(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTrash)(nil).List), ctx, userID)
+}
+
+// Purge mocks base method.
+func (m *MockTrash) Purge(ctx context.Context, before time.Time) (int64, []string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].([]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockTrashMockRecorder) Purge(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockTrash)(nil).Purge), ctx, before)
+}
+
+// RestoreAccount mocks base method.
+func (m *MockTrash) RestoreAccount(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreAccount", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreAccount indicates an expected call of RestoreAccount.
+func (mr *MockTrashMockRecorder) RestoreAccount(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreAccount", reflect.TypeOf((*MockTrash)(nil).RestoreAccount), ctx, id)
+}
+
+// RestoreTransaction mocks base method.
+func (m *MockTrash) RestoreTransaction(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTransaction", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreTransaction indicates an expected call of RestoreTransaction.
+func (mr *MockTrashMockRecorder) RestoreTransaction(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTransaction", reflect.TypeOf((*MockTrash)(nil).RestoreTransaction), ctx, id)
+}
+
+// MockTransactionTemplates is a mock of TransactionTemplates interface.
+type MockTransactionTemplates struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionTemplatesMockRecorder
+}
+
+// MockTransactionTemplatesMockRecorder is the mock recorder for MockTransactionTemplates.
+type MockTransactionTemplatesMockRecorder struct {
+	mock *MockTransactionTemplates
+}
+
+// NewMockTransactionTemplates creates a new mock instance.
+func NewMockTransactionTemplates(ctrl *gomock.Controller) *MockTransactionTemplates {
+	mock := &MockTransactionTemplates{ctrl: ctrl}
+	mock.recorder = &MockTransactionTemplatesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionTemplates) EXPECT() *MockTransactionTemplatesMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTransactionTemplates) Create(ctx context.Context, toCreate domain.TransactionTemplateToCreate, userID int64) (domain.TransactionTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, toCreate, userID)
+	ret0, _ := ret[0].(domain.TransactionTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTransactionTemplatesMockRecorder) Create(ctx, toCreate, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransactionTemplates)(nil).Create), ctx, toCreate, userID)
+}
+
+// Delete mocks base method.
+func (m *MockTransactionTemplates) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTransactionTemplatesMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTransactionTemplates)(nil).Delete), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockTransactionTemplates) Get(ctx context.Context, id int64) (domain.TransactionTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(domain.TransactionTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockTransactionTemplatesMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTransactionTemplates)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockTransactionTemplates) List(ctx context.Context, userID int64) ([]domain.TransactionTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID)
+	ret0, _ := ret[0].([]domain.TransactionTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTransactionTemplatesMockRecorder) List(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTransactionTemplates)(nil).List), ctx, userID)
+}
+
+// Update mocks base method.
+func (m *MockTransactionTemplates) Update(ctx context.Context, id int64, toUpdate domain.TransactionTemplateToCreate) (domain.TransactionTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, toUpdate)
+	ret0, _ := ret[0].(domain.TransactionTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTransactionTemplatesMockRecorder) Update(ctx, id, toUpdate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTransactionTemplates)(nil).Update), ctx, id, toUpdate)
+}
+
+// MockAudit is a mock of Audit interface.
+type MockAudit struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditMockRecorder
+}
+
+// MockAuditMockRecorder is the mock recorder for MockAudit.
+type MockAuditMockRecorder struct {
+	mock *MockAudit
+}
+
+// NewMockAudit creates a new mock instance.
+func NewMockAudit(ctrl *gomock.Controller) *MockAudit {
+	mock := &MockAudit{ctrl: ctrl}
+	mock.recorder = &MockAuditMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAudit) EXPECT() *MockAuditMockRecorder {
+	return m.recorder
+}
+
+// CreateLoginFailure mocks base method.
+func (m *MockAudit) CreateLoginFailure(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLoginFailure", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLoginFailure indicates an expected call of CreateLoginFailure.
+func (mr *MockAuditMockRecorder) CreateLoginFailure(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoginFailure", reflect.TypeOf((*MockAudit)(nil).CreateLoginFailure), ctx, email)
+}
+
+// List mocks base method.
+func (m *MockAudit) List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]domain.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAuditMockRecorder) List(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAudit)(nil).List), ctx, filter)
+}
+
+// MockTransactionCategories is a mock of TransactionCategories interface.
+type MockTransactionCategories struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionCategoriesMockRecorder
+}
+
+// MockTransactionCategoriesMockRecorder is the mock recorder for MockTransactionCategories.
+type MockTransactionCategoriesMockRecorder struct {
+	mock *MockTransactionCategories
+}
+
+// NewMockTransactionCategories creates a new mock instance.
+func NewMockTransactionCategories(ctrl *gomock.Controller) *MockTransactionCategories {
+	mock := &MockTransactionCategories{ctrl: ctrl}
+	mock.recorder = &MockTransactionCategoriesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionCategories) EXPECT() *MockTransactionCategoriesMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockTransactionCategories) Get(ctx context.Context, id int64) (domain.TransactionCategory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(domain.TransactionCategory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockTransactionCategoriesMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTransactionCategories)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockTransactionCategories) List(ctx context.Context) ([]domain.TransactionCategory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]domain.TransactionCategory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTransactionCategoriesMockRecorder) List(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTransactionCategories)(nil).List), ctx)
 }
@@ -626,3 +1528,56 @@ func (mr *MockBalancesMockRecorder) Get(ctx, accountID, date interface{}) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBalances)(nil).Get), ctx, accountID, date)
 }
+
+// History mocks base method.
+func (m *MockBalances) History(ctx context.Context, ownerID int64, from, to time.Time, interval domain.Interval) ([]domain.TypeBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, ownerID, from, to, interval)
+	ret0, _ := ret[0].([]domain.TypeBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockBalancesMockRecorder) History(ctx, ownerID, from, to, interval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockBalances)(nil).History), ctx, ownerID, from, to, interval)
+}
+
+// MockSystem is a mock of System interface.
+type MockSystem struct {
+	ctrl     *gomock.Controller
+	recorder *MockSystemMockRecorder
+}
+
+// MockSystemMockRecorder is the mock recorder for MockSystem.
+type MockSystemMockRecorder struct {
+	mock *MockSystem
+}
+
+// NewMockSystem creates a new mock instance.
+func NewMockSystem(ctrl *gomock.Controller) *MockSystem {
+	mock := &MockSystem{ctrl: ctrl}
+	mock.recorder = &MockSystemMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSystem) EXPECT() *MockSystemMockRecorder {
+	return m.recorder
+}
+
+// Stats mocks base method.
+func (m *MockSystem) Stats(ctx context.Context) (domain.SystemStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats", ctx)
+	ret0, _ := ret[0].(domain.SystemStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockSystemMockRecorder) Stats(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockSystem)(nil).Stats), ctx)
+}
@@ -0,0 +1,85 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"github.com/lotostudio/financial-api/internal/domain"
+)
+
+type OIDCFlowsRepo struct {
+	db *sqlx.DB
+}
+
+func newOIDCFlowsRepo(db *sqlx.DB) *OIDCFlowsRepo {
+	return &OIDCFlowsRepo{
+		db: db,
+	}
+}
+
+func (r *OIDCFlowsRepo) Create(ctx context.Context, flow domain.OIDCFlow) error {
+	// Forget flows which were never completed
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM oidc_flows WHERE expires_at < now() AT TIME ZONE 'UTC'`); err != nil {
+		return err
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+	INSERT INTO oidc_flows(state, provider, code_verifier, nonce, expires_at)
+	VALUES ($1, $2, $3, $4, $5)`,
+		flow.State, flow.Provider, flow.CodeVerifier, flow.Nonce, flow.ExpiresAt)
+
+	return err
+}
+
+// Pop returns flow by state and deletes it, so flow can be completed only once
+func (r *OIDCFlowsRepo) Pop(ctx context.Context, state string) (domain.OIDCFlow, error) {
+	var flow domain.OIDCFlow
+
+	if err := r.db.GetContext(ctx, &flow, `
+	DELETE FROM oidc_flows f WHERE f.state = $1
+	RETURNING f.state, f.provider, f.code_verifier, f.nonce, f.expires_at`, state); err != nil {
+		if err == sql.ErrNoRows {
+			return flow, ErrOIDCFlowNotFound
+		}
+
+		return flow, err
+	}
+
+	return flow, nil
+}
+
+type UserIdentitiesRepo struct {
+	db *sqlx.DB
+}
+
+func newUserIdentitiesRepo(db *sqlx.DB) *UserIdentitiesRepo {
+	return &UserIdentitiesRepo{
+		db: db,
+	}
+}
+
+func (r *UserIdentitiesRepo) Get(ctx context.Context, provider, subject string) (domain.UserIdentity, error) {
+	var identity domain.UserIdentity
+
+	if err := r.db.GetContext(ctx, &identity, `
+	SELECT i.provider, i.subject, i.email, i.user_id, i.created_at
+	FROM user_identities i
+	WHERE i.provider = $1 AND i.subject = $2`, provider, subject); err != nil {
+		if err == sql.ErrNoRows {
+			return identity, ErrUserIdentityNotFound
+		}
+
+		return identity, err
+	}
+
+	return identity, nil
+}
+
+func (r *UserIdentitiesRepo) Create(ctx context.Context, identity domain.UserIdentity) error {
+	_, err := r.db.ExecContext(ctx, `
+	INSERT INTO user_identities(provider, subject, email, user_id)
+	VALUES ($1, $2, $3, $4)`,
+		identity.Provider, identity.Subject, identity.Email, identity.UserId)
+
+	return err
+}
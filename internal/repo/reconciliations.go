@@ -0,0 +1,194 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/lotostudio/financial-api/internal/domain"
+)
+
+type ReconciliationsRepo struct {
+	db *sqlx.DB
+}
+
+func newReconciliationsRepo(db *sqlx.DB) *ReconciliationsRepo {
+	return &ReconciliationsRepo{
+		db: db,
+	}
+}
+
+func (r *ReconciliationsRepo) List(ctx context.Context, accountID int64) ([]domain.Reconciliation, error) {
+	reconciliations := make([]domain.Reconciliation, 0)
+
+	if err := r.db.SelectContext(ctx, &reconciliations, `
+	SELECT r.id, r.account_id, r.statement_date, r.statement_balance, r.created_at, r.completed_at
+	FROM reconciliations r
+	WHERE r.account_id = $1
+	ORDER BY r.statement_date DESC, r.id DESC`, accountID); err != nil {
+		return nil, err
+	}
+
+	return reconciliations, nil
+}
+
+func (r *ReconciliationsRepo) Create(ctx context.Context, accountID int64,
+	toCreate domain.ReconciliationToCreate) (domain.Reconciliation, error) {
+	var reconciliation domain.Reconciliation
+
+	if err := r.db.GetContext(ctx, &reconciliation, `
+	INSERT INTO reconciliations(account_id, statement_date, statement_balance)
+	VALUES ($1, $2, $3)
+	RETURNING id, account_id, statement_date, statement_balance, created_at, completed_at`,
+		accountID, toCreate.StatementDate, toCreate.StatementBalance); err != nil {
+		// Other reconciliation of account is not completed
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return reconciliation, ErrReconciliationInProgress
+		}
+
+		return reconciliation, err
+	}
+
+	return reconciliation, nil
+}
+
+func (r *ReconciliationsRepo) Get(ctx context.Context, id int64) (domain.Reconciliation, error) {
+	var reconciliation domain.Reconciliation
+
+	if err := r.db.GetContext(ctx, &reconciliation, `
+	SELECT r.id, r.account_id, r.statement_date, r.statement_balance, r.created_at, r.completed_at
+	FROM reconciliations r
+	WHERE r.id = $1`, id); err != nil {
+		if err == sql.ErrNoRows {
+			return reconciliation, ErrReconciliationNotFound
+		}
+
+		return reconciliation, err
+	}
+
+	return reconciliation, nil
+}
+
+// ListTransactions returns transactions of reconciled account up to end date of statement, which are not reconciled
+// or are ticked in reconciliation, oldest first
+func (r *ReconciliationsRepo) ListTransactions(ctx context.Context,
+	id int64) ([]domain.ReconciliationTransaction, error) {
+	query := fmt.Sprintf(transactionSelect, `,
+	       rt.transaction_id IS NOT NULL AS ticked`) + `
+	JOIN reconciliations r ON r.id = $1
+	LEFT JOIN reconciliation_transactions rt ON rt.reconciliation_id = r.id AND rt.transaction_id = t.id
+	WHERE (cr.id = r.account_id OR db.id = r.account_id) AND t.created_at <= r.statement_date AND t.deleted_at IS NULL
+	  AND (t.status <> 'reconciled' OR rt.transaction_id IS NOT NULL)
+	ORDER BY t.created_at, t.id`
+
+	rows, err := r.db.QueryContext(ctx, query, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	transactions := make([]domain.ReconciliationTransaction, 0)
+
+	for rows.Next() {
+		var tr domain.ReconciliationTransaction
+
+		if tr.Transaction, err = scanTransaction(rows, &tr.Ticked); err != nil {
+			return nil, err
+		}
+
+		transactions = append(transactions, tr)
+	}
+
+	return transactions, rows.Err()
+}
+
+// Tick marks transaction as found in bank statement
+func (r *ReconciliationsRepo) Tick(ctx context.Context, id int64, transactionID int64) error {
+	_, err := r.db.ExecContext(ctx, `
+	INSERT INTO reconciliation_transactions(reconciliation_id, transaction_id) VALUES ($1, $2)
+	ON CONFLICT DO NOTHING`, id, transactionID)
+
+	return err
+}
+
+func (r *ReconciliationsRepo) Untick(ctx context.Context, id int64, transactionID int64) error {
+	_, err := r.db.ExecContext(ctx,
+		"DELETE FROM reconciliation_transactions WHERE reconciliation_id = $1 AND transaction_id = $2",
+		id, transactionID)
+
+	return err
+}
+
+// Complete marks ticked transactions as reconciled and saves time of completion
+func (r *ReconciliationsRepo) Complete(ctx context.Context, id int64) (domain.Reconciliation, error) {
+	var reconciliation domain.Reconciliation
+
+	tx, err := r.db.Begin()
+
+	if err != nil {
+		return reconciliation, err
+	}
+
+	row := tx.QueryRowContext(ctx, `
+	UPDATE reconciliations SET completed_at = now() WHERE id = $1 AND completed_at IS NULL
+	RETURNING id, account_id, statement_date, statement_balance, created_at, completed_at`, id)
+
+	if err = row.Scan(&reconciliation.ID, &reconciliation.AccountID, &reconciliation.StatementDate,
+		&reconciliation.StatementBalance, &reconciliation.CreatedAt, &reconciliation.CompletedAt); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return reconciliation, err
+		}
+
+		if err == sql.ErrNoRows {
+			return reconciliation, ErrReconciliationNotFound
+		}
+
+		return reconciliation, err
+	}
+
+	ids, err := selectIDs(ctx, tx, `
+	SELECT t.id
+	FROM transactions t
+	JOIN reconciliation_transactions rt ON t.id = rt.transaction_id
+	WHERE rt.reconciliation_id = $1 AND t.deleted_at IS NULL`, id)
+
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return reconciliation, err
+		}
+
+		return reconciliation, err
+	}
+
+	before, err := auditStates(ctx, tx, domain.AuditTransaction, ids...)
+
+	if err != nil {
+		return reconciliation, err
+	}
+
+	if _, err = tx.ExecContext(ctx, "UPDATE transactions SET status = 'reconciled' WHERE id = ANY($1)",
+		pq.Array(ids)); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return reconciliation, err
+		}
+
+		return reconciliation, err
+	}
+
+	if err = audit(ctx, tx, domain.AuditTransaction, domain.AuditUpdate, before, ids...); err != nil {
+		return reconciliation, err
+	}
+
+	return reconciliation, tx.Commit()
+}
+
+func (r *ReconciliationsRepo) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM reconciliations WHERE id = $1", id)
+
+	return err
+}
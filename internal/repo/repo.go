@@ -14,13 +14,36 @@ type Users interface {
 	Create(ctx context.Context, user domain.User) (int64, error)
 	Get(ctx context.Context, id int64) (domain.User, error)
 	GetByCredentials(ctx context.Context, email, password string) (domain.User, error)
+	GetByEmail(ctx context.Context, email string) (domain.User, error)
 	UpdatePassword(ctx context.Context, userID int64, toUpdate domain.UserToUpdate) (domain.User, error)
+	SetDisabled(ctx context.Context, id int64, disabled bool) (domain.User, error)
 }
 
 type Sessions interface {
 	Create(ctx context.Context, userID int64) error
+	GetByUser(ctx context.Context, userID int64) (domain.Session, error)
 	GetByToken(ctx context.Context, token string) (domain.Session, error)
 	Update(ctx context.Context, toUpdate domain.SessionToUpdate, userID int64) (domain.Session, error)
+	Reset(ctx context.Context, userID int64) error
+}
+
+type AccessTokens interface {
+	List(ctx context.Context, userID int64) ([]domain.AccessToken, error)
+	Create(ctx context.Context, toCreate domain.AccessTokenToCreate, hash string, userID int64) (domain.AccessToken, error)
+	Get(ctx context.Context, id int64) (domain.AccessToken, error)
+	GetByHash(ctx context.Context, hash string) (domain.AccessToken, error)
+	UpdateLastUsed(ctx context.Context, id int64) error
+	Delete(ctx context.Context, id int64) error
+}
+
+type OIDCFlows interface {
+	Create(ctx context.Context, flow domain.OIDCFlow) error
+	Pop(ctx context.Context, state string) (domain.OIDCFlow, error)
+}
+
+type UserIdentities interface {
+	Get(ctx context.Context, provider, subject string) (domain.UserIdentity, error)
+	Create(ctx context.Context, identity domain.UserIdentity) error
 }
 
 type Currencies interface {
@@ -43,13 +66,68 @@ type AccountTypes interface {
 
 type Transactions interface {
 	List(ctx context.Context, filter domain.TransactionsFilter) ([]domain.Transaction, error)
-	Stats(ctx context.Context, filter domain.TransactionsFilter) ([]domain.TransactionStat, error)
+	Search(ctx context.Context, query string, filter domain.TransactionsFilter,
+		limit int) ([]domain.TransactionSearchResult, error)
+	Stats(ctx context.Context, filter domain.TransactionsFilter, groupBy domain.StatsGroup) ([]domain.TransactionStat, error)
+	CashFlow(ctx context.Context, filter domain.TransactionsFilter, interval domain.Interval) ([]domain.CashFlow, error)
 	Create(ctx context.Context, toCreate domain.TransactionToCreate, categoryId *int64, creditId *int64,
 		debitId *int64) (domain.Transaction, error)
+	CreateBatch(ctx context.Context, items []domain.TransactionBatchItem) ([]domain.Transaction, error)
 	GetOwner(ctx context.Context, id int64) (int64, error)
+	GetStatus(ctx context.Context, id int64) (domain.TransactionStatus, error)
+	SetStatus(ctx context.Context, id int64, status domain.TransactionStatus) error
+	// Delete moves transaction to trash, attachments are kept until it is purged
+	Delete(ctx context.Context, id int64) error
+	DeleteBatch(ctx context.Context, ids []int64) error
+	ListUnscannedOwners(ctx context.Context, to time.Time) ([]int64, error)
+	SetFlags(ctx context.Context, flags map[int64][]domain.TransactionFlag) error
+	SetScannedBefore(ctx context.Context, ownerID int64, before time.Time) error
+	DismissFlags(ctx context.Context, id int64) error
+}
+
+type Attachments interface {
+	List(ctx context.Context, transactionID int64) ([]domain.Attachment, error)
+	Create(ctx context.Context, toCreate domain.Attachment) (domain.Attachment, error)
+	Get(ctx context.Context, id int64) (domain.Attachment, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type Reconciliations interface {
+	List(ctx context.Context, accountID int64) ([]domain.Reconciliation, error)
+	Create(ctx context.Context, accountID int64, toCreate domain.ReconciliationToCreate) (domain.Reconciliation, error)
+	Get(ctx context.Context, id int64) (domain.Reconciliation, error)
+	ListTransactions(ctx context.Context, id int64) ([]domain.ReconciliationTransaction, error)
+	Tick(ctx context.Context, id int64, transactionID int64) error
+	Untick(ctx context.Context, id int64, transactionID int64) error
+	Complete(ctx context.Context, id int64) (domain.Reconciliation, error)
 	Delete(ctx context.Context, id int64) error
 }
 
+type Trash interface {
+	List(ctx context.Context, userID int64) (domain.Trash, error)
+	GetAccount(ctx context.Context, id int64) (domain.Account, error)
+	GetTransactionOwner(ctx context.Context, id int64) (int64, error)
+	RestoreAccount(ctx context.Context, id int64) error
+	RestoreTransaction(ctx context.Context, id int64) error
+	// Purge removes items deleted before given time and returns their count and keys of blobs of attachments
+	Purge(ctx context.Context, before time.Time) (int64, []string, error)
+}
+
+type TransactionTemplates interface {
+	List(ctx context.Context, userID int64) ([]domain.TransactionTemplate, error)
+	Get(ctx context.Context, id int64) (domain.TransactionTemplate, error)
+	Create(ctx context.Context, toCreate domain.TransactionTemplateToCreate, userID int64) (domain.TransactionTemplate,
+		error)
+	Update(ctx context.Context, id int64, toUpdate domain.TransactionTemplateToCreate) (domain.TransactionTemplate,
+		error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type Audit interface {
+	List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error)
+	CreateLoginFailure(ctx context.Context, email string) error
+}
+
 type TransactionCategories interface {
 	List(ctx context.Context) ([]domain.TransactionCategory, error)
 	ListByType(ctx context.Context, _type domain.TransactionType) ([]domain.TransactionCategory, error)
@@ -62,30 +140,54 @@ type TransactionTypes interface {
 
 type Balances interface {
 	Get(ctx context.Context, accountID int64, date time.Time) (domain.Balance, error)
+	History(ctx context.Context, ownerID int64, from time.Time, to time.Time,
+		interval domain.Interval) ([]domain.TypeBalance, error)
+}
+
+type System interface {
+	Stats(ctx context.Context) (domain.SystemStats, error)
 }
 
 type Repos struct {
 	Users
 	Sessions
+	AccessTokens
+	OIDCFlows
+	UserIdentities
 	Currencies
 	Accounts
 	AccountTypes
 	Transactions
+	Attachments
+	Reconciliations
+	Trash
+	TransactionTemplates
+	Audit
 	TransactionCategories
 	TransactionTypes
 	Balances
+	System
 }
 
 func NewRepos(db *sqlx.DB) *Repos {
 	return &Repos{
 		Users:                 newUsersRepo(db),
 		Sessions:              newSessionsRepo(db),
+		AccessTokens:          newAccessTokensRepo(db),
+		OIDCFlows:             newOIDCFlowsRepo(db),
+		UserIdentities:        newUserIdentitiesRepo(db),
 		Currencies:            newCurrenciesRepo(db),
 		Accounts:              newAccountsRepo(db),
 		AccountTypes:          newAccountTypesRepo(db),
 		Transactions:          newTransactionsRepo(db),
+		Attachments:           newAttachmentsRepo(db),
+		Reconciliations:       newReconciliationsRepo(db),
+		Trash:                 newTrashRepo(db),
+		TransactionTemplates:  newTransactionTemplatesRepo(db),
+		Audit:                 newAuditRepo(db),
 		TransactionCategories: newTransactionCategoriesRepo(db),
 		TransactionTypes:      newTransactionTypesRepo(db),
 		Balances:              newBalancesRepo(db),
+		System:                newSystemRepo(db),
 	}
 }
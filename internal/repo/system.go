@@ -0,0 +1,32 @@
+package repo
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"github.com/lotostudio/financial-api/internal/domain"
+)
+
+type SystemRepo struct {
+	db *sqlx.DB
+}
+
+func newSystemRepo(db *sqlx.DB) *SystemRepo {
+	return &SystemRepo{
+		db: db,
+	}
+}
+
+func (r *SystemRepo) Stats(ctx context.Context) (domain.SystemStats, error) {
+	var stats domain.SystemStats
+
+	if err := r.db.GetContext(ctx, &stats, `
+	SELECT (SELECT count(*) FROM users) AS users,
+	       (SELECT count(*) FROM users u WHERE u.disabled) AS disabled_users,
+	       (SELECT count(*) FROM sessions s WHERE s.expires_at > now() AT TIME ZONE 'UTC') AS active_sessions,
+	       (SELECT count(*) FROM accounts a WHERE a.deleted_at IS NULL) AS accounts,
+	       (SELECT count(*) FROM transactions t WHERE t.deleted_at IS NULL) AS transactions`); err != nil {
+		return stats, err
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,143 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/lotostudio/financial-api/internal/domain"
+)
+
+type TransactionTemplatesRepo struct {
+	db *sqlx.DB
+}
+
+func newTransactionTemplatesRepo(db *sqlx.DB) *TransactionTemplatesRepo {
+	return &TransactionTemplatesRepo{
+		db: db,
+	}
+}
+
+// Columns of transaction template in order of scanTemplate
+const templateColumns = `t.id, t.name, t.type, t.amount, t.category_id, t.credit_id, t.debit_id, t.tags, t.owner_id,
+	       t.created_at`
+
+// rowScanner is either single row or rows of query
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTemplate(row rowScanner) (domain.TransactionTemplate, error) {
+	var template domain.TransactionTemplate
+	var tags pq.StringArray
+
+	if err := row.Scan(&template.ID, &template.Name, &template.Type, &template.Amount, &template.CategoryId,
+		&template.CreditId, &template.DebitId, &tags, &template.OwnerId, &template.CreatedAt); err != nil {
+		return template, err
+	}
+
+	template.Tags = tags
+
+	if template.Tags == nil {
+		template.Tags = make([]string, 0)
+	}
+
+	return template, nil
+}
+
+func (r *TransactionTemplatesRepo) List(ctx context.Context, userID int64) ([]domain.TransactionTemplate, error) {
+	templates := make([]domain.TransactionTemplate, 0)
+
+	rows, err := r.db.QueryContext(ctx, `
+	SELECT `+templateColumns+`
+	FROM transaction_templates t
+	WHERE t.owner_id = $1
+	ORDER BY t.name`, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+
+		if err != nil {
+			return nil, err
+		}
+
+		templates = append(templates, template)
+	}
+
+	return templates, rows.Err()
+}
+
+func (r *TransactionTemplatesRepo) Get(ctx context.Context, id int64) (domain.TransactionTemplate, error) {
+	template, err := scanTemplate(r.db.QueryRowContext(ctx, `
+	SELECT `+templateColumns+`
+	FROM transaction_templates t
+	WHERE t.id = $1`, id))
+
+	if err == sql.ErrNoRows {
+		return template, ErrTransactionTemplateNotFound
+	}
+
+	return template, err
+}
+
+func (r *TransactionTemplatesRepo) Create(ctx context.Context, toCreate domain.TransactionTemplateToCreate,
+	userID int64) (domain.TransactionTemplate, error) {
+	template, err := scanTemplate(r.db.QueryRowContext(ctx, `
+	INSERT INTO transaction_templates AS t(name, type, amount, category_id, credit_id, debit_id, tags, owner_id)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	RETURNING `+templateColumns,
+		toCreate.Name, toCreate.Type, toCreate.Amount, toCreate.CategoryId, toCreate.CreditId, toCreate.DebitId,
+		pq.Array(templateTags(toCreate.Tags)), userID))
+
+	return template, templateError(err)
+}
+
+// Update replaces template by ID
+func (r *TransactionTemplatesRepo) Update(ctx context.Context, id int64,
+	toUpdate domain.TransactionTemplateToCreate) (domain.TransactionTemplate, error) {
+	template, err := scanTemplate(r.db.QueryRowContext(ctx, `
+	UPDATE transaction_templates t
+	SET name = $2, type = $3, amount = $4, category_id = $5, credit_id = $6, debit_id = $7, tags = $8
+	WHERE t.id = $1
+	RETURNING `+templateColumns,
+		id, toUpdate.Name, toUpdate.Type, toUpdate.Amount, toUpdate.CategoryId, toUpdate.CreditId, toUpdate.DebitId,
+		pq.Array(templateTags(toUpdate.Tags))))
+
+	if err == sql.ErrNoRows {
+		return template, ErrTransactionTemplateNotFound
+	}
+
+	return template, templateError(err)
+}
+
+func (r *TransactionTemplatesRepo) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM transaction_templates WHERE id = $1", id)
+
+	return err
+}
+
+// templateTags returns empty tags instead of nil, so column is never NULL
+func templateTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+
+	return tags
+}
+
+// templateError converts violation of unique name to ErrTransactionTemplateAlreadyExists
+func templateError(err error) error {
+	if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+		return ErrTransactionTemplateAlreadyExists
+	}
+
+	return err
+}
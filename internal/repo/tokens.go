@@ -0,0 +1,131 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"time"
+)
+
+type AccessTokensRepo struct {
+	db *sqlx.DB
+}
+
+func newAccessTokensRepo(db *sqlx.DB) *AccessTokensRepo {
+	return &AccessTokensRepo{
+		db: db,
+	}
+}
+
+func (r *AccessTokensRepo) List(ctx context.Context, userID int64) ([]domain.AccessToken, error) {
+	tokens := make([]domain.AccessToken, 0)
+
+	if err := r.db.SelectContext(ctx, &tokens, `
+	SELECT t.id, t.name, t.scope, t.token_hash, t.user_id, t.expires_at, t.last_used_at, t.created_at
+	FROM access_tokens t
+	WHERE t.user_id = $1
+	ORDER BY t.created_at`, userID); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (r *AccessTokensRepo) Create(ctx context.Context, toCreate domain.AccessTokenToCreate, hash string,
+	userID int64) (domain.AccessToken, error) {
+	var token domain.AccessToken
+
+	tx, err := r.db.Beginx()
+
+	if err != nil {
+		return token, err
+	}
+
+	if err = tx.GetContext(ctx, &token, `
+	INSERT INTO access_tokens(name, token_hash, scope, expires_at, user_id)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id, name, scope, token_hash, user_id, expires_at, last_used_at, created_at`,
+		toCreate.Name, hash, toCreate.Scope, toCreate.ExpiresAt, userID); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return token, err
+		}
+
+		return token, err
+	}
+
+	if err = audit(ctx, tx.Tx, domain.AuditAccessToken, domain.AuditCreate, nil, token.ID); err != nil {
+		return token, err
+	}
+
+	return token, tx.Commit()
+}
+
+func (r *AccessTokensRepo) Get(ctx context.Context, id int64) (domain.AccessToken, error) {
+	var token domain.AccessToken
+
+	if err := r.db.GetContext(ctx, &token, `
+	SELECT t.id, t.name, t.scope, t.token_hash, t.user_id, t.expires_at, t.last_used_at, t.created_at
+	FROM access_tokens t
+	WHERE t.id = $1`, id); err != nil {
+		if err == sql.ErrNoRows {
+			return token, ErrAccessTokenNotFound
+		}
+
+		return token, err
+	}
+
+	return token, nil
+}
+
+func (r *AccessTokensRepo) GetByHash(ctx context.Context, hash string) (domain.AccessToken, error) {
+	var token domain.AccessToken
+
+	if err := r.db.GetContext(ctx, &token, `
+	SELECT t.id, t.name, t.scope, t.token_hash, t.user_id, t.expires_at, t.last_used_at, t.created_at
+	FROM access_tokens t
+	WHERE t.token_hash = $1`, hash); err != nil {
+		if err == sql.ErrNoRows {
+			return token, ErrAccessTokenNotFound
+		}
+
+		return token, err
+	}
+
+	return token, nil
+}
+
+func (r *AccessTokensRepo) UpdateLastUsed(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE access_tokens SET last_used_at = $1 WHERE id = $2",
+		time.Now().UTC(), id)
+
+	return err
+}
+
+func (r *AccessTokensRepo) Delete(ctx context.Context, id int64) error {
+	tx, err := r.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	before, err := auditStates(ctx, tx, domain.AuditAccessToken, id)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM access_tokens WHERE id = $1", id); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if err = audit(ctx, tx, domain.AuditAccessToken, domain.AuditDelete, before, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -3,9 +3,12 @@ package repo
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/lotostudio/financial-api/internal/domain"
+	"sort"
 	"strings"
 	"time"
 )
@@ -20,57 +23,27 @@ func newTransactionsRepo(db *sqlx.DB) *TransactionsRepo {
 	}
 }
 
-func (r *TransactionsRepo) List(ctx context.Context, filter domain.TransactionsFilter) ([]domain.Transaction, error) {
-	setValues := make([]string, 0)
-	args := make([]interface{}, 0)
-	var argId = 1
-
-	setValues = append(setValues, "1 = 1")
-
-	if filter.OwnerId != nil {
-		setValues = append(setValues, fmt.Sprintf("(cr.owner_id = $%d OR db.owner_id = $%d)", argId, argId))
-		args = append(args, *filter.OwnerId)
-		argId++
-	}
-
-	if filter.AccountId != nil {
-		setValues = append(setValues, fmt.Sprintf("(cr.id=$%d OR db.id=$%d)", argId, argId))
-		args = append(args, *filter.AccountId)
-		argId++
-	}
-
-	if filter.Category != nil {
-		setValues = append(setValues, fmt.Sprintf("tc.title=$%d", argId))
-		args = append(args, *filter.Category)
-		argId++
-	}
-
-	if filter.Type != nil {
-		setValues = append(setValues, fmt.Sprintf("t.type=$%d", argId))
-		args = append(args, *filter.Type)
-		argId++
-	}
-
-	if filter.CreatedFrom != nil && filter.CreatedTo != nil {
-		setValues = append(setValues, fmt.Sprintf("t.created_at BETWEEN $%d AND $%d", argId, argId+1))
-		args = append(args, *filter.CreatedFrom, *filter.CreatedTo)
-
-		// last argId increasing must be marked as nolint
-		argId = argId + 2 //nolint
-	}
-
-	// Create WHERE statement variables with separated by ANDs
-	setQuery := strings.Join(setValues, " AND ")
-	query := fmt.Sprintf(`
-	SELECT t.id, t.amount, t.type, tc.title AS category, t.created_at, 
+// Columns and joins of transaction with its category, tags, lines and accounts, scanned by scanTransaction
+const transactionSelect = `
+	SELECT t.id, t.amount, t.type, tc.title AS category, t.created_at, t.flags, t.flags_dismissed, t.scanned,
+	       t.description, t.payee, t.status,
+	       array(SELECT tg.title FROM transaction_tags tt JOIN tags tg ON tt.tag_id = tg.id 
+	             WHERE tt.transaction_id = t.id ORDER BY tg.title) AS tags,
+	       (SELECT json_agg(json_build_object('category', ltc.title, 'amount', tl.amount) ORDER BY tl.id)
+	        FROM transaction_lines tl LEFT JOIN transaction_categories ltc ON tl.category_id = ltc.id
+	        WHERE tl.transaction_id = t.id) AS lines,
 	       cr.id, cr.title, cr.balance, cr_c.code, cr.type, cr.created_at, 
-	       db.id, db.title, db.balance, db_c.code, db.type, db.created_at
+	       db.id, db.title, db.balance, db_c.code, db.type, db.created_at%s
 	FROM transactions t
 	LEFT JOIN transaction_categories tc ON t.category_id = tc.id
 	LEFT JOIN accounts cr ON t.credit_id = cr.id
 	LEFT JOIN currencies cr_c ON cr.currency_id = cr_c.id
 	LEFT JOIN accounts db ON t.debit_id = db.id
-	LEFT JOIN currencies db_c ON db.currency_id = db_c.id
+	LEFT JOIN currencies db_c ON db.currency_id = db_c.id`
+
+func (r *TransactionsRepo) List(ctx context.Context, filter domain.TransactionsFilter) ([]domain.Transaction, error) {
+	setQuery, args, _ := transactionsFilterQuery(filter, 1)
+	query := fmt.Sprintf(transactionSelect, "") + fmt.Sprintf(`
 	WHERE %s`, setQuery)
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
@@ -82,118 +55,246 @@ func (r *TransactionsRepo) List(ctx context.Context, filter domain.TransactionsF
 	transactions := make([]domain.Transaction, 0)
 
 	for rows.Next() {
-		tr := domain.Transaction{}
+		tr, err := scanTransaction(rows)
 
-		var creditId, debitId *int64
-		var creditTitle, debitTitle, creditCurr, debitCurr *string
-		var creditBalance, debitBalance *float64
-		var creditType, debitType *domain.AccountType
-		var creditCreatedAt, debitCreatedAt *time.Time
-
-		if err = rows.Scan(&tr.ID, &tr.Amount, &tr.Type, &tr.Category, &tr.CreatedAt,
-			&creditId, &creditTitle, &creditBalance, &creditCurr, &creditType, &creditCreatedAt,
-			&debitId, &debitTitle, &debitBalance, &debitCurr, &debitType, &debitCreatedAt); err != nil {
+		if err != nil {
 			return nil, err
 		}
 
-		if creditId != nil {
-			tr.Credit = &domain.Account{
-				ID:        *creditId,
-				Title:     *creditTitle,
-				Balance:   *creditBalance,
-				Currency:  *creditCurr,
-				Type:      *creditType,
-				CreatedAt: *creditCreatedAt,
-			}
-		}
-
-		if debitId != nil {
-			tr.Debit = &domain.Account{
-				ID:        *debitId,
-				Title:     *debitTitle,
-				Balance:   *debitBalance,
-				Currency:  *debitCurr,
-				Type:      *debitType,
-				CreatedAt: *debitCreatedAt,
-			}
-		}
-
 		transactions = append(transactions, tr)
 	}
 
 	return transactions, nil
 }
 
-func (r *TransactionsRepo) Stats(ctx context.Context, filter domain.TransactionsFilter) ([]domain.TransactionStat, error) {
-	setValues := make([]string, 0)
-	args := make([]interface{}, 0)
-	var argId = 1
+// scanTransaction reads row of transactionSelect query. Values of extra columns are read to extra destinations
+func scanTransaction(rows *sql.Rows, extra ...interface{}) (domain.Transaction, error) {
+	tr := domain.Transaction{}
 
-	setValues = append(setValues, "1 = 1")
+	var creditId, debitId *int64
+	var creditTitle, debitTitle, creditCurr, debitCurr *string
+	var creditBalance, debitBalance *float64
+	var creditType, debitType *domain.AccountType
+	var creditCreatedAt, debitCreatedAt *time.Time
+	var flags, tags pq.StringArray
+	var lines []byte
 
-	if filter.OwnerId != nil {
-		setValues = append(setValues, fmt.Sprintf("(cr.owner_id = $%d OR db.owner_id = $%d)", argId, argId))
-		args = append(args, *filter.OwnerId)
-		argId++
-	}
+	dest := []interface{}{&tr.ID, &tr.Amount, &tr.Type, &tr.Category, &tr.CreatedAt, &flags, &tr.FlagsDismissed,
+		&tr.Scanned, &tr.Description, &tr.Payee, &tr.Status, &tags, &lines,
+		&creditId, &creditTitle, &creditBalance, &creditCurr, &creditType, &creditCreatedAt,
+		&debitId, &debitTitle, &debitBalance, &debitCurr, &debitType, &debitCreatedAt}
 
-	if filter.AccountId != nil {
-		setValues = append(setValues, fmt.Sprintf("(cr.id=$%d OR db.id=$%d)", argId, argId))
-		args = append(args, *filter.AccountId)
-		argId++
+	if err := rows.Scan(append(dest, extra...)...); err != nil {
+		return tr, err
 	}
 
-	if filter.Category != nil {
-		setValues = append(setValues, fmt.Sprintf("tc.title=$%d", argId))
-		args = append(args, *filter.Category)
-		argId++
+	for _, flag := range flags {
+		tr.Flags = append(tr.Flags, domain.TransactionFlag(flag))
 	}
 
-	if filter.Type != nil {
-		setValues = append(setValues, fmt.Sprintf("t.type=$%d", argId))
-		args = append(args, *filter.Type)
-		argId++
+	if len(tags) > 0 {
+		tr.Tags = tags
 	}
 
-	if filter.CreatedFrom != nil && filter.CreatedTo != nil {
-		setValues = append(setValues, fmt.Sprintf("t.created_at BETWEEN $%d AND $%d", argId, argId+1))
-		args = append(args, *filter.CreatedFrom, *filter.CreatedTo)
+	if lines != nil {
+		if err := json.Unmarshal(lines, &tr.Lines); err != nil {
+			return tr, err
+		}
+	}
 
-		// last argId increasing must be marked as nolint
-		argId = argId + 2 //nolint
+	if creditId != nil {
+		tr.Credit = &domain.Account{
+			ID:        *creditId,
+			Title:     *creditTitle,
+			Balance:   *creditBalance,
+			Currency:  *creditCurr,
+			Type:      *creditType,
+			CreatedAt: *creditCreatedAt,
+		}
 	}
 
-	// Create WHERE statement variables with separated by ANDs
-	setQuery := strings.Join(setValues, " AND ")
-	query := fmt.Sprintf(`
-	SELECT coalesce(tc.title, 'transfer') AS category, sum(t.amount) AS value
-	FROM transactions t
-	LEFT JOIN transaction_categories tc ON t.category_id = tc.id
-	LEFT JOIN accounts cr ON t.credit_id = cr.id
-	LEFT JOIN currencies cr_c ON cr.currency_id = cr_c.id
-	LEFT JOIN accounts db ON t.debit_id = db.id
-	LEFT JOIN currencies db_c ON db.currency_id = db_c.id
-	WHERE %s
-	GROUP BY tc.title`, setQuery)
+	if debitId != nil {
+		tr.Debit = &domain.Account{
+			ID:        *debitId,
+			Title:     *debitTitle,
+			Balance:   *debitBalance,
+			Currency:  *debitCurr,
+			Type:      *debitType,
+			CreatedAt: *debitCreatedAt,
+		}
+	}
 
-	fmt.Println(query)
+	return tr, nil
+}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+// Search finds transactions by words of description and payee ranked by relevance. Payees similar to query are found
+// too, so part of merchant name is enough
+func (r *TransactionsRepo) Search(ctx context.Context, query string, filter domain.TransactionsFilter,
+	limit int) ([]domain.TransactionSearchResult, error) {
+	setQuery, args, argId := transactionsFilterQuery(filter, 2)
+	sqlQuery := fmt.Sprintf(transactionSelect, `,
+	       ts_rank(t.search, q.query) + similarity(coalesce(t.payee, ''), $1) AS rank,
+	       ts_headline('simple', concat_ws(' - ', t.payee, t.description), q.query,
+	                   'StartSel=<b>, StopSel=</b>, MaxFragments=2, HighlightAll=false') AS highlight`) +
+		fmt.Sprintf(`
+	CROSS JOIN websearch_to_tsquery('simple', $1) AS q(query)
+	WHERE (t.search @@ q.query OR t.payee %% $1) AND %s
+	ORDER BY rank DESC, t.created_at DESC, t.id DESC
+	LIMIT $%d`, setQuery, argId)
+
+	args = append(append([]interface{}{query}, args...), limit)
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
 
 	if err != nil {
 		return nil, err
 	}
 
-	stats := make([]domain.TransactionStat, 0)
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	results := make([]domain.TransactionSearchResult, 0)
 
 	for rows.Next() {
-		st := domain.TransactionStat{}
+		var result domain.TransactionSearchResult
 
-		if err = rows.Scan(&st.Category, &st.Value); err != nil {
+		if result.Transaction, err = scanTransaction(rows, &result.Rank, &result.Highlight); err != nil {
 			return nil, err
 		}
 
-		stats = append(stats, st)
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+// CashFlow sums transactions by periods of given interval. Periods without transactions have zero sums
+func (r *TransactionsRepo) CashFlow(ctx context.Context, filter domain.TransactionsFilter,
+	interval domain.Interval) ([]domain.CashFlow, error) {
+	setQuery, args, argId := transactionsFilterQuery(filter, 4)
+
+	// Transfers are incoming or outgoing relative to selected account or to all accounts of owner
+	inCond, outCond := "true", "true"
+
+	if filter.AccountId != nil {
+		inCond = fmt.Sprintf("db.id = $%d", argId)
+		outCond = fmt.Sprintf("cr.id = $%d", argId)
+		args = append(args, *filter.AccountId)
+	} else if filter.OwnerId != nil {
+		inCond = fmt.Sprintf("db.owner_id = $%d AND db.deleted_at IS NULL", argId)
+		outCond = fmt.Sprintf("cr.owner_id = $%d AND cr.deleted_at IS NULL", argId)
+		args = append(args, *filter.OwnerId)
+	}
+
+	query := fmt.Sprintf(`
+	WITH periods AS (
+		SELECT generate_series(date_trunc($1, $2::timestamp), date_trunc($1, $3::timestamp), ('1 ' || $1)::interval) AS period
+	), sums AS (
+		SELECT date_trunc($1, t.created_at::timestamp) AS period,
+		       sum(t.amount) FILTER (WHERE t.type = 'income') AS income,
+		       sum(t.amount) FILTER (WHERE t.type = 'expense') AS expense,
+		       sum(t.amount) FILTER (WHERE t.type = 'transfer' AND %s) AS transfer_in,
+		       sum(t.amount) FILTER (WHERE t.type = 'transfer' AND %s) AS transfer_out
+		FROM transactions t
+		LEFT JOIN transaction_categories tc ON t.category_id = tc.id
+		LEFT JOIN accounts cr ON t.credit_id = cr.id
+		LEFT JOIN accounts db ON t.debit_id = db.id
+		WHERE %s
+		GROUP BY 1
+	)
+	SELECT p.period,
+	       coalesce(s.income, 0) AS income,
+	       coalesce(s.expense, 0) AS expense,
+	       coalesce(s.transfer_in, 0) AS transfer_in,
+	       coalesce(s.transfer_out, 0) AS transfer_out,
+	       coalesce(s.income, 0) - coalesce(s.expense, 0) + coalesce(s.transfer_in, 0) - coalesce(s.transfer_out, 0) AS net
+	FROM periods p
+	LEFT JOIN sums s ON s.period = p.period
+	ORDER BY p.period`, inCond, outCond, setQuery)
+
+	args = append([]interface{}{interval, *filter.CreatedFrom, *filter.CreatedTo}, args...)
+
+	flows := make([]domain.CashFlow, 0)
+
+	if err := r.db.SelectContext(ctx, &flows, query, args...); err != nil {
+		return nil, err
+	}
+
+	return flows, nil
+}
+
+// Expressions of stats groups: key of group, grouping and ordering
+var statsGroups = map[domain.StatsGroup][3]string{
+	domain.GroupByCategory: {"coalesce(tc.title, 'transfer')", "1", "1"},
+	domain.GroupByType:     {"t.type::text", "1", "1"},
+	// Income belongs to receiver account, expense and transfer - to sender
+	domain.GroupByAccount: {"coalesce(cr.title, db.title)", "coalesce(cr.id, db.id), 1", "1"},
+	domain.GroupByMonth:   {"to_char(t.created_at, 'YYYY-MM')", "1", "1"},
+	domain.GroupByWeekday: {"lower(to_char(t.created_at, 'FMDay'))", "extract(isodow FROM t.created_at), 1",
+		"extract(isodow FROM t.created_at)"},
+}
+
+func (r *TransactionsRepo) Stats(ctx context.Context, filter domain.TransactionsFilter,
+	groupBy domain.StatsGroup) ([]domain.TransactionStat, error) {
+	group, ok := statsGroups[groupBy]
+
+	if !ok {
+		return nil, domain.ErrInvalidStatsGroup
+	}
+
+	// Only categories split transactions into lines, other groups count and aggregate transactions as a whole
+	if groupBy != domain.GroupByCategory {
+		setQuery, args, _ := transactionsFilterQuery(filter, 1)
+
+		return r.stats(ctx, fmt.Sprintf(`
+		SELECT %s AS grp, sum(t.amount) AS sum, count(*) AS count, avg(t.amount) AS avg, min(t.amount) AS min,
+		       max(t.amount) AS max
+		FROM transactions t
+		LEFT JOIN transaction_categories tc ON tc.id = t.category_id
+		LEFT JOIN accounts cr ON t.credit_id = cr.id
+		LEFT JOIN accounts db ON t.debit_id = db.id
+		WHERE %s
+		GROUP BY %s
+		ORDER BY %s`, group[0], setQuery, group[1], group[2]), args...)
+	}
+
+	// Split transactions are aggregated by lines, so category is matched by line rather than by transaction
+	category := filter.Category
+	filter.Category = nil
+
+	setQuery, args, argId := transactionsFilterQuery(filter, 1)
+
+	if category != nil {
+		setQuery += fmt.Sprintf(" AND tc.title=$%d", argId)
+		args = append(args, *category)
+	}
+
+	// Lines of transaction in the same category are summed first, so every transaction is counted once in category
+	return r.stats(ctx, fmt.Sprintf(`
+	SELECT p.grp, sum(p.amount) AS sum, count(*) AS count, avg(p.amount) AS avg, min(p.amount) AS min,
+	       max(p.amount) AS max
+	FROM (
+		SELECT %s AS grp, sum(coalesce(tl.amount, t.amount)) AS amount
+		FROM transactions t
+		LEFT JOIN transaction_lines tl ON tl.transaction_id = t.id
+		LEFT JOIN transaction_categories tc ON tc.id = CASE WHEN tl.id IS NULL THEN t.category_id ELSE tl.category_id END
+		LEFT JOIN accounts cr ON t.credit_id = cr.id
+		LEFT JOIN accounts db ON t.debit_id = db.id
+		WHERE %s
+		GROUP BY t.id, 1
+	) p
+	GROUP BY %s
+	ORDER BY %s`, group[0], setQuery, group[1], group[2]), args...)
+}
+
+// stats selects stats of transactions by query
+func (r *TransactionsRepo) stats(ctx context.Context, query string, args ...interface{}) ([]domain.TransactionStat,
+	error) {
+	stats := make([]domain.TransactionStat, 0)
+
+	if err := r.db.SelectContext(ctx, &stats, query, args...); err != nil {
+		return nil, err
 	}
 
 	return stats, nil
@@ -207,15 +308,9 @@ func (r *TransactionsRepo) Create(ctx context.Context, toCreate domain.Transacti
 		return domain.Transaction{}, err
 	}
 
-	row := tx.QueryRowContext(ctx,
-		`INSERT INTO transactions(amount, type, created_at, category_id, credit_id, debit_id) 
-				VALUES ($1, $2, $3, $4, $5, $6) 
-				RETURNING id, amount, type, created_at`,
-		toCreate.Amount, toCreate.Type, toCreate.CreatedAt, categoryId, creditId, debitId)
-
-	var transaction domain.Transaction
+	transaction, err := insertTransaction(ctx, tx, toCreate, categoryId, creditId, debitId)
 
-	if err = row.Scan(&transaction.ID, &transaction.Amount, &transaction.Type, &transaction.CreatedAt); err != nil {
+	if err != nil {
 		if err := tx.Rollback(); err != nil {
 			return transaction, err
 		}
@@ -223,8 +318,9 @@ func (r *TransactionsRepo) Create(ctx context.Context, toCreate domain.Transacti
 		return transaction, err
 	}
 
+	// Balance changes by total amount, lines only split it by categories
 	if toCreate.Type == domain.Expense || toCreate.Type == domain.Transfer {
-		row = tx.QueryRowContext(ctx, "UPDATE accounts SET balance = balance - $1 WHERE id = $2 RETURNING balance",
+		row := tx.QueryRowContext(ctx, "UPDATE accounts SET balance = balance - $1 WHERE id = $2 RETURNING balance",
 			transaction.Amount, creditId)
 		var balance float64
 
@@ -250,7 +346,7 @@ func (r *TransactionsRepo) Create(ctx context.Context, toCreate domain.Transacti
 	}
 
 	if toCreate.Type == domain.Income || toCreate.Type == domain.Transfer {
-		row = tx.QueryRowContext(ctx, "UPDATE accounts SET balance = balance + $1 WHERE id = $2 RETURNING balance",
+		row := tx.QueryRowContext(ctx, "UPDATE accounts SET balance = balance + $1 WHERE id = $2 RETURNING balance",
 			transaction.Amount, debitId)
 		var balance float64
 
@@ -267,16 +363,197 @@ func (r *TransactionsRepo) Create(ctx context.Context, toCreate domain.Transacti
 		}
 	}
 
+	if err = audit(ctx, tx, domain.AuditTransaction, domain.AuditCreate, nil, transaction.ID); err != nil {
+		return transaction, err
+	}
+
 	return transaction, tx.Commit()
 }
 
+// insertTransaction saves transaction with its tags and lines without changing balances of accounts
+func insertTransaction(ctx context.Context, tx *sql.Tx, toCreate domain.TransactionToCreate, categoryId *int64,
+	creditId *int64, debitId *int64) (domain.Transaction, error) {
+	row := tx.QueryRowContext(ctx,
+		`INSERT INTO transactions(amount, type, created_at, category_id, credit_id, debit_id, description, payee,
+				                  status) 
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, coalesce(nullif($9, '')::transaction_status, 'cleared')) 
+				RETURNING id, amount, type, created_at, description, payee, status`,
+		toCreate.Amount, toCreate.Type, toCreate.CreatedAt, categoryId, creditId, debitId, toCreate.Description,
+		toCreate.Payee, toCreate.Status)
+
+	var transaction domain.Transaction
+
+	if err := row.Scan(&transaction.ID, &transaction.Amount, &transaction.Type, &transaction.CreatedAt,
+		&transaction.Description, &transaction.Payee, &transaction.Status); err != nil {
+		return transaction, err
+	}
+
+	if len(toCreate.Tags) > 0 {
+		if err := addTags(ctx, tx, transaction.ID, toCreate.Tags); err != nil {
+			return transaction, err
+		}
+
+		transaction.Tags = toCreate.Tags
+	}
+
+	if len(toCreate.Lines) > 0 {
+		if err := addLines(ctx, tx, transaction.ID, toCreate.Lines); err != nil {
+			return transaction, err
+		}
+	}
+
+	return transaction, nil
+}
+
+// CreateBatch saves transactions in one database transaction. Balance of every account is changed once by sum of
+// its transactions
+func (r *TransactionsRepo) CreateBatch(ctx context.Context, items []domain.TransactionBatchItem) ([]domain.Transaction,
+	error) {
+	tx, err := r.db.Begin()
+
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := make([]domain.Transaction, 0, len(items))
+	ids := make([]int64, 0, len(items))
+	changes := make(map[int64]float64)
+
+	for _, item := range items {
+		transaction, err := insertTransaction(ctx, tx, item.TransactionToCreate, item.CategoryId, item.CreditId,
+			item.DebitId)
+
+		if err != nil {
+			if err := tx.Rollback(); err != nil {
+				return nil, err
+			}
+
+			return nil, err
+		}
+
+		if item.Type == domain.Expense || item.Type == domain.Transfer {
+			changes[*item.CreditId] -= item.Amount
+		}
+
+		if item.Type == domain.Income || item.Type == domain.Transfer {
+			changes[*item.DebitId] += item.Amount
+		}
+
+		transactions = append(transactions, transaction)
+		ids = append(ids, transaction.ID)
+	}
+
+	if err = changeBalances(ctx, tx, changes); err != nil {
+		return nil, err
+	}
+
+	if err = audit(ctx, tx, domain.AuditTransaction, domain.AuditCreate, nil, ids...); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// changeBalances adds changes to balances of accounts by IDs. Accounts are updated in order of IDs to avoid deadlocks
+// with concurrent batches. Rolls back transaction on error, ErrAccountNotEnoughBalance is returned if decreased
+// balance becomes negative
+func changeBalances(ctx context.Context, tx *sql.Tx, changes map[int64]float64) error {
+	ids := make([]int64, 0, len(changes))
+
+	for id := range changes {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	for _, id := range ids {
+		row := tx.QueryRowContext(ctx,
+			"UPDATE accounts SET balance = balance + $1 WHERE id = $2 RETURNING balance", changes[id], id)
+		var balance float64
+
+		if err := row.Scan(&balance); err != nil {
+			if err := tx.Rollback(); err != nil {
+				return err
+			}
+
+			return err
+		}
+
+		if changes[id] < 0 && balance < 0 {
+			if err := tx.Rollback(); err != nil {
+				return err
+			}
+
+			return ErrAccountNotEnoughBalance
+		}
+
+		if err := updateBalance(ctx, tx, id, balance); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addLines saves lines of split transaction
+func addLines(ctx context.Context, tx *sql.Tx, transactionID int64, lines []domain.TransactionLineToCreate) error {
+	categoryIds := make([]int64, len(lines))
+	amounts := make([]float64, len(lines))
+
+	for i, line := range lines {
+		categoryIds[i] = line.CategoryId
+		amounts[i] = line.Amount
+	}
+
+	_, err := tx.ExecContext(ctx, `
+	INSERT INTO transaction_lines(transaction_id, category_id, amount)
+	SELECT $1, l.category_id, l.amount
+	FROM unnest($2::bigint[], $3::numeric[]) AS l(category_id, amount)`,
+		transactionID, pq.Array(categoryIds), pq.Array(amounts))
+
+	return err
+}
+
+// addTags links tags to transaction, creating missing tags of transaction owner
+func addTags(ctx context.Context, tx *sql.Tx, transactionID int64, tags []string) error {
+	if _, err := tx.ExecContext(ctx, `
+	INSERT INTO tags(title, owner_id)
+	SELECT tg.title, coalesce(cr.owner_id, db.owner_id)
+	FROM transactions t
+	LEFT JOIN accounts cr ON t.credit_id = cr.id
+	LEFT JOIN accounts db ON t.debit_id = db.id
+	CROSS JOIN unnest($2::varchar[]) AS tg(title)
+	WHERE t.id = $1
+	ON CONFLICT (owner_id, title) DO NOTHING`, transactionID, pq.Array(tags)); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, `
+	INSERT INTO transaction_tags(transaction_id, tag_id)
+	SELECT t.id, tg.id
+	FROM transactions t
+	LEFT JOIN accounts cr ON t.credit_id = cr.id
+	LEFT JOIN accounts db ON t.debit_id = db.id
+	JOIN tags tg ON tg.owner_id = coalesce(cr.owner_id, db.owner_id) AND tg.title = ANY($2)
+	WHERE t.id = $1
+	ON CONFLICT DO NOTHING`, transactionID, pq.Array(tags))
+
+	return err
+}
+
 func (r *TransactionsRepo) GetOwner(ctx context.Context, id int64) (int64, error) {
 	rows, err := r.db.QueryContext(ctx, `
 	SELECT cr.owner_id, db.owner_id 
 	FROM transactions t
-	LEFT JOIN accounts cr ON t.credit_id = cr.id
-	LEFT JOIN accounts db ON t.debit_id = db.id
-	WHERE t.id = $1`, id)
+	LEFT JOIN accounts cr ON t.credit_id = cr.id AND cr.deleted_at IS NULL
+	LEFT JOIN accounts db ON t.debit_id = db.id AND db.deleted_at IS NULL
+	WHERE t.id = $1 AND t.deleted_at IS NULL`, id)
 
 	if err != nil {
 		return 0, err
@@ -303,6 +580,61 @@ func (r *TransactionsRepo) GetOwner(ctx context.Context, id int64) (int64, error
 	return 0, ErrTransactionOwnerNotFound
 }
 
+func (r *TransactionsRepo) GetStatus(ctx context.Context, id int64) (domain.TransactionStatus, error) {
+	var status domain.TransactionStatus
+
+	if err := r.db.GetContext(ctx, &status,
+		"SELECT t.status FROM transactions t WHERE t.id = $1 AND t.deleted_at IS NULL", id); err != nil {
+		if err == sql.ErrNoRows {
+			return status, ErrTransactionNotFound
+		}
+
+		return status, err
+	}
+
+	return status, nil
+}
+
+func (r *TransactionsRepo) SetStatus(ctx context.Context, id int64, status domain.TransactionStatus) error {
+	tx, err := r.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	before, err := auditStates(ctx, tx, domain.AuditTransaction, id)
+
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, "UPDATE transactions SET status = $2 WHERE id = $1", id, status)
+
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if count, _ := res.RowsAffected(); count == 0 {
+		if err = tx.Rollback(); err != nil {
+			return err
+		}
+
+		return ErrTransactionNotFound
+	}
+
+	if err = audit(ctx, tx, domain.AuditTransaction, domain.AuditUpdate, before, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete moves transaction to trash and reverts its effect on balances of accounts. Attachments are kept until
+// transaction is purged
 func (r *TransactionsRepo) Delete(ctx context.Context, id int64) error {
 	tx, err := r.db.Begin()
 
@@ -310,26 +642,87 @@ func (r *TransactionsRepo) Delete(ctx context.Context, id int64) error {
 		return err
 	}
 
+	before, err := auditStates(ctx, tx, domain.AuditTransaction, id)
+
+	if err != nil {
+		return err
+	}
+
 	var creditId, debitId *int64
 	var amount float64
 
-	row := tx.QueryRowContext(ctx,
-		"DELETE FROM transactions t WHERE t.id = $1 RETURNING t.credit_id, t.debit_id, t.amount", id)
+	row := tx.QueryRowContext(ctx, `UPDATE transactions t SET deleted_at = now() 
+	WHERE t.id = $1 AND t.deleted_at IS NULL RETURNING t.credit_id, t.debit_id, t.amount`, id)
 
 	if err = row.Scan(&creditId, &debitId, &amount); err != nil {
 		if err := tx.Rollback(); err != nil {
 			return err
 		}
 
+		if err == sql.ErrNoRows {
+			return ErrTransactionNotFound
+		}
+
 		return err
 	}
 
+	changes := make(map[int64]float64)
+
 	if creditId != nil {
-		row = tx.QueryRowContext(ctx,
-			"UPDATE accounts SET balance = balance + $1 WHERE id = $2 RETURNING balance", amount, creditId)
-		var balance float64
+		changes[*creditId] += amount
+	}
+
+	if debitId != nil {
+		changes[*debitId] -= amount
+	}
+
+	if err = changeBalances(ctx, tx, changes); err != nil {
+		return err
+	}
+
+	if err = audit(ctx, tx, domain.AuditTransaction, domain.AuditDelete, before, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteBatch moves transactions to trash in one database transaction. Balance of every account is changed once by
+// sum of its transactions
+func (r *TransactionsRepo) DeleteBatch(ctx context.Context, ids []int64) error {
+	tx, err := r.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	before, err := auditStates(ctx, tx, domain.AuditTransaction, ids...)
+
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, `UPDATE transactions t SET deleted_at = now() 
+	WHERE t.id = ANY($1) AND t.deleted_at IS NULL RETURNING t.credit_id, t.debit_id, t.amount`, pq.Array(ids))
+
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	changes := make(map[int64]float64)
+	count := 0
+
+	for rows.Next() {
+		var creditId, debitId *int64
+		var amount float64
+
+		if err = rows.Scan(&creditId, &debitId, &amount); err != nil {
+			_ = rows.Close()
 
-		if err = row.Scan(&balance); err != nil {
 			if err := tx.Rollback(); err != nil {
 				return err
 			}
@@ -337,38 +730,201 @@ func (r *TransactionsRepo) Delete(ctx context.Context, id int64) error {
 			return err
 		}
 
-		if err := updateBalance(ctx, tx, *creditId, balance); err != nil {
+		if creditId != nil {
+			changes[*creditId] += amount
+		}
+
+		if debitId != nil {
+			changes[*debitId] -= amount
+		}
+
+		count++
+	}
+
+	if err = rows.Err(); err == nil && count != len(ids) {
+		// Some transaction is deleted concurrently
+		err = ErrTransactionNotFound
+	}
+
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
 			return err
 		}
+
+		return err
 	}
 
-	if debitId != nil {
-		row = tx.QueryRowContext(ctx,
-			"UPDATE accounts SET balance = balance - $1 WHERE id = $2 RETURNING balance", amount, debitId)
-		var balance float64
+	if err = changeBalances(ctx, tx, changes); err != nil {
+		return err
+	}
 
-		if err = row.Scan(&balance); err != nil {
+	if err = audit(ctx, tx, domain.AuditTransaction, domain.AuditDelete, before, ids...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListUnscannedOwners returns IDs of users having transactions not checked for anomalies, dated not later than to.
+// Sides of transactions in deleted accounts are skipped the same way as in List
+func (r *TransactionsRepo) ListUnscannedOwners(ctx context.Context, to time.Time) ([]int64, error) {
+	owners := make([]int64, 0)
+
+	if err := r.db.SelectContext(ctx, &owners, `
+	SELECT DISTINCT coalesce(cr.owner_id, db.owner_id)
+	FROM transactions t
+	LEFT JOIN accounts cr ON t.credit_id = cr.id AND cr.deleted_at IS NULL
+	LEFT JOIN accounts db ON t.debit_id = db.id AND db.deleted_at IS NULL
+	WHERE NOT t.scanned AND t.deleted_at IS NULL AND t.created_at <= $1
+	AND coalesce(cr.owner_id, db.owner_id) IS NOT NULL`, to); err != nil {
+		return nil, err
+	}
+
+	return owners, nil
+}
+
+// SetFlags marks transactions by IDs as checked for anomalies and stores their flags
+func (r *TransactionsRepo) SetFlags(ctx context.Context, flags map[int64][]domain.TransactionFlag) error {
+	tx, err := r.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	for id, txFlags := range flags {
+		values := make([]string, 0, len(txFlags))
+
+		for _, flag := range txFlags {
+			values = append(values, string(flag))
+		}
+
+		if _, err = tx.ExecContext(ctx, "UPDATE transactions SET scanned = true, flags = $2 WHERE id = $1",
+			id, pq.Array(values)); err != nil {
 			if err := tx.Rollback(); err != nil {
 				return err
 			}
 
 			return err
 		}
+	}
 
-		if balance < 0 {
-			if err = tx.Rollback(); err != nil {
-				return err
-			}
+	return tx.Commit()
+}
 
-			return ErrAccountNotEnoughBalance
-		}
+// SetScannedBefore marks not scanned transactions of owner made before date as checked for anomalies without flags
+func (r *TransactionsRepo) SetScannedBefore(ctx context.Context, ownerID int64, before time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+	UPDATE transactions t SET scanned = true
+	FROM accounts a
+	WHERE NOT t.scanned AND t.created_at < $2 AND a.id = coalesce(t.credit_id, t.debit_id) AND a.owner_id = $1`,
+		ownerID, before)
 
-		if err := updateBalance(ctx, tx, *debitId, balance); err != nil {
-			return err
+	return err
+}
+
+func (r *TransactionsRepo) DismissFlags(ctx context.Context, id int64) error {
+	res, err := r.db.ExecContext(ctx,
+		"UPDATE transactions SET flags_dismissed = true WHERE id = $1 AND deleted_at IS NULL", id)
+
+	if err != nil {
+		return err
+	}
+
+	count, err := res.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return ErrTransactionNotFound
+	}
+
+	return nil
+}
+
+// Escapes wildcards of LIKE pattern
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// transactionsFilterQuery creates WHERE statement of transactions filter with arguments numbered from given one.
+// Returns statement, its arguments and next argument number
+func transactionsFilterQuery(filter domain.TransactionsFilter, argId int) (string, []interface{}, int) {
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+
+	// Transactions in trash and sides of transactions in deleted accounts are skipped
+	setValues = append(setValues, "t.deleted_at IS NULL")
+
+	if filter.OwnerId != nil {
+		setValues = append(setValues, fmt.Sprintf(
+			"(cr.owner_id = $%d AND cr.deleted_at IS NULL OR db.owner_id = $%d AND db.deleted_at IS NULL)", argId, argId))
+		args = append(args, *filter.OwnerId)
+		argId++
+	}
+
+	if filter.AccountId != nil {
+		setValues = append(setValues, fmt.Sprintf("(cr.id=$%d OR db.id=$%d)", argId, argId))
+		args = append(args, *filter.AccountId)
+		argId++
+	}
+
+	if filter.Category != nil {
+		setValues = append(setValues, fmt.Sprintf(`(tc.title=$%d OR EXISTS (SELECT 1 FROM transaction_lines tl 
+			JOIN transaction_categories ltc ON tl.category_id = ltc.id WHERE tl.transaction_id = t.id AND ltc.title=$%d))`,
+			argId, argId))
+		args = append(args, *filter.Category)
+		argId++
+	}
+
+	if filter.Type != nil {
+		setValues = append(setValues, fmt.Sprintf("t.type=$%d", argId))
+		args = append(args, *filter.Type)
+		argId++
+	}
+
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil {
+		setValues = append(setValues, fmt.Sprintf("t.created_at BETWEEN $%d AND $%d", argId, argId+1))
+		args = append(args, *filter.CreatedFrom, *filter.CreatedTo)
+		argId += 2
+	}
+
+	if filter.Tag != nil {
+		setValues = append(setValues, fmt.Sprintf(`EXISTS (SELECT 1 FROM transaction_tags tt 
+			JOIN tags tg ON tt.tag_id = tg.id WHERE tt.transaction_id = t.id AND tg.title = lower($%d))`, argId))
+		args = append(args, *filter.Tag)
+		argId++
+	}
+
+	if filter.Payee != nil {
+		setValues = append(setValues, fmt.Sprintf("lower(t.payee) = lower($%d)", argId))
+		args = append(args, *filter.Payee)
+		argId++
+	}
+
+	if filter.Text != nil {
+		setValues = append(setValues, fmt.Sprintf("(t.description ILIKE $%d OR t.payee ILIKE $%d)", argId, argId))
+		args = append(args, "%"+likeEscaper.Replace(*filter.Text)+"%")
+		argId++
+	}
+
+	if filter.Status != nil {
+		setValues = append(setValues, fmt.Sprintf("t.status=$%d", argId))
+		args = append(args, *filter.Status)
+		argId++
+	}
+
+	if filter.Flagged != nil {
+		flagged := "(cardinality(t.flags) > 0 AND NOT t.flags_dismissed)"
+
+		if !*filter.Flagged {
+			flagged = "NOT " + flagged
 		}
+
+		setValues = append(setValues, flagged)
 	}
 
-	return tx.Commit()
+	// Create WHERE statement variables with separated by ANDs
+	return strings.Join(setValues, " AND "), args, argId
 }
 
 type TransactionCategoryRepo struct {
@@ -0,0 +1,352 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"time"
+)
+
+type TrashRepo struct {
+	db *sqlx.DB
+}
+
+func newTrashRepo(db *sqlx.DB) *TrashRepo {
+	return &TrashRepo{
+		db: db,
+	}
+}
+
+// List returns deleted accounts and transactions of user. Transactions of deleted accounts are not listed, they are
+// restored with account
+func (r *TrashRepo) List(ctx context.Context, userID int64) (domain.Trash, error) {
+	trash := domain.Trash{
+		Accounts:     make([]domain.Account, 0),
+		Transactions: make([]domain.Transaction, 0),
+	}
+
+	if err := r.db.SelectContext(ctx, &trash.Accounts, `
+	SELECT a.id, a.title, a.balance, cur.code currency, a.type, a.created_at, a.deleted_at,
+	       coalesce(l.term, d.term) AS term, coalesce(l.rate, d.rate) AS rate, c.number
+	FROM accounts a
+    LEFT JOIN loans l ON a.id = l.account_id
+    LEFT JOIN deposits d ON a.id = d.account_id
+	LEFT JOIN cards c ON a.id = c.account_id
+	JOIN currencies cur ON a.currency_id = cur.id
+	WHERE a.owner_id = $1 AND a.deleted_at IS NOT NULL
+	ORDER BY a.deleted_at DESC, a.id DESC`, userID); err != nil {
+		return trash, err
+	}
+
+	query := fmt.Sprintf(transactionSelect, `,
+	       t.deleted_at`) + `
+	WHERE (cr.owner_id = $1 OR db.owner_id = $1) AND t.deleted_at IS NOT NULL
+	ORDER BY t.deleted_at DESC, t.id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+
+	if err != nil {
+		return trash, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var deletedAt time.Time
+
+		tr, err := scanTransaction(rows, &deletedAt)
+
+		if err != nil {
+			return trash, err
+		}
+
+		tr.DeletedAt = &deletedAt
+		trash.Transactions = append(trash.Transactions, tr)
+	}
+
+	return trash, rows.Err()
+}
+
+// GetAccount returns deleted account
+func (r *TrashRepo) GetAccount(ctx context.Context, id int64) (domain.Account, error) {
+	var account domain.Account
+
+	if err := r.db.GetContext(ctx, &account, `
+	SELECT a.id, a.title, a.balance, a.type, a.owner_id, a.created_at, a.deleted_at
+	FROM accounts a
+	WHERE a.id = $1 AND a.deleted_at IS NOT NULL`, id); err != nil {
+		if err == sql.ErrNoRows {
+			return account, ErrAccountNotFound
+		}
+
+		return account, err
+	}
+
+	return account, nil
+}
+
+// GetTransactionOwner returns owner of deleted transaction
+func (r *TrashRepo) GetTransactionOwner(ctx context.Context, id int64) (int64, error) {
+	var ownerId *int64
+
+	if err := r.db.QueryRowContext(ctx, `
+	SELECT coalesce(cr.owner_id, db.owner_id)
+	FROM transactions t
+	LEFT JOIN accounts cr ON t.credit_id = cr.id
+	LEFT JOIN accounts db ON t.debit_id = db.id
+	WHERE t.id = $1 AND t.deleted_at IS NOT NULL`, id).Scan(&ownerId); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrTransactionNotFound
+		}
+
+		return 0, err
+	}
+
+	if ownerId == nil {
+		return 0, ErrTransactionOwnerNotFound
+	}
+
+	return *ownerId, nil
+}
+
+func (r *TrashRepo) RestoreAccount(ctx context.Context, id int64) error {
+	tx, err := r.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	before, err := auditStates(ctx, tx, domain.AuditAccount, id)
+
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx,
+		"UPDATE accounts SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL", id)
+
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if count, _ := res.RowsAffected(); count == 0 {
+		if err = tx.Rollback(); err != nil {
+			return err
+		}
+
+		return ErrAccountNotFound
+	}
+
+	if err = audit(ctx, tx, domain.AuditAccount, domain.AuditRestore, before, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RestoreTransaction takes transaction out of trash and applies it to balances of accounts again. Accounts of
+// transaction must not be deleted
+func (r *TrashRepo) RestoreTransaction(ctx context.Context, id int64) error {
+	tx, err := r.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	before, err := auditStates(ctx, tx, domain.AuditTransaction, id)
+
+	if err != nil {
+		return err
+	}
+
+	var creditId, debitId *int64
+	var amount float64
+	var accountDeleted bool
+
+	row := tx.QueryRowContext(ctx, `UPDATE transactions t SET deleted_at = NULL
+	WHERE t.id = $1 AND t.deleted_at IS NOT NULL
+	RETURNING t.credit_id, t.debit_id, t.amount,
+	          EXISTS (SELECT 1 FROM accounts a WHERE a.id IN (t.credit_id, t.debit_id) AND a.deleted_at IS NOT NULL)`,
+		id)
+
+	if err = row.Scan(&creditId, &debitId, &amount, &accountDeleted); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		if err == sql.ErrNoRows {
+			return ErrTransactionNotFound
+		}
+
+		return err
+	}
+
+	if accountDeleted {
+		if err = tx.Rollback(); err != nil {
+			return err
+		}
+
+		return ErrTransactionAccountDeleted
+	}
+
+	changes := make(map[int64]float64)
+
+	if creditId != nil {
+		changes[*creditId] -= amount
+	}
+
+	if debitId != nil {
+		changes[*debitId] += amount
+	}
+
+	if err = changeBalances(ctx, tx, changes); err != nil {
+		return err
+	}
+
+	if err = audit(ctx, tx, domain.AuditTransaction, domain.AuditRestore, before, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Purge removes accounts and transactions deleted before given time for good. Transactions without accounts left
+// are removed too. Returns count of removed accounts and transactions and keys of blobs of their attachments, which
+// are left to be removed
+func (r *TrashRepo) Purge(ctx context.Context, before time.Time) (int64, []string, error) {
+	tx, err := r.db.Begin()
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	ids, err := selectIDs(ctx, tx, `
+	SELECT t.id
+	FROM transactions t
+	LEFT JOIN accounts cr ON t.credit_id = cr.id
+	LEFT JOIN accounts db ON t.debit_id = db.id
+	WHERE t.deleted_at < $1
+	   OR (cr.id IS NULL OR cr.deleted_at < $1) AND (db.id IS NULL OR db.deleted_at < $1)`, before)
+
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return 0, nil, err
+		}
+
+		return 0, nil, err
+	}
+
+	accountIds, err := selectIDs(ctx, tx, "SELECT a.id FROM accounts a WHERE a.deleted_at < $1", before)
+
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return 0, nil, err
+		}
+
+		return 0, nil, err
+	}
+
+	// States are read before removal as purged items have no state after it
+	transactionStates, err := auditStates(ctx, tx, domain.AuditTransaction, ids...)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	accountStates, err := auditStates(ctx, tx, domain.AuditAccount, accountIds...)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// Attachments are removed before transactions to know keys of their blobs
+	keys, err := deleteAttachments(ctx, tx, ids)
+
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return 0, nil, err
+		}
+
+		return 0, nil, err
+	}
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM transactions WHERE id = ANY($1)", pq.Array(ids))
+
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return 0, nil, err
+		}
+
+		return 0, nil, err
+	}
+
+	transactions, _ := res.RowsAffected()
+
+	res, err = tx.ExecContext(ctx, "DELETE FROM accounts WHERE id = ANY($1)", pq.Array(accountIds))
+
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return 0, nil, err
+		}
+
+		return 0, nil, err
+	}
+
+	accounts, _ := res.RowsAffected()
+
+	if err = audit(ctx, tx, domain.AuditTransaction, domain.AuditPurge, transactionStates, ids...); err != nil {
+		return 0, nil, err
+	}
+
+	if err = audit(ctx, tx, domain.AuditAccount, domain.AuditPurge, accountStates, accountIds...); err != nil {
+		return 0, nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, nil, err
+	}
+
+	return accounts + transactions, keys, nil
+}
+
+// deleteAttachments removes attachments of transactions and returns keys of their files and thumbnails
+func deleteAttachments(ctx context.Context, tx *sql.Tx, transactionIDs []int64) ([]string, error) {
+	rows, err := tx.QueryContext(ctx,
+		"DELETE FROM attachments WHERE transaction_id = ANY($1) RETURNING key, thumbnail_key", pq.Array(transactionIDs))
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	keys := make([]string, 0)
+
+	for rows.Next() {
+		var key string
+		var thumbnailKey *string
+
+		if err = rows.Scan(&key, &thumbnailKey); err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+
+		if thumbnailKey != nil {
+			keys = append(keys, *thumbnailKey)
+		}
+	}
+
+	return keys, rows.Err()
+}
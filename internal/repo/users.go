@@ -54,6 +54,10 @@ func (r *UsersRepo) Create(ctx context.Context, user domain.User) (int64, error)
 		return 0, err
 	}
 
+	if err = audit(ctx, tx, domain.AuditUser, domain.AuditRegister, nil, userId); err != nil {
+		return 0, err
+	}
+
 	return userId, tx.Commit()
 }
 
@@ -61,6 +65,25 @@ func (r *UsersRepo) Get(ctx context.Context, id int64) (domain.User, error) {
 	var item domain.User
 
 	if err := r.db.Get(&item, `SELECT * FROM users WHERE users.id = $1`, id); err != nil {
+		if err == sql.ErrNoRows {
+			return item, ErrUserNotFound
+		}
+
+		return item, err
+	}
+
+	return item, nil
+}
+
+func (r *UsersRepo) GetByEmail(ctx context.Context, email string) (domain.User, error) {
+	var item domain.User
+
+	if err := r.db.GetContext(ctx, &item, `SELECT * FROM users WHERE lower(users.email) = lower($1)`,
+		email); err != nil {
+		if err == sql.ErrNoRows {
+			return item, ErrUserNotFound
+		}
+
 		return item, err
 	}
 
@@ -84,8 +107,6 @@ func (r *UsersRepo) GetByCredentials(ctx context.Context, email, password string
 }
 
 func (r *UsersRepo) UpdatePassword(ctx context.Context, userID int64, toUpdate domain.UserToUpdate) (domain.User, error) {
-	var user domain.User
-
 	setValues := make([]string, 0)
 	args := make([]interface{}, 0)
 	argId := 1
@@ -113,13 +134,59 @@ func (r *UsersRepo) UpdatePassword(ctx context.Context, userID int64, toUpdate d
 	query := fmt.Sprintf(`UPDATE users u SET %s WHERE u.id = $%d RETURNING u.*`, setQuery, argId)
 	args = append(args, userID)
 
-	err := r.db.GetContext(ctx, &user, query, args...)
+	action := domain.AuditUpdate
+
+	if toUpdate.Password != nil {
+		action = domain.AuditPasswordChange
+	}
+
+	return r.update(ctx, userID, action, query, args...)
+}
+
+func (r *UsersRepo) SetDisabled(ctx context.Context, id int64, disabled bool) (domain.User, error) {
+	action := domain.AuditEnable
+
+	if disabled {
+		action = domain.AuditDisable
+	}
+
+	return r.update(ctx, id, action, `UPDATE users u SET disabled = $1 WHERE u.id = $2 RETURNING u.*`, disabled, id)
+}
+
+// update updates user by query returning updated user and records action to audit log
+func (r *UsersRepo) update(ctx context.Context, id int64, action domain.AuditAction, query string,
+	args ...interface{}) (domain.User, error) {
+	var user domain.User
+
+	tx, err := r.db.Beginx()
+
+	if err != nil {
+		return user, err
+	}
+
+	before, err := auditStates(ctx, tx.Tx, domain.AuditUser, id)
 
 	if err != nil {
-		return domain.User{}, err
+		return user, err
+	}
+
+	if err = tx.GetContext(ctx, &user, query, args...); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return user, err
+		}
+
+		if err == sql.ErrNoRows {
+			return user, ErrUserNotFound
+		}
+
+		return user, err
 	}
 
-	return user, nil
+	if err = audit(ctx, tx.Tx, domain.AuditUser, action, before, id); err != nil {
+		return user, err
+	}
+
+	return user, tx.Commit()
 }
 
 type SessionsRepo struct {
@@ -136,6 +203,20 @@ func (r *SessionsRepo) Create(ctx context.Context, userID int64) error {
 	return err
 }
 
+func (r *SessionsRepo) GetByUser(ctx context.Context, userID int64) (domain.Session, error) {
+	var item domain.Session
+
+	if err := r.db.GetContext(ctx, &item, `SELECT s.* FROM sessions s WHERE s.user_id = $1`, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return item, ErrSessionNotFound
+		}
+
+		return item, err
+	}
+
+	return item, nil
+}
+
 func (r *SessionsRepo) GetByToken(ctx context.Context, token string) (domain.Session, error) {
 	var item domain.Session
 
@@ -151,14 +232,29 @@ func (r *SessionsRepo) GetByToken(ctx context.Context, token string) (domain.Ses
 	return item, nil
 }
 
+// Update issues refresh token of user's session and records action of update to audit log
 func (r *SessionsRepo) Update(ctx context.Context, toUpdate domain.SessionToUpdate, userID int64) (domain.Session, error) {
 	var session domain.Session
 
-	err := r.db.GetContext(ctx, &session,
-		"UPDATE sessions s SET refresh_token = $1, expires_at = $2 WHERE s.user_id =$3 RETURNING *",
-		toUpdate.RefreshToken, toUpdate.ExpiresAt, userID)
+	tx, err := r.db.Beginx()
 
 	if err != nil {
+		return session, err
+	}
+
+	err = tx.GetContext(ctx, &session,
+		`UPDATE sessions s SET refresh_token = $1, expires_at = $2, version = version + 1 WHERE s.user_id =$3
+		RETURNING *`, toUpdate.RefreshToken, toUpdate.ExpiresAt, userID)
+
+	if err == nil {
+		err = createAuditEntry(ctx, tx, userID, domain.AuditUser, userID, toUpdate.Action, nil, nil)
+	}
+
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return session, err
+		}
+
 		if err == sql.ErrNoRows {
 			return session, ErrSessionNotFound
 		}
@@ -166,5 +262,32 @@ func (r *SessionsRepo) Update(ctx context.Context, toUpdate domain.SessionToUpda
 		return session, err
 	}
 
-	return session, nil
+	return session, tx.Commit()
+}
+
+// Reset invalidates refresh token of user's session and access tokens issued for it
+func (r *SessionsRepo) Reset(ctx context.Context, userID int64) error {
+	tx, err := r.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE sessions s SET refresh_token = NULL, expires_at = NULL, version = version + 1 WHERE s.user_id = $1",
+		userID)
+
+	if err == nil {
+		err = createAuditEntry(ctx, tx, userID, domain.AuditUser, userID, domain.AuditSessionsReset, nil, nil)
+	}
+
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	return tx.Commit()
 }
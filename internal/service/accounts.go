@@ -69,30 +69,8 @@ func (s *AccountsService) Create(ctx context.Context, toCreate domain.AccountToC
 		return domain.Account{}, ErrInvalidCardData
 	}
 
-	// Check for limiting for cash anc card accounts
-	if toCreate.Type == domain.Cash || toCreate.Type == domain.Card {
-		var count int64
-
-		if count, err = s.repo.CountByTypes(ctx, userID, domain.Cash, domain.Card); err != nil {
-			return domain.Account{}, err
-		}
-
-		if uint8(count) >= s.cfg.CardAndCashLimit {
-			return domain.Account{}, ErrAccountCountLimited
-		}
-	}
-
-	// Check for limiting for loan anc deposit accounts
-	if toCreate.Type == domain.Loan || toCreate.Type == domain.Deposit {
-		var count int64
-
-		if count, err = s.repo.CountByTypes(ctx, userID, domain.Loan, domain.Deposit); err != nil {
-			return domain.Account{}, err
-		}
-
-		if uint8(count) >= s.cfg.LoanAndDepositLimit {
-			return domain.Account{}, ErrAccountCountLimited
-		}
+	if err = checkAccountsLimit(ctx, s.repo, s.cfg, userID, toCreate.Type); err != nil {
+		return domain.Account{}, err
 	}
 
 	account, err := s.repo.Create(ctx, toCreate, userID, currencyID)
@@ -153,6 +131,7 @@ func (s *AccountsService) Update(ctx context.Context, toUpdate domain.AccountToU
 	return account, nil
 }
 
+// Delete moves account of user to trash
 func (s *AccountsService) Delete(ctx context.Context, id int64, userID int64) error {
 	_, err := s.Get(ctx, id, userID)
 
@@ -163,6 +142,38 @@ func (s *AccountsService) Delete(ctx context.Context, id int64, userID int64) er
 	return s.repo.Delete(ctx, id)
 }
 
+// checkAccountsLimit returns ErrAccountCountLimited if user can't have one more account of given type
+func checkAccountsLimit(ctx context.Context, accountsRepo repo.Accounts, cfg config.Account, userID int64,
+	_type domain.AccountType) error {
+	// Check for limiting for cash anc card accounts
+	if _type == domain.Cash || _type == domain.Card {
+		count, err := accountsRepo.CountByTypes(ctx, userID, domain.Cash, domain.Card)
+
+		if err != nil {
+			return err
+		}
+
+		if uint8(count) >= cfg.CardAndCashLimit {
+			return ErrAccountCountLimited
+		}
+	}
+
+	// Check for limiting for loan anc deposit accounts
+	if _type == domain.Loan || _type == domain.Deposit {
+		count, err := accountsRepo.CountByTypes(ctx, userID, domain.Loan, domain.Deposit)
+
+		if err != nil {
+			return err
+		}
+
+		if uint8(count) >= cfg.LoanAndDepositLimit {
+			return ErrAccountCountLimited
+		}
+	}
+
+	return nil
+}
+
 type AccountTypesService struct {
 	repo repo.AccountTypes
 }
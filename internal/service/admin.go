@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+)
+
+type AdminService struct {
+	usersRepo    repo.Users
+	sessionsRepo repo.Sessions
+	systemRepo   repo.System
+}
+
+func newAdminService(usersRepo repo.Users, sessionsRepo repo.Sessions, systemRepo repo.System) *AdminService {
+	return &AdminService{
+		usersRepo:    usersRepo,
+		sessionsRepo: sessionsRepo,
+		systemRepo:   systemRepo,
+	}
+}
+
+func (s *AdminService) SetDisabled(ctx context.Context, userID int64, disabled bool) (domain.User, error) {
+	user, err := s.usersRepo.SetDisabled(ctx, userID, disabled)
+
+	if err != nil {
+		return user, err
+	}
+
+	// Disabled user must not be able to refresh tokens anymore
+	if disabled {
+		if err = s.sessionsRepo.Reset(ctx, userID); err != nil {
+			return user, err
+		}
+	}
+
+	return user, nil
+}
+
+func (s *AdminService) ResetSessions(ctx context.Context, userID int64) error {
+	if _, err := s.usersRepo.Get(ctx, userID); err != nil {
+		return err
+	}
+
+	return s.sessionsRepo.Reset(ctx, userID)
+}
+
+func (s *AdminService) Stats(ctx context.Context) (domain.SystemStats, error) {
+	return s.systemRepo.Stats(ctx)
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	mockRepo "github.com/lotostudio/financial-api/internal/repo/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func mockAdminService(t *testing.T) (*AdminService, *mockRepo.MockUsers, *mockRepo.MockSessions, *mockRepo.MockSystem) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	uRepo := mockRepo.NewMockUsers(mockCtl)
+	sRepo := mockRepo.NewMockSessions(mockCtl)
+	sysRepo := mockRepo.NewMockSystem(mockCtl)
+
+	s := newAdminService(uRepo, sRepo, sysRepo)
+
+	return s, uRepo, sRepo, sysRepo
+}
+
+func TestAdminService_Disable(t *testing.T) {
+	s, uRepo, sRepo, _ := mockAdminService(t)
+
+	ctx := context.Background()
+
+	uRepo.EXPECT().SetDisabled(ctx, userId, true).Return(domain.User{ID: userId, Disabled: true}, nil)
+	sRepo.EXPECT().Reset(ctx, userId).Return(nil)
+
+	user, err := s.SetDisabled(ctx, userId, true)
+
+	require.NoError(t, err)
+	require.True(t, user.Disabled)
+}
+
+func TestAdminService_Enable(t *testing.T) {
+	s, uRepo, _, _ := mockAdminService(t)
+
+	ctx := context.Background()
+
+	uRepo.EXPECT().SetDisabled(ctx, userId, false).Return(domain.User{ID: userId}, nil)
+
+	user, err := s.SetDisabled(ctx, userId, false)
+
+	require.NoError(t, err)
+	require.False(t, user.Disabled)
+}
+
+func TestAdminService_ResetSessions(t *testing.T) {
+	s, uRepo, sRepo, _ := mockAdminService(t)
+
+	ctx := context.Background()
+
+	uRepo.EXPECT().Get(ctx, userId).Return(domain.User{ID: userId}, nil)
+	sRepo.EXPECT().Reset(ctx, userId).Return(nil)
+
+	err := s.ResetSessions(ctx, userId)
+
+	require.NoError(t, err)
+}
+
+func TestAdminService_ResetSessionsErrUserNotFound(t *testing.T) {
+	s, uRepo, _, _ := mockAdminService(t)
+
+	ctx := context.Background()
+
+	uRepo.EXPECT().Get(ctx, userId).Return(domain.User{}, repo.ErrUserNotFound)
+
+	err := s.ResetSessions(ctx, userId)
+
+	require.ErrorIs(t, err, repo.ErrUserNotFound)
+}
+
+func TestAdminService_Stats(t *testing.T) {
+	s, _, _, sysRepo := mockAdminService(t)
+
+	ctx := context.Background()
+
+	sysRepo.EXPECT().Stats(ctx).Return(domain.SystemStats{Users: 1}, nil)
+
+	stats, err := s.Stats(ctx)
+
+	require.NoError(t, err)
+	require.Equal(t, int64(1), stats.Users)
+}
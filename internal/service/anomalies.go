@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// Only transactions made in this count of days are checked, older ones are marked as scanned without flags
+	anomalyRecentDays = 30
+	// Minimal count of earlier values needed to compare with
+	anomalyMinHistory = 5
+	// Modified z-score above which value is unusual
+	anomalyZScore = 3.5
+	// Minimal count of expenses in a day to be considered abnormal
+	anomalyMinDailyCount = 3
+	// Recent transactions are compared with transactions made in this count of months before them
+	anomalyHistoryMonths = 12
+)
+
+type AnomaliesService struct {
+	transRepo repo.Transactions
+}
+
+func newAnomaliesService(transRepo repo.Transactions) *AnomaliesService {
+	return &AnomaliesService{
+		transRepo: transRepo,
+	}
+}
+
+// Detect checks not scanned transactions of all users and flags unusual expenses. Returns count of flagged transactions
+func (s *AnomaliesService) Detect(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	since := today.AddDate(0, 0, -anomalyRecentDays)
+	// History is bounded, transactions dated up to a year ahead are checked as recent ones
+	from, to := since.AddDate(0, -anomalyHistoryMonths, 0), today.AddDate(1, 0, 0)
+
+	// Only transactions loaded with history below are selected, others would never be marked scanned
+	owners, err := s.transRepo.ListUnscannedOwners(ctx, to)
+
+	if err != nil {
+		return 0, err
+	}
+
+	flagged := 0
+
+	for _, ownerID := range owners {
+		ownerID := ownerID
+
+		// Transactions older than history are never checked
+		if err = s.transRepo.SetScannedBefore(ctx, ownerID, from); err != nil {
+			return flagged, err
+		}
+
+		history, err := s.transRepo.List(ctx, domain.TransactionsFilter{
+			OwnerId:     &ownerID,
+			CreatedFrom: &from,
+			CreatedTo:   &to,
+		})
+
+		if err != nil {
+			return flagged, err
+		}
+
+		flags := detectAnomalies(history, since)
+
+		if len(flags) == 0 {
+			continue
+		}
+
+		if err = s.transRepo.SetFlags(ctx, flags); err != nil {
+			return flagged, err
+		}
+
+		for _, txFlags := range flags {
+			if len(txFlags) > 0 {
+				flagged++
+			}
+		}
+	}
+
+	return flagged, nil
+}
+
+// detectAnomalies returns flags of every not scanned transaction in history of user. Expenses made since given date
+// are compared with earlier expenses: amount with amounts of the same category and count of expenses in a day with
+// counts of earlier days
+func detectAnomalies(history []domain.Transaction, since time.Time) map[int64][]domain.TransactionFlag {
+	expenses := make([]domain.Transaction, 0)
+	flags := make(map[int64][]domain.TransactionFlag)
+
+	for _, tx := range history {
+		if !tx.Scanned {
+			flags[tx.ID] = nil
+		}
+
+		if tx.Type == domain.Expense {
+			expenses = append(expenses, tx)
+		}
+	}
+
+	sort.Slice(expenses, func(i, j int) bool {
+		if !expenses[i].CreatedAt.Equal(expenses[j].CreatedAt) {
+			return expenses[i].CreatedAt.Before(expenses[j].CreatedAt)
+		}
+
+		return expenses[i].ID < expenses[j].ID
+	})
+
+	amounts := make(map[string][]float64)
+	dailyCounts := make([]float64, 0)
+	var day time.Time
+	var dayExpenses []domain.Transaction
+
+	// Count of expenses in a day is known after all of them are passed
+	checkDay := func() {
+		if len(dayExpenses) >= anomalyMinDailyCount && len(dailyCounts) >= anomalyMinHistory &&
+			isOutlier(float64(len(dayExpenses)), dailyCounts) {
+			for _, tx := range dayExpenses {
+				if _, ok := flags[tx.ID]; ok && !tx.CreatedAt.Before(since) {
+					flags[tx.ID] = append(flags[tx.ID], domain.FlagManyTransactions)
+				}
+			}
+		}
+
+		if len(dayExpenses) > 0 {
+			dailyCounts = append(dailyCounts, float64(len(dayExpenses)))
+		}
+	}
+
+	for _, tx := range expenses {
+		if !tx.CreatedAt.Equal(day) {
+			checkDay()
+
+			day = tx.CreatedAt
+			dayExpenses = dayExpenses[:0]
+		}
+
+		dayExpenses = append(dayExpenses, tx)
+
+		// Lines of split transaction are compared with earlier amounts of their own categories
+		parts := tx.Parts()
+
+		if _, ok := flags[tx.ID]; ok && !tx.CreatedAt.Before(since) {
+			var newCategory, unusualAmount bool
+
+			for _, part := range parts {
+				earlier := amounts[partCategory(part)]
+
+				if len(earlier) == 0 {
+					newCategory = true
+				} else if len(earlier) >= anomalyMinHistory && isOutlier(part.Amount, earlier) {
+					unusualAmount = true
+				}
+			}
+
+			if newCategory {
+				flags[tx.ID] = append(flags[tx.ID], domain.FlagNewCategory)
+			}
+
+			if unusualAmount {
+				flags[tx.ID] = append(flags[tx.ID], domain.FlagUnusualAmount)
+			}
+		}
+
+		for _, part := range parts {
+			amounts[partCategory(part)] = append(amounts[partCategory(part)], part.Amount)
+		}
+	}
+
+	checkDay()
+
+	return flags
+}
+
+// partCategory returns title of category of part of transaction, empty for transfers
+func partCategory(part domain.TransactionLine) string {
+	if part.Category == nil {
+		return ""
+	}
+
+	return *part.Category
+}
+
+// isOutlier checks whether value is far above values by modified z-score based on median absolute deviation.
+// If most of values are equal and deviation is zero, value is outlier when it is more than twice the median
+func isOutlier(value float64, values []float64) bool {
+	m := median(values)
+	deviations := make([]float64, len(values))
+
+	for i, v := range values {
+		deviations[i] = math.Abs(v - m)
+	}
+
+	mad := median(deviations)
+
+	if mad == 0 {
+		return value > 2*m
+	}
+
+	return 0.6745*(value-m)/mad > anomalyZScore
+}
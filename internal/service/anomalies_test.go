@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/lotostudio/financial-api/internal/domain"
+	mockRepo "github.com/lotostudio/financial-api/internal/repo/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func mockAnomaliesService(t *testing.T) (Anomalies, *mockRepo.MockTransactions) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	tRepo := mockRepo.NewMockTransactions(mockCtl)
+
+	s := newAnomaliesService(tRepo)
+
+	return s, tRepo
+}
+
+func TestAnomaliesService_Detect(t *testing.T) {
+	s, tRepo := mockAnomaliesService(t)
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	food := "food"
+
+	from, to := today.AddDate(0, 0, -30).AddDate(0, -12, 0), today.AddDate(1, 0, 0)
+
+	tRepo.EXPECT().ListUnscannedOwners(gomock.Any(), to).Return([]int64{userId}, nil)
+	tRepo.EXPECT().SetScannedBefore(gomock.Any(), userId, from).Return(nil)
+	tRepo.EXPECT().List(gomock.Any(), domain.TransactionsFilter{OwnerId: &userId, CreatedFrom: &from,
+		CreatedTo: &to}).Return([]domain.Transaction{
+		{ID: 1, Amount: 10, Type: domain.Expense, Category: &food, CreatedAt: today.AddDate(0, 0, -5), Scanned: true},
+		{ID: 2, Amount: 10, Type: domain.Expense, Category: &food, CreatedAt: today},
+		{ID: 3, Amount: 10, Type: domain.Expense, Category: &food, CreatedAt: today.AddDate(0, 0, -1)},
+	}, nil)
+	tRepo.EXPECT().SetFlags(gomock.Any(), map[int64][]domain.TransactionFlag{
+		2: nil,
+		3: nil,
+	}).Return(nil)
+
+	flagged, err := s.Detect(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, 0, flagged)
+}
+
+func TestAnomaliesService_DetectFlagged(t *testing.T) {
+	s, tRepo := mockAnomaliesService(t)
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	food := "food"
+
+	from, to := today.AddDate(0, 0, -30).AddDate(0, -12, 0), today.AddDate(1, 0, 0)
+
+	tRepo.EXPECT().ListUnscannedOwners(gomock.Any(), to).Return([]int64{userId}, nil)
+	tRepo.EXPECT().SetScannedBefore(gomock.Any(), userId, from).Return(nil)
+	tRepo.EXPECT().List(gomock.Any(), domain.TransactionsFilter{OwnerId: &userId, CreatedFrom: &from,
+		CreatedTo: &to}).Return([]domain.Transaction{
+		{ID: 1, Amount: 10, Type: domain.Expense, Category: &food, CreatedAt: today},
+	}, nil)
+	tRepo.EXPECT().SetFlags(gomock.Any(), map[int64][]domain.TransactionFlag{
+		1: {domain.FlagNewCategory},
+	}).Return(nil)
+
+	flagged, err := s.Detect(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, 1, flagged)
+}
+
+func TestAnomaliesService_DetectErrScanned(t *testing.T) {
+	s, tRepo := mockAnomaliesService(t)
+
+	tRepo.EXPECT().ListUnscannedOwners(gomock.Any(), gomock.Any()).Return([]int64{userId}, nil)
+	tRepo.EXPECT().SetScannedBefore(gomock.Any(), userId, gomock.Any()).Return(errDefault)
+
+	_, err := s.Detect(context.Background())
+
+	require.ErrorIs(t, err, errDefault)
+}
+
+func TestAnomaliesService_DetectErr(t *testing.T) {
+	s, tRepo := mockAnomaliesService(t)
+
+	tRepo.EXPECT().ListUnscannedOwners(gomock.Any(), gomock.Any()).Return(nil, errDefault)
+
+	_, err := s.Detect(context.Background())
+
+	require.ErrorIs(t, err, errDefault)
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2022, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	food, travel, salary := "food", "travel", "salary"
+	history := []domain.Transaction{
+		// Not checked as made before recent period
+		{ID: 1, Amount: 500, Type: domain.Expense, Category: &food, CreatedAt: date(time.January, 1)},
+		// Income is not checked
+		{ID: 2, Amount: 5000, Type: domain.Income, Category: &salary, CreatedAt: date(time.March, 2)},
+		{ID: 3, Amount: 100, Type: domain.Expense, Category: &food, CreatedAt: date(time.March, 2)},
+		{ID: 4, Amount: 11, Type: domain.Expense, Category: &food, CreatedAt: date(time.March, 3)},
+		{ID: 5, Amount: 50, Type: domain.Expense, Category: &travel, CreatedAt: date(time.March, 4)},
+		// Already checked
+		{ID: 6, Amount: 1000, Type: domain.Expense, Category: &food, CreatedAt: date(time.March, 6), Scanned: true},
+	}
+
+	for i, amount := range []float64{10, 12, 11, 9, 10, 13, 10, 11, 12, 10} {
+		history = append(history, domain.Transaction{ID: int64(10 + i), Amount: amount, Type: domain.Expense,
+			Category: &food, CreatedAt: date(time.February, i+1), Scanned: true})
+	}
+
+	for i := 0; i < 5; i++ {
+		history = append(history, domain.Transaction{ID: int64(20 + i), Amount: 10, Type: domain.Expense,
+			Category: &food, CreatedAt: date(time.March, 5)})
+	}
+
+	flags := detectAnomalies(history, date(time.March, 1))
+
+	require.Equal(t, map[int64][]domain.TransactionFlag{
+		1:  nil,
+		2:  nil,
+		3:  {domain.FlagUnusualAmount},
+		4:  nil,
+		5:  {domain.FlagNewCategory},
+		20: {domain.FlagManyTransactions},
+		21: {domain.FlagManyTransactions},
+		22: {domain.FlagManyTransactions},
+		23: {domain.FlagManyTransactions},
+		24: {domain.FlagManyTransactions},
+	}, flags)
+}
+
+func TestDetectAnomaliesSplit(t *testing.T) {
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2022, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	food, travel := "food", "travel"
+	history := []domain.Transaction{
+		{ID: 1, Amount: 40, Type: domain.Expense, Category: &travel, CreatedAt: date(time.February, 20), Scanned: true},
+		// Lines are compared with earlier amounts of their categories
+		{ID: 2, Amount: 50, Type: domain.Expense, CreatedAt: date(time.March, 2), Lines: []domain.TransactionLine{
+			{Category: &food, Amount: 10}, {Category: &travel, Amount: 40}}},
+		{ID: 3, Amount: 250, Type: domain.Expense, CreatedAt: date(time.March, 3), Lines: []domain.TransactionLine{
+			{Category: &food, Amount: 200}, {Category: &travel, Amount: 50}}},
+	}
+
+	for i, amount := range []float64{10, 12, 11, 9, 10, 13, 10, 11, 12, 10} {
+		history = append(history, domain.Transaction{ID: int64(10 + i), Amount: amount, Type: domain.Expense,
+			Category: &food, CreatedAt: date(time.February, i+1), Scanned: true})
+	}
+
+	flags := detectAnomalies(history, date(time.March, 1))
+
+	require.Equal(t, map[int64][]domain.TransactionFlag{
+		2: nil,
+		3: {domain.FlagUnusualAmount},
+	}, flags)
+}
+
+func TestIsOutlier(t *testing.T) {
+	require.True(t, isOutlier(100, []float64{10, 12, 11, 9, 10}))
+	require.False(t, isOutlier(13, []float64{10, 12, 11, 9, 10}))
+	require.False(t, isOutlier(1, []float64{10, 12, 11, 9, 10}))
+	require.True(t, isOutlier(3, []float64{1, 1, 1, 1, 1}))
+	require.False(t, isOutlier(2, []float64{1, 1, 1, 1, 1}))
+}
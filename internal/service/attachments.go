@@ -0,0 +1,278 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"github.com/lotostudio/financial-api/internal/config"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"github.com/lotostudio/financial-api/pkg/storage"
+	"github.com/lotostudio/financial-api/pkg/thumbnail"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"unicode"
+)
+
+const (
+	// Length of random part of blob keys in bytes
+	attachmentKeyLength = 16
+	// Max length of stored filename
+	attachmentFilenameLength = 255
+)
+
+type AttachmentsService struct {
+	repo      repo.Attachments
+	transRepo repo.Transactions
+	blobs     storage.BlobStore
+	cfg       config.Attachments
+}
+
+func newAttachmentsService(repo repo.Attachments, transRepo repo.Transactions, blobs storage.BlobStore,
+	cfg config.Attachments) *AttachmentsService {
+	return &AttachmentsService{
+		repo:      repo,
+		transRepo: transRepo,
+		blobs:     blobs,
+		cfg:       cfg,
+	}
+}
+
+func (s *AttachmentsService) List(ctx context.Context, transactionID int64, userID int64) ([]domain.Attachment, error) {
+	if err := s.checkOwner(ctx, transactionID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.List(ctx, transactionID)
+}
+
+// Upload stores file attached to transaction. Type of file is detected by its content and must be one of allowed,
+// thumbnail is created for images unless they are too large to decode
+func (s *AttachmentsService) Upload(ctx context.Context, transactionID int64, userID int64, filename string,
+	file io.Reader) (domain.Attachment, error) {
+	if err := s.checkOwner(ctx, transactionID, userID); err != nil {
+		return domain.Attachment{}, err
+	}
+
+	// One byte more than allowed is read to find out that file is too large
+	data, err := io.ReadAll(io.LimitReader(file, s.cfg.MaxSize+1))
+
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+
+	if len(data) == 0 {
+		return domain.Attachment{}, ErrAttachmentEmpty
+	}
+
+	if int64(len(data)) > s.cfg.MaxSize {
+		return domain.Attachment{}, ErrAttachmentTooLarge
+	}
+
+	contentType, err := s.detectType(data)
+
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+
+	var thumb []byte
+
+	if s.cfg.ThumbnailSize > 0 && thumbnail.Supported(contentType) {
+		thumb, err = thumbnail.Create(data, s.cfg.ThumbnailSize)
+
+		if errors.Is(err, thumbnail.ErrImageTooLarge) {
+			thumb = nil
+		} else if err != nil {
+			return domain.Attachment{}, ErrAttachmentImageInvalid
+		}
+	}
+
+	key, err := attachmentKey(transactionID)
+
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+
+	if err = s.blobs.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return domain.Attachment{}, err
+	}
+
+	keys := []string{key}
+	toCreate := domain.Attachment{
+		TransactionID: transactionID,
+		Filename:      cleanFilename(filename),
+		ContentType:   contentType,
+		Size:          int64(len(data)),
+		Key:           key,
+	}
+
+	if thumb != nil {
+		thumbKey := key + "-thumbnail"
+
+		if err = s.blobs.Put(ctx, thumbKey, bytes.NewReader(thumb), int64(len(thumb)), thumbnail.ContentType); err != nil {
+			deleteBlobs(ctx, s.blobs, keys...)
+			return domain.Attachment{}, err
+		}
+
+		keys = append(keys, thumbKey)
+		toCreate.ThumbnailKey = &thumbKey
+	}
+
+	attachment, err := s.repo.Create(ctx, toCreate)
+
+	if err != nil {
+		deleteBlobs(ctx, s.blobs, keys...)
+		return domain.Attachment{}, err
+	}
+
+	return attachment, nil
+}
+
+// Open returns attachment of transaction with reader of its file or thumbnail. Reader must be closed after use
+func (s *AttachmentsService) Open(ctx context.Context, transactionID int64, id int64, userID int64,
+	thumb bool) (domain.Attachment, io.ReadCloser, error) {
+	attachment, err := s.get(ctx, transactionID, id, userID)
+
+	if err != nil {
+		return domain.Attachment{}, nil, err
+	}
+
+	key := attachment.Key
+
+	if thumb {
+		if attachment.ThumbnailKey == nil {
+			return domain.Attachment{}, nil, ErrThumbnailNotFound
+		}
+
+		key = *attachment.ThumbnailKey
+	}
+
+	r, err := s.blobs.Get(ctx, key)
+
+	if err != nil {
+		return domain.Attachment{}, nil, err
+	}
+
+	return attachment, r, nil
+}
+
+func (s *AttachmentsService) Delete(ctx context.Context, transactionID int64, id int64, userID int64) error {
+	attachment, err := s.get(ctx, transactionID, id, userID)
+
+	if err != nil {
+		return err
+	}
+
+	if err = s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	keys := []string{attachment.Key}
+
+	if attachment.ThumbnailKey != nil {
+		keys = append(keys, *attachment.ThumbnailKey)
+	}
+
+	deleteBlobs(ctx, s.blobs, keys...)
+
+	return nil
+}
+
+// get returns attachment if it belongs to transaction of user
+func (s *AttachmentsService) get(ctx context.Context, transactionID int64, id int64,
+	userID int64) (domain.Attachment, error) {
+	if err := s.checkOwner(ctx, transactionID, userID); err != nil {
+		return domain.Attachment{}, err
+	}
+
+	attachment, err := s.repo.Get(ctx, id)
+
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+
+	if attachment.TransactionID != transactionID {
+		return domain.Attachment{}, repo.ErrAttachmentNotFound
+	}
+
+	return attachment, nil
+}
+
+func (s *AttachmentsService) checkOwner(ctx context.Context, transactionID int64, userID int64) error {
+	ownerId, err := s.transRepo.GetOwner(ctx, transactionID)
+
+	if err != nil {
+		return err
+	}
+
+	if ownerId != userID {
+		return ErrTransactionForbidden
+	}
+
+	return nil
+}
+
+// detectType returns MIME type of file content without parameters if it is allowed
+func (s *AttachmentsService) detectType(data []byte) (string, error) {
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(data))
+
+	if err != nil {
+		return "", ErrAttachmentTypeNotAllowed
+	}
+
+	for _, allowed := range s.cfg.Types {
+		if contentType == allowed {
+			return contentType, nil
+		}
+	}
+
+	return "", ErrAttachmentTypeNotAllowed
+}
+
+// deleteBlobs removes blobs of deleted attachments. Failures are only logged, as attachments are already gone
+func deleteBlobs(ctx context.Context, blobs storage.BlobStore, keys ...string) {
+	for _, key := range keys {
+		if err := blobs.Delete(ctx, key); err != nil {
+			log.Warnf("error deleting blob %s error - %s", key, err)
+		}
+	}
+}
+
+// attachmentKey returns new random key of file attached to transaction
+func attachmentKey(transactionID int64) (string, error) {
+	b := make([]byte, attachmentKeyLength)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("transactions/%d/%x", transactionID, b), nil
+}
+
+// cleanFilename strips directories and control characters from name of uploaded file and limits its length
+func cleanFilename(filename string) string {
+	filename = path.Base(strings.ReplaceAll(filename, `\`, "/"))
+	filename = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+
+		return r
+	}, filename))
+
+	if filename == "" || filename == "." || filename == ".." || filename == "/" {
+		return "attachment"
+	}
+
+	if runes := []rune(filename); len(runes) > attachmentFilenameLength {
+		filename = string(runes[:attachmentFilenameLength])
+	}
+
+	return filename
+}
@@ -0,0 +1,336 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/lotostudio/financial-api/internal/config"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	mockRepo "github.com/lotostudio/financial-api/internal/repo/mocks"
+	mockStorage "github.com/lotostudio/financial-api/pkg/storage/mocks"
+	"github.com/stretchr/testify/require"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+)
+
+func mockAttachmentsService(t *testing.T) (*AttachmentsService, *mockRepo.MockAttachments, *mockRepo.MockTransactions,
+	*mockStorage.MockBlobStore) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	aRepo := mockRepo.NewMockAttachments(mockCtl)
+	tRepo := mockRepo.NewMockTransactions(mockCtl)
+	blobs := mockStorage.NewMockBlobStore(mockCtl)
+
+	s := newAttachmentsService(aRepo, tRepo, blobs, config.Attachments{
+		MaxSize:       1024,
+		Types:         []string{"image/png", "application/pdf"},
+		ThumbnailSize: 16,
+	})
+
+	return s, aRepo, tRepo, blobs
+}
+
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 64, 32))))
+
+	return buf.Bytes()
+}
+
+// hugePNG returns header of PNG image with too many pixels to create thumbnail
+func hugePNG() []byte {
+	chunk := []byte("IHDR\x00\x00\x27\x10\x00\x00\x27\x10\x08\x00\x00\x00\x00")
+	crc := crc32.ChecksumIEEE(chunk)
+
+	data := append([]byte("\x89PNG\x0D\x0A\x1A\x0A\x00\x00\x00\x0D"), chunk...)
+
+	return append(data, byte(crc>>24), byte(crc>>16), byte(crc>>8), byte(crc))
+}
+
+func TestAttachmentsService_List(t *testing.T) {
+	s, aRepo, tRepo, _ := mockAttachmentsService(t)
+
+	ctx := context.Background()
+	id := int64(1)
+
+	tRepo.EXPECT().GetOwner(ctx, id).Return(userId, nil)
+	aRepo.EXPECT().List(ctx, id).Return([]domain.Attachment{{ID: 2, TransactionID: id}}, nil)
+
+	attachments, err := s.List(ctx, id, userId)
+
+	require.NoError(t, err)
+	require.Len(t, attachments, 1)
+}
+
+func TestAttachmentsService_ListErrForbidden(t *testing.T) {
+	s, _, tRepo, _ := mockAttachmentsService(t)
+
+	ctx := context.Background()
+	id := int64(1)
+
+	tRepo.EXPECT().GetOwner(ctx, id).Return(userId+1, nil)
+
+	_, err := s.List(ctx, id, userId)
+
+	require.ErrorIs(t, err, ErrTransactionForbidden)
+}
+
+func TestAttachmentsService_UploadImage(t *testing.T) {
+	s, aRepo, tRepo, blobs := mockAttachmentsService(t)
+
+	ctx := context.Background()
+	id := int64(1)
+	data := testPNG(t)
+	var key string
+
+	tRepo.EXPECT().GetOwner(ctx, id).Return(userId, nil)
+	blobs.EXPECT().Put(ctx, gomock.Any(), gomock.Any(), int64(len(data)), "image/png").
+		DoAndReturn(func(_ context.Context, k string, r io.Reader, _ int64, _ string) error {
+			key = k
+			content, err := io.ReadAll(r)
+
+			require.NoError(t, err)
+			require.Equal(t, data, content)
+
+			return nil
+		})
+	blobs.EXPECT().Put(ctx, gomock.Any(), gomock.Any(), gomock.Any(), "image/jpeg").
+		DoAndReturn(func(_ context.Context, k string, _ io.Reader, _ int64, _ string) error {
+			require.Equal(t, key+"-thumbnail", k)
+
+			return nil
+		})
+	aRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context,
+		toCreate domain.Attachment) (domain.Attachment, error) {
+		require.Equal(t, id, toCreate.TransactionID)
+		require.Equal(t, "receipt.png", toCreate.Filename)
+		require.Equal(t, "image/png", toCreate.ContentType)
+		require.Equal(t, int64(len(data)), toCreate.Size)
+		require.Regexp(t, `^transactions/1/[0-9a-f]{32}$`, toCreate.Key)
+		require.NotNil(t, toCreate.ThumbnailKey)
+		require.Equal(t, toCreate.Key+"-thumbnail", *toCreate.ThumbnailKey)
+
+		toCreate.ID = 2
+		toCreate.HasThumbnail = true
+
+		return toCreate, nil
+	})
+
+	attachment, err := s.Upload(ctx, id, userId, "../receipt.png", bytes.NewReader(data))
+
+	require.NoError(t, err)
+	require.Equal(t, int64(2), attachment.ID)
+	require.True(t, attachment.HasThumbnail)
+}
+
+func TestAttachmentsService_UploadHugeImage(t *testing.T) {
+	s, aRepo, tRepo, blobs := mockAttachmentsService(t)
+
+	ctx := context.Background()
+	id := int64(1)
+	data := hugePNG()
+
+	tRepo.EXPECT().GetOwner(ctx, id).Return(userId, nil)
+	// Image is stored without thumbnail
+	blobs.EXPECT().Put(ctx, gomock.Any(), gomock.Any(), int64(len(data)), "image/png").Return(nil)
+	aRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context,
+		toCreate domain.Attachment) (domain.Attachment, error) {
+		require.Nil(t, toCreate.ThumbnailKey)
+
+		return toCreate, nil
+	})
+
+	attachment, err := s.Upload(ctx, id, userId, "scan.png", bytes.NewReader(data))
+
+	require.NoError(t, err)
+	require.False(t, attachment.HasThumbnail)
+}
+
+func TestAttachmentsService_UploadDocument(t *testing.T) {
+	s, aRepo, tRepo, blobs := mockAttachmentsService(t)
+
+	ctx := context.Background()
+	id := int64(1)
+
+	tRepo.EXPECT().GetOwner(ctx, id).Return(userId, nil)
+	blobs.EXPECT().Put(ctx, gomock.Any(), gomock.Any(), int64(13), "application/pdf").Return(nil)
+	aRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context,
+		toCreate domain.Attachment) (domain.Attachment, error) {
+		require.Nil(t, toCreate.ThumbnailKey)
+
+		return toCreate, nil
+	})
+
+	attachment, err := s.Upload(ctx, id, userId, "invoice.pdf", strings.NewReader("%PDF-1.4\n%EOF"))
+
+	require.NoError(t, err)
+	require.Equal(t, "application/pdf", attachment.ContentType)
+}
+
+func TestAttachmentsService_UploadErrCreate(t *testing.T) {
+	s, aRepo, tRepo, blobs := mockAttachmentsService(t)
+
+	ctx := context.Background()
+	id := int64(1)
+	var key string
+
+	tRepo.EXPECT().GetOwner(ctx, id).Return(userId, nil)
+	blobs.EXPECT().Put(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, k string, _ io.Reader, _ int64, _ string) error {
+			key = k
+
+			return nil
+		}).Times(2)
+	aRepo.EXPECT().Create(ctx, gomock.Any()).Return(domain.Attachment{}, errDefault)
+	// Stored files are removed
+	blobs.EXPECT().Delete(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, k string) error {
+		require.True(t, strings.HasPrefix(key, k))
+
+		return nil
+	}).Times(2)
+
+	_, err := s.Upload(ctx, id, userId, "receipt.png", bytes.NewReader(testPNG(t)))
+
+	require.ErrorIs(t, err, errDefault)
+}
+
+func TestAttachmentsService_UploadErr(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		content  string
+		owner    int64
+		ownerErr error
+		err      error
+	}{
+		{name: "transaction not found", ownerErr: repo.ErrTransactionNotFound, err: repo.ErrTransactionNotFound},
+		{name: "forbidden", owner: userId + 1, err: ErrTransactionForbidden},
+		{name: "empty", owner: userId, err: ErrAttachmentEmpty},
+		{name: "too large", owner: userId, content: "%PDF-" + strings.Repeat("a", 1024), err: ErrAttachmentTooLarge},
+		{name: "type not allowed", owner: userId, content: "plain text", err: ErrAttachmentTypeNotAllowed},
+		{name: "broken image", owner: userId, content: "\x89PNG\x0D\x0A\x1A\x0Abroken", err: ErrAttachmentImageInvalid},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, tRepo, _ := mockAttachmentsService(t)
+
+			ctx := context.Background()
+			id := int64(1)
+
+			tRepo.EXPECT().GetOwner(ctx, id).Return(tt.owner, tt.ownerErr)
+
+			_, err := s.Upload(ctx, id, userId, "file", strings.NewReader(tt.content))
+
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestAttachmentsService_Open(t *testing.T) {
+	s, aRepo, tRepo, blobs := mockAttachmentsService(t)
+
+	ctx := context.Background()
+	id, attachmentId := int64(1), int64(2)
+	thumbKey := "transactions/1/a-thumbnail"
+	attachment := domain.Attachment{ID: attachmentId, TransactionID: id, Key: "transactions/1/a",
+		ThumbnailKey: &thumbKey}
+
+	tRepo.EXPECT().GetOwner(ctx, id).Return(userId, nil).Times(2)
+	aRepo.EXPECT().Get(ctx, attachmentId).Return(attachment, nil).Times(2)
+	blobs.EXPECT().Get(ctx, "transactions/1/a").Return(io.NopCloser(strings.NewReader("file")), nil)
+	blobs.EXPECT().Get(ctx, thumbKey).Return(io.NopCloser(strings.NewReader("thumbnail")), nil)
+
+	opened, r, err := s.Open(ctx, id, attachmentId, userId, false)
+
+	require.NoError(t, err)
+	require.Equal(t, attachment, opened)
+
+	content, _ := io.ReadAll(r)
+
+	require.Equal(t, "file", string(content))
+
+	_, r, err = s.Open(ctx, id, attachmentId, userId, true)
+
+	require.NoError(t, err)
+
+	content, _ = io.ReadAll(r)
+
+	require.Equal(t, "thumbnail", string(content))
+}
+
+func TestAttachmentsService_OpenErrThumbnail(t *testing.T) {
+	s, aRepo, tRepo, _ := mockAttachmentsService(t)
+
+	ctx := context.Background()
+	id, attachmentId := int64(1), int64(2)
+
+	tRepo.EXPECT().GetOwner(ctx, id).Return(userId, nil)
+	aRepo.EXPECT().Get(ctx, attachmentId).Return(domain.Attachment{ID: attachmentId, TransactionID: id}, nil)
+
+	_, _, err := s.Open(ctx, id, attachmentId, userId, true)
+
+	require.ErrorIs(t, err, ErrThumbnailNotFound)
+}
+
+func TestAttachmentsService_OpenErrOtherTransaction(t *testing.T) {
+	s, aRepo, tRepo, _ := mockAttachmentsService(t)
+
+	ctx := context.Background()
+	id, attachmentId := int64(1), int64(2)
+
+	tRepo.EXPECT().GetOwner(ctx, id).Return(userId, nil)
+	aRepo.EXPECT().Get(ctx, attachmentId).Return(domain.Attachment{ID: attachmentId, TransactionID: id + 1}, nil)
+
+	_, _, err := s.Open(ctx, id, attachmentId, userId, false)
+
+	require.ErrorIs(t, err, repo.ErrAttachmentNotFound)
+}
+
+func TestAttachmentsService_Delete(t *testing.T) {
+	s, aRepo, tRepo, blobs := mockAttachmentsService(t)
+
+	ctx := context.Background()
+	id, attachmentId := int64(1), int64(2)
+	thumbKey := "transactions/1/a-thumbnail"
+
+	tRepo.EXPECT().GetOwner(ctx, id).Return(userId, nil)
+	aRepo.EXPECT().Get(ctx, attachmentId).Return(domain.Attachment{ID: attachmentId, TransactionID: id,
+		Key: "transactions/1/a", ThumbnailKey: &thumbKey}, nil)
+	aRepo.EXPECT().Delete(ctx, attachmentId).Return(nil)
+	blobs.EXPECT().Delete(ctx, "transactions/1/a").Return(nil)
+	blobs.EXPECT().Delete(ctx, thumbKey).Return(nil)
+
+	require.NoError(t, s.Delete(ctx, id, attachmentId, userId))
+}
+
+func TestAttachmentsService_DeleteErr(t *testing.T) {
+	s, aRepo, tRepo, _ := mockAttachmentsService(t)
+
+	ctx := context.Background()
+	id, attachmentId := int64(1), int64(2)
+
+	tRepo.EXPECT().GetOwner(ctx, id).Return(userId, nil)
+	aRepo.EXPECT().Get(ctx, attachmentId).Return(domain.Attachment{ID: attachmentId, TransactionID: id}, nil)
+	aRepo.EXPECT().Delete(ctx, attachmentId).Return(errDefault)
+
+	require.ErrorIs(t, s.Delete(ctx, id, attachmentId, userId), errDefault)
+}
+
+func TestCleanFilename(t *testing.T) {
+	require.Equal(t, "receipt.jpg", cleanFilename("receipt.jpg"))
+	require.Equal(t, "receipt.jpg", cleanFilename(`C:\Users\me\receipt.jpg`))
+	require.Equal(t, "passwd", cleanFilename("../../etc/passwd"))
+	require.Equal(t, "ab.pdf", cleanFilename(" a\x00b.pdf\n"))
+	require.Equal(t, "attachment", cleanFilename(""))
+	require.Equal(t, "attachment", cleanFilename("../"))
+	require.Len(t, []rune(cleanFilename(strings.Repeat("ф", 300))), 255)
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+)
+
+const auditMaxLimit = 100
+
+type AuditService struct {
+	repo repo.Audit
+}
+
+func newAuditService(repo repo.Audit) *AuditService {
+	return &AuditService{
+		repo: repo,
+	}
+}
+
+// List returns page of audit log of user by filter, latest entries first
+func (s *AuditService) List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	if filter.Entity != nil {
+		if err := filter.Entity.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if filter.Limit <= 0 || filter.Limit > auditMaxLimit {
+		return nil, ErrAuditLimitInvalid
+	}
+
+	if filter.Offset < 0 {
+		return nil, ErrAuditOffsetInvalid
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, ErrAuditPeriodInvalid
+	}
+
+	return s.repo.List(ctx, filter)
+}
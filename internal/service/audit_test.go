@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/lotostudio/financial-api/internal/domain"
+	mockRepo "github.com/lotostudio/financial-api/internal/repo/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func mockAuditService(t *testing.T) (*AuditService, *mockRepo.MockAudit) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	auRepo := mockRepo.NewMockAudit(mockCtl)
+
+	return newAuditService(auRepo), auRepo
+}
+
+func TestAuditService_List(t *testing.T) {
+	s, auRepo := mockAuditService(t)
+
+	ctx := context.Background()
+	entity := domain.AuditAccount
+	from := time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	filter := domain.AuditFilter{UserID: userId, Entity: &entity, From: &from, To: &to, Limit: 50}
+	entries := []domain.AuditEntry{{ID: 1, UserID: userId, Entity: entity, EntityID: 2, Action: domain.AuditCreate}}
+
+	auRepo.EXPECT().List(ctx, filter).Return(entries, nil)
+
+	res, err := s.List(ctx, filter)
+
+	require.NoError(t, err)
+	require.Equal(t, entries, res)
+}
+
+func TestAuditService_ListErrEntity(t *testing.T) {
+	s, _ := mockAuditService(t)
+
+	entity := domain.AuditEntity("category")
+
+	_, err := s.List(context.Background(), domain.AuditFilter{UserID: userId, Entity: &entity, Limit: 50})
+
+	require.ErrorIs(t, err, domain.ErrInvalidAuditEntity)
+}
+
+func TestAuditService_ListErrLimit(t *testing.T) {
+	s, _ := mockAuditService(t)
+
+	_, err := s.List(context.Background(), domain.AuditFilter{UserID: userId, Limit: 101})
+
+	require.ErrorIs(t, err, ErrAuditLimitInvalid)
+
+	_, err = s.List(context.Background(), domain.AuditFilter{UserID: userId})
+
+	require.ErrorIs(t, err, ErrAuditLimitInvalid)
+}
+
+func TestAuditService_ListErrOffset(t *testing.T) {
+	s, _ := mockAuditService(t)
+
+	_, err := s.List(context.Background(), domain.AuditFilter{UserID: userId, Limit: 10, Offset: -1})
+
+	require.ErrorIs(t, err, ErrAuditOffsetInvalid)
+}
+
+func TestAuditService_ListErrPeriod(t *testing.T) {
+	s, _ := mockAuditService(t)
+
+	from := time.Date(2022, 4, 2, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, -1)
+
+	_, err := s.List(context.Background(), domain.AuditFilter{UserID: userId, From: &from, To: &to, Limit: 10})
+
+	require.ErrorIs(t, err, ErrAuditPeriodInvalid)
+}
@@ -2,33 +2,44 @@ package service
 
 import (
 	"context"
+	"errors"
 	"github.com/lotostudio/financial-api/internal/domain"
 	"github.com/lotostudio/financial-api/internal/repo"
 	"github.com/lotostudio/financial-api/pkg/auth"
 	"github.com/lotostudio/financial-api/pkg/hash"
+	"github.com/lotostudio/financial-api/pkg/limiter"
+	"github.com/lotostudio/financial-api/pkg/oidc"
 	log "github.com/sirupsen/logrus"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type AuthService struct {
 	repo            repo.Users
 	sessionsRepo    repo.Sessions
+	identitiesRepo  repo.UserIdentities
+	auditRepo       repo.Audit
 	hasher          hash.PasswordHasher
 	tokenManager    auth.TokenManager
 	accessTokenTTL  time.Duration
 	refreshTokenTTL time.Duration
+	lockout         *limiter.Lockout
 }
 
-func newAuthService(repo repo.Users, sessionsRepo repo.Sessions, hasher hash.PasswordHasher,
-	tokenManager auth.TokenManager, accessTokenTTL time.Duration, refreshTokenTTL time.Duration) *AuthService {
+func newAuthService(repo repo.Users, sessionsRepo repo.Sessions, identitiesRepo repo.UserIdentities,
+	auditRepo repo.Audit, hasher hash.PasswordHasher, tokenManager auth.TokenManager, accessTokenTTL time.Duration,
+	refreshTokenTTL time.Duration, lockout *limiter.Lockout) *AuthService {
 	return &AuthService{
 		repo:            repo,
 		sessionsRepo:    sessionsRepo,
+		identitiesRepo:  identitiesRepo,
+		auditRepo:       auditRepo,
 		hasher:          hasher,
 		tokenManager:    tokenManager,
 		accessTokenTTL:  accessTokenTTL,
 		refreshTokenTTL: refreshTokenTTL,
+		lockout:         lockout,
 	}
 }
 
@@ -63,6 +74,18 @@ func (s *AuthService) Register(ctx context.Context, toCreate domain.UserToCreate
 }
 
 func (s *AuthService) Login(ctx context.Context, toLogin domain.UserToLogin) (domain.Tokens, error) {
+	lockKey := "login:" + strings.ToLower(toLogin.Email)
+
+	retryAfter, err := s.lockout.Check(ctx, lockKey)
+
+	if err != nil {
+		return domain.Tokens{}, err
+	}
+
+	if retryAfter > 0 {
+		return domain.Tokens{}, &LoginLockedError{RetryAfter: retryAfter}
+	}
+
 	passwordHash, err := s.hasher.Hash(toLogin.Password)
 
 	if err != nil {
@@ -71,11 +94,33 @@ func (s *AuthService) Login(ctx context.Context, toLogin domain.UserToLogin) (do
 
 	user, err := s.repo.GetByCredentials(ctx, toLogin.Email, passwordHash)
 
+	if errors.Is(err, repo.ErrUserNotFound) {
+		if auditErr := s.auditRepo.CreateLoginFailure(ctx, toLogin.Email); auditErr != nil {
+			log.Warnf("error auditing failed login of %s error - %s", toLogin.Email, auditErr)
+		}
+
+		if retryAfter, lockErr := s.lockout.Fail(ctx, lockKey); lockErr != nil {
+			log.Warnf("error registering failed login of %s error - %s", toLogin.Email, lockErr)
+		} else if retryAfter > 0 {
+			return domain.Tokens{}, &LoginLockedError{RetryAfter: retryAfter}
+		}
+
+		return domain.Tokens{}, err
+	}
+
 	if err != nil {
 		return domain.Tokens{}, err
 	}
 
-	return s.createSession(ctx, user.ID)
+	if err = s.lockout.Reset(ctx, lockKey); err != nil {
+		log.Warnf("error resetting failed logins of %s error - %s", toLogin.Email, err)
+	}
+
+	if user.Disabled {
+		return domain.Tokens{}, ErrUserDisabled
+	}
+
+	return s.createSession(ctx, user, domain.AuditLogin)
 }
 
 func (s *AuthService) Refresh(ctx context.Context, token string) (domain.Tokens, error) {
@@ -89,35 +134,158 @@ func (s *AuthService) Refresh(ctx context.Context, token string) (domain.Tokens,
 		return domain.Tokens{}, ErrRefreshTokenExpired
 	}
 
-	return s.createSession(ctx, session.UserId)
+	user, err := s.repo.Get(ctx, session.UserId)
+
+	if err != nil {
+		return domain.Tokens{}, err
+	}
+
+	if user.Disabled {
+		return domain.Tokens{}, ErrUserDisabled
+	}
+
+	return s.createSession(ctx, user, domain.AuditRefresh)
 }
 
-func (s *AuthService) createSession(ctx context.Context, userId int64) (domain.Tokens, error) {
-	var res domain.Tokens
-	var err error
+// CheckSession checks that access token is issued for current login of user's session. Tokens of previous logins
+// and reset sessions are revoked
+func (s *AuthService) CheckSession(ctx context.Context, userID int64, sessionID string) error {
+	session, err := s.sessionsRepo.GetByUser(ctx, userID)
+
+	if err != nil {
+		return err
+	}
 
-	res.AccessToken, err = s.tokenManager.Issue(strconv.FormatInt(userId, 10))
+	if session.SID() != sessionID {
+		return ErrSessionRevoked
+	}
+
+	return nil
+}
+
+// LoginIdentity logs in user authenticated by OpenID Connect provider. Unknown identity is linked to user
+// with the same verified email, new user is created if there is no such user
+func (s *AuthService) LoginIdentity(ctx context.Context, identity domain.ExternalIdentity) (domain.Tokens, error) {
+	user, err := s.identityUser(ctx, identity)
 
 	if err != nil {
-		return res, err
+		return domain.Tokens{}, err
 	}
 
-	res.AccessTokenExpiredAt = int16(s.accessTokenTTL.Seconds())
+	if user.Disabled {
+		return domain.Tokens{}, ErrUserDisabled
+	}
+
+	return s.createSession(ctx, user, domain.AuditLogin)
+}
+
+func (s *AuthService) identityUser(ctx context.Context, identity domain.ExternalIdentity) (domain.User, error) {
+	linked, err := s.identitiesRepo.Get(ctx, identity.Provider, identity.Subject)
+
+	if err == nil {
+		return s.repo.Get(ctx, linked.UserId)
+	}
+
+	if !errors.Is(err, repo.ErrUserIdentityNotFound) {
+		return domain.User{}, err
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return domain.User{}, ErrEmailNotVerified
+	}
+
+	user, err := s.repo.GetByEmail(ctx, identity.Email)
+
+	if errors.Is(err, repo.ErrUserNotFound) {
+		user, err = s.createIdentityUser(ctx, identity)
+	}
+
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if err = s.identitiesRepo.Create(ctx, domain.UserIdentity{
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+		UserId:   user.ID,
+	}); err != nil {
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+// createIdentityUser creates user with random password, so user can log in only through provider
+func (s *AuthService) createIdentityUser(ctx context.Context, identity domain.ExternalIdentity) (domain.User, error) {
+	password, err := oidc.Random()
+
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	passwordHash, err := s.hasher.Hash(password)
+
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	userId, err := s.repo.Create(ctx, domain.User{
+		Email:     identity.Email,
+		FirstName: identity.FirstName,
+		LastName:  identity.LastName,
+		Password:  passwordHash,
+	})
+
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	// Session is updated right after creation, so it can not be created async
+	if err = s.sessionsRepo.Create(ctx, userId); err != nil {
+		return domain.User{}, err
+	}
+
+	return s.repo.Get(ctx, userId)
+}
+
+// createSession issues tokens of new session of user. Action of session is recorded to audit log
+func (s *AuthService) createSession(ctx context.Context, user domain.User, action domain.AuditAction) (domain.Tokens,
+	error) {
+	var res domain.Tokens
+	var err error
 
 	res.RefreshToken, err = s.tokenManager.Random()
 
 	if err != nil {
-		return res, nil
+		return res, err
 	}
 
 	res.RefreshTokenExpiredAt = int16(s.refreshTokenTTL.Seconds())
 
-	session := domain.SessionToUpdate{
+	toUpdate := domain.SessionToUpdate{
 		RefreshToken: res.RefreshToken,
 		ExpiresAt:    time.Now().UTC().Add(s.refreshTokenTTL),
+		Action:       action,
+	}
+
+	session, err := s.sessionsRepo.Update(ctx, toUpdate, user.ID)
+
+	if err != nil {
+		return res, err
 	}
 
-	_, err = s.sessionsRepo.Update(ctx, session, userId)
+	res.AccessToken, err = s.tokenManager.Issue(auth.Claims{
+		Subject:   strconv.FormatInt(user.ID, 10),
+		Role:      string(user.Role),
+		SessionID: session.SID(),
+	})
+
+	if err != nil {
+		return res, err
+	}
+
+	res.AccessTokenExpiredAt = int16(s.accessTokenTTL.Seconds())
 
-	return res, err
+	return res, nil
 }
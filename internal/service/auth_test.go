@@ -9,6 +9,7 @@ import (
 	mockRepo "github.com/lotostudio/financial-api/internal/repo/mocks"
 	"github.com/lotostudio/financial-api/pkg/auth"
 	"github.com/lotostudio/financial-api/pkg/hash"
+	"github.com/lotostudio/financial-api/pkg/limiter"
 	"github.com/stretchr/testify/require"
 	"testing"
 	"time"
@@ -16,7 +17,8 @@ import (
 
 var errDefault = errors.New("error")
 
-func mockAuthService(t *testing.T) (*AuthService, *mockRepo.MockUsers, *mockRepo.MockSessions) {
+func mockAuthService(t *testing.T) (*AuthService, *mockRepo.MockUsers, *mockRepo.MockSessions,
+	*mockRepo.MockUserIdentities, *mockRepo.MockAudit) {
 	t.Helper()
 
 	mockCtl := gomock.NewController(t)
@@ -24,15 +26,20 @@ func mockAuthService(t *testing.T) (*AuthService, *mockRepo.MockUsers, *mockRepo
 
 	usersRepo := mockRepo.NewMockUsers(mockCtl)
 	sRepo := mockRepo.NewMockSessions(mockCtl)
+	iRepo := mockRepo.NewMockUserIdentities(mockCtl)
+	auRepo := mockRepo.NewMockAudit(mockCtl)
 	authManager, _ := auth.NewJWTManager("key", time.Duration(1)*time.Hour, 32)
 
-	service := newAuthService(usersRepo, sRepo, hash.NewSHA1PasswordHasher(""), authManager, 1*time.Second, 1*time.Second)
+	lockout := limiter.NewLockout(limiter.NewMemoryLockoutStore(time.Hour), 2, time.Minute, time.Hour)
 
-	return service, usersRepo, sRepo
+	service := newAuthService(usersRepo, sRepo, iRepo, auRepo, hash.NewSHA1PasswordHasher(""), authManager,
+		1*time.Second, 1*time.Second, lockout)
+
+	return service, usersRepo, sRepo, iRepo, auRepo
 }
 
 func TestAuthService_Register(t *testing.T) {
-	s, uRepo, sRepo := mockAuthService(t)
+	s, uRepo, sRepo, _, _ := mockAuthService(t)
 
 	ctx := context.Background()
 
@@ -47,14 +54,19 @@ func TestAuthService_Register(t *testing.T) {
 }
 
 func TestAuthService_Login(t *testing.T) {
-	s, uRepo, sRepo := mockAuthService(t)
+	s, uRepo, sRepo, _, _ := mockAuthService(t)
 
 	ctx := context.Background()
 
 	uRepo.EXPECT().GetByCredentials(ctx, gomock.Any(), gomock.Any()).Return(domain.User{
 		ID: userId,
 	}, nil)
-	sRepo.EXPECT().Update(ctx, gomock.Any(), userId).Return(domain.Session{}, nil)
+	sRepo.EXPECT().Update(ctx, gomock.Any(), userId).DoAndReturn(
+		func(_ context.Context, toUpdate domain.SessionToUpdate, _ int64) (domain.Session, error) {
+			require.Equal(t, domain.AuditLogin, toUpdate.Action)
+
+			return domain.Session{}, nil
+		})
 
 	res, err := s.Login(ctx, domain.UserToLogin{})
 
@@ -62,12 +74,59 @@ func TestAuthService_Login(t *testing.T) {
 	require.IsType(t, domain.Tokens{}, res)
 }
 
+func TestAuthService_LoginSessionClaim(t *testing.T) {
+	s, uRepo, sRepo, _, _ := mockAuthService(t)
+
+	ctx := context.Background()
+
+	uRepo.EXPECT().GetByCredentials(ctx, gomock.Any(), gomock.Any()).Return(domain.User{
+		ID:   userId,
+		Role: domain.RoleUser,
+	}, nil)
+	sRepo.EXPECT().Update(ctx, gomock.Any(), userId).Return(domain.Session{Id: 7, UserId: userId, Version: 3}, nil)
+
+	res, err := s.Login(ctx, domain.UserToLogin{})
+
+	require.NoError(t, err)
+
+	claims, err := s.tokenManager.Decode(res.AccessToken)
+
+	require.NoError(t, err)
+	require.Equal(t, auth.Claims{Subject: "1", Role: "user", SessionID: "7.3"}, claims)
+}
+
+func TestAuthService_CheckSession(t *testing.T) {
+	s, _, sRepo, _, _ := mockAuthService(t)
+
+	ctx := context.Background()
+
+	sRepo.EXPECT().GetByUser(ctx, userId).Return(domain.Session{Id: 7, UserId: userId, Version: 3}, nil)
+
+	err := s.CheckSession(ctx, userId, "7.3")
+
+	require.NoError(t, err)
+}
+
+func TestAuthService_CheckSessionErrRevoked(t *testing.T) {
+	s, _, sRepo, _, _ := mockAuthService(t)
+
+	ctx := context.Background()
+
+	// Session is reset or logged in again after token is issued
+	sRepo.EXPECT().GetByUser(ctx, userId).Return(domain.Session{Id: 7, UserId: userId, Version: 4}, nil)
+
+	err := s.CheckSession(ctx, userId, "7.3")
+
+	require.ErrorIs(t, err, ErrSessionRevoked)
+}
+
 func TestAuthService_LoginErrUserNotExists(t *testing.T) {
-	s, uRepo, _ := mockAuthService(t)
+	s, uRepo, _, _, auRepo := mockAuthService(t)
 
 	ctx := context.Background()
 
 	uRepo.EXPECT().GetByCredentials(ctx, gomock.Any(), gomock.Any()).Return(domain.User{}, repo.ErrUserNotFound)
+	auRepo.EXPECT().CreateLoginFailure(ctx, "").Return(nil)
 
 	_, err := s.Login(ctx, domain.UserToLogin{})
 
@@ -75,7 +134,7 @@ func TestAuthService_LoginErrUserNotExists(t *testing.T) {
 }
 
 func TestAuthService_LoginErr(t *testing.T) {
-	s, uRepo, _ := mockAuthService(t)
+	s, uRepo, _, _, _ := mockAuthService(t)
 
 	ctx := context.Background()
 
@@ -86,8 +145,75 @@ func TestAuthService_LoginErr(t *testing.T) {
 	require.ErrorIs(t, err, errDefault)
 }
 
+func TestAuthService_LoginErrUserDisabled(t *testing.T) {
+	s, uRepo, _, _, _ := mockAuthService(t)
+
+	ctx := context.Background()
+
+	uRepo.EXPECT().GetByCredentials(ctx, gomock.Any(), gomock.Any()).Return(domain.User{
+		ID:       userId,
+		Disabled: true,
+	}, nil)
+
+	_, err := s.Login(ctx, domain.UserToLogin{})
+
+	require.ErrorIs(t, err, ErrUserDisabled)
+}
+
+func TestAuthService_LoginErrLocked(t *testing.T) {
+	s, uRepo, _, _, auRepo := mockAuthService(t)
+
+	ctx := context.Background()
+	toLogin := domain.UserToLogin{Email: "user@mail.com"}
+
+	uRepo.EXPECT().GetByCredentials(ctx, gomock.Any(), gomock.Any()).Return(domain.User{}, repo.ErrUserNotFound).Times(2)
+	// Login is locked even if failures can't be audited
+	auRepo.EXPECT().CreateLoginFailure(ctx, toLogin.Email).Return(errDefault).Times(2)
+
+	_, err := s.Login(ctx, toLogin)
+
+	require.ErrorIs(t, err, repo.ErrUserNotFound)
+
+	_, err = s.Login(ctx, toLogin)
+
+	var lockedErr *LoginLockedError
+
+	require.ErrorIs(t, err, ErrLoginLocked)
+	require.ErrorAs(t, err, &lockedErr)
+	require.Equal(t, time.Minute, lockedErr.RetryAfter)
+
+	// Locked email is rejected without checking credentials
+	_, err = s.Login(ctx, domain.UserToLogin{Email: "USER@mail.com"})
+
+	require.ErrorIs(t, err, ErrLoginLocked)
+}
+
+func TestAuthService_LoginResetsFailures(t *testing.T) {
+	s, uRepo, sRepo, _, auRepo := mockAuthService(t)
+
+	ctx := context.Background()
+	toLogin := domain.UserToLogin{Email: "user@mail.com"}
+
+	gomock.InOrder(
+		uRepo.EXPECT().GetByCredentials(ctx, gomock.Any(), gomock.Any()).Return(domain.User{}, repo.ErrUserNotFound),
+		uRepo.EXPECT().GetByCredentials(ctx, gomock.Any(), gomock.Any()).Return(domain.User{ID: userId}, nil),
+		uRepo.EXPECT().GetByCredentials(ctx, gomock.Any(), gomock.Any()).Return(domain.User{}, repo.ErrUserNotFound),
+	)
+	auRepo.EXPECT().CreateLoginFailure(ctx, toLogin.Email).Return(nil).Times(2)
+	sRepo.EXPECT().Update(ctx, gomock.Any(), userId).Return(domain.Session{}, nil)
+
+	_, err := s.Login(ctx, toLogin)
+	require.ErrorIs(t, err, repo.ErrUserNotFound)
+
+	_, err = s.Login(ctx, toLogin)
+	require.NoError(t, err)
+
+	_, err = s.Login(ctx, toLogin)
+	require.ErrorIs(t, err, repo.ErrUserNotFound)
+}
+
 func TestAuthService_Refresh(t *testing.T) {
-	s, _, sRepo := mockAuthService(t)
+	s, uRepo, sRepo, _, _ := mockAuthService(t)
 
 	ctx := context.Background()
 
@@ -95,7 +221,13 @@ func TestAuthService_Refresh(t *testing.T) {
 		ExpiresAt: time.Now().Add(1 * time.Hour),
 		UserId:    userId,
 	}, nil)
-	sRepo.EXPECT().Update(ctx, gomock.Any(), userId).Return(domain.Session{}, nil)
+	uRepo.EXPECT().Get(ctx, userId).Return(domain.User{ID: userId}, nil)
+	sRepo.EXPECT().Update(ctx, gomock.Any(), userId).DoAndReturn(
+		func(_ context.Context, toUpdate domain.SessionToUpdate, _ int64) (domain.Session, error) {
+			require.Equal(t, domain.AuditRefresh, toUpdate.Action)
+
+			return domain.Session{}, nil
+		})
 
 	tokens, err := s.Refresh(ctx, "token")
 
@@ -104,7 +236,7 @@ func TestAuthService_Refresh(t *testing.T) {
 }
 
 func TestAuthService_RefreshExpiredToken(t *testing.T) {
-	s, _, sRepo := mockAuthService(t)
+	s, _, sRepo, _, _ := mockAuthService(t)
 
 	ctx := context.Background()
 
@@ -117,3 +249,107 @@ func TestAuthService_RefreshExpiredToken(t *testing.T) {
 
 	require.ErrorIs(t, err, ErrRefreshTokenExpired)
 }
+
+func TestAuthService_RefreshErrUserDisabled(t *testing.T) {
+	s, uRepo, sRepo, _, _ := mockAuthService(t)
+
+	ctx := context.Background()
+
+	sRepo.EXPECT().GetByToken(ctx, "token").Return(domain.Session{
+		ExpiresAt: time.Now().Add(1 * time.Hour),
+		UserId:    userId,
+	}, nil)
+	uRepo.EXPECT().Get(ctx, userId).Return(domain.User{ID: userId, Disabled: true}, nil)
+
+	_, err := s.Refresh(ctx, "token")
+
+	require.ErrorIs(t, err, ErrUserDisabled)
+}
+
+func TestAuthService_LoginIdentityLinked(t *testing.T) {
+	s, uRepo, sRepo, iRepo, _ := mockAuthService(t)
+
+	ctx := context.Background()
+	identity := domain.ExternalIdentity{Provider: "google", Subject: "42", Email: "user@mail.com"}
+
+	iRepo.EXPECT().Get(ctx, "google", "42").Return(domain.UserIdentity{UserId: userId}, nil)
+	uRepo.EXPECT().Get(ctx, userId).Return(domain.User{ID: userId}, nil)
+	sRepo.EXPECT().Update(ctx, gomock.Any(), userId).Return(domain.Session{}, nil)
+
+	res, err := s.LoginIdentity(ctx, identity)
+
+	require.NoError(t, err)
+	require.NotEmpty(t, res.AccessToken)
+}
+
+func TestAuthService_LoginIdentityLinksByEmail(t *testing.T) {
+	s, uRepo, sRepo, iRepo, _ := mockAuthService(t)
+
+	ctx := context.Background()
+	identity := domain.ExternalIdentity{Provider: "google", Subject: "42", Email: "user@mail.com", EmailVerified: true}
+
+	iRepo.EXPECT().Get(ctx, "google", "42").Return(domain.UserIdentity{}, repo.ErrUserIdentityNotFound)
+	uRepo.EXPECT().GetByEmail(ctx, "user@mail.com").Return(domain.User{ID: userId}, nil)
+	iRepo.EXPECT().Create(ctx, domain.UserIdentity{
+		Provider: "google",
+		Subject:  "42",
+		Email:    "user@mail.com",
+		UserId:   userId,
+	}).Return(nil)
+	sRepo.EXPECT().Update(ctx, gomock.Any(), userId).Return(domain.Session{}, nil)
+
+	_, err := s.LoginIdentity(ctx, identity)
+
+	require.NoError(t, err)
+}
+
+func TestAuthService_LoginIdentityCreatesUser(t *testing.T) {
+	s, uRepo, sRepo, iRepo, _ := mockAuthService(t)
+
+	ctx := context.Background()
+	identity := domain.ExternalIdentity{Provider: "google", Subject: "42", Email: "user@mail.com", EmailVerified: true,
+		FirstName: "John", LastName: "Doe"}
+
+	iRepo.EXPECT().Get(ctx, "google", "42").Return(domain.UserIdentity{}, repo.ErrUserIdentityNotFound)
+	uRepo.EXPECT().GetByEmail(ctx, "user@mail.com").Return(domain.User{}, repo.ErrUserNotFound)
+	uRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, user domain.User) (int64, error) {
+		require.Equal(t, "John", user.FirstName)
+		require.NotEmpty(t, user.Password)
+
+		return userId, nil
+	})
+	sRepo.EXPECT().Create(ctx, userId).Return(nil)
+	uRepo.EXPECT().Get(ctx, userId).Return(domain.User{ID: userId}, nil)
+	iRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	sRepo.EXPECT().Update(ctx, gomock.Any(), userId).Return(domain.Session{}, nil)
+
+	_, err := s.LoginIdentity(ctx, identity)
+
+	require.NoError(t, err)
+}
+
+func TestAuthService_LoginIdentityErrEmailNotVerified(t *testing.T) {
+	s, _, _, iRepo, _ := mockAuthService(t)
+
+	ctx := context.Background()
+	identity := domain.ExternalIdentity{Provider: "google", Subject: "42", Email: "user@mail.com"}
+
+	iRepo.EXPECT().Get(ctx, "google", "42").Return(domain.UserIdentity{}, repo.ErrUserIdentityNotFound)
+
+	_, err := s.LoginIdentity(ctx, identity)
+
+	require.ErrorIs(t, err, ErrEmailNotVerified)
+}
+
+func TestAuthService_LoginIdentityErrUserDisabled(t *testing.T) {
+	s, uRepo, _, iRepo, _ := mockAuthService(t)
+
+	ctx := context.Background()
+
+	iRepo.EXPECT().Get(ctx, "google", "42").Return(domain.UserIdentity{UserId: userId}, nil)
+	uRepo.EXPECT().Get(ctx, userId).Return(domain.User{ID: userId, Disabled: true}, nil)
+
+	_, err := s.LoginIdentity(ctx, domain.ExternalIdentity{Provider: "google", Subject: "42"})
+
+	require.ErrorIs(t, err, ErrUserDisabled)
+}
@@ -1,6 +1,9 @@
 package service
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
 	ErrInvalidLoanData     = errors.New("account with type 'loan' must have valid term and rate")
@@ -10,12 +13,67 @@ var (
 	ErrAccountCountLimited = errors.New("account count of this type reached limit")
 
 	ErrRefreshTokenExpired = errors.New("refresh token expired")
+	ErrSessionRevoked      = errors.New("session of token is revoked")
+	ErrUserDisabled        = errors.New("user is disabled")
+	ErrLoginLocked         = errors.New("too many failed login attempts")
+	ErrEmailNotVerified    = errors.New("email is not verified by provider")
+
+	ErrOIDCProviderNotFound = errors.New("identity provider doesn't exists")
+	ErrOIDCFlowInvalid      = errors.New("authorization flow is invalid or expired")
+
+	ErrAccessTokenExpired   = errors.New("access token expired")
+	ErrAccessTokenForbidden = errors.New("access token forbidden to access")
 
 	ErrAccountsHaveDifferenceCurrencies = errors.New("accounts have different currencies")
 	ErrCreditAccountForbidden           = errors.New("sender account forbidden to access")
 	ErrDebitAccountForbidden            = errors.New("receiver account forbidden to access")
 	ErrNoAccountSelected                = errors.New("no account selected")
+	ErrNoCategorySelected               = errors.New("no category selected")
 
 	ErrTransactionForbidden                = errors.New("transaction forbidden to access")
 	ErrTransactionAndCategoryTypesMismatch = errors.New("type of transaction and category does not match")
+	ErrTransactionLinesSumMismatch         = errors.New("sum of lines does not match amount of transaction")
+	ErrTransferSplit                       = errors.New("transfer can't be split into lines")
+	ErrSplitTransactionCategory            = errors.New("category of split transaction is set by its lines")
+	ErrTransactionReconciled               = errors.New("transaction is reconciled, unlock it to change")
+	ErrTransactionRepeated                 = errors.New("transaction is repeated in batch")
+	ErrBatchRejected                       = errors.New("batch is rejected, some of items are invalid")
+	ErrSearchQueryEmpty                    = errors.New("search query is empty")
+	ErrSearchLimitInvalid                  = errors.New("limit of search results must be from 1 to 100")
+
+	ErrAttachmentEmpty          = errors.New("attachment is empty")
+	ErrAttachmentTooLarge       = errors.New("attachment is too large")
+	ErrAttachmentTypeNotAllowed = errors.New("type of attachment is not allowed")
+	ErrAttachmentImageInvalid   = errors.New("image of attachment can't be decoded")
+	ErrThumbnailNotFound        = errors.New("attachment doesn't have thumbnail")
+
+	ErrStatementDateInvalid             = errors.New("end date of statement can't be in future")
+	ErrReconciliationCompleted          = errors.New("reconciliation is completed")
+	ErrReconciliationNotBalanced        = errors.New("statement balance differs from cleared balance")
+	ErrReconciliationTransactionInvalid = errors.New("transaction is not among transactions to reconcile")
+
+	ErrForecastMonthsInvalid = errors.New("months of forecast must be from 1 to 12")
+	ErrReportYearInvalid     = errors.New("year of report is invalid")
+
+	ErrTransactionTemplateForbidden = errors.New("transaction template forbidden to access")
+	ErrTemplateAmountMissing        = errors.New("amount is set neither in template nor in request")
+
+	ErrAuditLimitInvalid  = errors.New("limit of audit entries must be from 1 to 100")
+	ErrAuditOffsetInvalid = errors.New("offset of audit entries can't be negative")
+	ErrAuditPeriodInvalid = errors.New("start of audit period must be before its end")
+
+	ErrStatsPeriodsLimited = errors.New("too many periods, use longer interval or shorter date range")
 )
+
+// LoginLockedError is returned when login is locked after failed attempts. Matches ErrLoginLocked
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return ErrLoginLocked.Error()
+}
+
+func (e *LoginLockedError) Is(target error) bool {
+	return target == ErrLoginLocked
+}
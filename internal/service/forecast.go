@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"golang.org/x/sync/errgroup"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// Months of history used to find patterns
+	forecastHistoryMonths = 6
+	// Transaction is recurring if it happens once a month at least this count of months
+	forecastRecurringMonths = 3
+	// Recurring transaction is considered stopped if it didn't happen for this count of days
+	forecastRecurringGap = 45
+	// Limit of forecast length
+	forecastMaxMonths = 12
+)
+
+type ForecastService struct {
+	accRepo   repo.Accounts
+	transRepo repo.Transactions
+}
+
+func newForecastService(accRepo repo.Accounts, transRepo repo.Transactions) *ForecastService {
+	return &ForecastService{
+		accRepo:   accRepo,
+		transRepo: transRepo,
+	}
+}
+
+// Forecast projects daily balances of user's accounts for given count of months after date from current balances,
+// monthly recurring transactions, loan schedules and average daily expenses
+func (s *ForecastService) Forecast(ctx context.Context, userID int64, date time.Time,
+	months int) (domain.Forecast, error) {
+	if months <= 0 || months > forecastMaxMonths {
+		return domain.Forecast{}, ErrForecastMonthsInvalid
+	}
+
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	historyFrom := date.AddDate(0, -forecastHistoryMonths, 0)
+
+	var accounts []domain.Account
+	var history []domain.Transaction
+
+	errs, gctx := errgroup.WithContext(ctx)
+
+	errs.Go(func() error {
+		var err error
+		accounts, err = s.accRepo.List(gctx, userID)
+
+		return err
+	})
+
+	errs.Go(func() error {
+		var err error
+		history, err = s.transRepo.List(gctx, domain.TransactionsFilter{
+			OwnerId:     &userID,
+			CreatedFrom: &historyFrom,
+			CreatedTo:   &date,
+		})
+
+		return err
+	})
+
+	if err := errs.Wait(); err != nil {
+		return domain.Forecast{}, err
+	}
+
+	recurring, other := findRecurring(history, date)
+	historyDays := date.Sub(historyFrom).Hours() / 24
+
+	forecast := domain.Forecast{
+		Recurring: recurring,
+		Accounts:  make([]domain.AccountForecast, 0, len(accounts)),
+	}
+
+	for _, acc := range accounts {
+		forecast.Accounts = append(forecast.Accounts, projectAccount(acc, recurring, spending(other, acc.ID, historyDays),
+			date, date.AddDate(0, months, 0)))
+	}
+
+	return forecast, nil
+}
+
+// findRecurring splits history into transactions repeating once a month and all others
+func findRecurring(history []domain.Transaction, date time.Time) ([]domain.RecurringTransaction, []domain.Transaction) {
+	groups := make(map[string][]domain.Transaction)
+	keys := make([]string, 0)
+
+	for _, tx := range history {
+		key := recurringKey(tx)
+
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		groups[key] = append(groups[key], tx)
+	}
+
+	sort.Strings(keys)
+
+	recurring := make([]domain.RecurringTransaction, 0)
+	other := make([]domain.Transaction, 0)
+
+	for _, key := range keys {
+		txs := groups[key]
+		months := make(map[string]bool)
+		last := txs[0].CreatedAt
+
+		for _, tx := range txs {
+			months[tx.CreatedAt.Format("2006-01")] = true
+
+			if tx.CreatedAt.After(last) {
+				last = tx.CreatedAt
+			}
+		}
+
+		if len(months) < forecastRecurringMonths || len(months) != len(txs) ||
+			date.Sub(last).Hours()/24 > forecastRecurringGap {
+			other = append(other, txs...)
+			continue
+		}
+
+		amounts := make([]float64, len(txs))
+		days := make([]float64, len(txs))
+
+		for i, tx := range txs {
+			amounts[i] = tx.Amount
+			days[i] = float64(tx.CreatedAt.Day())
+		}
+
+		r := domain.RecurringTransaction{
+			Type:     txs[0].Type,
+			Category: recurringCategory(txs[0]),
+			Amount:   round(median(amounts)),
+			Day:      int(math.Round(median(days))),
+		}
+
+		if txs[0].Credit != nil {
+			r.CreditId = &txs[0].Credit.ID
+		}
+
+		if txs[0].Debit != nil {
+			r.DebitId = &txs[0].Debit.ID
+		}
+
+		recurring = append(recurring, r)
+	}
+
+	return recurring, other
+}
+
+// recurringCategory returns category of transaction or line categories of split transaction
+func recurringCategory(tx domain.Transaction) *string {
+	if tx.Category != nil || len(tx.Lines) == 0 {
+		return tx.Category
+	}
+
+	category := tx.CategoryTitle()
+
+	return &category
+}
+
+func recurringKey(tx domain.Transaction) string {
+	var category string
+	var creditId, debitId int64
+
+	// Split transactions recur only with the same categories of lines
+	if c := recurringCategory(tx); c != nil {
+		category = *c
+	}
+
+	if tx.Credit != nil {
+		creditId = tx.Credit.ID
+	}
+
+	if tx.Debit != nil {
+		debitId = tx.Debit.ID
+	}
+
+	return fmt.Sprintf("%s|%s|%d|%d", tx.Type, category, creditId, debitId)
+}
+
+// spending returns average daily expenses of account by categories
+func spending(history []domain.Transaction, accountID int64, days float64) map[string]float64 {
+	sums := make(map[string]float64)
+
+	for _, tx := range history {
+		if tx.Type != domain.Expense || tx.Credit == nil || tx.Credit.ID != accountID {
+			continue
+		}
+
+		for _, part := range tx.Parts() {
+			sums[partCategory(part)] += part.Amount
+		}
+	}
+
+	for category := range sums {
+		sums[category] = round(sums[category] / days)
+	}
+
+	return sums
+}
+
+// projectAccount computes balance of account for every day after date up to end
+func projectAccount(acc domain.Account, recurring []domain.RecurringTransaction, spending map[string]float64,
+	date time.Time, end time.Time) domain.AccountForecast {
+	f := domain.AccountForecast{
+		Account:  acc,
+		Spending: spending,
+		Balances: make([]domain.Balance, 0),
+	}
+
+	var daily float64
+
+	for _, v := range spending {
+		daily += v
+	}
+
+	schedule := loanSchedule(acc, date)
+	balance := acc.Balance
+
+	for day := date.AddDate(0, 0, 1); !day.After(end); day = day.AddDate(0, 0, 1) {
+		if acc.Type == domain.Loan {
+			balance -= schedule(day, balance)
+		} else {
+			balance -= daily
+
+			for _, r := range recurring {
+				if !isRecurringDay(r.Day, day) {
+					continue
+				}
+
+				if r.DebitId != nil && *r.DebitId == acc.ID {
+					balance += r.Amount
+				}
+
+				if r.CreditId != nil && *r.CreditId == acc.ID {
+					balance -= r.Amount
+				}
+			}
+		}
+
+		balance = round(balance)
+
+		f.Balances = append(f.Balances, domain.Balance{AccountID: acc.ID, Date: day, Value: balance})
+
+		if f.NegativeAt == nil && balance < 0 && (acc.Type == domain.Card || acc.Type == domain.Cash) {
+			negativeAt := day
+			f.NegativeAt = &negativeAt
+		}
+	}
+
+	return f
+}
+
+// isRecurringDay checks whether monthly transaction happens on day. Days missing in short months move to last day
+func isRecurringDay(recurringDay int, day time.Time) bool {
+	lastDay := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+
+	if recurringDay > lastDay {
+		return day.Day() == lastDay
+	}
+
+	return day.Day() == recurringDay
+}
+
+// loanSchedule returns function giving decrease of loan debt on day. Debt is paid by annuity payments every month
+// on day of loan creation till the end of term
+func loanSchedule(acc domain.Account, date time.Time) func(day time.Time, debt float64) float64 {
+	if acc.Term == nil || acc.Rate == nil {
+		return func(time.Time, float64) float64 { return 0 }
+	}
+
+	created := acc.CreatedAt
+	passed := (date.Year()-created.Year())*12 + int(date.Month()-created.Month())
+
+	if date.Day() < created.Day() {
+		passed--
+	}
+
+	remaining := int(*acc.Term) - passed
+
+	if remaining <= 0 || acc.Balance <= 0 {
+		return func(time.Time, float64) float64 { return 0 }
+	}
+
+	rate := float64(*acc.Rate) / 100 / 12
+	payment := acc.Balance / float64(remaining)
+
+	if rate > 0 {
+		payment = acc.Balance * rate / (1 - math.Pow(1+rate, -float64(remaining)))
+	}
+
+	return func(day time.Time, debt float64) float64 {
+		if debt <= 0 || !isRecurringDay(created.Day(), day) {
+			return 0
+		}
+
+		return math.Min(payment-debt*rate, debt)
+	}
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	if len(sorted)%2 == 1 {
+		return sorted[len(sorted)/2]
+	}
+
+	return (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/lotostudio/financial-api/internal/domain"
+	mockRepo "github.com/lotostudio/financial-api/internal/repo/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func mockForecastService(t *testing.T) (Forecast, *mockRepo.MockAccounts, *mockRepo.MockTransactions) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	aRepo := mockRepo.NewMockAccounts(mockCtl)
+	tRepo := mockRepo.NewMockTransactions(mockCtl)
+
+	s := newForecastService(aRepo, tRepo)
+
+	return s, aRepo, tRepo
+}
+
+func TestForecastService_Forecast(t *testing.T) {
+	s, aRepo, tRepo := mockForecastService(t)
+
+	date := func(month time.Month, day int) time.Time {
+		year := 2022
+
+		if month > time.March {
+			year = 2021
+		}
+
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	term, rate := uint8(12), float32(0)
+	card := domain.Account{ID: 1, Balance: 100, Type: domain.Card}
+	loan := domain.Account{ID: 2, Balance: 1200, Type: domain.Loan, Term: &term, Rate: &rate,
+		CreatedAt: date(time.March, 10)}
+	now := date(time.March, 15).Add(15 * time.Hour)
+	historyFrom, historyTo := date(time.September, 15), date(time.March, 15)
+
+	salary, rent, food := "salary", "rent", "food"
+	history := []domain.Transaction{
+		{Amount: 90.5, Type: domain.Expense, Category: &food, CreatedAt: date(time.October, 3), Credit: &card},
+		{Amount: 90.5, Type: domain.Expense, Category: &food, CreatedAt: date(time.October, 20), Credit: &card},
+	}
+
+	for _, month := range []time.Month{time.December, time.January, time.February, time.March} {
+		history = append(history,
+			domain.Transaction{Amount: 500, Type: domain.Income, Category: &salary, CreatedAt: date(month, 5), Debit: &card},
+			domain.Transaction{Amount: 700, Type: domain.Expense, Category: &rent, CreatedAt: date(month, 1), Credit: &card},
+		)
+	}
+
+	// Stopped two months ago
+	for _, month := range []time.Month{time.October, time.November, time.December} {
+		history = append(history,
+			domain.Transaction{Amount: 50, Type: domain.Expense, Category: &food, CreatedAt: date(month, 12), Credit: &card})
+	}
+
+	aRepo.EXPECT().List(gomock.Any(), userId).Return([]domain.Account{card, loan}, nil)
+	tRepo.EXPECT().List(gomock.Any(), domain.TransactionsFilter{
+		OwnerId:     &userId,
+		CreatedFrom: &historyFrom,
+		CreatedTo:   &historyTo,
+	}).Return(history, nil)
+
+	forecast, err := s.Forecast(context.Background(), userId, now, 2)
+
+	require.NoError(t, err)
+	require.Equal(t, []domain.RecurringTransaction{
+		{Type: domain.Expense, Category: &rent, Amount: 700, Day: 1, CreditId: &card.ID},
+		{Type: domain.Income, Category: &salary, Amount: 500, Day: 5, DebitId: &card.ID},
+	}, forecast.Recurring)
+	require.Len(t, forecast.Accounts, 2)
+
+	cardForecast := forecast.Accounts[0]
+
+	require.Equal(t, map[string]float64{"food": 1.83}, cardForecast.Spending)
+	require.Len(t, cardForecast.Balances, 61)
+	require.Equal(t, date(time.March, 16), cardForecast.Balances[0].Date)
+	require.Equal(t, 98.17, cardForecast.Balances[0].Value)
+	require.Equal(t, time.Date(2022, 5, 15, 0, 0, 0, 0, time.UTC), cardForecast.Balances[60].Date)
+	require.NotNil(t, cardForecast.NegativeAt)
+	require.Equal(t, time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC), *cardForecast.NegativeAt)
+
+	loanForecast := forecast.Accounts[1]
+
+	require.Nil(t, loanForecast.NegativeAt)
+	require.Equal(t, float64(1200), loanForecast.Balances[24].Value)
+	require.Equal(t, time.Date(2022, 4, 10, 0, 0, 0, 0, time.UTC), loanForecast.Balances[25].Date)
+	require.Equal(t, float64(1100), loanForecast.Balances[25].Value)
+	require.Equal(t, float64(1000), loanForecast.Balances[60].Value)
+}
+
+func TestForecastService_ForecastErrMonths(t *testing.T) {
+	s, _, _ := mockForecastService(t)
+
+	_, err := s.Forecast(context.Background(), userId, time.Now(), 13)
+
+	require.ErrorIs(t, err, ErrForecastMonthsInvalid)
+}
+
+func TestForecastService_ForecastErr(t *testing.T) {
+	s, aRepo, tRepo := mockForecastService(t)
+
+	aRepo.EXPECT().List(gomock.Any(), userId).Return(nil, errors.New("general error"))
+	tRepo.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+	_, err := s.Forecast(context.Background(), userId, time.Now(), 1)
+
+	require.Error(t, err)
+}
+
+func TestLoanSchedule(t *testing.T) {
+	term, rate := uint8(12), float32(12)
+	loan := domain.Account{Balance: 1000, Type: domain.Loan, Term: &term, Rate: &rate,
+		CreatedAt: time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC)}
+
+	schedule := loanSchedule(loan, time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC))
+
+	// Annuity payment of 1000 for 12 months with 1% monthly rate is 88.85, interest of first month is 10
+	require.Equal(t, float64(0), schedule(time.Date(2022, 2, 27, 0, 0, 0, 0, time.UTC), 1000))
+	require.Equal(t, 78.85, round(schedule(time.Date(2022, 2, 28, 0, 0, 0, 0, time.UTC), 1000)))
+	require.Equal(t, float64(5), schedule(time.Date(2022, 3, 31, 0, 0, 0, 0, time.UTC), 5))
+}
+
+func TestSpendingSplit(t *testing.T) {
+	food, home := "food", "home"
+	card := domain.Account{ID: 1}
+	history := []domain.Transaction{
+		{Amount: 20, Type: domain.Expense, Category: &food, Credit: &card},
+		{Amount: 50, Type: domain.Expense, Credit: &card, Lines: []domain.TransactionLine{
+			{Category: &food, Amount: 30}, {Category: &home, Amount: 20}}},
+	}
+
+	require.Equal(t, map[string]float64{"food": 5, "home": 2}, spending(history, card.ID, 10))
+}
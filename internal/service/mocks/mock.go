@@ -6,7 +6,9 @@ package mock_service
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	domain "github.com/lotostudio/financial-api/internal/domain"
@@ -103,6 +105,20 @@ func (m *MockAuth) EXPECT() *MockAuthMockRecorder {
 	return m.recorder
 }
 
+// CheckSession mocks base method.
+func (m *MockAuth) CheckSession(ctx context.Context, userID int64, sessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckSession", ctx, userID, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckSession indicates an expected call of CheckSession.
+func (mr *MockAuthMockRecorder) CheckSession(ctx, userID, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckSession", reflect.TypeOf((*MockAuth)(nil).CheckSession), ctx, userID, sessionID)
+}
+
 // Login mocks base method.
 func (m *MockAuth) Login(ctx context.Context, user domain.UserToLogin) (domain.Tokens, error) {
 	m.ctrl.T.Helper()
@@ -118,6 +134,21 @@ func (mr *MockAuthMockRecorder) Login(ctx, user interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuth)(nil).Login), ctx, user)
 }
 
+// LoginIdentity mocks base method.
+func (m *MockAuth) LoginIdentity(ctx context.Context, identity domain.ExternalIdentity) (domain.Tokens, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginIdentity", ctx, identity)
+	ret0, _ := ret[0].(domain.Tokens)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginIdentity indicates an expected call of LoginIdentity.
+func (mr *MockAuthMockRecorder) LoginIdentity(ctx, identity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginIdentity", reflect.TypeOf((*MockAuth)(nil).LoginIdentity), ctx, identity)
+}
+
 // Refresh mocks base method.
 func (m *MockAuth) Refresh(ctx context.Context, token string) (domain.Tokens, error) {
 	m.ctrl.T.Helper()
@@ -148,6 +179,155 @@ func (mr *MockAuthMockRecorder) Register(ctx, user interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAuth)(nil).Register), ctx, user)
 }
 
+// MockOIDC is a mock of OIDC interface.
+type MockOIDC struct {
+	ctrl     *gomock.Controller
+	recorder *MockOIDCMockRecorder
+}
+
+// MockOIDCMockRecorder is the mock recorder for MockOIDC.
+type MockOIDCMockRecorder struct {
+	mock *MockOIDC
+}
+
+// NewMockOIDC creates a new mock instance.
+func NewMockOIDC(ctrl *gomock.Controller) *MockOIDC {
+	mock := &MockOIDC{ctrl: ctrl}
+	mock.recorder = &MockOIDCMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOIDC) EXPECT() *MockOIDCMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MockOIDC) Begin(ctx context.Context, provider string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx, provider)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockOIDCMockRecorder) Begin(ctx, provider interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockOIDC)(nil).Begin), ctx, provider)
+}
+
+// Complete mocks base method.
+func (m *MockOIDC) Complete(ctx context.Context, provider, state, code string) (domain.ExternalIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, provider, state, code)
+	ret0, _ := ret[0].(domain.ExternalIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockOIDCMockRecorder) Complete(ctx, provider, state, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockOIDC)(nil).Complete), ctx, provider, state, code)
+}
+
+// Providers mocks base method.
+func (m *MockOIDC) Providers() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Providers")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Providers indicates an expected call of Providers.
+func (mr *MockOIDCMockRecorder) Providers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Providers", reflect.TypeOf((*MockOIDC)(nil).Providers))
+}
+
+// MockAccessTokens is a mock of AccessTokens interface.
+type MockAccessTokens struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessTokensMockRecorder
+}
+
+// MockAccessTokensMockRecorder is the mock recorder for MockAccessTokens.
+type MockAccessTokensMockRecorder struct {
+	mock *MockAccessTokens
+}
+
+// NewMockAccessTokens creates a new mock instance.
+func NewMockAccessTokens(ctrl *gomock.Controller) *MockAccessTokens {
+	mock := &MockAccessTokens{ctrl: ctrl}
+	mock.recorder = &MockAccessTokensMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccessTokens) EXPECT() *MockAccessTokensMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAccessTokens) Authenticate(ctx context.Context, token string) (domain.AccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, token)
+	ret0, _ := ret[0].(domain.AccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAccessTokensMockRecorder) Authenticate(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAccessTokens)(nil).Authenticate), ctx, token)
+}
+
+// Create mocks base method.
+func (m *MockAccessTokens) Create(ctx context.Context, toCreate domain.AccessTokenToCreate, userID int64) (domain.CreatedAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, toCreate, userID)
+	ret0, _ := ret[0].(domain.CreatedAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAccessTokensMockRecorder) Create(ctx, toCreate, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAccessTokens)(nil).Create), ctx, toCreate, userID)
+}
+
+// Delete mocks base method.
+func (m *MockAccessTokens) Delete(ctx context.Context, id, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAccessTokensMockRecorder) Delete(ctx, id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAccessTokens)(nil).Delete), ctx, id, userID)
+}
+
+// List mocks base method.
+func (m *MockAccessTokens) List(ctx context.Context, userID int64) ([]domain.AccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID)
+	ret0, _ := ret[0].([]domain.AccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAccessTokensMockRecorder) List(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAccessTokens)(nil).List), ctx, userID)
+}
+
 // MockCurrencies is a mock of Currencies interface.
 type MockCurrencies struct {
 	ctrl     *gomock.Controller
@@ -374,18 +554,62 @@ func (mr *MockTransactionsMockRecorder) Create(ctx, toCreate, userID, categoryId
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransactions)(nil).Create), ctx, toCreate, userID, categoryId, creditId, debitId)
 }
 
+// CreateBatch mocks base method.
+func (m *MockTransactions) CreateBatch(ctx context.Context, toCreate domain.TransactionsToCreate, userID int64) (domain.TransactionBatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, toCreate, userID)
+	ret0, _ := ret[0].(domain.TransactionBatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockTransactionsMockRecorder) CreateBatch(ctx, toCreate, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockTransactions)(nil).CreateBatch), ctx, toCreate, userID)
+}
+
 // Delete mocks base method.
-func (m *MockTransactions) Delete(ctx context.Context, id, userID int64) error {
+func (m *MockTransactions) Delete(ctx context.Context, id, userID int64, unlock bool) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id, userID)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, userID, unlock)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockTransactionsMockRecorder) Delete(ctx, id, userID interface{}) *gomock.Call {
+func (mr *MockTransactionsMockRecorder) Delete(ctx, id, userID, unlock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTransactions)(nil).Delete), ctx, id, userID, unlock)
+}
+
+// DeleteBatch mocks base method.
+func (m *MockTransactions) DeleteBatch(ctx context.Context, toDelete domain.TransactionsToDelete, userID int64) (domain.TransactionBatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBatch", ctx, toDelete, userID)
+	ret0, _ := ret[0].(domain.TransactionBatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteBatch indicates an expected call of DeleteBatch.
+func (mr *MockTransactionsMockRecorder) DeleteBatch(ctx, toDelete, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBatch", reflect.TypeOf((*MockTransactions)(nil).DeleteBatch), ctx, toDelete, userID)
+}
+
+// DismissFlags mocks base method.
+func (m *MockTransactions) DismissFlags(ctx context.Context, id, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DismissFlags", ctx, id, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DismissFlags indicates an expected call of DismissFlags.
+func (mr *MockTransactionsMockRecorder) DismissFlags(ctx, id, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTransactions)(nil).Delete), ctx, id, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DismissFlags", reflect.TypeOf((*MockTransactions)(nil).DismissFlags), ctx, id, userID)
 }
 
 // List mocks base method.
@@ -403,146 +627,834 @@ func (mr *MockTransactionsMockRecorder) List(ctx, filter interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTransactions)(nil).List), ctx, filter)
 }
 
+// Search mocks base method.
+func (m *MockTransactions) Search(ctx context.Context, query string, filter domain.TransactionsFilter, limit int) ([]domain.TransactionSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query, filter, limit)
+	ret0, _ := ret[0].([]domain.TransactionSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockTransactionsMockRecorder) Search(ctx, query, filter, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockTransactions)(nil).Search), ctx, query, filter, limit)
+}
+
+// SetStatus mocks base method.
+func (m *MockTransactions) SetStatus(ctx context.Context, id, userID int64, toUpdate domain.TransactionStatusToUpdate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStatus", ctx, id, userID, toUpdate)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetStatus indicates an expected call of SetStatus.
+func (mr *MockTransactionsMockRecorder) SetStatus(ctx, id, userID, toUpdate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStatus", reflect.TypeOf((*MockTransactions)(nil).SetStatus), ctx, id, userID, toUpdate)
+}
+
 // Stats mocks base method.
-func (m *MockTransactions) Stats(ctx context.Context, filter domain.TransactionsFilter) ([]domain.TransactionStat, error) {
+func (m *MockTransactions) Stats(ctx context.Context, filter domain.TransactionsFilter, groupBy domain.StatsGroup) ([]domain.TransactionStat, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Stats", ctx, filter)
+	ret := m.ctrl.Call(m, "Stats", ctx, filter, groupBy)
 	ret0, _ := ret[0].([]domain.TransactionStat)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Stats indicates an expected call of Stats.
-func (mr *MockTransactionsMockRecorder) Stats(ctx, filter interface{}) *gomock.Call {
+func (mr *MockTransactionsMockRecorder) Stats(ctx, filter, groupBy interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockTransactions)(nil).Stats), ctx, filter)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockTransactions)(nil).Stats), ctx, filter, groupBy)
 }
 
-// MockTransactionCategories is a mock of TransactionCategories interface.
-type MockTransactionCategories struct {
+// MockAttachments is a mock of Attachments interface.
+type MockAttachments struct {
 	ctrl     *gomock.Controller
-	recorder *MockTransactionCategoriesMockRecorder
+	recorder *MockAttachmentsMockRecorder
 }
 
-// MockTransactionCategoriesMockRecorder is the mock recorder for MockTransactionCategories.
-type MockTransactionCategoriesMockRecorder struct {
-	mock *MockTransactionCategories
+// MockAttachmentsMockRecorder is the mock recorder for MockAttachments.
+type MockAttachmentsMockRecorder struct {
+	mock *MockAttachments
 }
 
-// NewMockTransactionCategories creates a new mock instance.
-func NewMockTransactionCategories(ctrl *gomock.Controller) *MockTransactionCategories {
-	mock := &MockTransactionCategories{ctrl: ctrl}
-	mock.recorder = &MockTransactionCategoriesMockRecorder{mock}
+// NewMockAttachments creates a new mock instance.
+func NewMockAttachments(ctrl *gomock.Controller) *MockAttachments {
+	mock := &MockAttachments{ctrl: ctrl}
+	mock.recorder = &MockAttachmentsMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockTransactionCategories) EXPECT() *MockTransactionCategoriesMockRecorder {
+func (m *MockAttachments) EXPECT() *MockAttachmentsMockRecorder {
 	return m.recorder
 }
 
+// Delete mocks base method.
+func (m *MockAttachments) Delete(ctx context.Context, transactionID, id, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, transactionID, id, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAttachmentsMockRecorder) Delete(ctx, transactionID, id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAttachments)(nil).Delete), ctx, transactionID, id, userID)
+}
+
 // List mocks base method.
-func (m *MockTransactionCategories) List(ctx context.Context) ([]domain.TransactionCategory, error) {
+func (m *MockAttachments) List(ctx context.Context, transactionID, userID int64) ([]domain.Attachment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx)
-	ret0, _ := ret[0].([]domain.TransactionCategory)
+	ret := m.ctrl.Call(m, "List", ctx, transactionID, userID)
+	ret0, _ := ret[0].([]domain.Attachment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MockTransactionCategoriesMockRecorder) List(ctx interface{}) *gomock.Call {
+func (mr *MockAttachmentsMockRecorder) List(ctx, transactionID, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTransactionCategories)(nil).List), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAttachments)(nil).List), ctx, transactionID, userID)
 }
 
-// ListByType mocks base method.
-func (m *MockTransactionCategories) ListByType(ctx context.Context, _type domain.TransactionType) ([]domain.TransactionCategory, error) {
+// Open mocks base method.
+func (m *MockAttachments) Open(ctx context.Context, transactionID, id, userID int64, thumbnail bool) (domain.Attachment, io.ReadCloser, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListByType", ctx, _type)
-	ret0, _ := ret[0].([]domain.TransactionCategory)
+	ret := m.ctrl.Call(m, "Open", ctx, transactionID, id, userID, thumbnail)
+	ret0, _ := ret[0].(domain.Attachment)
+	ret1, _ := ret[1].(io.ReadCloser)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockAttachmentsMockRecorder) Open(ctx, transactionID, id, userID, thumbnail interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockAttachments)(nil).Open), ctx, transactionID, id, userID, thumbnail)
+}
+
+// Upload mocks base method.
+func (m *MockAttachments) Upload(ctx context.Context, transactionID, userID int64, filename string, file io.Reader) (domain.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upload", ctx, transactionID, userID, filename, file)
+	ret0, _ := ret[0].(domain.Attachment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListByType indicates an expected call of ListByType.
-func (mr *MockTransactionCategoriesMockRecorder) ListByType(ctx, _type interface{}) *gomock.Call {
+// Upload indicates an expected call of Upload.
+func (mr *MockAttachmentsMockRecorder) Upload(ctx, transactionID, userID, filename, file interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByType", reflect.TypeOf((*MockTransactionCategories)(nil).ListByType), ctx, _type)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockAttachments)(nil).Upload), ctx, transactionID, userID, filename, file)
 }
 
-// MockTransactionTypes is a mock of TransactionTypes interface.
-type MockTransactionTypes struct {
+// MockReconciliations is a mock of Reconciliations interface.
+type MockReconciliations struct {
 	ctrl     *gomock.Controller
-	recorder *MockTransactionTypesMockRecorder
+	recorder *MockReconciliationsMockRecorder
 }
 
-// MockTransactionTypesMockRecorder is the mock recorder for MockTransactionTypes.
-type MockTransactionTypesMockRecorder struct {
-	mock *MockTransactionTypes
+// MockReconciliationsMockRecorder is the mock recorder for MockReconciliations.
+type MockReconciliationsMockRecorder struct {
+	mock *MockReconciliations
 }
 
-// NewMockTransactionTypes creates a new mock instance.
-func NewMockTransactionTypes(ctrl *gomock.Controller) *MockTransactionTypes {
-	mock := &MockTransactionTypes{ctrl: ctrl}
-	mock.recorder = &MockTransactionTypesMockRecorder{mock}
+// NewMockReconciliations creates a new mock instance.
+func NewMockReconciliations(ctrl *gomock.Controller) *MockReconciliations {
+	mock := &MockReconciliations{ctrl: ctrl}
+	mock.recorder = &MockReconciliationsMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockTransactionTypes) EXPECT() *MockTransactionTypesMockRecorder {
+func (m *MockReconciliations) EXPECT() *MockReconciliationsMockRecorder {
 	return m.recorder
 }
 
-// List mocks base method.
-func (m *MockTransactionTypes) List(ctx context.Context) ([]domain.TransactionType, error) {
+// Complete mocks base method.
+func (m *MockReconciliations) Complete(ctx context.Context, accountID, id, userID int64) (domain.ReconciliationDetails, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx)
-	ret0, _ := ret[0].([]domain.TransactionType)
+	ret := m.ctrl.Call(m, "Complete", ctx, accountID, id, userID)
+	ret0, _ := ret[0].(domain.ReconciliationDetails)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// List indicates an expected call of List.
-func (mr *MockTransactionTypesMockRecorder) List(ctx interface{}) *gomock.Call {
+// Complete indicates an expected call of Complete.
+func (mr *MockReconciliationsMockRecorder) Complete(ctx, accountID, id, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTransactionTypes)(nil).List), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockReconciliations)(nil).Complete), ctx, accountID, id, userID)
 }
 
-// MockStats is a mock of Stats interface.
-type MockStats struct {
-	ctrl     *gomock.Controller
-	recorder *MockStatsMockRecorder
+// Create mocks base method.
+func (m *MockReconciliations) Create(ctx context.Context, accountID, userID int64, toCreate domain.ReconciliationToCreate) (domain.ReconciliationDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, accountID, userID, toCreate)
+	ret0, _ := ret[0].(domain.ReconciliationDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// MockStatsMockRecorder is the mock recorder for MockStats.
-type MockStatsMockRecorder struct {
-	mock *MockStats
+// Create indicates an expected call of Create.
+func (mr *MockReconciliationsMockRecorder) Create(ctx, accountID, userID, toCreate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReconciliations)(nil).Create), ctx, accountID, userID, toCreate)
 }
 
-// NewMockStats creates a new mock instance.
-func NewMockStats(ctrl *gomock.Controller) *MockStats {
-	mock := &MockStats{ctrl: ctrl}
-	mock.recorder = &MockStatsMockRecorder{mock}
-	return mock
+// Delete mocks base method.
+func (m *MockReconciliations) Delete(ctx context.Context, accountID, id, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, accountID, id, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockStats) EXPECT() *MockStatsMockRecorder {
-	return m.recorder
+// Delete indicates an expected call of Delete.
+func (mr *MockReconciliationsMockRecorder) Delete(ctx, accountID, id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockReconciliations)(nil).Delete), ctx, accountID, id, userID)
 }
 
-// Statement mocks base method.
-func (m *MockStats) Statement(ctx context.Context, filter domain.TransactionsFilter) (domain.Statement, error) {
+// Get mocks base method.
+func (m *MockReconciliations) Get(ctx context.Context, accountID, id, userID int64) (domain.ReconciliationDetails, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Statement", ctx, filter)
-	ret0, _ := ret[0].(domain.Statement)
+	ret := m.ctrl.Call(m, "Get", ctx, accountID, id, userID)
+	ret0, _ := ret[0].(domain.ReconciliationDetails)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Statement indicates an expected call of Statement.
-func (mr *MockStatsMockRecorder) Statement(ctx, filter interface{}) *gomock.Call {
+// Get indicates an expected call of Get.
+func (mr *MockReconciliationsMockRecorder) Get(ctx, accountID, id, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Statement", reflect.TypeOf((*MockStats)(nil).Statement), ctx, filter)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockReconciliations)(nil).Get), ctx, accountID, id, userID)
+}
+
+// List mocks base method.
+func (m *MockReconciliations) List(ctx context.Context, accountID, userID int64) ([]domain.Reconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, accountID, userID)
+	ret0, _ := ret[0].([]domain.Reconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockReconciliationsMockRecorder) List(ctx, accountID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockReconciliations)(nil).List), ctx, accountID, userID)
+}
+
+// Tick mocks base method.
+func (m *MockReconciliations) Tick(ctx context.Context, accountID, id, transactionID, userID int64) (domain.ReconciliationDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tick", ctx, accountID, id, transactionID, userID)
+	ret0, _ := ret[0].(domain.ReconciliationDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Tick indicates an expected call of Tick.
+func (mr *MockReconciliationsMockRecorder) Tick(ctx, accountID, id, transactionID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tick", reflect.TypeOf((*MockReconciliations)(nil).Tick), ctx, accountID, id, transactionID, userID)
+}
+
+// Untick mocks base method.
+func (m *MockReconciliations) Untick(ctx context.Context, accountID, id, transactionID, userID int64) (domain.ReconciliationDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Untick", ctx, accountID, id, transactionID, userID)
+	ret0, _ := ret[0].(domain.ReconciliationDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Untick indicates an expected call of Untick.
+func (mr *MockReconciliationsMockRecorder) Untick(ctx, accountID, id, transactionID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Untick", reflect.TypeOf((*MockReconciliations)(nil).Untick), ctx, accountID, id, transactionID, userID)
+}
+
+// MockTrash is a mock of Trash interface.
+type MockTrash struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrashMockRecorder
+}
+
+// MockTrashMockRecorder is the mock recorder for MockTrash.
+type MockTrashMockRecorder struct {
+	mock *MockTrash
+}
+
+// NewMockTrash creates a new mock instance.
+func NewMockTrash(ctrl *gomock.Controller) *MockTrash {
+	mock := &MockTrash{ctrl: ctrl}
+	mock.recorder = &MockTrashMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrash) EXPECT() *MockTrashMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockTrash) List(ctx context.Context, userID int64) (domain.Trash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID)
+	ret0, _ := ret[0].(domain.Trash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTrashMockRecorder) List(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTrash)(nil).List), ctx, userID)
+}
+
+// Purge mocks base method.
+func (m *MockTrash) Purge(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockTrashMockRecorder) Purge(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockTrash)(nil).Purge), ctx)
+}
+
+// RestoreAccount mocks base method.
+func (m *MockTrash) RestoreAccount(ctx context.Context, id, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreAccount", ctx, id, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreAccount indicates an expected call of RestoreAccount.
+func (mr *MockTrashMockRecorder) RestoreAccount(ctx, id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreAccount", reflect.TypeOf((*MockTrash)(nil).RestoreAccount), ctx, id, userID)
+}
+
+// RestoreTransaction mocks base method.
+func (m *MockTrash) RestoreTransaction(ctx context.Context, id, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTransaction", ctx, id, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreTransaction indicates an expected call of RestoreTransaction.
+func (mr *MockTrashMockRecorder) RestoreTransaction(ctx, id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTransaction", reflect.TypeOf((*MockTrash)(nil).RestoreTransaction), ctx, id, userID)
+}
+
+// MockTransactionTemplates is a mock of TransactionTemplates interface.
+type MockTransactionTemplates struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionTemplatesMockRecorder
+}
+
+// MockTransactionTemplatesMockRecorder is the mock recorder for MockTransactionTemplates.
+type MockTransactionTemplatesMockRecorder struct {
+	mock *MockTransactionTemplates
+}
+
+// NewMockTransactionTemplates creates a new mock instance.
+func NewMockTransactionTemplates(ctrl *gomock.Controller) *MockTransactionTemplates {
+	mock := &MockTransactionTemplates{ctrl: ctrl}
+	mock.recorder = &MockTransactionTemplatesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionTemplates) EXPECT() *MockTransactionTemplatesMockRecorder {
+	return m.recorder
+}
+
+// Apply mocks base method.
+func (m *MockTransactionTemplates) Apply(ctx context.Context, id int64, toApply domain.TransactionTemplateToApply, userID int64) (domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Apply", ctx, id, toApply, userID)
+	ret0, _ := ret[0].(domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Apply indicates an expected call of Apply.
+func (mr *MockTransactionTemplatesMockRecorder) Apply(ctx, id, toApply, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockTransactionTemplates)(nil).Apply), ctx, id, toApply, userID)
+}
+
+// Create mocks base method.
+func (m *MockTransactionTemplates) Create(ctx context.Context, toCreate domain.TransactionTemplateToCreate, userID int64) (domain.TransactionTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, toCreate, userID)
+	ret0, _ := ret[0].(domain.TransactionTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTransactionTemplatesMockRecorder) Create(ctx, toCreate, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransactionTemplates)(nil).Create), ctx, toCreate, userID)
+}
+
+// Delete mocks base method.
+func (m *MockTransactionTemplates) Delete(ctx context.Context, id, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTransactionTemplatesMockRecorder) Delete(ctx, id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTransactionTemplates)(nil).Delete), ctx, id, userID)
+}
+
+// Get mocks base method.
+func (m *MockTransactionTemplates) Get(ctx context.Context, id, userID int64) (domain.TransactionTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id, userID)
+	ret0, _ := ret[0].(domain.TransactionTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockTransactionTemplatesMockRecorder) Get(ctx, id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTransactionTemplates)(nil).Get), ctx, id, userID)
+}
+
+// List mocks base method.
+func (m *MockTransactionTemplates) List(ctx context.Context, userID int64) ([]domain.TransactionTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID)
+	ret0, _ := ret[0].([]domain.TransactionTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTransactionTemplatesMockRecorder) List(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTransactionTemplates)(nil).List), ctx, userID)
+}
+
+// Update mocks base method.
+func (m *MockTransactionTemplates) Update(ctx context.Context, id int64, toUpdate domain.TransactionTemplateToCreate, userID int64) (domain.TransactionTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, toUpdate, userID)
+	ret0, _ := ret[0].(domain.TransactionTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTransactionTemplatesMockRecorder) Update(ctx, id, toUpdate, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTransactionTemplates)(nil).Update), ctx, id, toUpdate, userID)
+}
+
+// MockAudit is a mock of Audit interface.
+type MockAudit struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditMockRecorder
+}
+
+// MockAuditMockRecorder is the mock recorder for MockAudit.
+type MockAuditMockRecorder struct {
+	mock *MockAudit
+}
+
+// NewMockAudit creates a new mock instance.
+func NewMockAudit(ctrl *gomock.Controller) *MockAudit {
+	mock := &MockAudit{ctrl: ctrl}
+	mock.recorder = &MockAuditMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAudit) EXPECT() *MockAuditMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockAudit) List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]domain.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAuditMockRecorder) List(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAudit)(nil).List), ctx, filter)
+}
+
+// MockTransactionCategories is a mock of TransactionCategories interface.
+type MockTransactionCategories struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionCategoriesMockRecorder
+}
+
+// MockTransactionCategoriesMockRecorder is the mock recorder for MockTransactionCategories.
+type MockTransactionCategoriesMockRecorder struct {
+	mock *MockTransactionCategories
+}
+
+// NewMockTransactionCategories creates a new mock instance.
+func NewMockTransactionCategories(ctrl *gomock.Controller) *MockTransactionCategories {
+	mock := &MockTransactionCategories{ctrl: ctrl}
+	mock.recorder = &MockTransactionCategoriesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionCategories) EXPECT() *MockTransactionCategoriesMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockTransactionCategories) List(ctx context.Context) ([]domain.TransactionCategory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]domain.TransactionCategory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTransactionCategoriesMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTransactionCategories)(nil).List), ctx)
+}
+
+// ListByType mocks base method.
+func (m *MockTransactionCategories) ListByType(ctx context.Context, _type domain.TransactionType) ([]domain.TransactionCategory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByType", ctx, _type)
+	ret0, _ := ret[0].([]domain.TransactionCategory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByType indicates an expected call of ListByType.
+func (mr *MockTransactionCategoriesMockRecorder) ListByType(ctx, _type interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByType", reflect.TypeOf((*MockTransactionCategories)(nil).ListByType), ctx, _type)
+}
+
+// MockTransactionTypes is a mock of TransactionTypes interface.
+type MockTransactionTypes struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionTypesMockRecorder
+}
+
+// MockTransactionTypesMockRecorder is the mock recorder for MockTransactionTypes.
+type MockTransactionTypesMockRecorder struct {
+	mock *MockTransactionTypes
+}
+
+// NewMockTransactionTypes creates a new mock instance.
+func NewMockTransactionTypes(ctrl *gomock.Controller) *MockTransactionTypes {
+	mock := &MockTransactionTypes{ctrl: ctrl}
+	mock.recorder = &MockTransactionTypesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionTypes) EXPECT() *MockTransactionTypesMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockTransactionTypes) List(ctx context.Context) ([]domain.TransactionType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]domain.TransactionType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTransactionTypesMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTransactionTypes)(nil).List), ctx)
+}
+
+// MockStats is a mock of Stats interface.
+type MockStats struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatsMockRecorder
+}
+
+// MockStatsMockRecorder is the mock recorder for MockStats.
+type MockStatsMockRecorder struct {
+	mock *MockStats
+}
+
+// NewMockStats creates a new mock instance.
+func NewMockStats(ctrl *gomock.Controller) *MockStats {
+	mock := &MockStats{ctrl: ctrl}
+	mock.recorder = &MockStatsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStats) EXPECT() *MockStatsMockRecorder {
+	return m.recorder
+}
+
+// AnnualReport mocks base method.
+func (m *MockStats) AnnualReport(ctx context.Context, userID int64, year int) (domain.AnnualReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnnualReport", ctx, userID, year)
+	ret0, _ := ret[0].(domain.AnnualReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnnualReport indicates an expected call of AnnualReport.
+func (mr *MockStatsMockRecorder) AnnualReport(ctx, userID, year interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnnualReport", reflect.TypeOf((*MockStats)(nil).AnnualReport), ctx, userID, year)
+}
+
+// CashFlow mocks base method.
+func (m *MockStats) CashFlow(ctx context.Context, filter domain.TransactionsFilter, interval domain.Interval) ([]domain.CashFlow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CashFlow", ctx, filter, interval)
+	ret0, _ := ret[0].([]domain.CashFlow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CashFlow indicates an expected call of CashFlow.
+func (mr *MockStatsMockRecorder) CashFlow(ctx, filter, interval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CashFlow", reflect.TypeOf((*MockStats)(nil).CashFlow), ctx, filter, interval)
+}
+
+// ConsolidatedStatement mocks base method.
+func (m *MockStats) ConsolidatedStatement(ctx context.Context, filter domain.ConsolidatedFilter) (domain.ConsolidatedStatement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsolidatedStatement", ctx, filter)
+	ret0, _ := ret[0].(domain.ConsolidatedStatement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsolidatedStatement indicates an expected call of ConsolidatedStatement.
+func (mr *MockStatsMockRecorder) ConsolidatedStatement(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsolidatedStatement", reflect.TypeOf((*MockStats)(nil).ConsolidatedStatement), ctx, filter)
+}
+
+// NetWorth mocks base method.
+func (m *MockStats) NetWorth(ctx context.Context, userID int64, from, to time.Time, interval domain.Interval) ([]domain.NetWorth, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetWorth", ctx, userID, from, to, interval)
+	ret0, _ := ret[0].([]domain.NetWorth)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NetWorth indicates an expected call of NetWorth.
+func (mr *MockStatsMockRecorder) NetWorth(ctx, userID, from, to, interval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetWorth", reflect.TypeOf((*MockStats)(nil).NetWorth), ctx, userID, from, to, interval)
+}
+
+// Statement mocks base method.
+func (m *MockStats) Statement(ctx context.Context, filter domain.TransactionsFilter) (domain.Statement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Statement", ctx, filter)
+	ret0, _ := ret[0].(domain.Statement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Statement indicates an expected call of Statement.
+func (mr *MockStatsMockRecorder) Statement(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Statement", reflect.TypeOf((*MockStats)(nil).Statement), ctx, filter)
+}
+
+// Trends mocks base method.
+func (m *MockStats) Trends(ctx context.Context, filter domain.TransactionsFilter) ([]domain.CategoryTrend, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trends", ctx, filter)
+	ret0, _ := ret[0].([]domain.CategoryTrend)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Trends indicates an expected call of Trends.
+func (mr *MockStatsMockRecorder) Trends(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trends", reflect.TypeOf((*MockStats)(nil).Trends), ctx, filter)
+}
+
+// MockForecast is a mock of Forecast interface.
+type MockForecast struct {
+	ctrl     *gomock.Controller
+	recorder *MockForecastMockRecorder
+}
+
+// MockForecastMockRecorder is the mock recorder for MockForecast.
+type MockForecastMockRecorder struct {
+	mock *MockForecast
+}
+
+// NewMockForecast creates a new mock instance.
+func NewMockForecast(ctrl *gomock.Controller) *MockForecast {
+	mock := &MockForecast{ctrl: ctrl}
+	mock.recorder = &MockForecastMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockForecast) EXPECT() *MockForecastMockRecorder {
+	return m.recorder
+}
+
+// Forecast mocks base method.
+func (m *MockForecast) Forecast(ctx context.Context, userID int64, date time.Time, months int) (domain.Forecast, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Forecast", ctx, userID, date, months)
+	ret0, _ := ret[0].(domain.Forecast)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Forecast indicates an expected call of Forecast.
+func (mr *MockForecastMockRecorder) Forecast(ctx, userID, date, months interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Forecast", reflect.TypeOf((*MockForecast)(nil).Forecast), ctx, userID, date, months)
+}
+
+// MockAnomalies is a mock of Anomalies interface.
+type MockAnomalies struct {
+	ctrl     *gomock.Controller
+	recorder *MockAnomaliesMockRecorder
+}
+
+// MockAnomaliesMockRecorder is the mock recorder for MockAnomalies.
+type MockAnomaliesMockRecorder struct {
+	mock *MockAnomalies
+}
+
+// NewMockAnomalies creates a new mock instance.
+func NewMockAnomalies(ctrl *gomock.Controller) *MockAnomalies {
+	mock := &MockAnomalies{ctrl: ctrl}
+	mock.recorder = &MockAnomaliesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAnomalies) EXPECT() *MockAnomaliesMockRecorder {
+	return m.recorder
+}
+
+// Detect mocks base method.
+func (m *MockAnomalies) Detect(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Detect", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Detect indicates an expected call of Detect.
+func (mr *MockAnomaliesMockRecorder) Detect(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Detect", reflect.TypeOf((*MockAnomalies)(nil).Detect), ctx)
+}
+
+// MockAdmin is a mock of Admin interface.
+type MockAdmin struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminMockRecorder
+}
+
+// MockAdminMockRecorder is the mock recorder for MockAdmin.
+type MockAdminMockRecorder struct {
+	mock *MockAdmin
+}
+
+// NewMockAdmin creates a new mock instance.
+func NewMockAdmin(ctrl *gomock.Controller) *MockAdmin {
+	mock := &MockAdmin{ctrl: ctrl}
+	mock.recorder = &MockAdminMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdmin) EXPECT() *MockAdminMockRecorder {
+	return m.recorder
+}
+
+// ResetSessions mocks base method.
+func (m *MockAdmin) ResetSessions(ctx context.Context, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetSessions", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetSessions indicates an expected call of ResetSessions.
+func (mr *MockAdminMockRecorder) ResetSessions(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetSessions", reflect.TypeOf((*MockAdmin)(nil).ResetSessions), ctx, userID)
+}
+
+// SetDisabled mocks base method.
+func (m *MockAdmin) SetDisabled(ctx context.Context, userID int64, disabled bool) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDisabled", ctx, userID, disabled)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetDisabled indicates an expected call of SetDisabled.
+func (mr *MockAdminMockRecorder) SetDisabled(ctx, userID, disabled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDisabled", reflect.TypeOf((*MockAdmin)(nil).SetDisabled), ctx, userID, disabled)
+}
+
+// Stats mocks base method.
+func (m *MockAdmin) Stats(ctx context.Context) (domain.SystemStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats", ctx)
+	ret0, _ := ret[0].(domain.SystemStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockAdminMockRecorder) Stats(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockAdmin)(nil).Stats), ctx)
 }
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"github.com/lotostudio/financial-api/pkg/oidc"
+	"sort"
+	"time"
+)
+
+// Time for user to authorize at provider
+const oidcFlowTTL = 10 * time.Minute
+
+type OIDCService struct {
+	providers map[string]*oidc.Provider
+	repo      repo.OIDCFlows
+}
+
+func newOIDCService(providers map[string]*oidc.Provider, repo repo.OIDCFlows) *OIDCService {
+	return &OIDCService{
+		providers: providers,
+		repo:      repo,
+	}
+}
+
+func (s *OIDCService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+
+	for name := range s.providers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Begin starts authorization flow and returns URL of provider's authorization page
+func (s *OIDCService) Begin(ctx context.Context, provider string) (string, error) {
+	p, ok := s.providers[provider]
+
+	if !ok {
+		return "", ErrOIDCProviderNotFound
+	}
+
+	flow := domain.OIDCFlow{
+		Provider:  provider,
+		ExpiresAt: time.Now().UTC().Add(oidcFlowTTL),
+	}
+
+	var err error
+
+	for _, v := range []*string{&flow.State, &flow.Nonce, &flow.CodeVerifier} {
+		if *v, err = oidc.Random(); err != nil {
+			return "", err
+		}
+	}
+
+	if err = s.repo.Create(ctx, flow); err != nil {
+		return "", err
+	}
+
+	return p.AuthCodeURL(ctx, flow.State, flow.Nonce, flow.CodeVerifier)
+}
+
+// Complete finishes authorization flow by code from provider's callback and returns authenticated identity
+func (s *OIDCService) Complete(ctx context.Context, provider, state, code string) (domain.ExternalIdentity, error) {
+	p, ok := s.providers[provider]
+
+	if !ok {
+		return domain.ExternalIdentity{}, ErrOIDCProviderNotFound
+	}
+
+	flow, err := s.repo.Pop(ctx, state)
+
+	if err != nil {
+		return domain.ExternalIdentity{}, err
+	}
+
+	if flow.Provider != provider || flow.Expired() {
+		return domain.ExternalIdentity{}, ErrOIDCFlowInvalid
+	}
+
+	identity, err := p.Exchange(ctx, code, flow.CodeVerifier, flow.Nonce)
+
+	if err != nil {
+		return domain.ExternalIdentity{}, err
+	}
+
+	return domain.ExternalIdentity{
+		Provider:      provider,
+		Subject:       identity.Subject,
+		Email:         identity.Email,
+		EmailVerified: identity.EmailVerified,
+		FirstName:     identity.GivenName,
+		LastName:      identity.FamilyName,
+	}, nil
+}
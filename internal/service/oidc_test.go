@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	mockRepo "github.com/lotostudio/financial-api/internal/repo/mocks"
+	"github.com/lotostudio/financial-api/pkg/oidc"
+	"github.com/lotostudio/financial-api/pkg/oidc/oidctest"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func mockOIDCService(t *testing.T) (*OIDCService, *mockRepo.MockOIDCFlows, *oidctest.Server) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	fRepo := mockRepo.NewMockOIDCFlows(mockCtl)
+
+	server, err := oidctest.NewServer("client", "secret")
+	require.NoError(t, err)
+
+	t.Cleanup(server.Close)
+
+	provider := oidc.NewProvider(oidc.Config{
+		Issuer:       server.Issuer(),
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost/callback",
+	}, server.Client())
+
+	s := newOIDCService(map[string]*oidc.Provider{"test": provider}, fRepo)
+
+	return s, fRepo, server
+}
+
+func TestOIDCService_Providers(t *testing.T) {
+	s, _, _ := mockOIDCService(t)
+
+	require.Equal(t, []string{"test"}, s.Providers())
+}
+
+func TestOIDCService_BeginAndComplete(t *testing.T) {
+	s, fRepo, server := mockOIDCService(t)
+
+	ctx := context.Background()
+	server.User = oidctest.User{Subject: "42", Email: "user@mail.com", EmailVerified: true, GivenName: "John"}
+
+	var flow domain.OIDCFlow
+
+	fRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, f domain.OIDCFlow) error {
+		flow = f
+		return nil
+	})
+
+	url, err := s.Begin(ctx, "test")
+
+	require.NoError(t, err)
+
+	code, state, err := server.Authorize(url)
+
+	require.NoError(t, err)
+	require.Equal(t, flow.State, state)
+
+	fRepo.EXPECT().Pop(ctx, state).Return(flow, nil)
+
+	identity, err := s.Complete(ctx, "test", state, code)
+
+	require.NoError(t, err)
+	require.Equal(t, domain.ExternalIdentity{
+		Provider:      "test",
+		Subject:       "42",
+		Email:         "user@mail.com",
+		EmailVerified: true,
+		FirstName:     "John",
+	}, identity)
+}
+
+func TestOIDCService_BeginErrProviderNotFound(t *testing.T) {
+	s, _, _ := mockOIDCService(t)
+
+	_, err := s.Begin(context.Background(), "other")
+
+	require.ErrorIs(t, err, ErrOIDCProviderNotFound)
+}
+
+func TestOIDCService_CompleteErrFlowNotFound(t *testing.T) {
+	s, fRepo, _ := mockOIDCService(t)
+
+	ctx := context.Background()
+
+	fRepo.EXPECT().Pop(ctx, "state").Return(domain.OIDCFlow{}, repo.ErrOIDCFlowNotFound)
+
+	_, err := s.Complete(ctx, "test", "state", "code")
+
+	require.ErrorIs(t, err, repo.ErrOIDCFlowNotFound)
+}
+
+func TestOIDCService_CompleteErrFlowExpired(t *testing.T) {
+	s, fRepo, _ := mockOIDCService(t)
+
+	ctx := context.Background()
+
+	fRepo.EXPECT().Pop(ctx, "state").Return(domain.OIDCFlow{
+		State:     "state",
+		Provider:  "test",
+		ExpiresAt: time.Now().UTC().Add(-time.Minute),
+	}, nil)
+
+	_, err := s.Complete(ctx, "test", "state", "code")
+
+	require.ErrorIs(t, err, ErrOIDCFlowInvalid)
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"math"
+	"time"
+)
+
+type ReconciliationsService struct {
+	repo         repo.Reconciliations
+	accountsRepo repo.Accounts
+	balancesRepo repo.Balances
+}
+
+func newReconciliationsService(repo repo.Reconciliations, accountsRepo repo.Accounts,
+	balancesRepo repo.Balances) *ReconciliationsService {
+	return &ReconciliationsService{
+		repo:         repo,
+		accountsRepo: accountsRepo,
+		balancesRepo: balancesRepo,
+	}
+}
+
+func (s *ReconciliationsService) List(ctx context.Context, accountID int64,
+	userID int64) ([]domain.Reconciliation, error) {
+	if err := s.checkAccount(ctx, accountID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.List(ctx, accountID)
+}
+
+// Create starts reconciliation of account with bank statement. Account has one reconciliation in progress at most
+func (s *ReconciliationsService) Create(ctx context.Context, accountID int64, userID int64,
+	toCreate domain.ReconciliationToCreate) (domain.ReconciliationDetails, error) {
+	if toCreate.StatementDate.After(time.Now()) {
+		return domain.ReconciliationDetails{}, ErrStatementDateInvalid
+	}
+
+	if err := s.checkAccount(ctx, accountID, userID); err != nil {
+		return domain.ReconciliationDetails{}, err
+	}
+
+	reconciliation, err := s.repo.Create(ctx, accountID, toCreate)
+
+	if err != nil {
+		return domain.ReconciliationDetails{}, err
+	}
+
+	return s.details(ctx, reconciliation)
+}
+
+// Get returns reconciliation with transactions to tick and difference between statement and cleared balances
+func (s *ReconciliationsService) Get(ctx context.Context, accountID int64, id int64,
+	userID int64) (domain.ReconciliationDetails, error) {
+	reconciliation, err := s.get(ctx, accountID, id, userID)
+
+	if err != nil {
+		return domain.ReconciliationDetails{}, err
+	}
+
+	return s.details(ctx, reconciliation)
+}
+
+// Tick marks transaction as found in bank statement
+func (s *ReconciliationsService) Tick(ctx context.Context, accountID int64, id int64, transactionID int64,
+	userID int64) (domain.ReconciliationDetails, error) {
+	return s.setTicked(ctx, accountID, id, transactionID, userID, true)
+}
+
+// Untick marks transaction as missing in bank statement
+func (s *ReconciliationsService) Untick(ctx context.Context, accountID int64, id int64, transactionID int64,
+	userID int64) (domain.ReconciliationDetails, error) {
+	return s.setTicked(ctx, accountID, id, transactionID, userID, false)
+}
+
+func (s *ReconciliationsService) setTicked(ctx context.Context, accountID int64, id int64, transactionID int64,
+	userID int64, ticked bool) (domain.ReconciliationDetails, error) {
+	reconciliation, err := s.getOpen(ctx, accountID, id, userID)
+
+	if err != nil {
+		return domain.ReconciliationDetails{}, err
+	}
+
+	details, err := s.details(ctx, reconciliation)
+
+	if err != nil {
+		return details, err
+	}
+
+	// Only listed transactions can be ticked
+	i := -1
+
+	for j := range details.Transactions {
+		if details.Transactions[j].ID == transactionID {
+			i = j
+			break
+		}
+	}
+
+	if i < 0 {
+		return domain.ReconciliationDetails{}, ErrReconciliationTransactionInvalid
+	}
+
+	if ticked {
+		err = s.repo.Tick(ctx, id, transactionID)
+	} else {
+		err = s.repo.Untick(ctx, id, transactionID)
+	}
+
+	if err != nil {
+		return domain.ReconciliationDetails{}, err
+	}
+
+	details.Transactions[i].Ticked = ticked
+	summarize(&details)
+
+	return details, nil
+}
+
+// Complete marks ticked transactions as reconciled once cleared balance matches statement balance
+func (s *ReconciliationsService) Complete(ctx context.Context, accountID int64, id int64,
+	userID int64) (domain.ReconciliationDetails, error) {
+	reconciliation, err := s.getOpen(ctx, accountID, id, userID)
+
+	if err != nil {
+		return domain.ReconciliationDetails{}, err
+	}
+
+	details, err := s.details(ctx, reconciliation)
+
+	if err != nil {
+		return details, err
+	}
+
+	if details.Difference != 0 {
+		return details, ErrReconciliationNotBalanced
+	}
+
+	if details.Reconciliation, err = s.repo.Complete(ctx, id); err != nil {
+		return domain.ReconciliationDetails{}, err
+	}
+
+	for i := range details.Transactions {
+		if details.Transactions[i].Ticked {
+			details.Transactions[i].Status = domain.Reconciled
+		}
+	}
+
+	return details, nil
+}
+
+// Delete cancels reconciliation in progress, statuses of transactions are kept
+func (s *ReconciliationsService) Delete(ctx context.Context, accountID int64, id int64, userID int64) error {
+	if _, err := s.getOpen(ctx, accountID, id, userID); err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *ReconciliationsService) checkAccount(ctx context.Context, accountID int64, userID int64) error {
+	account, err := s.accountsRepo.Get(ctx, accountID)
+
+	if err != nil {
+		return err
+	}
+
+	if account.OwnerId != userID {
+		return ErrAccountForbidden
+	}
+
+	return nil
+}
+
+// get returns reconciliation of account of user
+func (s *ReconciliationsService) get(ctx context.Context, accountID int64, id int64,
+	userID int64) (domain.Reconciliation, error) {
+	if err := s.checkAccount(ctx, accountID, userID); err != nil {
+		return domain.Reconciliation{}, err
+	}
+
+	reconciliation, err := s.repo.Get(ctx, id)
+
+	if err != nil {
+		return reconciliation, err
+	}
+
+	if reconciliation.AccountID != accountID {
+		return domain.Reconciliation{}, repo.ErrReconciliationNotFound
+	}
+
+	return reconciliation, nil
+}
+
+// getOpen returns reconciliation of account of user, which is in progress
+func (s *ReconciliationsService) getOpen(ctx context.Context, accountID int64, id int64,
+	userID int64) (domain.Reconciliation, error) {
+	reconciliation, err := s.get(ctx, accountID, id, userID)
+
+	if err != nil {
+		return reconciliation, err
+	}
+
+	if reconciliation.CompletedAt != nil {
+		return reconciliation, ErrReconciliationCompleted
+	}
+
+	return reconciliation, nil
+}
+
+// details loads transactions of reconciliation and book balance of account at end date of statement
+func (s *ReconciliationsService) details(ctx context.Context,
+	reconciliation domain.Reconciliation) (domain.ReconciliationDetails, error) {
+	transactions, err := s.repo.ListTransactions(ctx, reconciliation.ID)
+
+	if err != nil {
+		return domain.ReconciliationDetails{}, err
+	}
+
+	// Balance is taken before given date, so next day includes end date of statement
+	balance, err := s.balancesRepo.Get(ctx, reconciliation.AccountID, reconciliation.StatementDate.AddDate(0, 0, 1))
+
+	// Account didn't exist at end date of statement
+	if errors.Is(err, repo.ErrBalanceNotFound) {
+		err = nil
+	}
+
+	if err != nil {
+		return domain.ReconciliationDetails{}, err
+	}
+
+	details := domain.ReconciliationDetails{
+		Reconciliation: reconciliation,
+		BookBalance:    balance.Value,
+		Transactions:   transactions,
+	}
+
+	summarize(&details)
+
+	return details, nil
+}
+
+// summarize calculates cleared balance as book balance without transactions not ticked and its difference with
+// statement balance. Sums are rounded to cents
+func summarize(details *domain.ReconciliationDetails) {
+	cleared := details.BookBalance
+
+	for _, tr := range details.Transactions {
+		if tr.Ticked {
+			continue
+		}
+
+		if tr.Debit != nil && tr.Debit.ID == details.AccountID {
+			cleared -= tr.Amount
+		} else {
+			cleared += tr.Amount
+		}
+	}
+
+	details.ClearedBalance = math.Round(cleared*100) / 100
+	details.Difference = math.Round((details.StatementBalance-cleared)*100) / 100
+}
@@ -0,0 +1,303 @@
+package service
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	mockRepo "github.com/lotostudio/financial-api/internal/repo/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func mockReconciliationsService(t *testing.T) (*ReconciliationsService, *mockRepo.MockReconciliations,
+	*mockRepo.MockAccounts, *mockRepo.MockBalances) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	rRepo := mockRepo.NewMockReconciliations(mockCtl)
+	aRepo := mockRepo.NewMockAccounts(mockCtl)
+	bRepo := mockRepo.NewMockBalances(mockCtl)
+
+	s := newReconciliationsService(rRepo, aRepo, bRepo)
+
+	return s, rRepo, aRepo, bRepo
+}
+
+const reconciledAccountID = int64(2)
+
+// reconciliationTransactions returns expense of 100 and ticked income of 50 of reconciled account
+func reconciliationTransactions() []domain.ReconciliationTransaction {
+	account := &domain.Account{ID: reconciledAccountID}
+
+	return []domain.ReconciliationTransaction{
+		{Transaction: domain.Transaction{ID: 1, Amount: 100, Type: domain.Expense, Credit: account,
+			Status: domain.Pending}},
+		{Transaction: domain.Transaction{ID: 2, Amount: 50, Type: domain.Income, Debit: account,
+			Status: domain.Cleared}, Ticked: true},
+	}
+}
+
+func TestReconciliationsService_List(t *testing.T) {
+	s, rRepo, aRepo, _ := mockReconciliationsService(t)
+
+	ctx := context.Background()
+
+	aRepo.EXPECT().Get(ctx, reconciledAccountID).Return(domain.Account{OwnerId: userId}, nil)
+	rRepo.EXPECT().List(ctx, reconciledAccountID).Return([]domain.Reconciliation{{ID: 1}}, nil)
+
+	reconciliations, err := s.List(ctx, reconciledAccountID, userId)
+
+	require.NoError(t, err)
+	require.Len(t, reconciliations, 1)
+}
+
+func TestReconciliationsService_ListErrForbidden(t *testing.T) {
+	s, _, aRepo, _ := mockReconciliationsService(t)
+
+	ctx := context.Background()
+
+	aRepo.EXPECT().Get(ctx, reconciledAccountID).Return(domain.Account{OwnerId: userId + 1}, nil)
+
+	_, err := s.List(ctx, reconciledAccountID, userId)
+
+	require.ErrorIs(t, err, ErrAccountForbidden)
+}
+
+func TestReconciliationsService_Create(t *testing.T) {
+	s, rRepo, aRepo, bRepo := mockReconciliationsService(t)
+
+	ctx := context.Background()
+	date := time.Date(2022, 3, 31, 0, 0, 0, 0, time.UTC)
+	statementBalance := 1100.0
+	toCreate := domain.ReconciliationToCreate{StatementDate: date, StatementBalance: &statementBalance}
+	reconciliation := domain.Reconciliation{ID: 1, AccountID: reconciledAccountID, StatementDate: date,
+		StatementBalance: statementBalance}
+
+	aRepo.EXPECT().Get(ctx, reconciledAccountID).Return(domain.Account{OwnerId: userId}, nil)
+	rRepo.EXPECT().Create(ctx, reconciledAccountID, toCreate).Return(reconciliation, nil)
+	rRepo.EXPECT().ListTransactions(ctx, int64(1)).Return(reconciliationTransactions(), nil)
+	bRepo.EXPECT().Get(ctx, reconciledAccountID, date.AddDate(0, 0, 1)).Return(domain.Balance{Value: 1000}, nil)
+
+	details, err := s.Create(ctx, reconciledAccountID, userId, toCreate)
+
+	require.NoError(t, err)
+	require.Equal(t, 1000.0, details.BookBalance)
+	// Expense not found in statement is added back, ticked income is kept
+	require.Equal(t, 1100.0, details.ClearedBalance)
+	require.Equal(t, 0.0, details.Difference)
+	require.Len(t, details.Transactions, 2)
+}
+
+func TestReconciliationsService_CreateErrDate(t *testing.T) {
+	s, _, _, _ := mockReconciliationsService(t)
+
+	statementBalance := 1100.0
+	toCreate := domain.ReconciliationToCreate{StatementDate: time.Now().AddDate(0, 0, 2),
+		StatementBalance: &statementBalance}
+
+	_, err := s.Create(context.Background(), reconciledAccountID, userId, toCreate)
+
+	require.ErrorIs(t, err, ErrStatementDateInvalid)
+}
+
+func TestReconciliationsService_CreateErrInProgress(t *testing.T) {
+	s, rRepo, aRepo, _ := mockReconciliationsService(t)
+
+	ctx := context.Background()
+	statementBalance := 1100.0
+	toCreate := domain.ReconciliationToCreate{StatementDate: time.Date(2022, 3, 31, 0, 0, 0, 0, time.UTC),
+		StatementBalance: &statementBalance}
+
+	aRepo.EXPECT().Get(ctx, reconciledAccountID).Return(domain.Account{OwnerId: userId}, nil)
+	rRepo.EXPECT().Create(ctx, reconciledAccountID, toCreate).
+		Return(domain.Reconciliation{}, repo.ErrReconciliationInProgress)
+
+	_, err := s.Create(ctx, reconciledAccountID, userId, toCreate)
+
+	require.ErrorIs(t, err, repo.ErrReconciliationInProgress)
+}
+
+func TestReconciliationsService_GetNoBalance(t *testing.T) {
+	s, rRepo, aRepo, bRepo := mockReconciliationsService(t)
+
+	ctx := context.Background()
+	date := time.Date(2022, 3, 31, 0, 0, 0, 0, time.UTC)
+	reconciliation := domain.Reconciliation{ID: 1, AccountID: reconciledAccountID, StatementDate: date,
+		StatementBalance: 0}
+
+	aRepo.EXPECT().Get(ctx, reconciledAccountID).Return(domain.Account{OwnerId: userId}, nil)
+	rRepo.EXPECT().Get(ctx, int64(1)).Return(reconciliation, nil)
+	rRepo.EXPECT().ListTransactions(ctx, int64(1)).Return([]domain.ReconciliationTransaction{}, nil)
+	bRepo.EXPECT().Get(ctx, reconciledAccountID, date.AddDate(0, 0, 1)).
+		Return(domain.Balance{}, repo.ErrBalanceNotFound)
+
+	details, err := s.Get(ctx, reconciledAccountID, 1, userId)
+
+	require.NoError(t, err)
+	require.Equal(t, 0.0, details.BookBalance)
+	require.Equal(t, 0.0, details.Difference)
+}
+
+func TestReconciliationsService_GetErrOtherAccount(t *testing.T) {
+	s, rRepo, aRepo, _ := mockReconciliationsService(t)
+
+	ctx := context.Background()
+
+	aRepo.EXPECT().Get(ctx, reconciledAccountID).Return(domain.Account{OwnerId: userId}, nil)
+	rRepo.EXPECT().Get(ctx, int64(1)).Return(domain.Reconciliation{ID: 1, AccountID: reconciledAccountID + 1}, nil)
+
+	_, err := s.Get(ctx, reconciledAccountID, 1, userId)
+
+	require.ErrorIs(t, err, repo.ErrReconciliationNotFound)
+}
+
+func TestReconciliationsService_Tick(t *testing.T) {
+	s, rRepo, aRepo, bRepo := mockReconciliationsService(t)
+
+	ctx := context.Background()
+	date := time.Date(2022, 3, 31, 0, 0, 0, 0, time.UTC)
+	reconciliation := domain.Reconciliation{ID: 1, AccountID: reconciledAccountID, StatementDate: date,
+		StatementBalance: 1100}
+
+	aRepo.EXPECT().Get(ctx, reconciledAccountID).Return(domain.Account{OwnerId: userId}, nil)
+	rRepo.EXPECT().Get(ctx, int64(1)).Return(reconciliation, nil)
+	rRepo.EXPECT().ListTransactions(ctx, int64(1)).Return(reconciliationTransactions(), nil)
+	bRepo.EXPECT().Get(ctx, reconciledAccountID, date.AddDate(0, 0, 1)).Return(domain.Balance{Value: 1000}, nil)
+	rRepo.EXPECT().Tick(ctx, int64(1), int64(1)).Return(nil)
+
+	details, err := s.Tick(ctx, reconciledAccountID, 1, 1, userId)
+
+	require.NoError(t, err)
+	require.True(t, details.Transactions[0].Ticked)
+	require.Equal(t, 1000.0, details.ClearedBalance)
+	require.Equal(t, 100.0, details.Difference)
+}
+
+func TestReconciliationsService_Untick(t *testing.T) {
+	s, rRepo, aRepo, bRepo := mockReconciliationsService(t)
+
+	ctx := context.Background()
+	date := time.Date(2022, 3, 31, 0, 0, 0, 0, time.UTC)
+	reconciliation := domain.Reconciliation{ID: 1, AccountID: reconciledAccountID, StatementDate: date,
+		StatementBalance: 1100}
+
+	aRepo.EXPECT().Get(ctx, reconciledAccountID).Return(domain.Account{OwnerId: userId}, nil)
+	rRepo.EXPECT().Get(ctx, int64(1)).Return(reconciliation, nil)
+	rRepo.EXPECT().ListTransactions(ctx, int64(1)).Return(reconciliationTransactions(), nil)
+	bRepo.EXPECT().Get(ctx, reconciledAccountID, date.AddDate(0, 0, 1)).Return(domain.Balance{Value: 1000}, nil)
+	rRepo.EXPECT().Untick(ctx, int64(1), int64(2)).Return(nil)
+
+	details, err := s.Untick(ctx, reconciledAccountID, 1, 2, userId)
+
+	require.NoError(t, err)
+	require.False(t, details.Transactions[1].Ticked)
+	require.Equal(t, 1050.0, details.ClearedBalance)
+	require.Equal(t, 50.0, details.Difference)
+}
+
+func TestReconciliationsService_TickErrTransaction(t *testing.T) {
+	s, rRepo, aRepo, bRepo := mockReconciliationsService(t)
+
+	ctx := context.Background()
+	date := time.Date(2022, 3, 31, 0, 0, 0, 0, time.UTC)
+	reconciliation := domain.Reconciliation{ID: 1, AccountID: reconciledAccountID, StatementDate: date}
+
+	aRepo.EXPECT().Get(ctx, reconciledAccountID).Return(domain.Account{OwnerId: userId}, nil)
+	rRepo.EXPECT().Get(ctx, int64(1)).Return(reconciliation, nil)
+	rRepo.EXPECT().ListTransactions(ctx, int64(1)).Return(reconciliationTransactions(), nil)
+	bRepo.EXPECT().Get(ctx, reconciledAccountID, date.AddDate(0, 0, 1)).Return(domain.Balance{Value: 1000}, nil)
+
+	_, err := s.Tick(ctx, reconciledAccountID, 1, 3, userId)
+
+	require.ErrorIs(t, err, ErrReconciliationTransactionInvalid)
+}
+
+func TestReconciliationsService_TickErrCompleted(t *testing.T) {
+	s, rRepo, aRepo, _ := mockReconciliationsService(t)
+
+	ctx := context.Background()
+	completedAt := time.Now()
+
+	aRepo.EXPECT().Get(ctx, reconciledAccountID).Return(domain.Account{OwnerId: userId}, nil)
+	rRepo.EXPECT().Get(ctx, int64(1)).Return(domain.Reconciliation{ID: 1, AccountID: reconciledAccountID,
+		CompletedAt: &completedAt}, nil)
+
+	_, err := s.Tick(ctx, reconciledAccountID, 1, 1, userId)
+
+	require.ErrorIs(t, err, ErrReconciliationCompleted)
+}
+
+func TestReconciliationsService_Complete(t *testing.T) {
+	s, rRepo, aRepo, bRepo := mockReconciliationsService(t)
+
+	ctx := context.Background()
+	date := time.Date(2022, 3, 31, 0, 0, 0, 0, time.UTC)
+	completedAt := time.Now()
+	reconciliation := domain.Reconciliation{ID: 1, AccountID: reconciledAccountID, StatementDate: date,
+		StatementBalance: 1100}
+	completed := reconciliation
+	completed.CompletedAt = &completedAt
+
+	aRepo.EXPECT().Get(ctx, reconciledAccountID).Return(domain.Account{OwnerId: userId}, nil)
+	rRepo.EXPECT().Get(ctx, int64(1)).Return(reconciliation, nil)
+	rRepo.EXPECT().ListTransactions(ctx, int64(1)).Return(reconciliationTransactions(), nil)
+	bRepo.EXPECT().Get(ctx, reconciledAccountID, date.AddDate(0, 0, 1)).Return(domain.Balance{Value: 1000}, nil)
+	rRepo.EXPECT().Complete(ctx, int64(1)).Return(completed, nil)
+
+	details, err := s.Complete(ctx, reconciledAccountID, 1, userId)
+
+	require.NoError(t, err)
+	require.Equal(t, &completedAt, details.CompletedAt)
+	require.Equal(t, domain.Pending, details.Transactions[0].Status)
+	require.Equal(t, domain.Reconciled, details.Transactions[1].Status)
+}
+
+func TestReconciliationsService_CompleteErrNotBalanced(t *testing.T) {
+	s, rRepo, aRepo, bRepo := mockReconciliationsService(t)
+
+	ctx := context.Background()
+	date := time.Date(2022, 3, 31, 0, 0, 0, 0, time.UTC)
+	reconciliation := domain.Reconciliation{ID: 1, AccountID: reconciledAccountID, StatementDate: date,
+		StatementBalance: 1000}
+
+	aRepo.EXPECT().Get(ctx, reconciledAccountID).Return(domain.Account{OwnerId: userId}, nil)
+	rRepo.EXPECT().Get(ctx, int64(1)).Return(reconciliation, nil)
+	rRepo.EXPECT().ListTransactions(ctx, int64(1)).Return(reconciliationTransactions(), nil)
+	bRepo.EXPECT().Get(ctx, reconciledAccountID, date.AddDate(0, 0, 1)).Return(domain.Balance{Value: 1000}, nil)
+
+	details, err := s.Complete(ctx, reconciledAccountID, 1, userId)
+
+	require.ErrorIs(t, err, ErrReconciliationNotBalanced)
+	require.Equal(t, -100.0, details.Difference)
+}
+
+func TestReconciliationsService_Delete(t *testing.T) {
+	s, rRepo, aRepo, _ := mockReconciliationsService(t)
+
+	ctx := context.Background()
+
+	aRepo.EXPECT().Get(ctx, reconciledAccountID).Return(domain.Account{OwnerId: userId}, nil)
+	rRepo.EXPECT().Get(ctx, int64(1)).Return(domain.Reconciliation{ID: 1, AccountID: reconciledAccountID}, nil)
+	rRepo.EXPECT().Delete(ctx, int64(1)).Return(nil)
+
+	err := s.Delete(ctx, reconciledAccountID, 1, userId)
+
+	require.NoError(t, err)
+}
+
+func TestReconciliationsService_DeleteErr(t *testing.T) {
+	s, rRepo, aRepo, _ := mockReconciliationsService(t)
+
+	ctx := context.Background()
+
+	aRepo.EXPECT().Get(ctx, reconciledAccountID).Return(domain.Account{OwnerId: userId}, nil)
+	rRepo.EXPECT().Get(ctx, int64(1)).Return(domain.Reconciliation{}, errDefault)
+
+	err := s.Delete(ctx, reconciledAccountID, 1, userId)
+
+	require.ErrorIs(t, err, errDefault)
+}
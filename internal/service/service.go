@@ -7,6 +7,10 @@ import (
 	"github.com/lotostudio/financial-api/internal/repo"
 	"github.com/lotostudio/financial-api/pkg/auth"
 	"github.com/lotostudio/financial-api/pkg/hash"
+	"github.com/lotostudio/financial-api/pkg/limiter"
+	"github.com/lotostudio/financial-api/pkg/oidc"
+	"github.com/lotostudio/financial-api/pkg/storage"
+	"io"
 	"time"
 )
 
@@ -22,6 +26,21 @@ type Auth interface {
 	Register(ctx context.Context, user domain.UserToCreate) (domain.User, error)
 	Login(ctx context.Context, user domain.UserToLogin) (domain.Tokens, error)
 	Refresh(ctx context.Context, token string) (domain.Tokens, error)
+	LoginIdentity(ctx context.Context, identity domain.ExternalIdentity) (domain.Tokens, error)
+	CheckSession(ctx context.Context, userID int64, sessionID string) error
+}
+
+type OIDC interface {
+	Providers() []string
+	Begin(ctx context.Context, provider string) (string, error)
+	Complete(ctx context.Context, provider, state, code string) (domain.ExternalIdentity, error)
+}
+
+type AccessTokens interface {
+	List(ctx context.Context, userID int64) ([]domain.AccessToken, error)
+	Create(ctx context.Context, toCreate domain.AccessTokenToCreate, userID int64) (domain.CreatedAccessToken, error)
+	Delete(ctx context.Context, id int64, userID int64) error
+	Authenticate(ctx context.Context, token string) (domain.AccessToken, error)
 }
 
 type Currencies interface {
@@ -43,10 +62,61 @@ type AccountTypes interface {
 
 type Transactions interface {
 	List(ctx context.Context, filter domain.TransactionsFilter) ([]domain.Transaction, error)
-	Stats(ctx context.Context, filter domain.TransactionsFilter) ([]domain.TransactionStat, error)
+	Search(ctx context.Context, query string, filter domain.TransactionsFilter,
+		limit int) ([]domain.TransactionSearchResult, error)
+	Stats(ctx context.Context, filter domain.TransactionsFilter, groupBy domain.StatsGroup) ([]domain.TransactionStat, error)
 	Create(ctx context.Context, toCreate domain.TransactionToCreate, userID int64, categoryId *int64, creditId *int64,
 		debitId *int64) (domain.Transaction, error)
+	CreateBatch(ctx context.Context, toCreate domain.TransactionsToCreate, userID int64) (domain.TransactionBatch, error)
+	Delete(ctx context.Context, id int64, userID int64, unlock bool) error
+	DeleteBatch(ctx context.Context, toDelete domain.TransactionsToDelete, userID int64) (domain.TransactionBatch, error)
+	SetStatus(ctx context.Context, id int64, userID int64, toUpdate domain.TransactionStatusToUpdate) error
+	DismissFlags(ctx context.Context, id int64, userID int64) error
+}
+
+type Attachments interface {
+	List(ctx context.Context, transactionID int64, userID int64) ([]domain.Attachment, error)
+	Upload(ctx context.Context, transactionID int64, userID int64, filename string,
+		file io.Reader) (domain.Attachment, error)
+	Open(ctx context.Context, transactionID int64, id int64, userID int64,
+		thumbnail bool) (domain.Attachment, io.ReadCloser, error)
+	Delete(ctx context.Context, transactionID int64, id int64, userID int64) error
+}
+
+type Reconciliations interface {
+	List(ctx context.Context, accountID int64, userID int64) ([]domain.Reconciliation, error)
+	Create(ctx context.Context, accountID int64, userID int64,
+		toCreate domain.ReconciliationToCreate) (domain.ReconciliationDetails, error)
+	Get(ctx context.Context, accountID int64, id int64, userID int64) (domain.ReconciliationDetails, error)
+	Tick(ctx context.Context, accountID int64, id int64, transactionID int64,
+		userID int64) (domain.ReconciliationDetails, error)
+	Untick(ctx context.Context, accountID int64, id int64, transactionID int64,
+		userID int64) (domain.ReconciliationDetails, error)
+	Complete(ctx context.Context, accountID int64, id int64, userID int64) (domain.ReconciliationDetails, error)
+	Delete(ctx context.Context, accountID int64, id int64, userID int64) error
+}
+
+type Trash interface {
+	List(ctx context.Context, userID int64) (domain.Trash, error)
+	RestoreAccount(ctx context.Context, id int64, userID int64) error
+	RestoreTransaction(ctx context.Context, id int64, userID int64) error
+	Purge(ctx context.Context) (int64, error)
+}
+
+type TransactionTemplates interface {
+	List(ctx context.Context, userID int64) ([]domain.TransactionTemplate, error)
+	Get(ctx context.Context, id int64, userID int64) (domain.TransactionTemplate, error)
+	Create(ctx context.Context, toCreate domain.TransactionTemplateToCreate, userID int64) (domain.TransactionTemplate,
+		error)
+	Update(ctx context.Context, id int64, toUpdate domain.TransactionTemplateToCreate,
+		userID int64) (domain.TransactionTemplate, error)
 	Delete(ctx context.Context, id int64, userID int64) error
+	Apply(ctx context.Context, id int64, toApply domain.TransactionTemplateToApply,
+		userID int64) (domain.Transaction, error)
+}
+
+type Audit interface {
+	List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error)
 }
 
 type TransactionCategories interface {
@@ -60,31 +130,78 @@ type TransactionTypes interface {
 
 type Stats interface {
 	Statement(ctx context.Context, filter domain.TransactionsFilter) (domain.Statement, error)
+	ConsolidatedStatement(ctx context.Context, filter domain.ConsolidatedFilter) (domain.ConsolidatedStatement, error)
+	CashFlow(ctx context.Context, filter domain.TransactionsFilter, interval domain.Interval) ([]domain.CashFlow, error)
+	NetWorth(ctx context.Context, userID int64, from time.Time, to time.Time,
+		interval domain.Interval) ([]domain.NetWorth, error)
+	Trends(ctx context.Context, filter domain.TransactionsFilter) ([]domain.CategoryTrend, error)
+	AnnualReport(ctx context.Context, userID int64, year int) (domain.AnnualReport, error)
+}
+
+type Forecast interface {
+	Forecast(ctx context.Context, userID int64, date time.Time, months int) (domain.Forecast, error)
+}
+
+type Anomalies interface {
+	Detect(ctx context.Context) (int, error)
+}
+
+type Admin interface {
+	SetDisabled(ctx context.Context, userID int64, disabled bool) (domain.User, error)
+	ResetSessions(ctx context.Context, userID int64) error
+	Stats(ctx context.Context) (domain.SystemStats, error)
 }
 
 type Services struct {
 	Users
 	Auth
+	AccessTokens
+	OIDC
 	Currencies
 	Accounts
 	AccountTypes
 	Transactions
+	Attachments
+	Reconciliations
+	Trash
+	TransactionTemplates
+	Audit
 	TransactionCategories
 	TransactionTypes
 	Stats
+	Forecast
+	Anomalies
+	Admin
 }
 
 func NewServices(repos *repo.Repos, hasher hash.PasswordHasher, tokenManager auth.TokenManager,
-	accessTokenTTL time.Duration, refreshTokenTTL time.Duration, accCfg config.Account) *Services {
+	accessTokenTTL time.Duration, refreshTokenTTL time.Duration, accCfg config.Account, lockout *limiter.Lockout,
+	oidcProviders map[string]*oidc.Provider, blobs storage.BlobStore, attCfg config.Attachments,
+	trashCfg config.Trash) *Services {
+	// Transactions from templates are created as any other transactions
+	transactions := newTransactionsService(repos.Transactions, repos.Accounts, repos.TransactionCategories)
+
 	return &Services{
-		Users:                 newUsersService(repos.Users, hasher),
-		Auth:                  newAuthService(repos.Users, repos.Sessions, hasher, tokenManager, accessTokenTTL, refreshTokenTTL),
+		Users: newUsersService(repos.Users, hasher),
+		Auth: newAuthService(repos.Users, repos.Sessions, repos.UserIdentities, repos.Audit, hasher, tokenManager,
+			accessTokenTTL, refreshTokenTTL, lockout),
+		AccessTokens:          newAccessTokensService(repos.AccessTokens, repos.Users, hasher),
+		OIDC:                  newOIDCService(oidcProviders, repos.OIDCFlows),
 		Currencies:            newCurrenciesService(repos.Currencies),
 		Accounts:              newAccountsService(repos.Accounts, repos.Currencies, accCfg),
 		AccountTypes:          newAccountTypesService(repos.AccountTypes),
-		Transactions:          newTransactionsService(repos.Transactions, repos.Accounts, repos.TransactionCategories),
+		Transactions:          transactions,
+		Attachments:           newAttachmentsService(repos.Attachments, repos.Transactions, blobs, attCfg),
+		Reconciliations:       newReconciliationsService(repos.Reconciliations, repos.Accounts, repos.Balances),
+		Trash:                 newTrashService(repos.Trash, repos.Accounts, blobs, accCfg, trashCfg),
+		Audit:                 newAuditService(repos.Audit),
 		TransactionCategories: newTransactionCategoriesService(repos.TransactionCategories),
 		TransactionTypes:      newTransactionTypesService(repos.TransactionTypes),
 		Stats:                 newStatsService(repos.Accounts, repos.Balances, repos.Transactions),
+		Forecast:              newForecastService(repos.Accounts, repos.Transactions),
+		Anomalies:             newAnomaliesService(repos.Transactions),
+		Admin:                 newAdminService(repos.Users, repos.Sessions, repos.System),
+		TransactionTemplates: newTransactionTemplatesService(repos.TransactionTemplates, repos.Accounts,
+			repos.TransactionCategories, transactions),
 	}
 }
@@ -2,9 +2,14 @@ package service
 
 import (
 	"context"
+	"errors"
 	"github.com/lotostudio/financial-api/internal/domain"
 	"github.com/lotostudio/financial-api/internal/repo"
 	"golang.org/x/sync/errgroup"
+	"math"
+	"sort"
+	"strings"
+	"time"
 )
 
 type StatsService struct {
@@ -21,6 +26,8 @@ func newStatsService(accRepo repo.Accounts, balRepo repo.Balances, transRepo rep
 	}
 }
 
+// Statement returns account's transactions of period with running balance. Category and type of filter narrow listed
+// transactions and totals only, opening balance with all transactions of period is checked against closing balance
 func (s *StatsService) Statement(ctx context.Context, filter domain.TransactionsFilter) (domain.Statement, error) {
 	var acc domain.Account
 	var balIn, balOut domain.Balance
@@ -52,7 +59,8 @@ func (s *StatsService) Statement(ctx context.Context, filter domain.Transactions
 
 	errs.Go(func() error {
 		var err error
-		balOut, err = s.balRepo.Get(ctx, *filter.AccountId, *filter.CreatedTo)
+		// Closing balance includes transactions of last day
+		balOut, err = s.balRepo.Get(ctx, *filter.AccountId, filter.CreatedTo.AddDate(0, 0, 1))
 
 		if err != nil {
 			return err
@@ -62,8 +70,18 @@ func (s *StatsService) Statement(ctx context.Context, filter domain.Transactions
 	})
 
 	errs.Go(func() error {
+		// Running balance needs all transactions of account, filters only select rows of statement
+		all := filter
+		all.Category = nil
+		all.Type = nil
+		all.Tag = nil
+		all.Payee = nil
+		all.Text = nil
+		all.Status = nil
+		all.Flagged = nil
+
 		var err error
-		txs, err = s.transRepo.List(ctx, filter)
+		txs, err = s.transRepo.List(ctx, all)
 
 		if err != nil {
 			return err
@@ -79,10 +97,680 @@ func (s *StatsService) Statement(ctx context.Context, filter domain.Transactions
 	balIn.Date = *filter.CreatedFrom
 	balOut.Date = *filter.CreatedTo
 
-	return domain.Statement{
+	sort.SliceStable(txs, func(i, j int) bool {
+		if txs[i].CreatedAt.Equal(txs[j].CreatedAt) {
+			return txs[i].ID < txs[j].ID
+		}
+
+		return txs[i].CreatedAt.Before(txs[j].CreatedAt)
+	})
+
+	st := domain.Statement{
 		Account:      acc,
 		BalanceIn:    balIn,
 		BalanceOut:   balOut,
-		Transactions: txs,
-	}, nil
+		Transactions: make([]domain.StatementTransaction, 0, len(txs)),
+	}
+
+	balance := balIn.Value
+
+	for _, tx := range txs {
+		in, out := movement(tx, *filter.AccountId)
+		balance += in - out
+
+		if !statementRow(tx, filter) {
+			continue
+		}
+
+		st.Transactions = append(st.Transactions, domain.StatementTransaction{Transaction: tx, Balance: balance})
+
+		switch tx.Type {
+		case domain.Income:
+			st.Totals.Income += in
+		case domain.Expense:
+			st.Totals.Expense += out
+		case domain.Transfer:
+			st.Totals.TransferIn += in
+			st.Totals.TransferOut += out
+		}
+	}
+
+	// Amounts are compared in cents to ignore floating point errors
+	st.Discrepancy = round(balOut.Value - balance)
+	st.Reconciled = st.Discrepancy == 0
+
+	return st, nil
+}
+
+// statementRow reports whether transaction matches filters of statement rows, the same way as repo filters them
+func statementRow(tx domain.Transaction, filter domain.TransactionsFilter) bool {
+	if filter.Category != nil && !tx.HasCategory(*filter.Category) {
+		return false
+	}
+
+	if filter.Type != nil && tx.Type != *filter.Type {
+		return false
+	}
+
+	if filter.Tag != nil && !tx.HasTag(*filter.Tag) {
+		return false
+	}
+
+	if filter.Payee != nil && (tx.Payee == nil || !strings.EqualFold(*tx.Payee, *filter.Payee)) {
+		return false
+	}
+
+	if filter.Text != nil && !containsFold(tx.Description, *filter.Text) && !containsFold(tx.Payee, *filter.Text) {
+		return false
+	}
+
+	if filter.Status != nil && tx.Status != *filter.Status {
+		return false
+	}
+
+	if filter.Flagged != nil && tx.IsFlagged() != *filter.Flagged {
+		return false
+	}
+
+	return true
+}
+
+func containsFold(s *string, substr string) bool {
+	return s != nil && strings.Contains(strings.ToLower(*s), strings.ToLower(substr))
+}
+
+// movement returns amounts of transaction coming in and going out of account
+func movement(tx domain.Transaction, accountID int64) (float64, float64) {
+	var in, out float64
+
+	if tx.Debit != nil && tx.Debit.ID == accountID {
+		in = tx.Amount
+	}
+
+	if tx.Credit != nil && tx.Credit.ID == accountID {
+		out = tx.Amount
+	}
+
+	return in, out
+}
+
+// openedBalance returns balance of account at date, zero if account is opened later
+func (s *StatsService) openedBalance(ctx context.Context, accountID int64, date time.Time) (domain.Balance, error) {
+	balance, err := s.balRepo.Get(ctx, accountID, date)
+
+	if errors.Is(err, repo.ErrBalanceNotFound) {
+		return domain.Balance{}, nil
+	}
+
+	return balance, err
+}
+
+// ConsolidatedStatement returns balances of several accounts and their merged transactions. Accounts are selected by
+// IDs or by type, all of them must belong to owner and have the same currency
+func (s *StatsService) ConsolidatedStatement(ctx context.Context,
+	filter domain.ConsolidatedFilter) (domain.ConsolidatedStatement, error) {
+	accounts, err := s.selectAccounts(ctx, filter)
+
+	if err != nil {
+		return domain.ConsolidatedStatement{}, err
+	}
+
+	balances := make([]domain.AccountBalances, len(accounts))
+	txs := make([][]domain.Transaction, len(accounts))
+
+	errs, gctx := errgroup.WithContext(ctx)
+
+	for i, acc := range accounts {
+		i, acc := i, acc
+		balances[i].Account = acc
+
+		errs.Go(func() error {
+			var err error
+			balances[i].BalanceIn, err = s.openedBalance(gctx, acc.ID, filter.CreatedFrom)
+
+			return err
+		})
+
+		errs.Go(func() error {
+			var err error
+			// Closing balance includes transactions of last day
+			balances[i].BalanceOut, err = s.openedBalance(gctx, acc.ID, filter.CreatedTo.AddDate(0, 0, 1))
+
+			return err
+		})
+
+		errs.Go(func() error {
+			var err error
+			txs[i], err = s.transRepo.List(gctx, domain.TransactionsFilter{
+				AccountId:   &acc.ID,
+				CreatedFrom: &filter.CreatedFrom,
+				CreatedTo:   &filter.CreatedTo,
+			})
+
+			return err
+		})
+	}
+
+	if err = errs.Wait(); err != nil {
+		return domain.ConsolidatedStatement{}, err
+	}
+
+	st := domain.ConsolidatedStatement{
+		Accounts:     balances,
+		Transactions: make([]domain.ConsolidatedTransaction, 0),
+	}
+
+	selected := make(map[int64]bool, len(accounts))
+
+	for i := range balances {
+		balances[i].BalanceIn.Date = filter.CreatedFrom
+		balances[i].BalanceOut.Date = filter.CreatedTo
+		st.BalanceIn += balances[i].BalanceIn.Value
+		st.BalanceOut += balances[i].BalanceOut.Value
+		selected[balances[i].Account.ID] = true
+	}
+
+	// Transfers between selected accounts are listed for both of them
+	merged := make([]domain.Transaction, 0)
+	seen := make(map[int64]bool)
+
+	for _, list := range txs {
+		for _, tx := range list {
+			if !seen[tx.ID] {
+				seen[tx.ID] = true
+				merged = append(merged, tx)
+			}
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].CreatedAt.Equal(merged[j].CreatedAt) {
+			return merged[i].ID < merged[j].ID
+		}
+
+		return merged[i].CreatedAt.Before(merged[j].CreatedAt)
+	})
+
+	balance := st.BalanceIn
+
+	for _, tx := range merged {
+		var in, out float64
+
+		if tx.Debit != nil && selected[tx.Debit.ID] {
+			in = tx.Amount
+		}
+
+		if tx.Credit != nil && selected[tx.Credit.ID] {
+			out = tx.Amount
+		}
+
+		internal := in > 0 && out > 0
+		balance += in - out
+
+		st.Transactions = append(st.Transactions, domain.ConsolidatedTransaction{
+			StatementTransaction: domain.StatementTransaction{Transaction: tx, Balance: balance},
+			Internal:             internal,
+		})
+
+		switch {
+		case internal && filter.ExcludeInternal:
+			// Internal transfers move money between selected accounts only
+		case tx.Type == domain.Income:
+			st.Totals.Income += in
+		case tx.Type == domain.Expense:
+			st.Totals.Expense += out
+		case tx.Type == domain.Transfer:
+			st.Totals.TransferIn += in
+			st.Totals.TransferOut += out
+		}
+	}
+
+	// Amounts are compared in cents to ignore floating point errors
+	st.Discrepancy = round(st.BalanceOut - balance)
+	st.Reconciled = st.Discrepancy == 0
+
+	return st, nil
+}
+
+// selectAccounts returns owner's accounts by IDs or type of filter
+func (s *StatsService) selectAccounts(ctx context.Context, filter domain.ConsolidatedFilter) ([]domain.Account, error) {
+	owned, err := s.accRepo.List(ctx, filter.OwnerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]domain.Account, 0)
+
+	if filter.AccountType != nil {
+		for _, acc := range owned {
+			if acc.Type == *filter.AccountType {
+				accounts = append(accounts, acc)
+			}
+		}
+	} else {
+		byId := make(map[int64]domain.Account, len(owned))
+
+		for _, acc := range owned {
+			byId[acc.ID] = acc
+		}
+
+		added := make(map[int64]bool, len(filter.AccountIds))
+
+		for _, id := range filter.AccountIds {
+			acc, ok := byId[id]
+
+			if !ok {
+				return nil, ErrAccountForbidden
+			}
+
+			if !added[id] {
+				added[id] = true
+				accounts = append(accounts, acc)
+			}
+		}
+	}
+
+	if len(accounts) == 0 {
+		return nil, ErrNoAccountSelected
+	}
+
+	for _, acc := range accounts[1:] {
+		if acc.Currency != accounts[0].Currency {
+			return nil, ErrAccountsHaveDifferenceCurrencies
+		}
+	}
+
+	return accounts, nil
+}
+
+// Maximal count of periods in stats grouped by interval, a bit more than days of year
+const statsMaxPeriods = 400
+
+func (s *StatsService) CashFlow(ctx context.Context, filter domain.TransactionsFilter,
+	interval domain.Interval) ([]domain.CashFlow, error) {
+	if err := interval.Validate(); err != nil {
+		return nil, err
+	}
+
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil &&
+		interval.Periods(*filter.CreatedFrom, *filter.CreatedTo) > statsMaxPeriods {
+		return nil, ErrStatsPeriodsLimited
+	}
+
+	return s.transRepo.CashFlow(ctx, filter, interval)
+}
+
+// NetWorth returns assets, liabilities and their difference on last day of every period
+func (s *StatsService) NetWorth(ctx context.Context, userID int64, from time.Time, to time.Time,
+	interval domain.Interval) ([]domain.NetWorth, error) {
+	if err := interval.Validate(); err != nil {
+		return nil, err
+	}
+
+	if interval.Periods(from, to) > statsMaxPeriods {
+		return nil, ErrStatsPeriodsLimited
+	}
+
+	balances, err := s.balRepo.History(ctx, userID, from, to, interval)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Balances are ordered by dates
+	worths := make([]domain.NetWorth, 0)
+
+	for _, b := range balances {
+		if len(worths) == 0 || !worths[len(worths)-1].Date.Equal(b.Date) {
+			worths = append(worths, domain.NetWorth{
+				Date:  b.Date,
+				Types: make(map[domain.AccountType]float64),
+			})
+		}
+
+		w := &worths[len(worths)-1]
+		w.Types[b.Type] += b.Value
+
+		if b.Type.IsLiability() {
+			w.Liabilities += b.Value
+		} else {
+			w.Assets += b.Value
+		}
+
+		w.Total = w.Assets - w.Liabilities
+	}
+
+	return worths, nil
+}
+
+// Trends compares sums of categories for period of filter with previous period and the same period last year,
+// and adds rolling monthly averages
+func (s *StatsService) Trends(ctx context.Context, filter domain.TransactionsFilter) ([]domain.CategoryTrend, error) {
+	from, to := *filter.CreatedFrom, *filter.CreatedTo
+	prevFrom, prevTo := previousPeriod(from, to)
+
+	periods := [][2]time.Time{
+		{from, to},
+		{prevFrom, prevTo},
+		{from.AddDate(-1, 0, 0), to.AddDate(-1, 0, 0)},
+	}
+
+	months := []int{3, 6, 12}
+
+	// Rolling periods start at first day of month, so the month of period end is the last one
+	for _, m := range months {
+		periods = append(periods, [2]time.Time{
+			time.Date(to.Year(), to.Month()-time.Month(m-1), 1, 0, 0, 0, 0, to.Location()),
+			to,
+		})
+	}
+
+	sums := make([]map[string]float64, len(periods))
+
+	errs, gctx := errgroup.WithContext(ctx)
+
+	for i, period := range periods {
+		i, period := i, period
+
+		errs.Go(func() error {
+			f := filter
+			f.CreatedFrom, f.CreatedTo = &period[0], &period[1]
+
+			stats, err := s.transRepo.Stats(gctx, f, domain.GroupByCategory)
+
+			if err != nil {
+				return err
+			}
+
+			sums[i] = make(map[string]float64, len(stats))
+
+			for _, st := range stats {
+				sums[i][st.Group] = st.Sum
+			}
+
+			return nil
+		})
+	}
+
+	if err := errs.Wait(); err != nil {
+		return nil, err
+	}
+
+	categories := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, m := range sums {
+		for category := range m {
+			if !seen[category] {
+				seen[category] = true
+				categories = append(categories, category)
+			}
+		}
+	}
+
+	trends := make([]domain.CategoryTrend, 0, len(categories))
+
+	for _, category := range categories {
+		t := domain.CategoryTrend{
+			Category:    category,
+			Current:     sums[0][category],
+			Previous:    sums[1][category],
+			LastYear:    sums[2][category],
+			Avg3Months:  round(sums[3][category] / float64(months[0])),
+			Avg6Months:  round(sums[4][category] / float64(months[1])),
+			Avg12Months: round(sums[5][category] / float64(months[2])),
+		}
+
+		t.Change, t.ChangePercent = change(t.Current, t.Previous)
+		t.LastYearChange, t.LastYearChangePercent = change(t.Current, t.LastYear)
+
+		trends = append(trends, t)
+	}
+
+	sort.Slice(trends, func(i, j int) bool {
+		if trends[i].Current == trends[j].Current {
+			return trends[i].Category < trends[j].Category
+		}
+
+		return trends[i].Current > trends[j].Current
+	})
+
+	return trends, nil
+}
+
+// Count of largest transactions in annual report
+const annualLargestCount = 10
+
+// AnnualReport summarizes incomes and expenses of user for year, change of net worth and interest of deposits and loans
+func (s *StatsService) AnnualReport(ctx context.Context, userID int64, year int) (domain.AnnualReport, error) {
+	if year < 1 || year > 9999 {
+		return domain.AnnualReport{}, ErrReportYearInvalid
+	}
+
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	filter := domain.TransactionsFilter{OwnerId: &userID, CreatedFrom: &from, CreatedTo: &to}
+	income, expense := domain.Income, domain.Expense
+
+	report := domain.AnnualReport{Year: year}
+
+	var transactions []domain.Transaction
+	var worths []domain.NetWorth
+	var accounts []domain.Account
+
+	errs, gctx := errgroup.WithContext(ctx)
+
+	errs.Go(func() error {
+		f := filter
+		f.Type = &income
+
+		var err error
+		report.IncomeByCategory, err = s.transRepo.Stats(gctx, f, domain.GroupByCategory)
+
+		return err
+	})
+
+	errs.Go(func() error {
+		f := filter
+		f.Type = &expense
+
+		var err error
+		report.ExpenseByCategory, err = s.transRepo.Stats(gctx, f, domain.GroupByCategory)
+
+		return err
+	})
+
+	errs.Go(func() error {
+		var err error
+		report.Months, err = s.transRepo.CashFlow(gctx, filter, domain.Month)
+
+		return err
+	})
+
+	errs.Go(func() error {
+		var err error
+		transactions, err = s.transRepo.List(gctx, filter)
+
+		return err
+	})
+
+	errs.Go(func() error {
+		var err error
+		// Periods of year end on last day of previous year and of report year
+		worths, err = s.NetWorth(gctx, userID, from.AddDate(0, 0, -1), to, domain.Year)
+
+		return err
+	})
+
+	errs.Go(func() error {
+		var err error
+		accounts, err = s.accRepo.List(gctx, userID)
+
+		return err
+	})
+
+	if err := errs.Wait(); err != nil {
+		return domain.AnnualReport{}, err
+	}
+
+	for _, st := range report.IncomeByCategory {
+		report.Income += st.Sum
+	}
+
+	for _, st := range report.ExpenseByCategory {
+		report.Expense += st.Sum
+	}
+
+	report.Income, report.Expense = round(report.Income), round(report.Expense)
+
+	if report.Income != 0 {
+		rate := round((report.Income - report.Expense) / report.Income * 100)
+		report.SavingsRate = &rate
+	}
+
+	report.LargestTransactions = largestTransactions(transactions, annualLargestCount)
+
+	for _, w := range worths {
+		if w.Date.Equal(from.AddDate(0, 0, -1)) {
+			report.NetWorthStart = w
+		}
+
+		if w.Date.Equal(to) {
+			report.NetWorthEnd = w
+		}
+	}
+
+	report.NetWorthChange = round(report.NetWorthEnd.Total - report.NetWorthStart.Total)
+
+	interest, err := s.interest(ctx, accounts, from, to)
+
+	if err != nil {
+		return domain.AnnualReport{}, err
+	}
+
+	report.Interest = interest
+
+	for _, i := range interest {
+		if i.Account.Type.IsLiability() {
+			report.InterestPaid += i.Interest
+		} else {
+			report.InterestEarned += i.Interest
+		}
+	}
+
+	report.InterestPaid, report.InterestEarned = round(report.InterestPaid), round(report.InterestEarned)
+
+	return report, nil
+}
+
+// largestTransactions returns incomes and expenses with largest amounts, latest first among equal ones
+func largestTransactions(transactions []domain.Transaction, count int) []domain.Transaction {
+	largest := make([]domain.Transaction, 0, len(transactions))
+
+	for _, tx := range transactions {
+		if tx.Type != domain.Transfer {
+			largest = append(largest, tx)
+		}
+	}
+
+	sort.SliceStable(largest, func(i, j int) bool {
+		if largest[i].Amount == largest[j].Amount {
+			return largest[i].CreatedAt.After(largest[j].CreatedAt)
+		}
+
+		return largest[i].Amount > largest[j].Amount
+	})
+
+	if len(largest) > count {
+		largest = largest[:count]
+	}
+
+	return largest
+}
+
+// interest estimates interest of deposits and loans for months of period started till now by monthly rate on balance
+// for start of every month
+func (s *StatsService) interest(ctx context.Context, accounts []domain.Account, from time.Time,
+	to time.Time) ([]domain.AccountInterest, error) {
+	until := to.AddDate(0, 0, 1)
+
+	if now := time.Now(); now.Before(until) {
+		until = now
+	}
+
+	interest := make([]domain.AccountInterest, 0)
+
+	for _, acc := range accounts {
+		if (acc.Type == domain.Deposit || acc.Type == domain.Loan) && acc.Rate != nil {
+			interest = append(interest, domain.AccountInterest{Account: acc})
+		}
+	}
+
+	errs, gctx := errgroup.WithContext(ctx)
+
+	for i := range interest {
+		i := i
+
+		errs.Go(func() error {
+			rate := float64(*interest[i].Account.Rate) / 100 / 12
+
+			for month := from; month.Before(until); month = month.AddDate(0, 1, 0) {
+				balance, err := s.balRepo.Get(gctx, interest[i].Account.ID, month)
+
+				// Account is opened later
+				if errors.Is(err, repo.ErrBalanceNotFound) {
+					continue
+				}
+
+				if err != nil {
+					return err
+				}
+
+				interest[i].Interest += balance.Value * rate
+			}
+
+			interest[i].Interest = round(interest[i].Interest)
+
+			return nil
+		})
+	}
+
+	if err := errs.Wait(); err != nil {
+		return nil, err
+	}
+
+	return interest, nil
+}
+
+// previousPeriod returns period of the same length right before given one.
+// Periods of whole months are shifted by months, e.g. previous of February is January
+func previousPeriod(from time.Time, to time.Time) (time.Time, time.Time) {
+	if from.Day() == 1 && to.AddDate(0, 0, 1).Day() == 1 {
+		months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+		prevFrom := from.AddDate(0, -months, 0)
+
+		return prevFrom, from.AddDate(0, 0, -1)
+	}
+
+	days := int(to.Sub(from).Hours()/24) + 1
+
+	return from.AddDate(0, 0, -days), from.AddDate(0, 0, -1)
+}
+
+// change returns difference of values and its percentage of previous value, rounded to cents
+func change(current float64, previous float64) (float64, *float64) {
+	diff := round(current - previous)
+
+	if previous == 0 {
+		return diff, nil
+	}
+
+	percent := round((current - previous) / previous * 100)
+
+	return diff, &percent
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/golang/mock/gomock"
 	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
 	mockRepo "github.com/lotostudio/financial-api/internal/repo/mocks"
 	"github.com/stretchr/testify/require"
 	"testing"
@@ -40,11 +41,582 @@ func TestStatsService_Statement(t *testing.T) {
 
 	aRepo.EXPECT().Get(gomock.Any(), *filter.AccountId).Return(domain.Account{}, nil)
 	bRepo.EXPECT().Get(gomock.Any(), *filter.AccountId, *filter.CreatedFrom).Return(domain.Balance{}, nil)
-	bRepo.EXPECT().Get(gomock.Any(), *filter.AccountId, *filter.CreatedTo).Return(domain.Balance{}, nil)
+	bRepo.EXPECT().Get(gomock.Any(), *filter.AccountId, filter.CreatedTo.AddDate(0, 0, 1)).Return(domain.Balance{}, nil)
 	tRepo.EXPECT().List(gomock.Any(), filter).Return([]domain.Transaction{}, nil)
 
 	st, err := s.Statement(ctx, filter)
 
 	require.NoError(t, err)
 	require.IsType(t, domain.Statement{}, st)
+	require.True(t, st.Reconciled)
+}
+
+func TestStatsService_StatementRunningBalance(t *testing.T) {
+	s, aRepo, bRepo, tRepo := mockStatsService(t)
+
+	acc, other := &domain.Account{ID: 1}, &domain.Account{ID: 2}
+	food, salary := "food", "salary"
+	dateFrom := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	dateTo := time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC)
+	expense := domain.Expense
+
+	ctx := context.Background()
+	filter := domain.TransactionsFilter{
+		AccountId:   &acc.ID,
+		Type:        &expense,
+		CreatedFrom: &dateFrom,
+		CreatedTo:   &dateTo,
+	}
+
+	all := filter
+	all.Type = nil
+
+	txs := []domain.Transaction{
+		{ID: 4, Amount: 30, Type: domain.Transfer, CreatedAt: dateFrom.AddDate(0, 0, 9), Credit: acc, Debit: other},
+		{ID: 3, Amount: 20, Type: domain.Expense, Category: &food, CreatedAt: dateFrom.AddDate(0, 0, 2), Credit: acc},
+		{ID: 2, Amount: 500, Type: domain.Income, Category: &salary, CreatedAt: dateFrom, Debit: acc},
+		{ID: 1, Amount: 10, Type: domain.Expense, Category: &food, CreatedAt: dateFrom, Credit: acc},
+	}
+
+	aRepo.EXPECT().Get(gomock.Any(), acc.ID).Return(*acc, nil)
+	bRepo.EXPECT().Get(gomock.Any(), acc.ID, dateFrom).Return(domain.Balance{Value: 100}, nil)
+	bRepo.EXPECT().Get(gomock.Any(), acc.ID, dateTo.AddDate(0, 0, 1)).Return(domain.Balance{Value: 545.5}, nil)
+	tRepo.EXPECT().List(gomock.Any(), all).Return(txs, nil)
+
+	expected := []domain.StatementTransaction{
+		{Transaction: txs[3], Balance: 90},
+		{Transaction: txs[1], Balance: 570},
+	}
+
+	st, err := s.Statement(ctx, filter)
+
+	require.NoError(t, err)
+	require.Equal(t, expected, st.Transactions)
+	require.Equal(t, domain.StatementTotals{Expense: 30}, st.Totals)
+	require.False(t, st.Reconciled)
+	require.Equal(t, 5.5, st.Discrepancy)
+}
+
+func TestStatsService_StatementFilteredByTag(t *testing.T) {
+	s, aRepo, bRepo, tRepo := mockStatsService(t)
+
+	acc := &domain.Account{ID: 1}
+	dateFrom := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	dateTo := time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC)
+	tag, payee, text := "Work", "navat", "dinner"
+	description, place := "Dinner with team", "Navat"
+
+	ctx := context.Background()
+	filter := domain.TransactionsFilter{
+		AccountId:   &acc.ID,
+		CreatedFrom: &dateFrom,
+		CreatedTo:   &dateTo,
+		Tag:         &tag,
+		Payee:       &payee,
+		Text:        &text,
+	}
+
+	all := filter
+	all.Tag = nil
+	all.Payee = nil
+	all.Text = nil
+
+	txs := []domain.Transaction{
+		{ID: 1, Amount: 500, Type: domain.Income, CreatedAt: dateFrom, Debit: acc},
+		{ID: 2, Amount: 20, Type: domain.Expense, CreatedAt: dateFrom, Credit: acc, Tags: []string{"work"},
+			Payee: &place, Description: &description},
+		{ID: 3, Amount: 30, Type: domain.Expense, CreatedAt: dateFrom, Credit: acc, Tags: []string{"work"}},
+	}
+
+	aRepo.EXPECT().Get(gomock.Any(), acc.ID).Return(*acc, nil)
+	bRepo.EXPECT().Get(gomock.Any(), acc.ID, dateFrom).Return(domain.Balance{Value: 100}, nil)
+	bRepo.EXPECT().Get(gomock.Any(), acc.ID, dateTo.AddDate(0, 0, 1)).Return(domain.Balance{Value: 550}, nil)
+	tRepo.EXPECT().List(gomock.Any(), all).Return(txs, nil)
+
+	st, err := s.Statement(ctx, filter)
+
+	require.NoError(t, err)
+	require.Equal(t, []domain.StatementTransaction{{Transaction: txs[1], Balance: 580}}, st.Transactions)
+	require.Equal(t, domain.StatementTotals{Expense: 20}, st.Totals)
+	require.True(t, st.Reconciled)
+}
+
+func TestStatsService_StatementFilteredByStatus(t *testing.T) {
+	s, aRepo, bRepo, tRepo := mockStatsService(t)
+
+	acc := &domain.Account{ID: 1}
+	dateFrom := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	dateTo := time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC)
+	pending := domain.Pending
+
+	ctx := context.Background()
+	filter := domain.TransactionsFilter{
+		AccountId:   &acc.ID,
+		CreatedFrom: &dateFrom,
+		CreatedTo:   &dateTo,
+		Status:      &pending,
+	}
+
+	all := filter
+	all.Status = nil
+
+	txs := []domain.Transaction{
+		{ID: 1, Amount: 500, Type: domain.Income, CreatedAt: dateFrom, Debit: acc, Status: domain.Cleared},
+		{ID: 2, Amount: 20, Type: domain.Expense, CreatedAt: dateFrom, Credit: acc, Status: domain.Pending},
+	}
+
+	aRepo.EXPECT().Get(gomock.Any(), acc.ID).Return(*acc, nil)
+	bRepo.EXPECT().Get(gomock.Any(), acc.ID, dateFrom).Return(domain.Balance{Value: 100}, nil)
+	bRepo.EXPECT().Get(gomock.Any(), acc.ID, dateTo.AddDate(0, 0, 1)).Return(domain.Balance{Value: 580}, nil)
+	tRepo.EXPECT().List(gomock.Any(), all).Return(txs, nil)
+
+	st, err := s.Statement(ctx, filter)
+
+	require.NoError(t, err)
+	require.Equal(t, []domain.StatementTransaction{{Transaction: txs[1], Balance: 580}}, st.Transactions)
+	require.True(t, st.Reconciled)
+}
+
+func TestStatsService_StatementFlagged(t *testing.T) {
+	s, aRepo, bRepo, tRepo := mockStatsService(t)
+
+	acc := &domain.Account{ID: 1}
+	dateFrom := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	dateTo := time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC)
+	flagged := true
+
+	ctx := context.Background()
+	filter := domain.TransactionsFilter{
+		AccountId:   &acc.ID,
+		CreatedFrom: &dateFrom,
+		CreatedTo:   &dateTo,
+		Flagged:     &flagged,
+	}
+
+	all := filter
+	all.Flagged = nil
+
+	txs := []domain.Transaction{
+		{ID: 1, Amount: 500, Type: domain.Income, CreatedAt: dateFrom, Debit: acc},
+		{ID: 2, Amount: 20, Type: domain.Expense, CreatedAt: dateFrom, Credit: acc,
+			Flags: []domain.TransactionFlag{domain.FlagUnusualAmount}},
+		{ID: 3, Amount: 30, Type: domain.Expense, CreatedAt: dateFrom, Credit: acc,
+			Flags: []domain.TransactionFlag{domain.FlagNewCategory}, FlagsDismissed: true},
+	}
+
+	aRepo.EXPECT().Get(gomock.Any(), acc.ID).Return(*acc, nil)
+	bRepo.EXPECT().Get(gomock.Any(), acc.ID, dateFrom).Return(domain.Balance{Value: 100}, nil)
+	bRepo.EXPECT().Get(gomock.Any(), acc.ID, dateTo.AddDate(0, 0, 1)).Return(domain.Balance{Value: 550}, nil)
+	tRepo.EXPECT().List(gomock.Any(), all).Return(txs, nil)
+
+	st, err := s.Statement(ctx, filter)
+
+	require.NoError(t, err)
+	require.Equal(t, []domain.StatementTransaction{{Transaction: txs[1], Balance: 580}}, st.Transactions)
+	require.True(t, st.Reconciled)
+}
+
+func TestStatsService_CashFlow(t *testing.T) {
+	s, _, _, tRepo := mockStatsService(t)
+
+	ctx := context.Background()
+	filter := domain.TransactionsFilter{OwnerId: &userId}
+
+	tRepo.EXPECT().CashFlow(ctx, filter, domain.Week).Return([]domain.CashFlow{{Income: 10, Net: 10}}, nil)
+
+	flows, err := s.CashFlow(ctx, filter, domain.Week)
+
+	require.NoError(t, err)
+	require.Len(t, flows, 1)
+}
+
+func TestStatsService_CashFlowErrInterval(t *testing.T) {
+	s, _, _, _ := mockStatsService(t)
+
+	_, err := s.CashFlow(context.Background(), domain.TransactionsFilter{}, "hour")
+
+	require.ErrorIs(t, err, domain.ErrInvalidInterval)
+}
+
+func TestStatsService_CashFlowErrPeriods(t *testing.T) {
+	s, _, _, _ := mockStatsService(t)
+
+	from := time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	_, err := s.CashFlow(context.Background(), domain.TransactionsFilter{CreatedFrom: &from, CreatedTo: &to}, domain.Day)
+
+	require.ErrorIs(t, err, ErrStatsPeriodsLimited)
+}
+
+func TestStatsService_NetWorth(t *testing.T) {
+	s, _, bRepo, _ := mockStatsService(t)
+
+	ctx := context.Background()
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 2, 28, 0, 0, 0, 0, time.UTC)
+	jan := time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	bRepo.EXPECT().History(ctx, userId, from, to, domain.Month).Return([]domain.TypeBalance{
+		{Date: jan, Type: domain.Card, Value: 1000},
+		{Date: jan, Type: domain.Loan, Value: 400},
+		{Date: to, Type: domain.Card, Value: 700},
+		{Date: to, Type: domain.Deposit, Value: 500},
+		{Date: to, Type: domain.Loan, Value: 300},
+	}, nil)
+
+	worths, err := s.NetWorth(ctx, userId, from, to, domain.Month)
+
+	require.NoError(t, err)
+	require.Equal(t, []domain.NetWorth{
+		{
+			Date:        jan,
+			Assets:      1000,
+			Liabilities: 400,
+			Total:       600,
+			Types:       map[domain.AccountType]float64{domain.Card: 1000, domain.Loan: 400},
+		},
+		{
+			Date:        to,
+			Assets:      1200,
+			Liabilities: 300,
+			Total:       900,
+			Types:       map[domain.AccountType]float64{domain.Card: 700, domain.Deposit: 500, domain.Loan: 300},
+		},
+	}, worths)
+}
+
+func TestStatsService_NetWorthErrInterval(t *testing.T) {
+	s, _, _, _ := mockStatsService(t)
+
+	_, err := s.NetWorth(context.Background(), userId, time.Now(), time.Now(), "hour")
+
+	require.ErrorIs(t, err, domain.ErrInvalidInterval)
+}
+
+func TestStatsService_NetWorthErrPeriods(t *testing.T) {
+	s, _, _, _ := mockStatsService(t)
+
+	from := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := s.NetWorth(context.Background(), userId, from, to, domain.Week)
+
+	require.ErrorIs(t, err, ErrStatsPeriodsLimited)
+}
+
+func TestStatsService_ConsolidatedStatement(t *testing.T) {
+	card := domain.Account{ID: 1, Type: domain.Card, Currency: "KZT"}
+	cash := domain.Account{ID: 2, Type: domain.Cash, Currency: "KZT"}
+	other := domain.Account{ID: 3, Type: domain.Card, Currency: "KZT"}
+	food := "food"
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	internal := domain.Transaction{ID: 3, Amount: 50, Type: domain.Transfer, CreatedAt: from.AddDate(0, 0, 2),
+		Credit: &card, Debit: &cash}
+	expense := domain.Transaction{ID: 2, Amount: 20, Type: domain.Expense, Category: &food, CreatedAt: from.AddDate(0, 0, 5),
+		Credit: &cash}
+	external := domain.Transaction{ID: 1, Amount: 100, Type: domain.Transfer, CreatedAt: from, Credit: &other, Debit: &card}
+
+	tests := []struct {
+		name            string
+		excludeInternal bool
+		expectedTotals  domain.StatementTotals
+	}{
+		{
+			name:           "with internal",
+			expectedTotals: domain.StatementTotals{Expense: 20, TransferIn: 150, TransferOut: 50},
+		},
+		{
+			name:            "without internal",
+			excludeInternal: true,
+			expectedTotals:  domain.StatementTotals{Expense: 20, TransferIn: 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, aRepo, bRepo, tRepo := mockStatsService(t)
+
+			aRepo.EXPECT().List(gomock.Any(), userId).Return([]domain.Account{card, cash, other}, nil)
+			bRepo.EXPECT().Get(gomock.Any(), card.ID, from).Return(domain.Balance{Value: 500}, nil)
+			bRepo.EXPECT().Get(gomock.Any(), card.ID, to.AddDate(0, 0, 1)).Return(domain.Balance{Value: 550}, nil)
+			bRepo.EXPECT().Get(gomock.Any(), cash.ID, from).Return(domain.Balance{Value: 10}, nil)
+			bRepo.EXPECT().Get(gomock.Any(), cash.ID, to.AddDate(0, 0, 1)).Return(domain.Balance{Value: 40}, nil)
+			tRepo.EXPECT().List(gomock.Any(), domain.TransactionsFilter{AccountId: &card.ID, CreatedFrom: &from,
+				CreatedTo: &to}).Return([]domain.Transaction{internal, external}, nil)
+			tRepo.EXPECT().List(gomock.Any(), domain.TransactionsFilter{AccountId: &cash.ID, CreatedFrom: &from,
+				CreatedTo: &to}).Return([]domain.Transaction{expense, internal}, nil)
+
+			st, err := s.ConsolidatedStatement(context.Background(), domain.ConsolidatedFilter{
+				OwnerId:         userId,
+				AccountIds:      []int64{card.ID, cash.ID},
+				CreatedFrom:     from,
+				CreatedTo:       to,
+				ExcludeInternal: tt.excludeInternal,
+			})
+
+			require.NoError(t, err)
+			require.Len(t, st.Accounts, 2)
+			require.Equal(t, float64(510), st.BalanceIn)
+			require.Equal(t, float64(590), st.BalanceOut)
+			require.Equal(t, []domain.ConsolidatedTransaction{
+				{StatementTransaction: domain.StatementTransaction{Transaction: external, Balance: 610}},
+				{StatementTransaction: domain.StatementTransaction{Transaction: internal, Balance: 610}, Internal: true},
+				{StatementTransaction: domain.StatementTransaction{Transaction: expense, Balance: 590}},
+			}, st.Transactions)
+			require.Equal(t, tt.expectedTotals, st.Totals)
+			require.True(t, st.Reconciled)
+		})
+	}
+}
+
+func TestStatsService_ConsolidatedStatementOpenedLater(t *testing.T) {
+	s, aRepo, bRepo, tRepo := mockStatsService(t)
+
+	card := domain.Account{ID: 1, Type: domain.Card, Currency: "KZT"}
+	cash := domain.Account{ID: 2, Type: domain.Cash, Currency: "KZT"}
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC)
+	// Cash is opened inside period, so it has no balance before it
+	income := domain.Transaction{ID: 1, Amount: 30, Type: domain.Income, CreatedAt: from.AddDate(0, 0, 10),
+		Debit: &cash}
+
+	aRepo.EXPECT().List(gomock.Any(), userId).Return([]domain.Account{card, cash}, nil)
+	bRepo.EXPECT().Get(gomock.Any(), card.ID, from).Return(domain.Balance{Value: 500}, nil)
+	bRepo.EXPECT().Get(gomock.Any(), card.ID, to.AddDate(0, 0, 1)).Return(domain.Balance{Value: 500}, nil)
+	bRepo.EXPECT().Get(gomock.Any(), cash.ID, from).Return(domain.Balance{}, repo.ErrBalanceNotFound)
+	bRepo.EXPECT().Get(gomock.Any(), cash.ID, to.AddDate(0, 0, 1)).Return(domain.Balance{Value: 30}, nil)
+	tRepo.EXPECT().List(gomock.Any(), domain.TransactionsFilter{AccountId: &card.ID, CreatedFrom: &from,
+		CreatedTo: &to}).Return([]domain.Transaction{}, nil)
+	tRepo.EXPECT().List(gomock.Any(), domain.TransactionsFilter{AccountId: &cash.ID, CreatedFrom: &from,
+		CreatedTo: &to}).Return([]domain.Transaction{income}, nil)
+
+	st, err := s.ConsolidatedStatement(context.Background(), domain.ConsolidatedFilter{
+		OwnerId:     userId,
+		AccountIds:  []int64{card.ID, cash.ID},
+		CreatedFrom: from,
+		CreatedTo:   to,
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, float64(0), st.Accounts[1].BalanceIn.Value)
+	require.Equal(t, from, st.Accounts[1].BalanceIn.Date)
+	require.Equal(t, float64(500), st.BalanceIn)
+	require.Equal(t, float64(530), st.BalanceOut)
+	require.True(t, st.Reconciled)
+}
+
+func TestStatsService_ConsolidatedStatementErrors(t *testing.T) {
+	card := domain.Account{ID: 1, Type: domain.Card, Currency: "KZT"}
+	usd := domain.Account{ID: 2, Type: domain.Card, Currency: "USD"}
+	loan := domain.Loan
+
+	tests := []struct {
+		name        string
+		filter      domain.ConsolidatedFilter
+		expectedErr error
+	}{
+		{
+			name:        "not owned",
+			filter:      domain.ConsolidatedFilter{OwnerId: userId, AccountIds: []int64{card.ID, 5}},
+			expectedErr: ErrAccountForbidden,
+		},
+		{
+			name:        "no accounts of type",
+			filter:      domain.ConsolidatedFilter{OwnerId: userId, AccountType: &loan},
+			expectedErr: ErrNoAccountSelected,
+		},
+		{
+			name:        "different currencies",
+			filter:      domain.ConsolidatedFilter{OwnerId: userId, AccountIds: []int64{card.ID, usd.ID}},
+			expectedErr: ErrAccountsHaveDifferenceCurrencies,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, aRepo, _, _ := mockStatsService(t)
+
+			aRepo.EXPECT().List(gomock.Any(), userId).Return([]domain.Account{card, usd}, nil)
+
+			_, err := s.ConsolidatedStatement(context.Background(), tt.filter)
+
+			require.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestStatsService_Trends(t *testing.T) {
+	s, _, _, tRepo := mockStatsService(t)
+
+	expense := domain.Expense
+	from := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 3, 31, 0, 0, 0, 0, time.UTC)
+	filter := domain.TransactionsFilter{OwnerId: &userId, Type: &expense, CreatedFrom: &from, CreatedTo: &to}
+
+	period := func(from, to time.Time) domain.TransactionsFilter {
+		f := filter
+		f.CreatedFrom, f.CreatedTo = &from, &to
+
+		return f
+	}
+
+	tRepo.EXPECT().Stats(gomock.Any(), period(from, to), domain.GroupByCategory).Return([]domain.TransactionStat{
+		{Group: "food", Sum: 123},
+		{Group: "taxi", Sum: 40},
+	}, nil)
+	tRepo.EXPECT().Stats(gomock.Any(), period(time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2022, 2, 28, 0, 0, 0, 0, time.UTC)), domain.GroupByCategory).Return([]domain.TransactionStat{
+		{Group: "food", Sum: 100},
+	}, nil)
+	tRepo.EXPECT().Stats(gomock.Any(), period(time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, 3, 31, 0, 0, 0, 0, time.UTC)), domain.GroupByCategory).Return([]domain.TransactionStat{
+		{Group: "food", Sum: 150},
+		{Group: "rent", Sum: 500},
+	}, nil)
+	tRepo.EXPECT().Stats(gomock.Any(), period(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), to),
+		domain.GroupByCategory).Return([]domain.TransactionStat{{Group: "food", Sum: 300}}, nil)
+	tRepo.EXPECT().Stats(gomock.Any(), period(time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC), to),
+		domain.GroupByCategory).Return([]domain.TransactionStat{{Group: "food", Sum: 600}}, nil)
+	tRepo.EXPECT().Stats(gomock.Any(), period(time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC), to),
+		domain.GroupByCategory).Return([]domain.TransactionStat{{Group: "food", Sum: 1200}}, nil)
+
+	trends, err := s.Trends(context.Background(), filter)
+
+	require.NoError(t, err)
+
+	percent := func(v float64) *float64 {
+		return &v
+	}
+
+	require.Equal(t, []domain.CategoryTrend{
+		{
+			Category:              "food",
+			Current:               123,
+			Previous:              100,
+			Change:                23,
+			ChangePercent:         percent(23),
+			LastYear:              150,
+			LastYearChange:        -27,
+			LastYearChangePercent: percent(-18),
+			Avg3Months:            100,
+			Avg6Months:            100,
+			Avg12Months:           100,
+		},
+		{
+			Category:       "taxi",
+			Current:        40,
+			Change:         40,
+			LastYearChange: 40,
+		},
+		{
+			Category:              "rent",
+			LastYear:              500,
+			LastYearChange:        -500,
+			LastYearChangePercent: percent(-100),
+		},
+	}, trends)
+}
+
+func TestPreviousPeriod(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		prevFrom time.Time
+		prevTo   time.Time
+	}{
+		{"month", date(2022, 3, 1), date(2022, 3, 31), date(2022, 2, 1), date(2022, 2, 28)},
+		{"quarter", date(2022, 1, 1), date(2022, 3, 31), date(2021, 10, 1), date(2021, 12, 31)},
+		{"days", date(2022, 3, 10), date(2022, 3, 16), date(2022, 3, 3), date(2022, 3, 9)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prevFrom, prevTo := previousPeriod(tt.from, tt.to)
+
+			require.Equal(t, tt.prevFrom, prevFrom)
+			require.Equal(t, tt.prevTo, prevTo)
+		})
+	}
+}
+
+func TestStatsService_AnnualReport(t *testing.T) {
+	s, aRepo, bRepo, tRepo := mockStatsService(t)
+
+	from := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC)
+	start := from.AddDate(0, 0, -1)
+	income, expense := domain.Income, domain.Expense
+	filter := domain.TransactionsFilter{OwnerId: &userId, CreatedFrom: &from, CreatedTo: &to}
+	incomeFilter, expenseFilter := filter, filter
+	incomeFilter.Type, expenseFilter.Type = &income, &expense
+
+	depositRate, loanRate := float32(12), float32(24)
+	card := domain.Account{ID: 1, Type: domain.Card}
+	deposit := domain.Account{ID: 2, Type: domain.Deposit, Rate: &depositRate}
+	loan := domain.Account{ID: 3, Type: domain.Loan, Rate: &loanRate}
+
+	transactions := make([]domain.Transaction, 0)
+
+	for i := 1; i <= 12; i++ {
+		transactions = append(transactions, domain.Transaction{ID: int64(i), Amount: float64(i * 10),
+			Type: domain.Expense, CreatedAt: time.Date(2021, time.Month(i), 1, 0, 0, 0, 0, time.UTC)})
+	}
+
+	transactions = append(transactions, domain.Transaction{ID: 13, Amount: 5000, Type: domain.Transfer, CreatedAt: from})
+
+	tRepo.EXPECT().Stats(gomock.Any(), incomeFilter, domain.GroupByCategory).Return([]domain.TransactionStat{
+		{Group: "salary", Sum: 1000, Count: 12},
+	}, nil)
+	tRepo.EXPECT().Stats(gomock.Any(), expenseFilter, domain.GroupByCategory).Return([]domain.TransactionStat{
+		{Group: "food", Sum: 600, Count: 30},
+		{Group: "rent", Sum: 150, Count: 12},
+	}, nil)
+	tRepo.EXPECT().CashFlow(gomock.Any(), filter, domain.Month).Return([]domain.CashFlow{}, nil)
+	tRepo.EXPECT().List(gomock.Any(), filter).Return(transactions, nil)
+	bRepo.EXPECT().History(gomock.Any(), userId, start, to, domain.Year).Return([]domain.TypeBalance{
+		{Date: start, Type: domain.Card, Value: 1000},
+		{Date: to, Type: domain.Card, Value: 1500},
+		{Date: to, Type: domain.Deposit, Value: 1200},
+		{Date: to, Type: domain.Loan, Value: 600},
+	}, nil)
+	aRepo.EXPECT().List(gomock.Any(), userId).Return([]domain.Account{card, deposit, loan}, nil)
+	bRepo.EXPECT().Get(gomock.Any(), deposit.ID, from).Return(domain.Balance{}, repo.ErrBalanceNotFound)
+	bRepo.EXPECT().Get(gomock.Any(), deposit.ID, gomock.Any()).Return(domain.Balance{Value: 1200}, nil).Times(11)
+	bRepo.EXPECT().Get(gomock.Any(), loan.ID, gomock.Any()).Return(domain.Balance{Value: 600}, nil).Times(12)
+
+	report, err := s.AnnualReport(context.Background(), userId, 2021)
+
+	require.NoError(t, err)
+	require.Equal(t, 2021, report.Year)
+	require.Equal(t, float64(1000), report.Income)
+	require.Equal(t, float64(750), report.Expense)
+	require.NotNil(t, report.SavingsRate)
+	require.Equal(t, float64(25), *report.SavingsRate)
+	require.Len(t, report.LargestTransactions, annualLargestCount)
+	require.Equal(t, int64(12), report.LargestTransactions[0].ID)
+	require.Equal(t, int64(3), report.LargestTransactions[9].ID)
+	require.Equal(t, float64(1000), report.NetWorthStart.Total)
+	require.Equal(t, float64(2100), report.NetWorthEnd.Total)
+	require.Equal(t, float64(1100), report.NetWorthChange)
+	require.Equal(t, float64(132), report.InterestEarned)
+	require.Equal(t, float64(144), report.InterestPaid)
+	require.Equal(t, []domain.AccountInterest{
+		{Account: deposit, Interest: 132},
+		{Account: loan, Interest: 144},
+	}, report.Interest)
+}
+
+func TestStatsService_AnnualReportErrYear(t *testing.T) {
+	s, _, _, _ := mockStatsService(t)
+
+	_, err := s.AnnualReport(context.Background(), userId, 0)
+
+	require.ErrorIs(t, err, ErrReportYearInvalid)
 }
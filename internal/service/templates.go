@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"time"
+)
+
+type TransactionTemplatesService struct {
+	repo           repo.TransactionTemplates
+	accountsRepo   repo.Accounts
+	categoriesRepo repo.TransactionCategories
+	transactions   Transactions
+}
+
+func newTransactionTemplatesService(repo repo.TransactionTemplates, accountsRepo repo.Accounts,
+	categoriesRepo repo.TransactionCategories, transactions Transactions) *TransactionTemplatesService {
+	return &TransactionTemplatesService{
+		repo:           repo,
+		accountsRepo:   accountsRepo,
+		categoriesRepo: categoriesRepo,
+		transactions:   transactions,
+	}
+}
+
+func (s *TransactionTemplatesService) List(ctx context.Context, userID int64) ([]domain.TransactionTemplate, error) {
+	return s.repo.List(ctx, userID)
+}
+
+func (s *TransactionTemplatesService) Get(ctx context.Context, id int64,
+	userID int64) (domain.TransactionTemplate, error) {
+	template, err := s.repo.Get(ctx, id)
+
+	if err != nil {
+		return template, err
+	}
+
+	if template.OwnerId != userID {
+		return domain.TransactionTemplate{}, ErrTransactionTemplateForbidden
+	}
+
+	return template, nil
+}
+
+func (s *TransactionTemplatesService) Create(ctx context.Context, toCreate domain.TransactionTemplateToCreate,
+	userID int64) (domain.TransactionTemplate, error) {
+	if err := s.check(ctx, toCreate, userID); err != nil {
+		return domain.TransactionTemplate{}, err
+	}
+
+	toCreate.Tags = normalizeTags(toCreate.Tags)
+
+	return s.repo.Create(ctx, toCreate, userID)
+}
+
+func (s *TransactionTemplatesService) Update(ctx context.Context, id int64,
+	toUpdate domain.TransactionTemplateToCreate, userID int64) (domain.TransactionTemplate, error) {
+	if _, err := s.Get(ctx, id, userID); err != nil {
+		return domain.TransactionTemplate{}, err
+	}
+
+	if err := s.check(ctx, toUpdate, userID); err != nil {
+		return domain.TransactionTemplate{}, err
+	}
+
+	toUpdate.Tags = normalizeTags(toUpdate.Tags)
+
+	return s.repo.Update(ctx, id, toUpdate)
+}
+
+func (s *TransactionTemplatesService) Delete(ctx context.Context, id int64, userID int64) error {
+	if _, err := s.Get(ctx, id, userID); err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// Apply creates transaction from template of user. Defaults of template are overridden by given values, transaction
+// is dated today unless date is given
+func (s *TransactionTemplatesService) Apply(ctx context.Context, id int64, toApply domain.TransactionTemplateToApply,
+	userID int64) (domain.Transaction, error) {
+	template, err := s.Get(ctx, id, userID)
+
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	amount := template.Amount
+
+	if toApply.Amount != nil {
+		amount = toApply.Amount
+	}
+
+	if amount == nil {
+		return domain.Transaction{}, ErrTemplateAmountMissing
+	}
+
+	createdAt := time.Now().UTC().Truncate(24 * time.Hour)
+
+	if toApply.CreatedAt != nil {
+		createdAt = *toApply.CreatedAt
+	}
+
+	toCreate := domain.TransactionToCreate{
+		Amount:      *amount,
+		Type:        template.Type,
+		CreatedAt:   createdAt,
+		Status:      toApply.Status,
+		Description: toApply.Description,
+		Payee:       toApply.Payee,
+		Tags:        template.Tags,
+	}
+
+	if len(toApply.Tags) > 0 {
+		toCreate.Tags = toApply.Tags
+	}
+
+	categoryId, creditId, debitId := template.CategoryId, template.CreditId, template.DebitId
+
+	if toApply.CategoryId != nil {
+		categoryId = toApply.CategoryId
+	}
+
+	if toApply.CreditId != nil {
+		creditId = toApply.CreditId
+	}
+
+	if toApply.DebitId != nil {
+		debitId = toApply.DebitId
+	}
+
+	return s.transactions.Create(ctx, toCreate, userID, categoryId, creditId, debitId)
+}
+
+// check validates that category of template matches its type and accounts of template belong to user. Defaults may be
+// missing, they are required only when template is applied
+func (s *TransactionTemplatesService) check(ctx context.Context, toCreate domain.TransactionTemplateToCreate,
+	userID int64) error {
+	if err := toCreate.Type.Validate(); err != nil {
+		return err
+	}
+
+	if toCreate.CategoryId != nil {
+		category, err := s.categoriesRepo.Get(ctx, *toCreate.CategoryId)
+
+		if err != nil {
+			return err
+		}
+
+		if category.Type != toCreate.Type {
+			return ErrTransactionAndCategoryTypesMismatch
+		}
+	}
+
+	if toCreate.CreditId != nil {
+		account, err := s.accountsRepo.Get(ctx, *toCreate.CreditId)
+
+		if err != nil {
+			return err
+		}
+
+		if account.OwnerId != userID {
+			return ErrCreditAccountForbidden
+		}
+	}
+
+	if toCreate.DebitId != nil {
+		account, err := s.accountsRepo.Get(ctx, *toCreate.DebitId)
+
+		if err != nil {
+			return err
+		}
+
+		if account.OwnerId != userID {
+			return ErrDebitAccountForbidden
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	mockRepo "github.com/lotostudio/financial-api/internal/repo/mocks"
+	mockService "github.com/lotostudio/financial-api/internal/service/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func mockTransactionTemplatesService(t *testing.T) (*TransactionTemplatesService, *mockRepo.MockTransactionTemplates,
+	*mockRepo.MockAccounts, *mockRepo.MockTransactionCategories, *mockService.MockTransactions) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	ttRepo := mockRepo.NewMockTransactionTemplates(mockCtl)
+	aRepo := mockRepo.NewMockAccounts(mockCtl)
+	cRepo := mockRepo.NewMockTransactionCategories(mockCtl)
+	transactions := mockService.NewMockTransactions(mockCtl)
+
+	s := newTransactionTemplatesService(ttRepo, aRepo, cRepo, transactions)
+
+	return s, ttRepo, aRepo, cRepo, transactions
+}
+
+func TestTransactionTemplatesService_Create(t *testing.T) {
+	s, ttRepo, aRepo, cRepo, _ := mockTransactionTemplatesService(t)
+
+	ctx := context.Background()
+	categoryId, creditId := int64(3), int64(1)
+	amount := 950.0
+	toCreate := domain.TransactionTemplateToCreate{Name: "Coffee", Type: domain.Expense, Amount: &amount,
+		CategoryId: &categoryId, CreditId: &creditId, Tags: []string{" Coffee ", "coffee"}}
+	template := domain.TransactionTemplate{ID: 1, Name: "Coffee", OwnerId: userId}
+
+	cRepo.EXPECT().Get(ctx, categoryId).Return(domain.TransactionCategory{ID: categoryId, Type: domain.Expense}, nil)
+	aRepo.EXPECT().Get(ctx, creditId).Return(domain.Account{ID: creditId, OwnerId: userId}, nil)
+	ttRepo.EXPECT().Create(ctx, gomock.Any(), userId).DoAndReturn(
+		func(_ context.Context, toCreate domain.TransactionTemplateToCreate,
+			_ int64) (domain.TransactionTemplate, error) {
+			require.Equal(t, []string{"coffee"}, toCreate.Tags)
+
+			return template, nil
+		})
+
+	res, err := s.Create(ctx, toCreate, userId)
+
+	require.NoError(t, err)
+	require.Equal(t, template, res)
+}
+
+func TestTransactionTemplatesService_CreateErrCategoryType(t *testing.T) {
+	s, _, _, cRepo, _ := mockTransactionTemplatesService(t)
+
+	ctx := context.Background()
+	categoryId := int64(3)
+
+	cRepo.EXPECT().Get(ctx, categoryId).Return(domain.TransactionCategory{ID: categoryId, Type: domain.Income}, nil)
+
+	_, err := s.Create(ctx, domain.TransactionTemplateToCreate{Name: "Coffee", Type: domain.Expense,
+		CategoryId: &categoryId}, userId)
+
+	require.ErrorIs(t, err, ErrTransactionAndCategoryTypesMismatch)
+}
+
+func TestTransactionTemplatesService_CreateErrAccountForbidden(t *testing.T) {
+	s, _, aRepo, _, _ := mockTransactionTemplatesService(t)
+
+	ctx := context.Background()
+	debitId := int64(2)
+
+	aRepo.EXPECT().Get(ctx, debitId).Return(domain.Account{ID: debitId, OwnerId: userId + 1}, nil)
+
+	_, err := s.Create(ctx, domain.TransactionTemplateToCreate{Name: "Salary", Type: domain.Income,
+		DebitId: &debitId}, userId)
+
+	require.ErrorIs(t, err, ErrDebitAccountForbidden)
+}
+
+func TestTransactionTemplatesService_GetErrForbidden(t *testing.T) {
+	s, ttRepo, _, _, _ := mockTransactionTemplatesService(t)
+
+	ctx := context.Background()
+
+	ttRepo.EXPECT().Get(ctx, int64(1)).Return(domain.TransactionTemplate{ID: 1, OwnerId: userId + 1}, nil)
+
+	_, err := s.Get(ctx, 1, userId)
+
+	require.ErrorIs(t, err, ErrTransactionTemplateForbidden)
+}
+
+func TestTransactionTemplatesService_Update(t *testing.T) {
+	s, ttRepo, _, _, _ := mockTransactionTemplatesService(t)
+
+	ctx := context.Background()
+	toUpdate := domain.TransactionTemplateToCreate{Name: "Bus", Type: domain.Expense}
+	template := domain.TransactionTemplate{ID: 1, Name: "Bus", OwnerId: userId}
+
+	ttRepo.EXPECT().Get(ctx, int64(1)).Return(domain.TransactionTemplate{ID: 1, OwnerId: userId}, nil)
+	ttRepo.EXPECT().Update(ctx, int64(1), toUpdate).Return(template, nil)
+
+	res, err := s.Update(ctx, 1, toUpdate, userId)
+
+	require.NoError(t, err)
+	require.Equal(t, template, res)
+}
+
+func TestTransactionTemplatesService_Delete(t *testing.T) {
+	s, ttRepo, _, _, _ := mockTransactionTemplatesService(t)
+
+	ctx := context.Background()
+
+	ttRepo.EXPECT().Get(ctx, int64(1)).Return(domain.TransactionTemplate{ID: 1, OwnerId: userId}, nil)
+	ttRepo.EXPECT().Delete(ctx, int64(1)).Return(nil)
+
+	err := s.Delete(ctx, 1, userId)
+
+	require.NoError(t, err)
+}
+
+func TestTransactionTemplatesService_DeleteErrNotFound(t *testing.T) {
+	s, ttRepo, _, _, _ := mockTransactionTemplatesService(t)
+
+	ctx := context.Background()
+
+	ttRepo.EXPECT().Get(ctx, int64(1)).Return(domain.TransactionTemplate{}, repo.ErrTransactionTemplateNotFound)
+
+	err := s.Delete(ctx, 1, userId)
+
+	require.ErrorIs(t, err, repo.ErrTransactionTemplateNotFound)
+}
+
+func TestTransactionTemplatesService_Apply(t *testing.T) {
+	s, ttRepo, _, _, transactions := mockTransactionTemplatesService(t)
+
+	ctx := context.Background()
+	categoryId, creditId := int64(3), int64(1)
+	amount := 950.0
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	transaction := domain.Transaction{ID: 5, Amount: amount}
+
+	ttRepo.EXPECT().Get(ctx, int64(1)).Return(domain.TransactionTemplate{ID: 1, Type: domain.Expense, Amount: &amount,
+		CategoryId: &categoryId, CreditId: &creditId, Tags: []string{"coffee"}, OwnerId: userId}, nil)
+	transactions.EXPECT().Create(ctx, domain.TransactionToCreate{Amount: amount, Type: domain.Expense, CreatedAt: today,
+		Tags: []string{"coffee"}}, userId, &categoryId, &creditId, nil).Return(transaction, nil)
+
+	res, err := s.Apply(ctx, 1, domain.TransactionTemplateToApply{}, userId)
+
+	require.NoError(t, err)
+	require.Equal(t, transaction, res)
+}
+
+func TestTransactionTemplatesService_ApplyOverrides(t *testing.T) {
+	s, ttRepo, _, _, transactions := mockTransactionTemplatesService(t)
+
+	ctx := context.Background()
+	categoryId, creditId, otherCreditId := int64(3), int64(1), int64(4)
+	amount, otherAmount := 950.0, 1100.0
+	createdAt := time.Date(2022, 4, 2, 0, 0, 0, 0, time.UTC)
+	payee := "Starbucks"
+
+	ttRepo.EXPECT().Get(ctx, int64(1)).Return(domain.TransactionTemplate{ID: 1, Type: domain.Expense, Amount: &amount,
+		CategoryId: &categoryId, CreditId: &creditId, Tags: []string{"coffee"}, OwnerId: userId}, nil)
+	transactions.EXPECT().Create(ctx, domain.TransactionToCreate{Amount: otherAmount, Type: domain.Expense,
+		CreatedAt: createdAt, Status: domain.Pending, Payee: &payee, Tags: []string{"work"}}, userId, &categoryId,
+		&otherCreditId, nil).Return(domain.Transaction{ID: 5}, nil)
+
+	_, err := s.Apply(ctx, 1, domain.TransactionTemplateToApply{Amount: &otherAmount, CreatedAt: &createdAt,
+		Status: domain.Pending, Payee: &payee, Tags: []string{"work"}, CreditId: &otherCreditId}, userId)
+
+	require.NoError(t, err)
+}
+
+func TestTransactionTemplatesService_ApplyErrAmountMissing(t *testing.T) {
+	s, ttRepo, _, _, _ := mockTransactionTemplatesService(t)
+
+	ctx := context.Background()
+
+	ttRepo.EXPECT().Get(ctx, int64(1)).Return(domain.TransactionTemplate{ID: 1, Type: domain.Expense,
+		OwnerId: userId}, nil)
+
+	_, err := s.Apply(ctx, 1, domain.TransactionTemplateToApply{}, userId)
+
+	require.ErrorIs(t, err, ErrTemplateAmountMissing)
+}
+
+func TestTransactionTemplatesService_ApplyErr(t *testing.T) {
+	s, ttRepo, _, _, transactions := mockTransactionTemplatesService(t)
+
+	ctx := context.Background()
+	amount := 950.0
+
+	ttRepo.EXPECT().Get(ctx, int64(1)).Return(domain.TransactionTemplate{ID: 1, Type: domain.Expense, Amount: &amount,
+		OwnerId: userId}, nil)
+	transactions.EXPECT().Create(ctx, gomock.Any(), userId, nil, nil, nil).
+		Return(domain.Transaction{}, ErrNoCategorySelected)
+
+	_, err := s.Apply(ctx, 1, domain.TransactionTemplateToApply{}, userId)
+
+	require.ErrorIs(t, err, ErrNoCategorySelected)
+}
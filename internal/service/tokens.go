@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"github.com/lotostudio/financial-api/pkg/hash"
+)
+
+// Count of random bytes in personal access token
+const accessTokenLength = 32
+
+type AccessTokensService struct {
+	repo      repo.AccessTokens
+	usersRepo repo.Users
+	hasher    hash.PasswordHasher
+}
+
+func newAccessTokensService(repo repo.AccessTokens, usersRepo repo.Users, hasher hash.PasswordHasher) *AccessTokensService {
+	return &AccessTokensService{
+		repo:      repo,
+		usersRepo: usersRepo,
+		hasher:    hasher,
+	}
+}
+
+func (s *AccessTokensService) List(ctx context.Context, userID int64) ([]domain.AccessToken, error) {
+	return s.repo.List(ctx, userID)
+}
+
+func (s *AccessTokensService) Create(ctx context.Context, toCreate domain.AccessTokenToCreate,
+	userID int64) (domain.CreatedAccessToken, error) {
+	b := make([]byte, accessTokenLength)
+
+	if _, err := rand.Read(b); err != nil {
+		return domain.CreatedAccessToken{}, err
+	}
+
+	token := fmt.Sprintf("%s%x", domain.AccessTokenPrefix, b)
+
+	// Only hash of token is stored, so raw value can be shown only once
+	tokenHash, err := s.hasher.Hash(token)
+
+	if err != nil {
+		return domain.CreatedAccessToken{}, err
+	}
+
+	created, err := s.repo.Create(ctx, toCreate, tokenHash, userID)
+
+	if err != nil {
+		return domain.CreatedAccessToken{}, err
+	}
+
+	return domain.CreatedAccessToken{
+		AccessToken: created,
+		Token:       token,
+	}, nil
+}
+
+func (s *AccessTokensService) Delete(ctx context.Context, id int64, userID int64) error {
+	token, err := s.repo.Get(ctx, id)
+
+	if err != nil {
+		return err
+	}
+
+	if token.UserId != userID {
+		return ErrAccessTokenForbidden
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *AccessTokensService) Authenticate(ctx context.Context, token string) (domain.AccessToken, error) {
+	tokenHash, err := s.hasher.Hash(token)
+
+	if err != nil {
+		return domain.AccessToken{}, err
+	}
+
+	accessToken, err := s.repo.GetByHash(ctx, tokenHash)
+
+	if err != nil {
+		return domain.AccessToken{}, err
+	}
+
+	if accessToken.Expired() {
+		return domain.AccessToken{}, ErrAccessTokenExpired
+	}
+
+	user, err := s.usersRepo.Get(ctx, accessToken.UserId)
+
+	if err != nil {
+		return domain.AccessToken{}, err
+	}
+
+	if user.Disabled {
+		return domain.AccessToken{}, ErrUserDisabled
+	}
+
+	accessToken.Role = user.Role
+
+	if err = s.repo.UpdateLastUsed(ctx, accessToken.ID); err != nil {
+		return domain.AccessToken{}, err
+	}
+
+	return accessToken, nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	mockRepo "github.com/lotostudio/financial-api/internal/repo/mocks"
+	"github.com/lotostudio/financial-api/pkg/hash"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mockAccessTokensService(t *testing.T) (*AccessTokensService, *mockRepo.MockAccessTokens, *mockRepo.MockUsers) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	tRepo := mockRepo.NewMockAccessTokens(mockCtl)
+	uRepo := mockRepo.NewMockUsers(mockCtl)
+
+	s := newAccessTokensService(tRepo, uRepo, hash.NewSHA1PasswordHasher(""))
+
+	return s, tRepo, uRepo
+}
+
+func TestAccessTokensService_List(t *testing.T) {
+	s, tRepo, _ := mockAccessTokensService(t)
+
+	ctx := context.Background()
+
+	tRepo.EXPECT().List(ctx, userId).Return([]domain.AccessToken{}, nil)
+
+	tokens, err := s.List(ctx, userId)
+
+	require.NoError(t, err)
+	require.IsType(t, []domain.AccessToken{}, tokens)
+}
+
+func TestAccessTokensService_Create(t *testing.T) {
+	s, tRepo, _ := mockAccessTokensService(t)
+
+	ctx := context.Background()
+	toCreate := domain.AccessTokenToCreate{Name: "script", Scope: domain.ReadOnly}
+
+	var storedHash string
+
+	tRepo.EXPECT().Create(ctx, toCreate, gomock.Any(), userId).DoAndReturn(
+		func(_ context.Context, _ domain.AccessTokenToCreate, hash string, _ int64) (domain.AccessToken, error) {
+			storedHash = hash
+
+			return domain.AccessToken{Name: "script", Scope: domain.ReadOnly, Hash: hash}, nil
+		})
+
+	created, err := s.Create(ctx, toCreate, userId)
+
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(created.Token, domain.AccessTokenPrefix))
+
+	expectedHash, _ := s.hasher.Hash(created.Token)
+
+	require.Equal(t, expectedHash, storedHash)
+	require.NotEqual(t, created.Token, storedHash)
+}
+
+func TestAccessTokensService_Delete(t *testing.T) {
+	s, tRepo, _ := mockAccessTokensService(t)
+
+	ctx := context.Background()
+
+	tRepo.EXPECT().Get(ctx, int64(1)).Return(domain.AccessToken{ID: 1, UserId: userId}, nil)
+	tRepo.EXPECT().Delete(ctx, int64(1)).Return(nil)
+
+	err := s.Delete(ctx, 1, userId)
+
+	require.NoError(t, err)
+}
+
+func TestAccessTokensService_DeleteErrForbidden(t *testing.T) {
+	s, tRepo, _ := mockAccessTokensService(t)
+
+	ctx := context.Background()
+
+	tRepo.EXPECT().Get(ctx, int64(1)).Return(domain.AccessToken{ID: 1, UserId: userId + 1}, nil)
+
+	err := s.Delete(ctx, 1, userId)
+
+	require.ErrorIs(t, err, ErrAccessTokenForbidden)
+}
+
+func TestAccessTokensService_Authenticate(t *testing.T) {
+	s, tRepo, uRepo := mockAccessTokensService(t)
+
+	ctx := context.Background()
+	tokenHash, _ := s.hasher.Hash("fa_token")
+
+	tRepo.EXPECT().GetByHash(ctx, tokenHash).Return(domain.AccessToken{ID: 1, UserId: userId}, nil)
+	uRepo.EXPECT().Get(ctx, userId).Return(domain.User{ID: userId, Role: domain.RoleSupport}, nil)
+	tRepo.EXPECT().UpdateLastUsed(ctx, int64(1)).Return(nil)
+
+	token, err := s.Authenticate(ctx, "fa_token")
+
+	require.NoError(t, err)
+	require.Equal(t, userId, token.UserId)
+	require.Equal(t, domain.RoleSupport, token.Role)
+}
+
+func TestAccessTokensService_AuthenticateErrUserDisabled(t *testing.T) {
+	s, tRepo, uRepo := mockAccessTokensService(t)
+
+	ctx := context.Background()
+
+	tRepo.EXPECT().GetByHash(ctx, gomock.Any()).Return(domain.AccessToken{ID: 1, UserId: userId}, nil)
+	uRepo.EXPECT().Get(ctx, userId).Return(domain.User{ID: userId, Disabled: true}, nil)
+
+	_, err := s.Authenticate(ctx, "fa_token")
+
+	require.ErrorIs(t, err, ErrUserDisabled)
+}
+
+func TestAccessTokensService_AuthenticateErrNotFound(t *testing.T) {
+	s, tRepo, _ := mockAccessTokensService(t)
+
+	ctx := context.Background()
+
+	tRepo.EXPECT().GetByHash(ctx, gomock.Any()).Return(domain.AccessToken{}, repo.ErrAccessTokenNotFound)
+
+	_, err := s.Authenticate(ctx, "fa_token")
+
+	require.ErrorIs(t, err, repo.ErrAccessTokenNotFound)
+}
+
+func TestAccessTokensService_AuthenticateErrExpired(t *testing.T) {
+	s, tRepo, _ := mockAccessTokensService(t)
+
+	ctx := context.Background()
+	expiresAt := time.Now().Add(-1 * time.Hour)
+
+	tRepo.EXPECT().GetByHash(ctx, gomock.Any()).Return(domain.AccessToken{ID: 1, ExpiresAt: &expiresAt}, nil)
+
+	_, err := s.Authenticate(ctx, "fa_token")
+
+	require.ErrorIs(t, err, ErrAccessTokenExpired)
+}
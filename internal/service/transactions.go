@@ -2,8 +2,12 @@ package service
 
 import (
 	"context"
+	"errors"
 	"github.com/lotostudio/financial-api/internal/domain"
 	"github.com/lotostudio/financial-api/internal/repo"
+	"math"
+	"sort"
+	"strings"
 )
 
 type TransactionsService struct {
@@ -24,8 +28,32 @@ func (s *TransactionsService) List(ctx context.Context, filter domain.Transactio
 	return s.repo.List(ctx, filter)
 }
 
-func (s *TransactionsService) Stats(ctx context.Context, filter domain.TransactionsFilter) ([]domain.TransactionStat, error) {
-	return s.repo.Stats(ctx, filter)
+// Limit of search results count
+const searchMaxLimit = 100
+
+// Search finds transactions of filter by words or part of payee in query, most relevant first
+func (s *TransactionsService) Search(ctx context.Context, query string, filter domain.TransactionsFilter,
+	limit int) ([]domain.TransactionSearchResult, error) {
+	query = strings.TrimSpace(query)
+
+	if query == "" {
+		return nil, ErrSearchQueryEmpty
+	}
+
+	if limit <= 0 || limit > searchMaxLimit {
+		return nil, ErrSearchLimitInvalid
+	}
+
+	return s.repo.Search(ctx, query, filter, limit)
+}
+
+func (s *TransactionsService) Stats(ctx context.Context, filter domain.TransactionsFilter,
+	groupBy domain.StatsGroup) ([]domain.TransactionStat, error) {
+	if err := groupBy.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Stats(ctx, filter, groupBy)
 }
 
 func (s *TransactionsService) Create(ctx context.Context, toCreate domain.TransactionToCreate, userID int64,
@@ -35,67 +63,360 @@ func (s *TransactionsService) Create(ctx context.Context, toCreate domain.Transa
 		return domain.Transaction{}, err
 	}
 
-	var category domain.TransactionCategory
+	toCreate.Tags = normalizeTags(toCreate.Tags)
+
+	checked, err := s.check(ctx, toCreate, userID, categoryId, creditId, debitId)
+
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	transaction, err := s.repo.Create(ctx, toCreate, categoryId, creditId, debitId)
+
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	transaction.Category = checked.category
+	transaction.Lines = checked.lines
+
+	if transaction.Type == domain.Income || transaction.Type == domain.Transfer {
+		debitAcc, err := s.accountsRepo.Get(ctx, *debitId)
+
+		if err != nil {
+			return transaction, err
+		}
+
+		transaction.Debit = &debitAcc
+	}
+
+	if transaction.Type == domain.Expense || transaction.Type == domain.Transfer {
+		creditAcc, err := s.accountsRepo.Get(ctx, *creditId)
+
+		if err != nil {
+			return transaction, err
+		}
+
+		transaction.Credit = &creditAcc
+	}
+
+	return transaction, nil
+}
+
+// checkedTransaction is transaction to create that passed checks, with title of its category, its lines and accounts
+type checkedTransaction struct {
+	category *string
+	lines    []domain.TransactionLine
+	credit   *domain.Account
+	debit    *domain.Account
+}
+
+// check validates category or lines and accounts of transaction to create by rules of its type
+func (s *TransactionsService) check(ctx context.Context, toCreate domain.TransactionToCreate, userID int64,
+	categoryId *int64, creditId *int64, debitId *int64) (checkedTransaction, error) {
+	var checked checkedTransaction
 	var err error
 
-	if toCreate.Type != domain.Transfer {
-		category, err = s.categoriesRepo.Get(ctx, *categoryId)
+	if len(toCreate.Lines) > 0 {
+		if checked.lines, err = s.checkLines(ctx, toCreate, categoryId); err != nil {
+			return checked, err
+		}
+	} else if toCreate.Type != domain.Transfer {
+		if categoryId == nil {
+			return checked, ErrNoCategorySelected
+		}
+
+		category, err := s.categoriesRepo.Get(ctx, *categoryId)
 
 		if err != nil {
-			return domain.Transaction{}, err
+			return checked, err
 		}
 
 		if category.Type != toCreate.Type {
-			return domain.Transaction{}, ErrTransactionAndCategoryTypesMismatch
+			return checked, ErrTransactionAndCategoryTypesMismatch
 		}
+
+		checked.category = &category.Title
 	}
 
+	var credit, debit domain.Account
+
 	switch toCreate.Type {
 	case domain.Income:
-		if _, err = s.checkIncome(ctx, userID, debitId); err != nil {
-			return domain.Transaction{}, err
+		if debit, err = s.checkIncome(ctx, userID, debitId); err != nil {
+			return checked, err
 		}
+
+		checked.debit = &debit
 	case domain.Expense:
-		if _, err = s.checkExpense(ctx, userID, creditId); err != nil {
-			return domain.Transaction{}, err
+		if credit, err = s.checkExpense(ctx, userID, creditId); err != nil {
+			return checked, err
 		}
+
+		checked.credit = &credit
 	case domain.Transfer:
-		if err = s.checkTransfer(ctx, userID, creditId, debitId); err != nil {
-			return domain.Transaction{}, err
+		if credit, debit, err = s.checkTransfer(ctx, userID, creditId, debitId); err != nil {
+			return checked, err
 		}
+
+		checked.credit, checked.debit = &credit, &debit
 	}
 
-	transaction, err := s.repo.Create(ctx, toCreate, categoryId, creditId, debitId)
+	return checked, nil
+}
 
-	if err != nil {
-		return domain.Transaction{}, err
+// Errors of invalid batch items, which are reported in results of items instead of failing whole batch
+var batchItemErrors = []error{
+	domain.ErrInvalidTransactionType,
+	ErrNoCategorySelected,
+	ErrTransactionAndCategoryTypesMismatch,
+	ErrTransactionLinesSumMismatch,
+	ErrTransferSplit,
+	ErrSplitTransactionCategory,
+	ErrNoAccountSelected,
+	ErrAccountsHaveDifferenceCurrencies,
+	ErrCreditAccountForbidden,
+	ErrDebitAccountForbidden,
+	ErrTransactionForbidden,
+	ErrTransactionReconciled,
+	ErrTransactionRepeated,
+	repo.ErrTransactionCategoryNotFound,
+	repo.ErrAccountNotFound,
+	repo.ErrAccountNotEnoughBalance,
+	repo.ErrTransactionNotFound,
+	repo.ErrTransactionOwnerNotFound,
+}
+
+func isBatchItemError(err error) bool {
+	for _, itemErr := range batchItemErrors {
+		if errors.Is(err, itemErr) {
+			return true
+		}
 	}
 
-	if category.Title != "" {
-		transaction.Category = &category.Title
+	return false
+}
+
+// batchMode returns mode of batch, all or nothing by default
+func batchMode(mode domain.BatchMode) (domain.BatchMode, error) {
+	if mode == "" {
+		return domain.AllOrNothing, nil
 	}
 
-	if transaction.Type == domain.Income || transaction.Type == domain.Transfer {
-		debitAcc, err := s.accountsRepo.Get(ctx, *debitId)
+	return mode, mode.Validate()
+}
+
+// CreateBatch checks all items by rules of Create and saves valid ones in one database transaction. Items are not
+// allowed to overdraw accounts, given balances changed by previous items. If any item is invalid, batch is rejected
+// with ErrBatchRejected in all or nothing mode and item is skipped in best effort mode
+func (s *TransactionsService) CreateBatch(ctx context.Context, toCreate domain.TransactionsToCreate,
+	userID int64) (domain.TransactionBatch, error) {
+	mode, err := batchMode(toCreate.Mode)
+
+	if err != nil {
+		return domain.TransactionBatch{}, err
+	}
+
+	batch := domain.TransactionBatch{Results: make([]domain.TransactionBatchResult, len(toCreate.Items))}
+	valid := make([]domain.TransactionBatchItem, 0, len(toCreate.Items))
+	checked := make([]checkedTransaction, 0, len(toCreate.Items))
+	indexes := make([]int, 0, len(toCreate.Items))
+	balances := make(map[int64]float64)
+
+	for i, item := range toCreate.Items {
+		batch.Results[i].Index = i
+
+		item.Tags = normalizeTags(item.Tags)
+
+		itemChecked, err := s.checkBatchItem(ctx, item, userID, balances)
 
 		if err != nil {
-			return transaction, err
+			if !isBatchItemError(err) {
+				return domain.TransactionBatch{}, err
+			}
+
+			batch.Results[i].Error = err.Error()
+			batch.Failed++
+			continue
 		}
 
-		transaction.Debit = &debitAcc
+		valid = append(valid, item)
+		checked = append(checked, itemChecked)
+		indexes = append(indexes, i)
 	}
 
-	if transaction.Type == domain.Expense || transaction.Type == domain.Transfer {
-		creditAcc, err := s.accountsRepo.Get(ctx, *creditId)
+	if batch.Failed > 0 && mode == domain.AllOrNothing {
+		return batch, ErrBatchRejected
+	}
+
+	if len(valid) == 0 {
+		return batch, nil
+	}
+
+	transactions, err := s.repo.CreateBatch(ctx, valid)
+
+	if err != nil {
+		return domain.TransactionBatch{}, err
+	}
+
+	// Accounts are loaded once, after all balances are changed
+	accounts := make(map[int64]*domain.Account)
+	account := func(id int64) (*domain.Account, error) {
+		if acc, ok := accounts[id]; ok {
+			return acc, nil
+		}
+
+		acc, err := s.accountsRepo.Get(ctx, id)
 
 		if err != nil {
-			return transaction, err
+			return nil, err
 		}
 
-		transaction.Credit = &creditAcc
+		accounts[id] = &acc
+
+		return &acc, nil
 	}
 
-	return transaction, nil
+	for j := range transactions {
+		transaction := transactions[j]
+		transaction.Category = checked[j].category
+		transaction.Lines = checked[j].lines
+
+		if checked[j].debit != nil {
+			if transaction.Debit, err = account(*valid[j].DebitId); err != nil {
+				return domain.TransactionBatch{}, err
+			}
+		}
+
+		if checked[j].credit != nil {
+			if transaction.Credit, err = account(*valid[j].CreditId); err != nil {
+				return domain.TransactionBatch{}, err
+			}
+		}
+
+		result := &batch.Results[indexes[j]]
+		result.ID = transaction.ID
+		result.Transaction = &transaction
+		batch.Applied++
+	}
+
+	return batch, nil
+}
+
+// checkBatchItem checks item of batch and tracks balances of its accounts
+func (s *TransactionsService) checkBatchItem(ctx context.Context, item domain.TransactionBatchItem, userID int64,
+	balances map[int64]float64) (checkedTransaction, error) {
+	if err := item.Type.Validate(); err != nil {
+		return checkedTransaction{}, err
+	}
+
+	checked, err := s.check(ctx, item.TransactionToCreate, userID, item.CategoryId, item.CreditId, item.DebitId)
+
+	if err != nil {
+		return checked, err
+	}
+
+	if checked.credit != nil {
+		balance, ok := balances[*item.CreditId]
+
+		if !ok {
+			balance = checked.credit.Balance
+		}
+
+		// Balances are compared in cents to ignore floating point errors
+		if math.Round((balance-item.Amount)*100) < 0 {
+			return checked, repo.ErrAccountNotEnoughBalance
+		}
+
+		balances[*item.CreditId] = balance - item.Amount
+	}
+
+	if checked.debit != nil {
+		balance, ok := balances[*item.DebitId]
+
+		if !ok {
+			balance = checked.debit.Balance
+		}
+
+		balances[*item.DebitId] = balance + item.Amount
+	}
+
+	return checked, nil
+}
+
+// checkLines validates split of transaction: every line must have category of transaction type and amounts of lines
+// must sum to amount of transaction. Returns lines with titles of categories
+func (s *TransactionsService) checkLines(ctx context.Context, toCreate domain.TransactionToCreate,
+	categoryId *int64) ([]domain.TransactionLine, error) {
+	if toCreate.Type == domain.Transfer {
+		return nil, ErrTransferSplit
+	}
+
+	if categoryId != nil {
+		return nil, ErrSplitTransactionCategory
+	}
+
+	lines := make([]domain.TransactionLine, 0, len(toCreate.Lines))
+	categories := make(map[int64]domain.TransactionCategory)
+	var sum float64
+
+	for _, line := range toCreate.Lines {
+		category, ok := categories[line.CategoryId]
+
+		if !ok {
+			var err error
+
+			if category, err = s.categoriesRepo.Get(ctx, line.CategoryId); err != nil {
+				return nil, err
+			}
+
+			if category.Type != toCreate.Type {
+				return nil, ErrTransactionAndCategoryTypesMismatch
+			}
+
+			categories[line.CategoryId] = category
+		}
+
+		title := category.Title
+		lines = append(lines, domain.TransactionLine{Category: &title, Amount: line.Amount})
+		sum += line.Amount
+	}
+
+	// Amounts are compared in cents to ignore floating point errors
+	if math.Round(sum*100) != math.Round(toCreate.Amount*100) {
+		return nil, ErrTransactionLinesSumMismatch
+	}
+
+	return lines, nil
+}
+
+// normalizeTags trims tags and turns them to lower case, leaving out empty and repeated ones. Tags are sorted
+func normalizeTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+
+		if tag == "" || seen[tag] {
+			continue
+		}
+
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	if len(normalized) == 0 {
+		return nil
+	}
+
+	sort.Strings(normalized)
+
+	return normalized
 }
 
 func (s *TransactionsService) checkIncome(ctx context.Context, userID int64, debitId *int64) (domain.Account, error) {
@@ -134,27 +455,29 @@ func (s *TransactionsService) checkExpense(ctx context.Context, userID int64, cr
 	return creditAcc, nil
 }
 
-func (s *TransactionsService) checkTransfer(ctx context.Context, userID int64, creditId *int64, debitId *int64) error {
+func (s *TransactionsService) checkTransfer(ctx context.Context, userID int64, creditId *int64,
+	debitId *int64) (domain.Account, domain.Account, error) {
 	creditAcc, err := s.checkExpense(ctx, userID, creditId)
 
 	if err != nil {
-		return err
+		return creditAcc, domain.Account{}, err
 	}
 
 	debitAcc, err := s.checkIncome(ctx, userID, debitId)
 
 	if err != nil {
-		return err
+		return creditAcc, debitAcc, err
 	}
 
 	if creditAcc.Currency != debitAcc.Currency {
-		return ErrAccountsHaveDifferenceCurrencies
+		return creditAcc, debitAcc, ErrAccountsHaveDifferenceCurrencies
 	}
 
-	return nil
+	return creditAcc, debitAcc, nil
 }
 
-func (s *TransactionsService) Delete(ctx context.Context, id int64, userID int64) error {
+// Delete moves transaction of user to trash. Reconciled transaction is deleted only if unlock is set
+func (s *TransactionsService) Delete(ctx context.Context, id int64, userID int64, unlock bool) error {
 	ownerId, err := s.repo.GetOwner(ctx, id)
 
 	if err != nil {
@@ -165,9 +488,132 @@ func (s *TransactionsService) Delete(ctx context.Context, id int64, userID int64
 		return ErrTransactionForbidden
 	}
 
+	if err = s.checkUnlocked(ctx, id, unlock); err != nil {
+		return err
+	}
+
 	return s.repo.Delete(ctx, id)
 }
 
+// DeleteBatch checks ownership and locks of all transactions and deletes permitted ones in one database transaction. If any
+// transaction can't be deleted, batch is rejected with ErrBatchRejected in all or nothing mode and transaction is
+// skipped in best effort mode
+func (s *TransactionsService) DeleteBatch(ctx context.Context, toDelete domain.TransactionsToDelete,
+	userID int64) (domain.TransactionBatch, error) {
+	mode, err := batchMode(toDelete.Mode)
+
+	if err != nil {
+		return domain.TransactionBatch{}, err
+	}
+
+	batch := domain.TransactionBatch{Results: make([]domain.TransactionBatchResult, len(toDelete.IDs))}
+	ids := make([]int64, 0, len(toDelete.IDs))
+	seen := make(map[int64]bool, len(toDelete.IDs))
+
+	for i, id := range toDelete.IDs {
+		batch.Results[i].Index = i
+		batch.Results[i].ID = id
+
+		var ownerId int64
+
+		if seen[id] {
+			err = ErrTransactionRepeated
+		} else if ownerId, err = s.repo.GetOwner(ctx, id); err == nil && ownerId != userID {
+			err = ErrTransactionForbidden
+		} else if err == nil {
+			err = s.checkUnlocked(ctx, id, toDelete.Unlock)
+		}
+
+		seen[id] = true
+
+		if err != nil {
+			if !isBatchItemError(err) {
+				return domain.TransactionBatch{}, err
+			}
+
+			batch.Results[i].Error = err.Error()
+			batch.Failed++
+			continue
+		}
+
+		ids = append(ids, id)
+	}
+
+	if batch.Failed > 0 && mode == domain.AllOrNothing {
+		return batch, ErrBatchRejected
+	}
+
+	if len(ids) == 0 {
+		return batch, nil
+	}
+
+	if err = s.repo.DeleteBatch(ctx, ids); err != nil {
+		return domain.TransactionBatch{}, err
+	}
+
+	batch.Applied = len(ids)
+
+	return batch, nil
+}
+
+// checkUnlocked returns ErrTransactionReconciled if transaction is reconciled and unlock is not set
+func (s *TransactionsService) checkUnlocked(ctx context.Context, id int64, unlock bool) error {
+	if unlock {
+		return nil
+	}
+
+	status, err := s.repo.GetStatus(ctx, id)
+
+	if err != nil {
+		return err
+	}
+
+	if status == domain.Reconciled {
+		return ErrTransactionReconciled
+	}
+
+	return nil
+}
+
+// SetStatus changes status of transaction of user. Status of reconciled transaction is changed only if unlock is set
+func (s *TransactionsService) SetStatus(ctx context.Context, id int64, userID int64,
+	toUpdate domain.TransactionStatusToUpdate) error {
+	if err := toUpdate.Status.Validate(); err != nil {
+		return err
+	}
+
+	ownerId, err := s.repo.GetOwner(ctx, id)
+
+	if err != nil {
+		return err
+	}
+
+	if ownerId != userID {
+		return ErrTransactionForbidden
+	}
+
+	if err = s.checkUnlocked(ctx, id, toUpdate.Unlock || toUpdate.Status == domain.Reconciled); err != nil {
+		return err
+	}
+
+	return s.repo.SetStatus(ctx, id, toUpdate.Status)
+}
+
+// DismissFlags marks flags of unusual transaction as seen by user
+func (s *TransactionsService) DismissFlags(ctx context.Context, id int64, userID int64) error {
+	ownerId, err := s.repo.GetOwner(ctx, id)
+
+	if err != nil {
+		return err
+	}
+
+	if ownerId != userID {
+		return ErrTransactionForbidden
+	}
+
+	return s.repo.DismissFlags(ctx, id)
+}
+
 type TransactionCategoryService struct {
 	repo repo.TransactionCategories
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/golang/mock/gomock"
 	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
 	mockRepo "github.com/lotostudio/financial-api/internal/repo/mocks"
 	"github.com/stretchr/testify/require"
 	"testing"
@@ -39,20 +40,74 @@ func TestTransactionsService_List(t *testing.T) {
 	require.IsType(t, []domain.Transaction{}, categories)
 }
 
+func TestTransactionsService_Search(t *testing.T) {
+	s, tRepo, _, _ := mockTransactionsService(t)
+
+	ctx := context.Background()
+	filter := domain.TransactionsFilter{OwnerId: &userId}
+
+	tRepo.EXPECT().Search(ctx, "pharmacy", filter, 20).Return([]domain.TransactionSearchResult{}, nil)
+
+	results, err := s.Search(ctx, " pharmacy ", filter, 20)
+
+	require.NoError(t, err)
+	require.Equal(t, []domain.TransactionSearchResult{}, results)
+}
+
+func TestTransactionsService_SearchErrQuery(t *testing.T) {
+	s, _, _, _ := mockTransactionsService(t)
+
+	_, err := s.Search(context.Background(), "  ", domain.TransactionsFilter{}, 20)
+
+	require.ErrorIs(t, err, ErrSearchQueryEmpty)
+}
+
+func TestTransactionsService_SearchErrLimit(t *testing.T) {
+	s, _, _, _ := mockTransactionsService(t)
+
+	_, err := s.Search(context.Background(), "pharmacy", domain.TransactionsFilter{}, 101)
+
+	require.ErrorIs(t, err, ErrSearchLimitInvalid)
+}
+
 func TestTransactionsService_Stats(t *testing.T) {
 	s, tRepo, _, _ := mockTransactionsService(t)
 
 	ctx := context.Background()
 	filter := domain.TransactionsFilter{}
 
-	tRepo.EXPECT().Stats(ctx, filter).Return([]domain.TransactionStat{}, nil)
+	tRepo.EXPECT().Stats(ctx, filter, domain.GroupByMonth).Return([]domain.TransactionStat{}, nil)
 
-	stats, err := s.Stats(ctx, filter)
+	stats, err := s.Stats(ctx, filter, domain.GroupByMonth)
 
 	require.NoError(t, err)
 	require.IsType(t, []domain.TransactionStat{}, stats)
 }
 
+func TestTransactionsService_StatsSplitByType(t *testing.T) {
+	s, tRepo, _, _ := mockTransactionsService(t)
+
+	ctx := context.Background()
+	filter := domain.TransactionsFilter{}
+	// Split expense of 1000 with lines of 600 and 400 is counted once by its total amount
+	expected := []domain.TransactionStat{{Group: "expense", Sum: 1000, Count: 1, Avg: 1000, Min: 1000, Max: 1000}}
+
+	tRepo.EXPECT().Stats(ctx, filter, domain.GroupByType).Return(expected, nil)
+
+	stats, err := s.Stats(ctx, filter, domain.GroupByType)
+
+	require.NoError(t, err)
+	require.Equal(t, expected, stats)
+}
+
+func TestTransactionsService_StatsErrGroup(t *testing.T) {
+	s, _, _, _ := mockTransactionsService(t)
+
+	_, err := s.Stats(context.Background(), domain.TransactionsFilter{}, "year")
+
+	require.ErrorIs(t, err, domain.ErrInvalidStatsGroup)
+}
+
 func TestTransactionsService_CreateIncome(t *testing.T) {
 	s, tRepo, aRepo, tcRepo := mockTransactionsService(t)
 
@@ -165,6 +220,141 @@ func TestTransactionsService_CreateExpense(t *testing.T) {
 	require.IsType(t, domain.Transaction{}, created)
 }
 
+func TestTransactionsService_CreateTags(t *testing.T) {
+	s, tRepo, aRepo, tcRepo := mockTransactionsService(t)
+
+	ctx := context.Background()
+	toCreate := domain.TransactionToCreate{
+		Type: domain.Expense,
+		Tags: []string{" Work", "restaurants", "work", ""},
+	}
+	var categoryId, creditId = new(int64), new(int64)
+	*categoryId = 1
+	*creditId = 1
+
+	credit := domain.Account{
+		OwnerId: userId,
+	}
+
+	tcRepo.EXPECT().Get(ctx, *categoryId).Return(domain.TransactionCategory{
+		Type:  domain.Expense,
+		Title: "food",
+	}, nil)
+	aRepo.EXPECT().Get(ctx, *creditId).Return(credit, nil)
+	tRepo.EXPECT().Create(ctx, domain.TransactionToCreate{
+		Type: domain.Expense,
+		Tags: []string{"restaurants", "work"},
+	}, categoryId, creditId, nil).Return(domain.Transaction{Type: domain.Expense}, nil)
+	aRepo.EXPECT().Get(ctx, *creditId).Return(credit, nil)
+
+	_, err := s.Create(ctx, toCreate, userId, categoryId, creditId, nil)
+
+	require.NoError(t, err)
+}
+
+func TestTransactionsService_CreateSplit(t *testing.T) {
+	s, tRepo, aRepo, tcRepo := mockTransactionsService(t)
+
+	ctx := context.Background()
+	toCreate := domain.TransactionToCreate{
+		Amount: 100.3,
+		Type:   domain.Expense,
+		Lines: []domain.TransactionLineToCreate{
+			{CategoryId: 1, Amount: 60.1},
+			{CategoryId: 2, Amount: 30.1},
+			{CategoryId: 1, Amount: 10.1},
+		},
+	}
+	creditId := new(int64)
+	*creditId = 1
+
+	credit := domain.Account{
+		OwnerId: userId,
+	}
+
+	tcRepo.EXPECT().Get(ctx, int64(1)).Return(domain.TransactionCategory{Type: domain.Expense, Title: "groceries"}, nil)
+	tcRepo.EXPECT().Get(ctx, int64(2)).Return(domain.TransactionCategory{Type: domain.Expense, Title: "household"}, nil)
+	aRepo.EXPECT().Get(ctx, *creditId).Return(credit, nil).Times(2)
+	tRepo.EXPECT().Create(ctx, toCreate, nil, creditId, nil).Return(domain.Transaction{Amount: 100.3,
+		Type: domain.Expense}, nil)
+
+	created, err := s.Create(ctx, toCreate, userId, nil, creditId, nil)
+
+	require.NoError(t, err)
+	require.Nil(t, created.Category)
+	require.Len(t, created.Lines, 3)
+	require.Equal(t, "household", *created.Lines[1].Category)
+	require.Equal(t, 30.1, created.Lines[1].Amount)
+	require.Equal(t, "groceries, household", created.CategoryTitle())
+	require.True(t, created.HasCategory("household"))
+	require.False(t, created.HasCategory("alcohol"))
+}
+
+func TestTransactionsService_CreateSplitErr(t *testing.T) {
+	categoryId := int64(1)
+	lines := []domain.TransactionLineToCreate{{CategoryId: 1, Amount: 60}, {CategoryId: 2, Amount: 40}}
+
+	for _, tt := range []struct {
+		name       string
+		toCreate   domain.TransactionToCreate
+		categoryId *int64
+		categories []domain.TransactionCategory
+		err        error
+	}{
+		{
+			name:     "transfer",
+			toCreate: domain.TransactionToCreate{Amount: 100, Type: domain.Transfer, Lines: lines},
+			err:      ErrTransferSplit,
+		},
+		{
+			name:       "category of transaction",
+			toCreate:   domain.TransactionToCreate{Amount: 100, Type: domain.Expense, Lines: lines},
+			categoryId: &categoryId,
+			err:        ErrSplitTransactionCategory,
+		},
+		{
+			name:       "category type",
+			toCreate:   domain.TransactionToCreate{Amount: 100, Type: domain.Expense, Lines: lines},
+			categories: []domain.TransactionCategory{{ID: 1, Type: domain.Expense}, {ID: 2, Type: domain.Income}},
+			err:        ErrTransactionAndCategoryTypesMismatch,
+		},
+		{
+			name:       "sum",
+			toCreate:   domain.TransactionToCreate{Amount: 100.01, Type: domain.Expense, Lines: lines},
+			categories: []domain.TransactionCategory{{ID: 1, Type: domain.Expense}, {ID: 2, Type: domain.Expense}},
+			err:        ErrTransactionLinesSumMismatch,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, _, tcRepo := mockTransactionsService(t)
+
+			ctx := context.Background()
+
+			for _, c := range tt.categories {
+				tcRepo.EXPECT().Get(ctx, c.ID).Return(c, nil)
+			}
+
+			_, err := s.Create(ctx, tt.toCreate, userId, tt.categoryId, nil, nil)
+
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestTransactionsService_CreateErrCategoryNotSelected(t *testing.T) {
+	s, _, _, _ := mockTransactionsService(t)
+
+	_, err := s.Create(context.Background(), domain.TransactionToCreate{Type: domain.Expense}, userId, nil, nil, nil)
+
+	require.ErrorIs(t, err, ErrNoCategorySelected)
+}
+
+func TestNormalizeTags(t *testing.T) {
+	require.Nil(t, normalizeTags(nil))
+	require.Nil(t, normalizeTags([]string{" ", ""}))
+	require.Equal(t, []string{"restaurants", "work"}, normalizeTags([]string{"Work ", "restaurants", "WORK"}))
+}
+
 func TestTransactionsService_CreateExpenseErrAccountNotSelected(t *testing.T) {
 	s, _, _, tcRepo := mockTransactionsService(t)
 
@@ -359,6 +549,106 @@ func TestTransactionsService_CreateErrDefault(t *testing.T) {
 	require.ErrorIs(t, err, errDefault)
 }
 
+func TestTransactionsService_CreateBatch(t *testing.T) {
+	s, tRepo, aRepo, tcRepo := mockTransactionsService(t)
+
+	ctx := context.Background()
+	categoryId, creditId := int64(1), int64(2)
+	credit := domain.Account{ID: creditId, OwnerId: userId, Balance: 150}
+
+	expense := domain.TransactionBatchItem{
+		TransactionToCreate: domain.TransactionToCreate{Amount: 100, Type: domain.Expense, Tags: []string{" Food "}},
+		CategoryId:          &categoryId,
+		CreditId:            &creditId,
+	}
+	toCreate := domain.TransactionsToCreate{
+		Mode: domain.BestEffort,
+		Items: []domain.TransactionBatchItem{
+			expense,
+			{TransactionToCreate: domain.TransactionToCreate{Amount: 10, Type: domain.Expense}},
+			// Balance is already decreased by first expense
+			expense,
+		},
+	}
+
+	saved := expense
+	saved.Tags = []string{"food"}
+
+	tcRepo.EXPECT().Get(ctx, categoryId).Return(domain.TransactionCategory{Type: domain.Expense, Title: "food"}, nil).
+		Times(2)
+	aRepo.EXPECT().Get(ctx, creditId).Return(credit, nil).Times(3)
+	tRepo.EXPECT().CreateBatch(ctx, []domain.TransactionBatchItem{saved}).Return([]domain.Transaction{
+		{ID: 4, Amount: 100, Type: domain.Expense, Tags: []string{"food"}},
+	}, nil)
+
+	batch, err := s.CreateBatch(ctx, toCreate, userId)
+
+	require.NoError(t, err)
+	require.Equal(t, 1, batch.Applied)
+	require.Equal(t, 2, batch.Failed)
+	require.Len(t, batch.Results, 3)
+	require.Equal(t, int64(4), batch.Results[0].ID)
+	require.Equal(t, "food", *batch.Results[0].Transaction.Category)
+	require.Equal(t, creditId, batch.Results[0].Transaction.Credit.ID)
+	require.Equal(t, ErrNoCategorySelected.Error(), batch.Results[1].Error)
+	require.Equal(t, 2, batch.Results[2].Index)
+	require.Equal(t, "account doesn't have enough balance", batch.Results[2].Error)
+}
+
+func TestTransactionsService_CreateBatchRejected(t *testing.T) {
+	s, _, aRepo, tcRepo := mockTransactionsService(t)
+
+	ctx := context.Background()
+	categoryId, debitId := int64(1), int64(2)
+
+	toCreate := domain.TransactionsToCreate{
+		Items: []domain.TransactionBatchItem{
+			{
+				TransactionToCreate: domain.TransactionToCreate{Amount: 100, Type: domain.Income},
+				CategoryId:          &categoryId,
+				DebitId:             &debitId,
+			},
+		},
+	}
+
+	tcRepo.EXPECT().Get(ctx, categoryId).Return(domain.TransactionCategory{Type: domain.Income}, nil)
+	aRepo.EXPECT().Get(ctx, debitId).Return(domain.Account{OwnerId: userId + 1}, nil)
+
+	batch, err := s.CreateBatch(ctx, toCreate, userId)
+
+	require.ErrorIs(t, err, ErrBatchRejected)
+	require.Equal(t, 0, batch.Applied)
+	require.Equal(t, ErrDebitAccountForbidden.Error(), batch.Results[0].Error)
+}
+
+func TestTransactionsService_CreateBatchErr(t *testing.T) {
+	s, _, _, tcRepo := mockTransactionsService(t)
+
+	ctx := context.Background()
+	categoryId := int64(1)
+
+	toCreate := domain.TransactionsToCreate{
+		Mode: domain.BestEffort,
+		Items: []domain.TransactionBatchItem{
+			{TransactionToCreate: domain.TransactionToCreate{Type: domain.Income}, CategoryId: &categoryId},
+		},
+	}
+
+	tcRepo.EXPECT().Get(ctx, categoryId).Return(domain.TransactionCategory{}, errDefault)
+
+	_, err := s.CreateBatch(ctx, toCreate, userId)
+
+	require.ErrorIs(t, err, errDefault)
+}
+
+func TestTransactionsService_CreateBatchErrMode(t *testing.T) {
+	s, _, _, _ := mockTransactionsService(t)
+
+	_, err := s.CreateBatch(context.Background(), domain.TransactionsToCreate{Mode: "some"}, userId)
+
+	require.ErrorIs(t, err, domain.ErrInvalidBatchMode)
+}
+
 func TestTransactionsService_Delete(t *testing.T) {
 	s, tRepo, _, _ := mockTransactionsService(t)
 
@@ -366,9 +656,10 @@ func TestTransactionsService_Delete(t *testing.T) {
 	id := int64(1)
 
 	tRepo.EXPECT().GetOwner(ctx, id).Return(userId, nil)
+	tRepo.EXPECT().GetStatus(ctx, id).Return(domain.Cleared, nil)
 	tRepo.EXPECT().Delete(ctx, id).Return(nil)
 
-	err := s.Delete(ctx, id, userId)
+	err := s.Delete(ctx, id, userId, false)
 
 	require.NoError(t, err)
 }
@@ -381,7 +672,7 @@ func TestTransactionsService_DeleteErrOwner(t *testing.T) {
 
 	tRepo.EXPECT().GetOwner(ctx, id).Return(userId, errDefault)
 
-	err := s.Delete(ctx, id, userId)
+	err := s.Delete(ctx, id, userId, false)
 
 	require.ErrorIs(t, err, errDefault)
 }
@@ -394,11 +685,39 @@ func TestTransactionsService_DeleteErrForbidden(t *testing.T) {
 
 	tRepo.EXPECT().GetOwner(ctx, id).Return(userId+1, nil)
 
-	err := s.Delete(ctx, id, userId)
+	err := s.Delete(ctx, id, userId, false)
 
 	require.ErrorIs(t, err, ErrTransactionForbidden)
 }
 
+func TestTransactionsService_DeleteErrReconciled(t *testing.T) {
+	s, tRepo, _, _ := mockTransactionsService(t)
+
+	ctx := context.Background()
+	id := int64(1)
+
+	tRepo.EXPECT().GetOwner(ctx, id).Return(userId, nil)
+	tRepo.EXPECT().GetStatus(ctx, id).Return(domain.Reconciled, nil)
+
+	err := s.Delete(ctx, id, userId, false)
+
+	require.ErrorIs(t, err, ErrTransactionReconciled)
+}
+
+func TestTransactionsService_DeleteUnlocked(t *testing.T) {
+	s, tRepo, _, _ := mockTransactionsService(t)
+
+	ctx := context.Background()
+	id := int64(1)
+
+	tRepo.EXPECT().GetOwner(ctx, id).Return(userId, nil)
+	tRepo.EXPECT().Delete(ctx, id).Return(nil)
+
+	err := s.Delete(ctx, id, userId, true)
+
+	require.NoError(t, err)
+}
+
 func TestTransactionsService_DeleteErr(t *testing.T) {
 	s, tRepo, _, _ := mockTransactionsService(t)
 
@@ -406,13 +725,163 @@ func TestTransactionsService_DeleteErr(t *testing.T) {
 	id := int64(1)
 
 	tRepo.EXPECT().GetOwner(ctx, id).Return(userId, nil)
+	tRepo.EXPECT().GetStatus(ctx, id).Return(domain.Cleared, nil)
 	tRepo.EXPECT().Delete(ctx, id).Return(errDefault)
 
-	err := s.Delete(ctx, id, userId)
+	err := s.Delete(ctx, id, userId, false)
 
 	require.ErrorIs(t, err, errDefault)
 }
 
+func TestTransactionsService_DeleteBatch(t *testing.T) {
+	s, tRepo, _, _ := mockTransactionsService(t)
+
+	ctx := context.Background()
+
+	tRepo.EXPECT().GetOwner(ctx, int64(1)).Return(userId, nil)
+	tRepo.EXPECT().GetStatus(ctx, int64(1)).Return(domain.Pending, nil)
+	tRepo.EXPECT().GetOwner(ctx, int64(2)).Return(userId+1, nil)
+	tRepo.EXPECT().GetOwner(ctx, int64(3)).Return(int64(0), repo.ErrTransactionNotFound)
+	tRepo.EXPECT().GetOwner(ctx, int64(4)).Return(userId, nil)
+	tRepo.EXPECT().GetStatus(ctx, int64(4)).Return(domain.Reconciled, nil)
+	tRepo.EXPECT().DeleteBatch(ctx, []int64{1}).Return(nil)
+
+	batch, err := s.DeleteBatch(ctx, domain.TransactionsToDelete{Mode: domain.BestEffort, IDs: []int64{1, 2, 1, 3, 4}},
+		userId)
+
+	require.NoError(t, err)
+	require.Equal(t, 1, batch.Applied)
+	require.Equal(t, 4, batch.Failed)
+	require.Equal(t, "", batch.Results[0].Error)
+	require.Equal(t, ErrTransactionForbidden.Error(), batch.Results[1].Error)
+	require.Equal(t, ErrTransactionRepeated.Error(), batch.Results[2].Error)
+	require.Equal(t, int64(3), batch.Results[3].ID)
+	require.Equal(t, repo.ErrTransactionNotFound.Error(), batch.Results[3].Error)
+	require.Equal(t, ErrTransactionReconciled.Error(), batch.Results[4].Error)
+}
+
+func TestTransactionsService_DeleteBatchRejected(t *testing.T) {
+	s, tRepo, _, _ := mockTransactionsService(t)
+
+	ctx := context.Background()
+
+	tRepo.EXPECT().GetOwner(ctx, int64(1)).Return(userId, nil)
+	tRepo.EXPECT().GetStatus(ctx, int64(1)).Return(domain.Cleared, nil)
+	tRepo.EXPECT().GetOwner(ctx, int64(2)).Return(userId+1, nil)
+
+	batch, err := s.DeleteBatch(ctx, domain.TransactionsToDelete{IDs: []int64{1, 2}}, userId)
+
+	require.ErrorIs(t, err, ErrBatchRejected)
+	require.Equal(t, 0, batch.Applied)
+	require.Equal(t, 1, batch.Failed)
+}
+
+func TestTransactionsService_DeleteBatchErr(t *testing.T) {
+	s, tRepo, _, _ := mockTransactionsService(t)
+
+	ctx := context.Background()
+
+	tRepo.EXPECT().GetOwner(ctx, int64(1)).Return(userId, nil)
+	tRepo.EXPECT().DeleteBatch(ctx, []int64{1}).Return(repo.ErrAccountNotEnoughBalance)
+
+	// Status is not checked if transactions are unlocked
+	_, err := s.DeleteBatch(ctx, domain.TransactionsToDelete{IDs: []int64{1}, Unlock: true}, userId)
+
+	require.ErrorIs(t, err, repo.ErrAccountNotEnoughBalance)
+}
+
+func TestTransactionsService_SetStatus(t *testing.T) {
+	s, tRepo, _, _ := mockTransactionsService(t)
+
+	ctx := context.Background()
+	id := int64(1)
+
+	tRepo.EXPECT().GetOwner(ctx, id).Return(userId, nil)
+	tRepo.EXPECT().GetStatus(ctx, id).Return(domain.Pending, nil)
+	tRepo.EXPECT().SetStatus(ctx, id, domain.Cleared).Return(nil)
+
+	err := s.SetStatus(ctx, id, userId, domain.TransactionStatusToUpdate{Status: domain.Cleared})
+
+	require.NoError(t, err)
+}
+
+func TestTransactionsService_SetStatusReconciled(t *testing.T) {
+	s, tRepo, _, _ := mockTransactionsService(t)
+
+	ctx := context.Background()
+	id := int64(1)
+
+	// Reconciling doesn't need unlock
+	tRepo.EXPECT().GetOwner(ctx, id).Return(userId, nil)
+	tRepo.EXPECT().SetStatus(ctx, id, domain.Reconciled).Return(nil)
+
+	err := s.SetStatus(ctx, id, userId, domain.TransactionStatusToUpdate{Status: domain.Reconciled})
+
+	require.NoError(t, err)
+}
+
+func TestTransactionsService_SetStatusErrReconciled(t *testing.T) {
+	s, tRepo, _, _ := mockTransactionsService(t)
+
+	ctx := context.Background()
+	id := int64(1)
+
+	tRepo.EXPECT().GetOwner(ctx, id).Return(userId, nil)
+	tRepo.EXPECT().GetStatus(ctx, id).Return(domain.Reconciled, nil)
+
+	err := s.SetStatus(ctx, id, userId, domain.TransactionStatusToUpdate{Status: domain.Cleared})
+
+	require.ErrorIs(t, err, ErrTransactionReconciled)
+}
+
+func TestTransactionsService_SetStatusErrForbidden(t *testing.T) {
+	s, tRepo, _, _ := mockTransactionsService(t)
+
+	ctx := context.Background()
+	id := int64(1)
+
+	tRepo.EXPECT().GetOwner(ctx, id).Return(userId+1, nil)
+
+	err := s.SetStatus(ctx, id, userId, domain.TransactionStatusToUpdate{Status: domain.Cleared, Unlock: true})
+
+	require.ErrorIs(t, err, ErrTransactionForbidden)
+}
+
+func TestTransactionsService_SetStatusErrStatus(t *testing.T) {
+	s, _, _, _ := mockTransactionsService(t)
+
+	err := s.SetStatus(context.Background(), 1, userId, domain.TransactionStatusToUpdate{Status: "lost"})
+
+	require.ErrorIs(t, err, domain.ErrInvalidTransactionStatus)
+}
+
+func TestTransactionsService_DismissFlags(t *testing.T) {
+	s, tRepo, _, _ := mockTransactionsService(t)
+
+	ctx := context.Background()
+	id := int64(1)
+
+	tRepo.EXPECT().GetOwner(ctx, id).Return(userId, nil)
+	tRepo.EXPECT().DismissFlags(ctx, id).Return(nil)
+
+	err := s.DismissFlags(ctx, id, userId)
+
+	require.NoError(t, err)
+}
+
+func TestTransactionsService_DismissFlagsErrForbidden(t *testing.T) {
+	s, tRepo, _, _ := mockTransactionsService(t)
+
+	ctx := context.Background()
+	id := int64(1)
+
+	tRepo.EXPECT().GetOwner(ctx, id).Return(userId+1, nil)
+
+	err := s.DismissFlags(ctx, id, userId)
+
+	require.ErrorIs(t, err, ErrTransactionForbidden)
+}
+
 func mockTransactionCategoriesService(t *testing.T) (*TransactionCategoryService, *mockRepo.MockTransactionCategories) {
 	t.Helper()
 
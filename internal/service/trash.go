@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"github.com/lotostudio/financial-api/internal/config"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	"github.com/lotostudio/financial-api/pkg/storage"
+	"time"
+)
+
+type TrashService struct {
+	repo         repo.Trash
+	accountsRepo repo.Accounts
+	blobs        storage.BlobStore
+	accCfg       config.Account
+	cfg          config.Trash
+}
+
+func newTrashService(repo repo.Trash, accountsRepo repo.Accounts, blobs storage.BlobStore, accCfg config.Account,
+	cfg config.Trash) *TrashService {
+	return &TrashService{
+		repo:         repo,
+		accountsRepo: accountsRepo,
+		blobs:        blobs,
+		accCfg:       accCfg,
+		cfg:          cfg,
+	}
+}
+
+func (s *TrashService) List(ctx context.Context, userID int64) (domain.Trash, error) {
+	return s.repo.List(ctx, userID)
+}
+
+// RestoreAccount takes account of user out of trash if limit of accounts of its type is not reached
+func (s *TrashService) RestoreAccount(ctx context.Context, id int64, userID int64) error {
+	account, err := s.repo.GetAccount(ctx, id)
+
+	if err != nil {
+		return err
+	}
+
+	if account.OwnerId != userID {
+		return ErrAccountForbidden
+	}
+
+	if err = checkAccountsLimit(ctx, s.accountsRepo, s.accCfg, userID, account.Type); err != nil {
+		return err
+	}
+
+	return s.repo.RestoreAccount(ctx, id)
+}
+
+// RestoreTransaction takes transaction of user out of trash and applies it to balances of accounts again
+func (s *TrashService) RestoreTransaction(ctx context.Context, id int64, userID int64) error {
+	ownerId, err := s.repo.GetTransactionOwner(ctx, id)
+
+	if err != nil {
+		return err
+	}
+
+	if ownerId != userID {
+		return ErrTransactionForbidden
+	}
+
+	return s.repo.RestoreTransaction(ctx, id)
+}
+
+// Purge removes items kept in trash longer than retention period with files of their attachments. Returns count of
+// removed items, nothing is removed if retention is not set
+func (s *TrashService) Purge(ctx context.Context) (int64, error) {
+	if s.cfg.Retention <= 0 {
+		return 0, nil
+	}
+
+	count, keys, err := s.repo.Purge(ctx, time.Now().Add(-s.cfg.Retention))
+
+	if err != nil {
+		return 0, err
+	}
+
+	// Files of attachments are removed after transactions are purged, so they are never referenced while missing
+	deleteBlobs(ctx, s.blobs, keys...)
+
+	return count, nil
+}
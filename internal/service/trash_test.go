@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/lotostudio/financial-api/internal/config"
+	"github.com/lotostudio/financial-api/internal/domain"
+	"github.com/lotostudio/financial-api/internal/repo"
+	mockRepo "github.com/lotostudio/financial-api/internal/repo/mocks"
+	mockStorage "github.com/lotostudio/financial-api/pkg/storage/mocks"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func mockTrashService(t *testing.T, cfg config.Trash) (*TrashService, *mockRepo.MockTrash, *mockRepo.MockAccounts,
+	*mockStorage.MockBlobStore) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	trRepo := mockRepo.NewMockTrash(mockCtl)
+	aRepo := mockRepo.NewMockAccounts(mockCtl)
+	blobs := mockStorage.NewMockBlobStore(mockCtl)
+
+	s := newTrashService(trRepo, aRepo, blobs, config.Account{CardAndCashLimit: 2, LoanAndDepositLimit: 2}, cfg)
+
+	return s, trRepo, aRepo, blobs
+}
+
+func TestTrashService_List(t *testing.T) {
+	s, trRepo, _, _ := mockTrashService(t, config.Trash{})
+
+	ctx := context.Background()
+	trash := domain.Trash{Accounts: []domain.Account{{ID: 1}}, Transactions: []domain.Transaction{}}
+
+	trRepo.EXPECT().List(ctx, userId).Return(trash, nil)
+
+	res, err := s.List(ctx, userId)
+
+	require.NoError(t, err)
+	require.Equal(t, trash, res)
+}
+
+func TestTrashService_RestoreAccount(t *testing.T) {
+	s, trRepo, aRepo, _ := mockTrashService(t, config.Trash{})
+
+	ctx := context.Background()
+	id := int64(1)
+
+	trRepo.EXPECT().GetAccount(ctx, id).Return(domain.Account{ID: id, Type: domain.Card, OwnerId: userId}, nil)
+	aRepo.EXPECT().CountByTypes(ctx, userId, domain.Cash, domain.Card).Return(int64(1), nil)
+	trRepo.EXPECT().RestoreAccount(ctx, id).Return(nil)
+
+	err := s.RestoreAccount(ctx, id, userId)
+
+	require.NoError(t, err)
+}
+
+func TestTrashService_RestoreAccountErrNotFound(t *testing.T) {
+	s, trRepo, _, _ := mockTrashService(t, config.Trash{})
+
+	ctx := context.Background()
+	id := int64(1)
+
+	trRepo.EXPECT().GetAccount(ctx, id).Return(domain.Account{}, repo.ErrAccountNotFound)
+
+	err := s.RestoreAccount(ctx, id, userId)
+
+	require.ErrorIs(t, err, repo.ErrAccountNotFound)
+}
+
+func TestTrashService_RestoreAccountErrForbidden(t *testing.T) {
+	s, trRepo, _, _ := mockTrashService(t, config.Trash{})
+
+	ctx := context.Background()
+	id := int64(1)
+
+	trRepo.EXPECT().GetAccount(ctx, id).Return(domain.Account{ID: id, Type: domain.Cash, OwnerId: userId + 1}, nil)
+
+	err := s.RestoreAccount(ctx, id, userId)
+
+	require.ErrorIs(t, err, ErrAccountForbidden)
+}
+
+func TestTrashService_RestoreAccountErrLimit(t *testing.T) {
+	s, trRepo, aRepo, _ := mockTrashService(t, config.Trash{})
+
+	ctx := context.Background()
+	id := int64(1)
+
+	trRepo.EXPECT().GetAccount(ctx, id).Return(domain.Account{ID: id, Type: domain.Loan, OwnerId: userId}, nil)
+	aRepo.EXPECT().CountByTypes(ctx, userId, domain.Loan, domain.Deposit).Return(int64(2), nil)
+
+	err := s.RestoreAccount(ctx, id, userId)
+
+	require.ErrorIs(t, err, ErrAccountCountLimited)
+}
+
+func TestTrashService_RestoreTransaction(t *testing.T) {
+	s, trRepo, _, _ := mockTrashService(t, config.Trash{})
+
+	ctx := context.Background()
+	id := int64(1)
+
+	trRepo.EXPECT().GetTransactionOwner(ctx, id).Return(userId, nil)
+	trRepo.EXPECT().RestoreTransaction(ctx, id).Return(nil)
+
+	err := s.RestoreTransaction(ctx, id, userId)
+
+	require.NoError(t, err)
+}
+
+func TestTrashService_RestoreTransactionErrForbidden(t *testing.T) {
+	s, trRepo, _, _ := mockTrashService(t, config.Trash{})
+
+	ctx := context.Background()
+	id := int64(1)
+
+	trRepo.EXPECT().GetTransactionOwner(ctx, id).Return(userId+1, nil)
+
+	err := s.RestoreTransaction(ctx, id, userId)
+
+	require.ErrorIs(t, err, ErrTransactionForbidden)
+}
+
+func TestTrashService_RestoreTransactionErr(t *testing.T) {
+	s, trRepo, _, _ := mockTrashService(t, config.Trash{})
+
+	ctx := context.Background()
+	id := int64(1)
+
+	trRepo.EXPECT().GetTransactionOwner(ctx, id).Return(userId, nil)
+	trRepo.EXPECT().RestoreTransaction(ctx, id).Return(repo.ErrTransactionAccountDeleted)
+
+	err := s.RestoreTransaction(ctx, id, userId)
+
+	require.ErrorIs(t, err, repo.ErrTransactionAccountDeleted)
+}
+
+func TestTrashService_Purge(t *testing.T) {
+	s, trRepo, _, blobs := mockTrashService(t, config.Trash{Retention: 24 * time.Hour})
+
+	ctx := context.Background()
+	before := time.Now().Add(-24 * time.Hour)
+
+	trRepo.EXPECT().Purge(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, t time.Time) (int64, []string, error) {
+		if t.Before(before) || t.After(before.Add(time.Minute)) {
+			return 0, nil, errDefault
+		}
+
+		return 3, []string{"transactions/1/a", "transactions/1/a-thumbnail"}, nil
+	})
+	blobs.EXPECT().Delete(ctx, "transactions/1/a").Return(nil)
+	// Items are purged even if file is not
+	blobs.EXPECT().Delete(ctx, "transactions/1/a-thumbnail").Return(errDefault)
+
+	count, err := s.Purge(ctx)
+
+	require.NoError(t, err)
+	require.Equal(t, int64(3), count)
+}
+
+func TestTrashService_PurgeNoRetention(t *testing.T) {
+	s, _, _, _ := mockTrashService(t, config.Trash{})
+
+	count, err := s.Purge(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, int64(0), count)
+}
+
+func TestTrashService_PurgeErr(t *testing.T) {
+	s, trRepo, _, _ := mockTrashService(t, config.Trash{Retention: time.Hour})
+
+	ctx := context.Background()
+
+	trRepo.EXPECT().Purge(ctx, gomock.Any()).Return(int64(0), nil, errDefault)
+
+	_, err := s.Purge(ctx)
+
+	require.ErrorIs(t, err, errDefault)
+}
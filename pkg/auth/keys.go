@@ -0,0 +1,249 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/dgrijalva/jwt-go"
+	"math/big"
+	"os"
+)
+
+var (
+	ErrKeyNotFound     = errors.New("signing key not found")
+	errKeyNotPrivate   = errors.New("active key must be private key")
+	errKeyInvalidPEM   = errors.New("key file is not valid PEM")
+	errKeyNotSupported = errors.New("key type is not supported, use RSA or Ed25519")
+)
+
+// Key is a key for signing (if private part is present) and verifying tokens
+type Key struct {
+	// ID is RFC 7638 thumbprint of public key, used as 'kid' header of tokens
+	ID         string
+	Method     jwt.SigningMethod
+	privateKey crypto.PrivateKey
+	publicKey  crypto.PublicKey
+}
+
+// ParseKey parses PEM encoded RSA or Ed25519 key. Private keys can be PKCS #1 or PKCS #8,
+// public keys must be PKIX. Public keys can only verify tokens
+func ParseKey(data []byte) (Key, error) {
+	block, _ := pem.Decode(data)
+
+	if block == nil {
+		return Key{}, errKeyInvalidPEM
+	}
+
+	var parsed interface{}
+	var err error
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return Key{}, errKeyNotSupported
+	}
+
+	if err != nil {
+		return Key{}, err
+	}
+
+	var key Key
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key = Key{Method: jwt.SigningMethodRS256, privateKey: k, publicKey: &k.PublicKey}
+	case *rsa.PublicKey:
+		key = Key{Method: jwt.SigningMethodRS256, publicKey: k}
+	case ed25519.PrivateKey:
+		key = Key{Method: SigningMethodEdDSA, privateKey: k, publicKey: k.Public()}
+	case ed25519.PublicKey:
+		key = Key{Method: SigningMethodEdDSA, publicKey: k}
+	default:
+		return Key{}, errKeyNotSupported
+	}
+
+	key.ID, err = key.JWK().thumbprint()
+
+	return key, err
+}
+
+// LoadKey reads key from PEM file
+func LoadKey(path string) (Key, error) {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return Key{}, err
+	}
+
+	key, err := ParseKey(data)
+
+	if err != nil {
+		return Key{}, fmt.Errorf("error parsing key %s - %w", path, err)
+	}
+
+	return key, nil
+}
+
+// JWK returns public part of key as JSON Web Key
+func (k Key) JWK() JWK {
+	jwk := JWK{
+		KeyID:     k.ID,
+		Use:       "sig",
+		Algorithm: k.Method.Alg(),
+	}
+
+	switch p := k.publicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(p.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(p)
+	}
+
+	return jwk
+}
+
+// JWK is JSON Web Key (RFC 7517) with public key parameters
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	// RSA key parameters
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519 key parameters
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+// thumbprint computes RFC 7638 thumbprint of key
+func (j JWK) thumbprint() (string, error) {
+	var members interface{}
+
+	switch j.KeyType {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{j.E, j.KeyType, j.N}
+	case "OKP":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{j.Curve, j.KeyType, j.X}
+	default:
+		return "", errKeyNotSupported
+	}
+
+	data, err := json.Marshal(members)
+
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// JWKSet is JSON Web Key Set served to verifiers of tokens
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// KeyRing holds active key used for signing and previous keys still accepted for verifying
+type KeyRing struct {
+	active Key
+	keys   map[string]Key
+	order  []string
+}
+
+func NewKeyRing(active Key, previous ...Key) (*KeyRing, error) {
+	if active.privateKey == nil {
+		return nil, errKeyNotPrivate
+	}
+
+	r := &KeyRing{
+		active: active,
+		keys:   make(map[string]Key),
+	}
+
+	for _, k := range append([]Key{active}, previous...) {
+		if _, ok := r.keys[k.ID]; ok {
+			continue
+		}
+
+		r.keys[k.ID] = k
+		r.order = append(r.order, k.ID)
+	}
+
+	return r, nil
+}
+
+// LoadKeyRing reads active private key and previous keys from PEM files
+func LoadKeyRing(activePath string, previousPaths []string) (*KeyRing, error) {
+	active, err := LoadKey(activePath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	previous := make([]Key, 0, len(previousPaths))
+
+	for _, path := range previousPaths {
+		key, err := LoadKey(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		previous = append(previous, key)
+	}
+
+	return NewKeyRing(active, previous...)
+}
+
+// Active returns key used for signing
+func (r *KeyRing) Active() Key {
+	return r.active
+}
+
+// Get returns key by its ID
+func (r *KeyRing) Get(id string) (Key, error) {
+	key, ok := r.keys[id]
+
+	if !ok {
+		return Key{}, ErrKeyNotFound
+	}
+
+	return key, nil
+}
+
+// JWKS returns public parts of all keys, active key first
+func (r *KeyRing) JWKS() JWKSet {
+	set := JWKSet{Keys: make([]JWK, 0, len(r.order))}
+
+	for _, id := range r.order {
+		set.Keys = append(set.Keys, r.keys[id].JWK())
+	}
+
+	return set
+}
@@ -10,13 +10,32 @@ import (
 
 // TokenManager provides token issuing and decoding
 type TokenManager interface {
-	Issue(subject string) (string, error)
-	Decode(token string) (string, error)
+	Issue(claims Claims) (string, error)
+	Decode(token string) (Claims, error)
 	Random() (string, error)
+	// JWKS returns public keys for verifying tokens. Set is empty for symmetric signing
+	JWKS() JWKSet
 }
 
+// Claims are data carried by access token
+type Claims struct {
+	Subject   string
+	Role      string
+	SessionID string
+}
+
+type jwtClaims struct {
+	jwt.StandardClaims
+	Role      string `json:"role,omitempty"`
+	SessionID string `json:"sid,omitempty"`
+}
+
+// JWTManager issues tokens signed either with HMAC secret or with key ring of asymmetric keys
 type JWTManager struct {
 	signingKey        string
+	keys              *KeyRing
+	issuer            string
+	audience          string
 	accessTokenTTL    time.Duration
 	randomTokenLength int
 }
@@ -33,34 +52,116 @@ func NewJWTManager(signingKey string, accessTokenTTL time.Duration, randomTokenL
 	}, nil
 }
 
-func (m *JWTManager) Issue(subject string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{
-		ExpiresAt: time.Now().Add(m.accessTokenTTL).Unix(),
-		Subject:   subject,
-	})
+// NewKeyRingJWTManager creates manager signing tokens with active key of key ring.
+// Tokens are issued for given issuer and audience and only such tokens are decoded
+func NewKeyRingJWTManager(keys *KeyRing, issuer string, audience string, accessTokenTTL time.Duration,
+	randomTokenLength int) (*JWTManager, error) {
+	if keys == nil {
+		return nil, errors.New("empty key ring")
+	}
+
+	return &JWTManager{
+		keys:              keys,
+		issuer:            issuer,
+		audience:          audience,
+		accessTokenTTL:    accessTokenTTL,
+		randomTokenLength: randomTokenLength,
+	}, nil
+}
+
+func (m *JWTManager) Issue(claims Claims) (string, error) {
+	now := time.Now()
+
+	c := jwtClaims{
+		StandardClaims: jwt.StandardClaims{
+			Issuer:    m.issuer,
+			Audience:  m.audience,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(m.accessTokenTTL).Unix(),
+			Subject:   claims.Subject,
+		},
+		Role:      claims.Role,
+		SessionID: claims.SessionID,
+	}
+
+	if m.keys == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString([]byte(m.signingKey))
+	}
+
+	key := m.keys.Active()
+
+	token := jwt.NewWithClaims(key.Method, c)
+	token.Header["kid"] = key.ID
+
+	return token.SignedString(key.privateKey)
+}
+
+func (m *JWTManager) Decode(token string) (Claims, error) {
+	t, err := jwt.ParseWithClaims(token, &jwtClaims{}, m.verificationKey)
+
+	if err != nil {
+		return Claims{}, err
+	}
+
+	claims, ok := t.Claims.(*jwtClaims)
+	if !ok {
+		return Claims{}, fmt.Errorf("error get user claims from t")
+	}
+
+	if m.issuer != "" && !claims.VerifyIssuer(m.issuer, true) {
+		return Claims{}, errors.New("token has invalid issuer")
+	}
+
+	if m.audience != "" && !claims.VerifyAudience(m.audience, true) {
+		return Claims{}, errors.New("token has invalid audience")
+	}
+
+	if claims.IssuedAt == 0 {
+		return Claims{}, errors.New("token has no issue time")
+	}
+
+	if claims.SessionID == "" {
+		return Claims{}, errors.New("token has no session")
+	}
 
-	return token.SignedString([]byte(m.signingKey))
+	return Claims{
+		Subject:   claims.Subject,
+		Role:      claims.Role,
+		SessionID: claims.SessionID,
+	}, nil
 }
 
-func (m *JWTManager) Decode(token string) (string, error) {
-	t, err := jwt.Parse(token, func(token *jwt.Token) (i interface{}, err error) {
+// verificationKey selects key by signing method and 'kid' header of token
+func (m *JWTManager) verificationKey(token *jwt.Token) (interface{}, error) {
+	if m.keys == nil {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 
 		return []byte(m.signingKey), nil
-	})
+	}
+
+	kid, _ := token.Header["kid"].(string)
+
+	key, err := m.keys.Get(kid)
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	claims, ok := t.Claims.(jwt.MapClaims)
-	if !ok {
-		return "", fmt.Errorf("error get user claims from t")
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	return key.publicKey, nil
+}
+
+func (m *JWTManager) JWKS() JWKSet {
+	if m.keys == nil {
+		return JWKSet{Keys: []JWK{}}
 	}
 
-	return claims["sub"].(string), nil
+	return m.keys.JWKS()
 }
 
 func (m *JWTManager) Random() (string, error) {
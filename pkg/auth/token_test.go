@@ -1,6 +1,12 @@
 package auth
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/stretchr/testify/require"
 	"testing"
 	"time"
@@ -22,17 +28,17 @@ func TestNewJWTManager_emptyKey(t *testing.T) {
 
 func TestJWTManager_IssueAndDecode(t *testing.T) {
 	m := newTestJWTManager(t)
-	userId := "1"
+	claims := Claims{Subject: "1", Role: "admin", SessionID: "5.1"}
 
-	token, err := m.Issue(userId)
+	token, err := m.Issue(claims)
 
 	require.NoError(t, err)
 	require.NotNil(t, token)
 
-	id, err := m.Decode(token)
+	decoded, err := m.Decode(token)
 
 	require.NoError(t, err)
-	require.Equal(t, userId, id)
+	require.Equal(t, claims, decoded)
 }
 
 func TestJWTManager_DecodeErr(t *testing.T) {
@@ -43,6 +49,27 @@ func TestJWTManager_DecodeErr(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestJWTManager_DecodeErrSession(t *testing.T) {
+	m := newTestJWTManager(t)
+
+	// Session ID is required
+	token, _ := m.Issue(Claims{Subject: "1"})
+
+	_, err := m.Decode(token)
+
+	require.EqualError(t, err, "token has no session")
+
+	// Issue time is required
+	token, _ = jwt.NewWithClaims(jwt.SigningMethodHS256, jwtClaims{
+		StandardClaims: jwt.StandardClaims{Subject: "1", ExpiresAt: time.Now().Add(time.Hour).Unix()},
+		SessionID:      "5.1",
+	}).SignedString([]byte("key"))
+
+	_, err = m.Decode(token)
+
+	require.EqualError(t, err, "token has no issue time")
+}
+
 func TestJWTManager_Refresh(t *testing.T) {
 	m := newTestJWTManager(t)
 
@@ -50,3 +77,137 @@ func TestJWTManager_Refresh(t *testing.T) {
 
 	require.NoError(t, err)
 }
+
+func newTestKey(t *testing.T, private interface{}) Key {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+
+	key, err := ParseKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	require.NoError(t, err)
+
+	return key
+}
+
+func newTestRSAKey(t *testing.T) Key {
+	t.Helper()
+
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	return newTestKey(t, private)
+}
+
+func newTestEd25519Key(t *testing.T) Key {
+	t.Helper()
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	return newTestKey(t, private)
+}
+
+func TestJWTManager_KeyRing(t *testing.T) {
+	for _, key := range []Key{newTestRSAKey(t), newTestEd25519Key(t)} {
+		t.Run(key.Method.Alg(), func(t *testing.T) {
+			ring, err := NewKeyRing(key)
+			require.NoError(t, err)
+
+			m, err := NewKeyRingJWTManager(ring, "issuer", "audience", time.Hour, 32)
+			require.NoError(t, err)
+
+			claims := Claims{Subject: "1", Role: "user", SessionID: "5"}
+
+			token, err := m.Issue(claims)
+			require.NoError(t, err)
+
+			decoded, err := m.Decode(token)
+
+			require.NoError(t, err)
+			require.Equal(t, claims, decoded)
+
+			jwks := m.JWKS()
+
+			require.Len(t, jwks.Keys, 1)
+			require.Equal(t, key.ID, jwks.Keys[0].KeyID)
+			require.Equal(t, key.Method.Alg(), jwks.Keys[0].Algorithm)
+		})
+	}
+}
+
+func TestJWTManager_KeyRotation(t *testing.T) {
+	oldKey := newTestEd25519Key(t)
+	newKey := newTestRSAKey(t)
+
+	oldRing, _ := NewKeyRing(oldKey)
+	oldManager, _ := NewKeyRingJWTManager(oldRing, "issuer", "audience", time.Hour, 32)
+
+	token, err := oldManager.Issue(Claims{Subject: "1", SessionID: "5.1"})
+	require.NoError(t, err)
+
+	// Token signed with previous key is still valid
+	ring, _ := NewKeyRing(newKey, oldKey)
+	m, _ := NewKeyRingJWTManager(ring, "issuer", "audience", time.Hour, 32)
+
+	decoded, err := m.Decode(token)
+
+	require.NoError(t, err)
+	require.Equal(t, "1", decoded.Subject)
+	require.Len(t, m.JWKS().Keys, 2)
+	require.Equal(t, newKey.ID, m.JWKS().Keys[0].KeyID)
+
+	// Token signed with removed key is rejected
+	ring, _ = NewKeyRing(newKey)
+	m, _ = NewKeyRingJWTManager(ring, "issuer", "audience", time.Hour, 32)
+
+	_, err = m.Decode(token)
+
+	require.Error(t, err)
+}
+
+func TestJWTManager_DecodeErrClaims(t *testing.T) {
+	ring, _ := NewKeyRing(newTestEd25519Key(t))
+
+	m, _ := NewKeyRingJWTManager(ring, "issuer", "audience", time.Hour, 32)
+	other, _ := NewKeyRingJWTManager(ring, "other", "other", time.Hour, 32)
+	expired, _ := NewKeyRingJWTManager(ring, "issuer", "audience", -time.Hour, 32)
+
+	token, _ := other.Issue(Claims{Subject: "1"})
+
+	_, err := m.Decode(token)
+
+	require.Error(t, err)
+
+	token, _ = expired.Issue(Claims{Subject: "1"})
+
+	_, err = m.Decode(token)
+
+	require.Error(t, err)
+}
+
+func TestJWTManager_DecodeErrAlgorithm(t *testing.T) {
+	ring, _ := NewKeyRing(newTestEd25519Key(t))
+	m, _ := NewKeyRingJWTManager(ring, "", "", time.Hour, 32)
+
+	// Token signed with HMAC must not be accepted by key ring manager
+	token, _ := newTestJWTManager(t).Issue(Claims{Subject: "1"})
+
+	_, err := m.Decode(token)
+
+	require.Error(t, err)
+}
+
+func TestNewKeyRing_publicKey(t *testing.T) {
+	key := newTestEd25519Key(t)
+
+	der, _ := x509.MarshalPKIXPublicKey(key.publicKey)
+	public, err := ParseKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	require.NoError(t, err)
+	require.Equal(t, key.ID, public.ID)
+
+	_, err = NewKeyRing(public)
+
+	require.Error(t, err)
+}
@@ -0,0 +1,53 @@
+package limiter
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit describes token bucket: bucket holds at most Burst tokens and is refilled with Rate tokens per second
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Every creates limit allowing given count of requests per period
+func Every(requests int, period time.Duration) Limit {
+	if requests <= 0 || period <= 0 {
+		return Limit{}
+	}
+
+	return Limit{
+		Rate:  float64(requests) / period.Seconds(),
+		Burst: requests,
+	}
+}
+
+// Unlimited reports whether limit does not restrict anything
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0 || l.Burst <= 0
+}
+
+// take refills bucket having given count of tokens since last update and tries to take one token.
+// Returns count of tokens left and time to wait for the next token if bucket is empty
+func (l Limit) take(tokens float64, last time.Time, now time.Time) (float64, time.Duration) {
+	if elapsed := now.Sub(last).Seconds(); elapsed > 0 {
+		tokens = math.Min(float64(l.Burst), tokens+elapsed*l.Rate)
+	}
+
+	if tokens >= 1 {
+		return tokens - 1, 0
+	}
+
+	wait := time.Duration((1 - tokens) / l.Rate * float64(time.Second))
+
+	return tokens, wait
+}
+
+// Store keeps token buckets by keys
+type Store interface {
+	// Take tries to take token from bucket of given key.
+	// Returns zero if token taken, otherwise time to wait until the next token is available
+	Take(ctx context.Context, key string, limit Limit) (time.Duration, error)
+}
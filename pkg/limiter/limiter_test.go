@@ -0,0 +1,78 @@
+package limiter
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestEvery(t *testing.T) {
+	l := Every(60, time.Minute)
+
+	require.Equal(t, Limit{Rate: 1, Burst: 60}, l)
+	require.False(t, l.Unlimited())
+	require.True(t, Every(0, time.Minute).Unlimited())
+	require.True(t, Every(10, 0).Unlimited())
+}
+
+func TestLimit_take(t *testing.T) {
+	l := Limit{Rate: 1, Burst: 2}
+	now := time.Now()
+
+	tokens, wait := l.take(2, now, now)
+
+	require.Equal(t, 1.0, tokens)
+	require.Zero(t, wait)
+
+	tokens, wait = l.take(0.5, now, now)
+
+	require.Equal(t, 0.5, tokens)
+	require.Equal(t, 500*time.Millisecond, wait)
+
+	// Bucket is refilled up to burst
+	tokens, wait = l.take(0, now, now.Add(time.Hour))
+
+	require.Equal(t, 1.0, tokens)
+	require.Zero(t, wait)
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	l := Every(2, time.Hour)
+
+	for i := 0; i < 2; i++ {
+		wait, err := s.Take(ctx, "key", l)
+
+		require.NoError(t, err)
+		require.Zero(t, wait)
+	}
+
+	wait, err := s.Take(ctx, "key", l)
+
+	require.NoError(t, err)
+	require.True(t, wait > 0)
+
+	wait, err = s.Take(ctx, "other", l)
+
+	require.NoError(t, err)
+	require.Zero(t, wait)
+}
+
+func TestMemoryStore_TakeUnlimited(t *testing.T) {
+	s := NewMemoryStore()
+
+	for i := 0; i < 5; i++ {
+		wait, err := s.Take(context.Background(), "key", Limit{})
+
+		require.NoError(t, err)
+		require.Zero(t, wait)
+	}
+}
+
+func TestNewPostgresStore_Retention(t *testing.T) {
+	require.Equal(t, bucketRetention, NewPostgresStore(nil).retention)
+	require.Equal(t, bucketRetention, NewPostgresStore(nil, time.Minute, time.Hour).retention)
+	require.Equal(t, 72*time.Hour, NewPostgresStore(nil, time.Minute, 72*time.Hour).retention)
+}
@@ -0,0 +1,126 @@
+package limiter
+
+import (
+	"context"
+	"time"
+)
+
+// Max exponent of lock duration growth, protects from overflow
+const maxLockoutShift = 20
+
+// Failures are forgotten after this time without new failures if window of store is not set
+const defaultLockoutWindow = 24 * time.Hour
+
+// lockoutWindow returns window of failures, default one if window is not set
+func lockoutWindow(window time.Duration) time.Duration {
+	if window <= 0 {
+		return defaultLockoutWindow
+	}
+
+	return window
+}
+
+// LockoutStore keeps consecutive failed attempts by keys
+type LockoutStore interface {
+	// LockedUntil returns time until key is locked. Zero time is returned if key is not locked
+	LockedUntil(ctx context.Context, key string) (time.Time, error)
+	// Fail increments and returns count of consecutive failures of key. Failures are counted anew once window of
+	// store passed since the last failure and lock of key is over
+	Fail(ctx context.Context, key string) (int, error)
+	// Lock locks key until given time
+	Lock(ctx context.Context, key string, until time.Time) error
+	// Reset forgets failures and lock of key
+	Reset(ctx context.Context, key string) error
+}
+
+// Lockout locks keys after given count of consecutive failed attempts.
+// Every next failure of locked key doubles lock duration up to max duration
+type Lockout struct {
+	store       LockoutStore
+	attempts    int
+	duration    time.Duration
+	maxDuration time.Duration
+}
+
+// NewLockout creates lockout. Lockout is disabled if attempts count is not positive
+func NewLockout(store LockoutStore, attempts int, duration time.Duration, maxDuration time.Duration) *Lockout {
+	return &Lockout{
+		store:       store,
+		attempts:    attempts,
+		duration:    duration,
+		maxDuration: maxDuration,
+	}
+}
+
+func (l *Lockout) enabled() bool {
+	return l != nil && l.store != nil && l.attempts > 0
+}
+
+// Check returns time left until key is unlocked
+func (l *Lockout) Check(ctx context.Context, key string) (time.Duration, error) {
+	if !l.enabled() {
+		return 0, nil
+	}
+
+	until, err := l.store.LockedUntil(ctx, key)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if left := time.Until(until); left > 0 {
+		return left, nil
+	}
+
+	return 0, nil
+}
+
+// Fail registers failed attempt of key. Returns lock duration if key became locked
+func (l *Lockout) Fail(ctx context.Context, key string) (time.Duration, error) {
+	if !l.enabled() {
+		return 0, nil
+	}
+
+	failures, err := l.store.Fail(ctx, key)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if failures < l.attempts {
+		return 0, nil
+	}
+
+	d := l.lockDuration(failures)
+
+	if err = l.store.Lock(ctx, key, time.Now().Add(d)); err != nil {
+		return 0, err
+	}
+
+	return d, nil
+}
+
+// Reset forgets failures of key, e.g. after successful attempt
+func (l *Lockout) Reset(ctx context.Context, key string) error {
+	if !l.enabled() {
+		return nil
+	}
+
+	return l.store.Reset(ctx, key)
+}
+
+func (l *Lockout) lockDuration(failures int) time.Duration {
+	shift := failures - l.attempts
+
+	if shift > maxLockoutShift {
+		shift = maxLockoutShift
+	}
+
+	d := l.duration << uint(shift)
+
+	if l.maxDuration > 0 && d > l.maxDuration {
+		d = l.maxDuration
+	}
+
+	return d
+}
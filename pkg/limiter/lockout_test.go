@@ -0,0 +1,101 @@
+package limiter
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestLockout(t *testing.T) {
+	l := NewLockout(NewMemoryLockoutStore(time.Hour), 2, time.Minute, 3*time.Minute)
+	ctx := context.Background()
+
+	d, err := l.Fail(ctx, "key")
+
+	require.NoError(t, err)
+	require.Zero(t, d)
+
+	left, _ := l.Check(ctx, "key")
+
+	require.Zero(t, left)
+
+	// Lock duration doubles on every next failure up to max duration
+	for _, expected := range []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute} {
+		d, err = l.Fail(ctx, "key")
+
+		require.NoError(t, err)
+		require.Equal(t, expected, d)
+	}
+
+	left, err = l.Check(ctx, "key")
+
+	require.NoError(t, err)
+	require.True(t, left > 0)
+
+	require.NoError(t, l.Reset(ctx, "key"))
+
+	left, _ = l.Check(ctx, "key")
+
+	require.Zero(t, left)
+}
+
+func TestLockout_Disabled(t *testing.T) {
+	l := NewLockout(NewMemoryLockoutStore(time.Hour), 0, time.Minute, time.Hour)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		d, err := l.Fail(ctx, "key")
+
+		require.NoError(t, err)
+		require.Zero(t, d)
+	}
+
+	left, _ := l.Check(ctx, "key")
+
+	require.Zero(t, left)
+}
+
+func TestMemoryLockoutStore_Window(t *testing.T) {
+	s := NewMemoryLockoutStore(time.Hour)
+	ctx := context.Background()
+
+	_, _ = s.Fail(ctx, "key")
+	failures, _ := s.Fail(ctx, "key")
+
+	require.Equal(t, 2, failures)
+
+	// Failures made before window are forgotten
+	s.attempts["key"].updated = time.Now().Add(-2 * time.Hour)
+
+	failures, err := s.Fail(ctx, "key")
+
+	require.NoError(t, err)
+	require.Equal(t, 1, failures)
+
+	// Failures of locked key are kept until lock is over
+	s.attempts["key"].updated = time.Now().Add(-2 * time.Hour)
+	s.attempts["key"].lockedUntil = time.Now().Add(time.Minute)
+
+	failures, _ = s.Fail(ctx, "key")
+
+	require.Equal(t, 2, failures)
+}
+
+func TestMemoryLockoutStore_Sweep(t *testing.T) {
+	s := NewMemoryLockoutStore(time.Hour)
+	ctx := context.Background()
+
+	_, _ = s.Fail(ctx, "old")
+	_, _ = s.Fail(ctx, "recent")
+
+	s.attempts["old"].updated = time.Now().Add(-2 * time.Hour)
+	s.lastSweep = time.Now().Add(-sweepPeriod)
+
+	until, err := s.LockedUntil(ctx, "recent")
+
+	require.NoError(t, err)
+	require.Zero(t, until)
+	require.Len(t, s.attempts, 1)
+	require.Contains(t, s.attempts, "recent")
+}
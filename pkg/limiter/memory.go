@@ -0,0 +1,175 @@
+package limiter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Period of removing idle buckets from memory
+const sweepPeriod = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   Limit
+}
+
+// MemoryStore keeps token buckets in memory of single instance
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (time.Duration, error) {
+	if limit.Unlimited() {
+		return 0, nil
+	}
+
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		s.buckets[key] = b
+	}
+
+	var wait time.Duration
+
+	b.tokens, wait = limit.take(b.tokens, b.updated, now)
+	b.updated = now
+	b.limit = limit
+
+	return wait, nil
+}
+
+// sweep removes buckets which are full again, so they are equal to absent ones
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepPeriod {
+		return
+	}
+
+	for key, b := range s.buckets {
+		if b.tokens+now.Sub(b.updated).Seconds()*b.limit.Rate >= float64(b.limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+
+	s.lastSweep = now
+}
+
+type attempts struct {
+	failures    int
+	lockedUntil time.Time
+	updated     time.Time
+}
+
+// expired reports whether attempts are outdated: window passed since the last failure and lock is over
+func (a *attempts) expired(now time.Time, window time.Duration) bool {
+	return now.Sub(a.updated) > window && !now.Before(a.lockedUntil)
+}
+
+// MemoryLockoutStore keeps failed attempts in memory of single instance
+type MemoryLockoutStore struct {
+	mu        sync.Mutex
+	attempts  map[string]*attempts
+	window    time.Duration
+	lastSweep time.Time
+}
+
+// NewMemoryLockoutStore creates store forgetting failures after window without new failures
+func NewMemoryLockoutStore(window time.Duration) *MemoryLockoutStore {
+	return &MemoryLockoutStore{
+		attempts:  make(map[string]*attempts),
+		window:    lockoutWindow(window),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *MemoryLockoutStore) LockedUntil(_ context.Context, key string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(time.Now())
+
+	if a, ok := s.attempts[key]; ok {
+		return a.lockedUntil, nil
+	}
+
+	return time.Time{}, nil
+}
+
+func (s *MemoryLockoutStore) Fail(_ context.Context, key string) (int, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	a, ok := s.attempts[key]
+
+	if !ok || a.expired(now, s.window) {
+		a = &attempts{}
+		s.attempts[key] = a
+	}
+
+	a.failures++
+	a.updated = now
+
+	return a.failures, nil
+}
+
+func (s *MemoryLockoutStore) Lock(_ context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.attempts[key]
+
+	if !ok {
+		a = &attempts{updated: time.Now()}
+		s.attempts[key] = a
+	}
+
+	a.lockedUntil = until
+
+	return nil
+}
+
+func (s *MemoryLockoutStore) Reset(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.attempts, key)
+
+	return nil
+}
+
+// sweep removes outdated attempts, so keys of arbitrary logins don't pile up in memory
+func (s *MemoryLockoutStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepPeriod {
+		return
+	}
+
+	for key, a := range s.attempts {
+		if a.expired(now, s.window) {
+			delete(s.attempts, key)
+		}
+	}
+
+	s.lastSweep = now
+}
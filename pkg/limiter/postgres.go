@@ -0,0 +1,188 @@
+package limiter
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+)
+
+// Buckets not updated for this time are removed from table unless limits have longer periods. Buckets are full after
+// period of their limit, so such buckets are equal to absent ones
+const bucketRetention = 24 * time.Hour
+
+// PostgresStore keeps token buckets in rate_limits table, so limits are shared between instances
+type PostgresStore struct {
+	db        *sql.DB
+	retention time.Duration
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+// NewPostgresStore creates store for limits with given periods. Idle buckets are kept for the longest of periods
+func NewPostgresStore(db *sql.DB, periods ...time.Duration) *PostgresStore {
+	retention := bucketRetention
+
+	for _, period := range periods {
+		if period > retention {
+			retention = period
+		}
+	}
+
+	return &PostgresStore{db: db, retention: retention, lastSweep: time.Now()}
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit) (time.Duration, error) {
+	if limit.Unlimited() {
+		return 0, nil
+	}
+
+	if err := s.sweep(ctx); err != nil {
+		return 0, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return 0, err
+	}
+
+	wait, err := s.take(ctx, tx, key, limit)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	return wait, tx.Commit()
+}
+
+func (s *PostgresStore) take(ctx context.Context, tx *sql.Tx, key string, limit Limit) (time.Duration, error) {
+	now := time.Now().UTC()
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO rate_limits (key, tokens, updated_at) VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO NOTHING`, key, limit.Burst, now); err != nil {
+		return 0, err
+	}
+
+	var tokens float64
+	var updated time.Time
+
+	if err := tx.QueryRowContext(ctx, `SELECT tokens, updated_at FROM rate_limits WHERE key = $1 FOR UPDATE`,
+		key).Scan(&tokens, &updated); err != nil {
+		return 0, err
+	}
+
+	tokens, wait := limit.take(tokens, updated, now)
+
+	if _, err := tx.ExecContext(ctx, `UPDATE rate_limits SET tokens = $1, updated_at = $2 WHERE key = $3`,
+		tokens, now, key); err != nil {
+		return 0, err
+	}
+
+	return wait, nil
+}
+
+// sweep removes idle buckets once in sweep period
+func (s *PostgresStore) sweep(ctx context.Context) error {
+	if !sweepDue(&s.mu, &s.lastSweep) {
+		return nil
+	}
+
+	_, err := s.db.ExecContext(ctx, `DELETE FROM rate_limits WHERE updated_at < $1`,
+		time.Now().UTC().Add(-s.retention))
+
+	return err
+}
+
+// PostgresLockoutStore keeps failed attempts in login_attempts table, so lockouts are shared between instances
+type PostgresLockoutStore struct {
+	db     *sql.DB
+	window time.Duration
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+// NewPostgresLockoutStore creates store forgetting failures after window without new failures
+func NewPostgresLockoutStore(db *sql.DB, window time.Duration) *PostgresLockoutStore {
+	return &PostgresLockoutStore{db: db, window: lockoutWindow(window), lastSweep: time.Now()}
+}
+
+func (s *PostgresLockoutStore) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	var until sql.NullTime
+
+	err := s.db.QueryRowContext(ctx, `SELECT locked_until FROM login_attempts WHERE key = $1`, key).Scan(&until)
+
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return until.Time, nil
+}
+
+func (s *PostgresLockoutStore) Fail(ctx context.Context, key string) (int, error) {
+	if err := s.sweep(ctx); err != nil {
+		return 0, err
+	}
+
+	var failures int
+	now := time.Now().UTC()
+
+	// Outdated failures are counted anew
+	err := s.db.QueryRowContext(ctx, `INSERT INTO login_attempts (key, failures, updated_at) VALUES ($1, 1, $2)
+		ON CONFLICT (key) DO UPDATE SET failures = CASE
+			WHEN login_attempts.updated_at < $3 AND coalesce(login_attempts.locked_until <= $2, true) THEN 1
+			ELSE login_attempts.failures + 1 END,
+		updated_at = EXCLUDED.updated_at
+		RETURNING failures`, key, now, now.Add(-s.window)).Scan(&failures)
+
+	return failures, err
+}
+
+func (s *PostgresLockoutStore) Lock(ctx context.Context, key string, until time.Time) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE login_attempts SET locked_until = $1 WHERE key = $2`, until.UTC(), key)
+
+	return err
+}
+
+func (s *PostgresLockoutStore) Reset(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM login_attempts WHERE key = $1`, key)
+
+	return err
+}
+
+// sweep removes outdated attempts once in sweep period, so keys of arbitrary logins don't pile up in table
+func (s *PostgresLockoutStore) sweep(ctx context.Context) error {
+	if !sweepDue(&s.mu, &s.lastSweep) {
+		return nil
+	}
+
+	now := time.Now().UTC()
+
+	_, err := s.db.ExecContext(ctx, `DELETE FROM login_attempts
+		WHERE updated_at < $1 AND coalesce(locked_until <= $2, true)`, now.Add(-s.window), now)
+
+	return err
+}
+
+// sweepDue reports whether sweep period passed since the last sweep and marks sweep as done then
+func sweepDue(mu *sync.Mutex, lastSweep *time.Time) bool {
+	mu.Lock()
+	defer mu.Unlock()
+
+	now := time.Now()
+
+	if now.Sub(*lastSweep) < sweepPeriod {
+		return false
+	}
+
+	*lastSweep = now
+
+	return true
+}
@@ -0,0 +1,91 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"github.com/lotostudio/financial-api/pkg/oidc/oidctest"
+	"github.com/stretchr/testify/require"
+	"net/url"
+	"testing"
+)
+
+func newTestProvider(t *testing.T) (*Provider, *oidctest.Server) {
+	t.Helper()
+
+	server, err := oidctest.NewServer("client", "secret")
+	require.NoError(t, err)
+
+	t.Cleanup(server.Close)
+
+	p := NewProvider(Config{
+		Issuer:       server.Issuer(),
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost/callback",
+		Scopes:       []string{"email"},
+	}, server.Client())
+
+	return p, server
+}
+
+func TestProvider_Flow(t *testing.T) {
+	p, server := newTestProvider(t)
+	ctx := context.Background()
+
+	server.User = oidctest.User{Subject: "42", Email: "user@example.com", EmailVerified: true, GivenName: "John"}
+
+	verifier, _ := Random()
+
+	authURL, err := p.AuthCodeURL(ctx, "state", "nonce", verifier)
+
+	require.NoError(t, err)
+
+	u, _ := url.Parse(authURL)
+
+	require.Equal(t, "openid email", u.Query().Get("scope"))
+	require.Equal(t, Challenge(verifier), u.Query().Get("code_challenge"))
+
+	code, state, err := server.Authorize(authURL)
+
+	require.NoError(t, err)
+	require.Equal(t, "state", state)
+
+	identity, err := p.Exchange(ctx, code, verifier, "nonce")
+
+	require.NoError(t, err)
+	require.Equal(t, Identity{Subject: "42", Email: "user@example.com", EmailVerified: true, GivenName: "John"}, identity)
+}
+
+func TestProvider_ExchangeErrVerifier(t *testing.T) {
+	p, server := newTestProvider(t)
+	ctx := context.Background()
+
+	verifier, _ := Random()
+	authURL, _ := p.AuthCodeURL(ctx, "state", "nonce", verifier)
+	code, _, _ := server.Authorize(authURL)
+
+	_, err := p.Exchange(ctx, code, "other verifier", "nonce")
+
+	require.True(t, errors.Is(err, ErrTokenExchange))
+}
+
+func TestProvider_VerifyErr(t *testing.T) {
+	p, server := newTestProvider(t)
+	ctx := context.Background()
+
+	token, _ := server.IDToken("client", "nonce")
+
+	_, err := p.Verify(ctx, token, "other nonce")
+
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	token, _ = server.IDToken("other client", "nonce")
+
+	_, err = p.Verify(ctx, token, "nonce")
+
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = p.Verify(ctx, "qwe", "nonce")
+
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
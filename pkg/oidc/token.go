@@ -0,0 +1,222 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dgrijalva/jwt-go"
+	"math/big"
+	"time"
+)
+
+// Allowed clock difference with provider
+const leeway = time.Minute
+
+// audience is 'aud' claim, which can be either string or array of strings
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+
+	var multiple []string
+
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+
+	*a = multiple
+
+	return nil
+}
+
+func (a audience) contains(v string) bool {
+	for _, item := range a {
+		if item == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+type idTokenClaims struct {
+	Issuer        string   `json:"iss"`
+	Subject       string   `json:"sub"`
+	Audience      audience `json:"aud"`
+	ExpiresAt     int64    `json:"exp"`
+	IssuedAt      int64    `json:"iat"`
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified bool     `json:"email_verified"`
+	GivenName     string   `json:"given_name"`
+	FamilyName    string   `json:"family_name"`
+}
+
+func (c idTokenClaims) Valid() error {
+	now := time.Now()
+
+	if c.ExpiresAt == 0 || now.Add(-leeway).Unix() > c.ExpiresAt {
+		return errors.New("token is expired")
+	}
+
+	if now.Add(leeway).Unix() < c.IssuedAt {
+		return errors.New("token used before issued")
+	}
+
+	return nil
+}
+
+// Verify validates signature and claims of ID token issued for this client with given nonce
+func (p *Provider) Verify(ctx context.Context, rawToken, nonce string) (Identity, error) {
+	d, err := p.Discover(ctx)
+
+	if err != nil {
+		return Identity{}, err
+	}
+
+	var claims idTokenClaims
+
+	_, err = jwt.ParseWithClaims(rawToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+		default:
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+
+		kid, _ := token.Header["kid"].(string)
+
+		return p.key(ctx, d, kid)
+	})
+
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w - %s", ErrInvalidToken, err)
+	}
+
+	switch {
+	case claims.Issuer != d.Issuer:
+		return Identity{}, fmt.Errorf("%w - unexpected issuer %s", ErrInvalidToken, claims.Issuer)
+	case !claims.Audience.contains(p.cfg.ClientID):
+		return Identity{}, fmt.Errorf("%w - token is not issued for this client", ErrInvalidToken)
+	case claims.Nonce != nonce:
+		return Identity{}, fmt.Errorf("%w - nonce mismatch", ErrInvalidToken)
+	case claims.Subject == "":
+		return Identity{}, fmt.Errorf("%w - empty subject", ErrInvalidToken)
+	}
+
+	return Identity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		GivenName:     claims.GivenName,
+		FamilyName:    claims.FamilyName,
+	}, nil
+}
+
+// key returns provider's public key by ID. Keys are reloaded once if key is unknown, e.g. after rotation
+func (p *Provider) key(ctx context.Context, d Discovery, kid string) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	if err := p.getJSON(ctx, d.JWKSURI, &set); err != nil {
+		return nil, err
+	}
+
+	p.keys = make(map[string]interface{}, len(set.Keys))
+
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		// Keys of unsupported types are skipped
+		if key, err := k.publicKey(); err == nil {
+			p.keys[k.KeyID] = key
+		}
+	}
+
+	key, ok := p.keys[kid]
+
+	if !ok {
+		return nil, fmt.Errorf("unknown key %s", kid)
+	}
+
+	return key, nil
+}
+
+type jwk struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+func (k jwk) publicKey() (interface{}, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(k.E)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Curve != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %s", k.Curve)
+		}
+
+		x, err := decodeBigInt(k.X)
+
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(k.Y)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", k.KeyType)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
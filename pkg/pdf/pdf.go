@@ -0,0 +1,360 @@
+// Package pdf writes simple PDF documents with text and lines. Text is drawn with embedded DejaVu Sans fonts,
+// which are subset to used glyphs, so any Unicode text is supported. Characters missing in fonts are replaced with '?'
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	_ "embed"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// A4 page size in points
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+type Font int
+
+// Fonts available in document
+const (
+	Regular Font = iota
+	Bold
+)
+
+var (
+	//go:embed fonts/DejaVuSans.ttf
+	regularData []byte
+	//go:embed fonts/DejaVuSans-Bold.ttf
+	boldData []byte
+
+	fonts = []*trueType{
+		Regular: mustParseTrueType("DejaVuSans", regularData),
+		Bold:    mustParseTrueType("DejaVuSans-Bold", boldData),
+	}
+)
+
+// Document is set of pages. Coordinates of page start at top left corner and measured in points
+type Document struct {
+	width  float64
+	height float64
+	pages  []*bytes.Buffer
+	page   int
+	// Glyphs drawn with every font and characters they stand for
+	used []map[uint16]rune
+}
+
+// New creates document without pages with A4 page size
+func New() *Document {
+	used := make([]map[uint16]rune, len(fonts))
+
+	for i := range used {
+		used[i] = make(map[uint16]rune)
+	}
+
+	return &Document{
+		width:  A4Width,
+		height: A4Height,
+		used:   used,
+	}
+}
+
+// Width returns width of page
+func (d *Document) Width() float64 {
+	return d.width
+}
+
+// Height returns height of page
+func (d *Document) Height() float64 {
+	return d.height
+}
+
+// Pages returns count of pages
+func (d *Document) Pages() int {
+	return len(d.pages)
+}
+
+// AddPage adds new page, which becomes current one for drawing
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, new(bytes.Buffer))
+	d.page = len(d.pages) - 1
+}
+
+// SetPage makes page with zero-based index current one, e.g. to add footers after all pages are drawn
+func (d *Document) SetPage(i int) {
+	if i >= 0 && i < len(d.pages) {
+		d.page = i
+	}
+}
+
+// Text draws text on current page with baseline at y
+func (d *Document) Text(x, y float64, font Font, size float64, s string) {
+	page := d.current()
+
+	var sb strings.Builder
+
+	for _, r := range s {
+		g := glyph(font, r)
+
+		if _, ok := d.used[font][g]; !ok {
+			d.used[font][g] = r
+		}
+
+		_, _ = fmt.Fprintf(&sb, "%04X", g)
+	}
+
+	_, _ = fmt.Fprintf(page, "BT /F%d %s Tf %s %s Td <%s> Tj ET\n",
+		font+1, number(size), number(x), number(d.height-y), sb.String())
+}
+
+// TextRight draws text on current page ending at x
+func (d *Document) TextRight(x, y float64, font Font, size float64, s string) {
+	d.Text(x-TextWidth(font, size, s), y, font, size, s)
+}
+
+// Line draws line on current page
+func (d *Document) Line(x1, y1, x2, y2, width float64) {
+	page := d.current()
+
+	_, _ = fmt.Fprintf(page, "%s w %s %s m %s %s l S\n",
+		number(width), number(x1), number(d.height-y1), number(x2), number(d.height-y2))
+}
+
+// WriteTo writes document in PDF format
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	cw := &countWriter{w: bufio.NewWriter(w)}
+	offsets := make([]int64, 0)
+
+	object := func(body string) {
+		offsets = append(offsets, cw.n)
+		_, _ = fmt.Fprintf(cw, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	pages := d.pages
+
+	if len(pages) == 0 {
+		pages = []*bytes.Buffer{new(bytes.Buffer)}
+	}
+
+	// Objects: catalog, pages, fonts, pair of page and its content for every page and
+	// CID font, descriptor, font file and Unicode map for every font
+	const firstPage = 5
+	kids := make([]string, len(pages))
+
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+i*2)
+	}
+
+	firstFont := firstPage + len(pages)*2
+	names := make([]string, len(fonts))
+
+	for i, f := range fonts {
+		names[i] = subsetTag(d.used[i]) + "+" + f.name
+	}
+
+	_, _ = io.WriteString(cw, "%PDF-1.4\n")
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %s %s] >>",
+		strings.Join(kids, " "), len(pages), number(d.width), number(d.height)))
+
+	for i := range fonts {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H "+
+			"/DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>", names[i], firstFont+i*4, firstFont+i*4+3))
+	}
+
+	for i, page := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			firstPage+i*2+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	for i, f := range fonts {
+		id := firstFont + i*4
+		subset := f.subset(d.used[i])
+		file := deflate(subset)
+		unicode := toUnicode(d.used[i])
+
+		object(fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s "+
+			"/CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+			"/FontDescriptor %d 0 R /DW 1000 /W [%s] /CIDToGIDMap /Identity >>", names[i], id+1, widths(f, d.used[i])))
+		object(fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] /ItalicAngle 0 "+
+			"/Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>", names[i],
+			f.scale(f.bbox[0]), f.scale(f.bbox[1]), f.scale(f.bbox[2]), f.scale(f.bbox[3]),
+			f.scale(f.ascent), f.scale(f.descent), f.scale(f.capHeight), id+2))
+		object(fmt.Sprintf("<< /Length %d /Length1 %d /Filter /FlateDecode >>\nstream\n%s\nendstream",
+			len(file), len(subset), file))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(unicode), unicode))
+	}
+
+	xref := cw.n
+
+	_, _ = fmt.Fprintf(cw, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+
+	for _, offset := range offsets {
+		_, _ = fmt.Fprintf(cw, "%010d 00000 n \n", offset)
+	}
+
+	_, _ = fmt.Fprintf(cw, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+
+	return cw.n, cw.w.Flush()
+}
+
+func (d *Document) current() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	return d.pages[d.page]
+}
+
+// TextWidth returns width of text in points
+func TextWidth(font Font, size float64, s string) float64 {
+	var total int
+
+	for _, r := range s {
+		total += fonts[font].width(glyph(font, r))
+	}
+
+	return float64(total) * size / 1000
+}
+
+// glyph returns glyph of character in font, control and missing characters are replaced with '?'
+func glyph(font Font, r rune) uint16 {
+	f := fonts[font]
+
+	if g, ok := f.glyphs[r]; ok && r >= 32 {
+		return g
+	}
+
+	return f.glyphs['?']
+}
+
+// widths returns widths of used glyphs for W array of CID font
+func widths(f *trueType, used map[uint16]rune) string {
+	parts := make([]string, 0, len(used))
+
+	for _, g := range sortedGlyphs(used) {
+		parts = append(parts, fmt.Sprintf("%d [%d]", g, f.width(g)))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// toUnicode returns CMap which maps used glyphs to their characters, so text can be copied and searched
+func toUnicode(used map[uint16]rune) string {
+	// Count of mappings in one block is limited by PDF
+	const blockSize = 100
+
+	var sb strings.Builder
+
+	sb.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+
+	glyphs := sortedGlyphs(used)
+
+	for start := 0; start < len(glyphs); start += blockSize {
+		block := glyphs[start:]
+
+		if len(block) > blockSize {
+			block = block[:blockSize]
+		}
+
+		_, _ = fmt.Fprintf(&sb, "%d beginbfchar\n", len(block))
+
+		for _, g := range block {
+			_, _ = fmt.Fprintf(&sb, "<%04X> <", g)
+
+			for _, unit := range utf16.Encode([]rune{used[g]}) {
+				_, _ = fmt.Fprintf(&sb, "%04X", unit)
+			}
+
+			sb.WriteString(">\n")
+		}
+
+		sb.WriteString("endbfchar\n")
+	}
+
+	sb.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+
+	return sb.String()
+}
+
+func sortedGlyphs(used map[uint16]rune) []uint16 {
+	glyphs := make([]uint16, 0, len(used))
+
+	for g := range used {
+		glyphs = append(glyphs, g)
+	}
+
+	sort.Slice(glyphs, func(i, j int) bool {
+		return glyphs[i] < glyphs[j]
+	})
+
+	return glyphs
+}
+
+// subsetTag returns prefix of subset font name, which is six uppercase letters depending on used glyphs
+func subsetTag(used map[uint16]rune) string {
+	h := fnv.New32a()
+
+	for _, g := range sortedGlyphs(used) {
+		_, _ = h.Write([]byte{byte(g >> 8), byte(g)})
+	}
+
+	sum := h.Sum32()
+	tag := make([]byte, 6)
+
+	for i := range tag {
+		tag[i] = byte('A' + sum%26)
+		sum /= 26
+	}
+
+	return string(tag)
+}
+
+func deflate(b []byte) []byte {
+	var buf bytes.Buffer
+
+	zw := zlib.NewWriter(&buf)
+	_, _ = zw.Write(b)
+	_ = zw.Close()
+
+	return buf.Bytes()
+}
+
+func number(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+type countWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+
+	return n, err
+}
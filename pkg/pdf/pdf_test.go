@@ -0,0 +1,120 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDocument_WriteTo(t *testing.T) {
+	d := New()
+	d.AddPage()
+	d.Text(40, 40, Bold, 14, "Statement (main)")
+	d.Line(40, 50, 200, 50, 0.5)
+	d.AddPage()
+	d.TextRight(500, 40, Regular, 10, "Привет café")
+	d.SetPage(0)
+	d.Text(40, 800, Regular, 8, "Page 1 of 2")
+
+	var buf bytes.Buffer
+
+	n, err := d.WriteTo(&buf)
+
+	require.NoError(t, err)
+	require.Equal(t, int64(buf.Len()), n)
+
+	out := buf.String()
+
+	require.True(t, strings.HasPrefix(out, "%PDF-1.4\n"))
+	require.True(t, strings.HasSuffix(out, "%%EOF\n"))
+	require.Contains(t, out, "/Count 2")
+	require.Contains(t, out, "<"+glyphs(Bold, "Statement (main)")+"> Tj")
+	require.Contains(t, out, "<"+glyphs(Regular, "Привет café")+"> Tj")
+	require.Regexp(t, `(?s)`+glyphs(Bold, "Statement")+`.*`+glyphs(Regular, "Page 1")+`.*endstream.*`+glyphs(Regular, "caf"), out)
+	require.Contains(t, out, "/Encoding /Identity-H")
+	require.Contains(t, out, "/FontFile2")
+	require.Contains(t, out, fmt.Sprintf("<%04X> <041F>", glyph(Regular, 'П')))
+	require.Contains(t, out, fmt.Sprintf("<%04X> <00E9>", glyph(Regular, 'é')))
+	require.NotContains(t, out, "Helvetica")
+
+	// Every offset in cross-reference table points to its object
+	xref := regexp.MustCompile(`(?m)^(\d{10}) 00000 n $`).FindAllStringSubmatch(out, -1)
+	require.Len(t, xref, 16)
+
+	for i, m := range xref {
+		offset, _ := strconv.Atoi(m[1])
+		require.True(t, strings.HasPrefix(out[offset:], fmt.Sprintf("%d 0 obj", i+1)))
+	}
+
+	start := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(out)
+	offset, _ := strconv.Atoi(start[1])
+	require.True(t, strings.HasPrefix(out[offset:], "xref"))
+}
+
+func TestDocument_WriteToEmpty(t *testing.T) {
+	var buf bytes.Buffer
+
+	_, err := New().WriteTo(&buf)
+
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "/Count 1")
+}
+
+func TestDocument_WriteToMissing(t *testing.T) {
+	d := New()
+	d.Text(40, 40, Regular, 10, "a\tb")
+
+	var buf bytes.Buffer
+
+	_, err := d.WriteTo(&buf)
+
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "<"+glyphs(Regular, "a?b")+"> Tj")
+}
+
+func TestTextWidth(t *testing.T) {
+	require.Equal(t, 6.36, TextWidth(Regular, 10, "0"))
+	require.Equal(t, 3.48+7.15, TextWidth(Bold, 10, " b"))
+	require.Equal(t, TextWidth(Regular, 10, "?"), TextWidth(Regular, 10, "\n"))
+}
+
+func TestTrueType_subset(t *testing.T) {
+	f := fonts[Regular]
+	used := map[uint16]rune{glyph(Regular, 'A'): 'A', glyph(Regular, 'é'): 'é'}
+
+	subset := f.subset(used)
+
+	require.Equal(t, uint32(0xB1B0AFBA), checksum(subset))
+
+	parsed, err := parseTrueTypeTables("subset", subset)
+
+	require.NoError(t, err)
+	require.Equal(t, f.advances, parsed.advances)
+	require.Equal(t, f.glyph(0), parsed.glyph(0))
+	require.Equal(t, f.glyph(glyph(Regular, 'A')), parsed.glyph(glyph(Regular, 'A')))
+	require.Equal(t, f.glyph(glyph(Regular, 'é')), parsed.glyph(glyph(Regular, 'é')))
+	require.Empty(t, parsed.glyph(glyph(Regular, 'B')))
+
+	// Components of composite glyph are kept
+	components := f.components(glyph(Regular, 'é'))
+
+	require.NotEmpty(t, components)
+
+	for _, g := range components {
+		require.NotEmpty(t, parsed.glyph(g))
+	}
+}
+
+func glyphs(font Font, s string) string {
+	var sb strings.Builder
+
+	for _, r := range s {
+		sb.WriteString(fmt.Sprintf("%04X", glyph(font, r)))
+	}
+
+	return sb.String()
+}
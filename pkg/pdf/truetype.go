@@ -0,0 +1,472 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Tables required in fonts embedded into PDF
+var subsetTables = []string{"cvt ", "fpgm", "glyf", "head", "hhea", "hmtx", "loca", "maxp", "prep"}
+
+// Flags of composite glyph components
+const (
+	argsAreWords  = 0x0001
+	haveScale     = 0x0008
+	moreComponent = 0x0020
+	haveXYScale   = 0x0040
+	haveTwoByTwo  = 0x0080
+)
+
+var errFontInvalid = errors.New("invalid TrueType font")
+
+// trueType is parsed TrueType font with metrics in font units
+type trueType struct {
+	name       string
+	tables     map[string][]byte
+	unitsPerEm int
+	bbox       [4]int
+	ascent     int
+	descent    int
+	capHeight  int
+	advances   []int
+	offsets    []int
+	glyphs     map[rune]uint16
+}
+
+func mustParseTrueType(name string, data []byte) *trueType {
+	f, err := parseTrueType(name, data)
+	if err != nil {
+		panic(fmt.Sprintf("pdf: parse font %s: %s", name, err))
+	}
+
+	return f
+}
+
+func parseTrueType(name string, data []byte) (*trueType, error) {
+	f, err := parseTrueTypeTables(name, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.parseCmap(); err != nil {
+		return nil, err
+	}
+
+	f.capHeight = f.ascent
+
+	if os2 := f.tables["OS/2"]; len(os2) >= 90 && u16(os2, 0) >= 2 {
+		f.capHeight = int(i16(os2, 88))
+	} else if glyph := f.glyph(f.glyphs['H']); len(glyph) >= 10 {
+		f.capHeight = int(i16(glyph, 8))
+	}
+
+	return f, nil
+}
+
+// parseTrueTypeTables reads metrics and glyph locations, which are present in subset fonts too
+func parseTrueTypeTables(name string, data []byte) (*trueType, error) {
+	if len(data) < 12 {
+		return nil, errFontInvalid
+	}
+
+	f := &trueType{name: name, tables: make(map[string][]byte)}
+	count := int(u16(data, 4))
+
+	for i := 0; i < count; i++ {
+		record := 12 + i*16
+		if record+16 > len(data) {
+			return nil, errFontInvalid
+		}
+
+		offset, length := int(u32(data, record+8)), int(u32(data, record+12))
+		if offset+length > len(data) {
+			return nil, errFontInvalid
+		}
+
+		f.tables[string(data[record:record+4])] = data[offset : offset+length]
+	}
+
+	for _, tag := range []string{"head", "hhea", "maxp", "hmtx", "loca", "glyf"} {
+		if _, ok := f.tables[tag]; !ok {
+			return nil, fmt.Errorf("%w: no %s table", errFontInvalid, tag)
+		}
+	}
+
+	head, hhea := f.tables["head"], f.tables["hhea"]
+	if len(head) < 54 || len(hhea) < 36 || len(f.tables["maxp"]) < 6 {
+		return nil, errFontInvalid
+	}
+
+	f.unitsPerEm = int(u16(head, 18))
+	f.bbox = [4]int{int(i16(head, 36)), int(i16(head, 38)), int(i16(head, 40)), int(i16(head, 42))}
+	f.ascent = int(i16(hhea, 4))
+	f.descent = int(i16(hhea, 6))
+
+	if f.unitsPerEm == 0 {
+		return nil, errFontInvalid
+	}
+
+	if err := f.parseMetrics(); err != nil {
+		return nil, err
+	}
+
+	if err := f.parseLocations(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (f *trueType) parseMetrics() error {
+	glyphs := int(u16(f.tables["maxp"], 4))
+	metrics := int(u16(f.tables["hhea"], 34))
+	hmtx := f.tables["hmtx"]
+
+	if metrics == 0 || metrics > glyphs || len(hmtx) < metrics*4 {
+		return errFontInvalid
+	}
+
+	f.advances = make([]int, glyphs)
+
+	for i := range f.advances {
+		if i < metrics {
+			f.advances[i] = int(u16(hmtx, i*4))
+		} else {
+			f.advances[i] = f.advances[metrics-1]
+		}
+	}
+
+	return nil
+}
+
+func (f *trueType) parseLocations() error {
+	loca, glyf := f.tables["loca"], f.tables["glyf"]
+	long := i16(f.tables["head"], 50) == 1
+	f.offsets = make([]int, len(f.advances)+1)
+
+	for i := range f.offsets {
+		switch {
+		case long && len(loca) >= i*4+4:
+			f.offsets[i] = int(u32(loca, i*4))
+		case !long && len(loca) >= i*2+2:
+			f.offsets[i] = int(u16(loca, i*2)) * 2
+		default:
+			return errFontInvalid
+		}
+
+		if f.offsets[i] > len(glyf) || i > 0 && f.offsets[i] < f.offsets[i-1] {
+			return errFontInvalid
+		}
+	}
+
+	return nil
+}
+
+// parseCmap reads Unicode subtable of format 12 or 4
+func (f *trueType) parseCmap() error {
+	cmap := f.tables["cmap"]
+	if len(cmap) < 4 {
+		return errFontInvalid
+	}
+
+	var full, bmp []byte
+
+	for i := 0; i < int(u16(cmap, 2)); i++ {
+		record := 4 + i*8
+		if record+8 > len(cmap) {
+			return errFontInvalid
+		}
+
+		platform, encoding, offset := u16(cmap, record), u16(cmap, record+2), int(u32(cmap, record+4))
+		if offset+4 > len(cmap) {
+			return errFontInvalid
+		}
+
+		table := cmap[offset:]
+
+		switch format := u16(table, 0); {
+		case format == 12 && (platform == 3 && encoding == 10 || platform == 0):
+			full = table
+		case format == 4 && (platform == 3 && encoding == 1 || platform == 0):
+			bmp = table
+		}
+	}
+
+	f.glyphs = make(map[rune]uint16)
+
+	switch {
+	case full != nil:
+		return f.parseCmap12(full)
+	case bmp != nil:
+		return f.parseCmap4(bmp)
+	default:
+		return fmt.Errorf("%w: no Unicode cmap", errFontInvalid)
+	}
+}
+
+func (f *trueType) parseCmap12(table []byte) error {
+	if len(table) < 16 {
+		return errFontInvalid
+	}
+
+	groups := int(u32(table, 12))
+	if len(table) < 16+groups*12 {
+		return errFontInvalid
+	}
+
+	for i := 0; i < groups; i++ {
+		group := 16 + i*12
+		start, end, glyph := u32(table, group), u32(table, group+4), u32(table, group+8)
+
+		for c := start; c <= end && c <= 0x10FFFF; c++ {
+			if g := glyph + c - start; g < uint32(len(f.advances)) {
+				f.glyphs[rune(c)] = uint16(g)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (f *trueType) parseCmap4(table []byte) error {
+	if len(table) < 14 {
+		return errFontInvalid
+	}
+
+	segments := int(u16(table, 6)) / 2
+	ends, starts := 14, 16+segments*2
+	deltas, ranges := starts+segments*2, starts+segments*4
+
+	if len(table) < ranges+segments*2 {
+		return errFontInvalid
+	}
+
+	for i := 0; i < segments; i++ {
+		start, end := int(u16(table, starts+i*2)), int(u16(table, ends+i*2))
+		delta, rangeOffset := int(u16(table, deltas+i*2)), int(u16(table, ranges+i*2))
+
+		for c := start; c <= end && c != 0xFFFF; c++ {
+			g := (c + delta) & 0xFFFF
+
+			if rangeOffset != 0 {
+				at := ranges + i*2 + rangeOffset + (c-start)*2
+				if at+2 > len(table) {
+					return errFontInvalid
+				}
+
+				if g = int(u16(table, at)); g != 0 {
+					g = (g + delta) & 0xFFFF
+				}
+			}
+
+			if g != 0 && g < len(f.advances) {
+				f.glyphs[rune(c)] = uint16(g)
+			}
+		}
+	}
+
+	return nil
+}
+
+// glyph returns outline data of glyph, which is empty for glyphs without contours
+func (f *trueType) glyph(g uint16) []byte {
+	if int(g)+1 >= len(f.offsets) {
+		return nil
+	}
+
+	return f.tables["glyf"][f.offsets[g]:f.offsets[g+1]]
+}
+
+// components returns glyphs referenced by composite glyph
+func (f *trueType) components(g uint16) []uint16 {
+	glyph := f.glyph(g)
+	if len(glyph) < 10 || i16(glyph, 0) >= 0 {
+		return nil
+	}
+
+	var result []uint16
+
+	for at := 10; at+4 <= len(glyph); {
+		flags := u16(glyph, at)
+		result = append(result, u16(glyph, at+2))
+		at += 4
+
+		if flags&argsAreWords != 0 {
+			at += 4
+		} else {
+			at += 2
+		}
+
+		switch {
+		case flags&haveScale != 0:
+			at += 2
+		case flags&haveXYScale != 0:
+			at += 4
+		case flags&haveTwoByTwo != 0:
+			at += 8
+		}
+
+		if flags&moreComponent == 0 {
+			break
+		}
+	}
+
+	return result
+}
+
+// width returns advance width of glyph in 1/1000 of font size
+func (f *trueType) width(g uint16) int {
+	return f.scale(f.advances[g])
+}
+
+func (f *trueType) scale(units int) int {
+	return units * 1000 / f.unitsPerEm
+}
+
+// subset builds font with outlines of used glyphs only, other glyphs are left empty
+func (f *trueType) subset(used map[uint16]rune) []byte {
+	keep := make(map[uint16]bool)
+	queue := []uint16{0}
+
+	for g := range used {
+		queue = append(queue, g)
+	}
+
+	for len(queue) > 0 {
+		g := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+
+		if keep[g] {
+			continue
+		}
+
+		keep[g] = true
+		queue = append(queue, f.components(g)...)
+	}
+
+	var glyf bytes.Buffer
+
+	loca := make([]byte, len(f.offsets)*4)
+
+	for g := 0; g < len(f.offsets)-1; g++ {
+		binary.BigEndian.PutUint32(loca[g*4:], uint32(glyf.Len()))
+
+		if keep[uint16(g)] {
+			glyf.Write(f.glyph(uint16(g)))
+
+			for glyf.Len()%4 != 0 {
+				glyf.WriteByte(0)
+			}
+		}
+	}
+
+	binary.BigEndian.PutUint32(loca[len(loca)-4:], uint32(glyf.Len()))
+
+	// Long offsets are used in loca, checksum adjustment is calculated after font is built
+	head := append([]byte(nil), f.tables["head"]...)
+	binary.BigEndian.PutUint32(head[8:], 0)
+	binary.BigEndian.PutUint16(head[50:], 1)
+
+	tables := map[string][]byte{"glyf": glyf.Bytes(), "loca": loca, "head": head}
+	tags := make([]string, 0, len(subsetTables))
+
+	for _, tag := range subsetTables {
+		if _, ok := tables[tag]; !ok {
+			table, ok := f.tables[tag]
+			if !ok {
+				continue
+			}
+
+			tables[tag] = table
+		}
+
+		tags = append(tags, tag)
+	}
+
+	sort.Strings(tags)
+
+	out := buildFont(tags, tables)
+	binary.BigEndian.PutUint32(out[headOffset(out)+8:], 0xB1B0AFBA-checksum(out))
+
+	return out
+}
+
+func buildFont(tags []string, tables map[string][]byte) []byte {
+	var out bytes.Buffer
+
+	selector := 0
+	for 1<<(selector+1) <= len(tags) {
+		selector++
+	}
+
+	header := make([]byte, 12+len(tags)*16)
+	binary.BigEndian.PutUint32(header, 0x00010000)
+	binary.BigEndian.PutUint16(header[4:], uint16(len(tags)))
+	binary.BigEndian.PutUint16(header[6:], uint16(16<<selector))
+	binary.BigEndian.PutUint16(header[8:], uint16(selector))
+	binary.BigEndian.PutUint16(header[10:], uint16(len(tags)*16-16<<selector))
+
+	offset := len(header)
+
+	for i, tag := range tags {
+		table := tables[tag]
+		record := header[12+i*16:]
+
+		copy(record, tag)
+		binary.BigEndian.PutUint32(record[4:], checksum(table))
+		binary.BigEndian.PutUint32(record[8:], uint32(offset))
+		binary.BigEndian.PutUint32(record[12:], uint32(len(table)))
+
+		offset += (len(table) + 3) &^ 3
+	}
+
+	out.Write(header)
+
+	for _, tag := range tags {
+		out.Write(tables[tag])
+
+		for out.Len()%4 != 0 {
+			out.WriteByte(0)
+		}
+	}
+
+	return out.Bytes()
+}
+
+func headOffset(font []byte) int {
+	for i := 0; i < int(u16(font, 4)); i++ {
+		if string(font[12+i*16:16+i*16]) == "head" {
+			return int(u32(font, 20+i*16))
+		}
+	}
+
+	return 0
+}
+
+func checksum(b []byte) uint32 {
+	var sum uint32
+
+	for i := 0; i < len(b); i += 4 {
+		var word [4]byte
+
+		copy(word[:], b[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+
+	return sum
+}
+
+func u16(b []byte, at int) uint16 {
+	return binary.BigEndian.Uint16(b[at:])
+}
+
+func i16(b []byte, at int) int16 {
+	return int16(u16(b, at))
+}
+
+func u32(b []byte, at int) uint32 {
+	return binary.BigEndian.Uint32(b[at:])
+}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStore(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewLocalStore(root)
+
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	key := "transactions/1/receipt.jpg"
+
+	require.NoError(t, s.Put(ctx, key, strings.NewReader("content"), 7, "image/jpeg"))
+
+	data, err := os.ReadFile(filepath.Join(root, "transactions", "1", "receipt.jpg"))
+
+	require.NoError(t, err)
+	require.Equal(t, "content", string(data))
+
+	r, err := s.Get(ctx, key)
+
+	require.NoError(t, err)
+
+	data, err = io.ReadAll(r)
+
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, "content", string(data))
+
+	require.NoError(t, s.Delete(ctx, key))
+	require.NoError(t, s.Delete(ctx, key))
+
+	_, err = s.Get(ctx, key)
+
+	require.ErrorIs(t, err, ErrBlobNotFound)
+}
+
+func TestLocalStore_PutErrShortRead(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewLocalStore(root)
+
+	require.NoError(t, err)
+
+	err = s.Put(context.Background(), "short", strings.NewReader("abc"), 10, "")
+
+	require.Error(t, err)
+
+	// Temporary file is removed
+	entries, err := os.ReadDir(root)
+
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestLocalStore_ErrKeyInvalid(t *testing.T) {
+	s, err := NewLocalStore(t.TempDir())
+
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	for _, key := range []string{"", "/etc/passwd", "../secret", "a/../../b", "a//b", `a\b`} {
+		require.ErrorIs(t, s.Put(ctx, key, strings.NewReader(""), 0, ""), ErrBlobKeyInvalid, key)
+
+		_, err = s.Get(ctx, key)
+
+		require.ErrorIs(t, err, ErrBlobKeyInvalid, key)
+		require.ErrorIs(t, s.Delete(ctx, key), ErrBlobKeyInvalid, key)
+	}
+}
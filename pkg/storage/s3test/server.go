@@ -0,0 +1,223 @@
+// Package s3test provides local S3 compatible storage for tests, answering like MinIO does
+package s3test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+type object struct {
+	data        []byte
+	contentType string
+}
+
+// Server keeps objects of path-style buckets in memory. Every request must be signed with AWS Signature Version 4
+// using configured credentials
+type Server struct {
+	*httptest.Server
+
+	AccessKey string
+	SecretKey string
+
+	mu      sync.Mutex
+	buckets map[string]map[string]object
+}
+
+// NewServer starts storage with given buckets, it must be closed after use
+func NewServer(accessKey, secretKey string, buckets ...string) *Server {
+	s := &Server{
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+		buckets:   make(map[string]map[string]object),
+	}
+
+	for _, bucket := range buckets {
+		s.buckets[bucket] = make(map[string]object)
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+
+	return s
+}
+
+// Object returns content and type of object in bucket
+func (s *Server) Object(bucket, key string) ([]byte, string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.buckets[bucket][key]
+
+	return obj.data, obj.contentType, ok
+}
+
+// Len returns count of objects in bucket
+func (s *Server) Len(bucket string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.buckets[bucket])
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+		return
+	}
+
+	if code, message := s.verify(r, body); code != "" {
+		writeError(w, http.StatusForbidden, code, message)
+		return
+	}
+
+	path := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+
+	if len(path) != 2 || path[1] == "" {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", "only object requests are supported")
+		return
+	}
+
+	bucket, key := path[0], path[1]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	objects, ok := s.buckets[bucket]
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		objects[key] = object{data: body, contentType: r.Header.Get("Content-Type")}
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		obj, ok := objects[key]
+
+		if !ok {
+			writeError(w, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+			return
+		}
+
+		w.Header().Set("Content-Type", obj.contentType)
+		_, _ = w.Write(obj.data)
+	case http.MethodDelete:
+		delete(objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed",
+			"The specified method is not allowed against this resource.")
+	}
+}
+
+// verify checks signature of request. Returns code and message of error if request is not authorized
+func (s *Server) verify(r *http.Request, body []byte) (string, string) {
+	auth := r.Header.Get("Authorization")
+
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 ") {
+		return "AccessDenied", "Access Denied."
+	}
+
+	params := make(map[string]string)
+
+	for _, param := range strings.Split(strings.TrimPrefix(auth, "AWS4-HMAC-SHA256 "), ",") {
+		if kv := strings.SplitN(strings.TrimSpace(param), "=", 2); len(kv) == 2 {
+			params[kv[0]] = kv[1]
+		}
+	}
+
+	credential := strings.SplitN(params["Credential"], "/", 2)
+
+	if len(credential) != 2 || credential[0] != s.AccessKey {
+		return "InvalidAccessKeyId", "The Access Key Id you provided does not exist in our records."
+	}
+
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+
+	if payloadHash != unsignedPayload && payloadHash != hashHex(body) {
+		return "XAmzContentSHA256Mismatch", "The provided 'x-amz-content-sha256' header does not match what was computed."
+	}
+
+	signedHeaders := strings.Split(params["SignedHeaders"], ";")
+	var headers strings.Builder
+
+	for _, name := range signedHeaders {
+		value := r.Header.Get(name)
+
+		if name == "host" {
+			value = r.Host
+		}
+
+		headers.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+
+	query := make([]string, 0)
+
+	for name, values := range r.URL.Query() {
+		for _, value := range values {
+			query = append(query, url.QueryEscape(name)+"="+url.QueryEscape(value))
+		}
+	}
+
+	sort.Strings(query)
+
+	canonicalRequest := strings.Join([]string{r.Method, r.URL.EscapedPath(), strings.Join(query, "&"),
+		headers.String(), params["SignedHeaders"], payloadHash}, "\n")
+
+	amzDate := r.Header.Get("X-Amz-Date")
+	scope := credential[1]
+	scopeParts := strings.Split(scope, "/")
+
+	if len(scopeParts) != 4 || len(amzDate) < 8 || scopeParts[0] != amzDate[:8] {
+		return "AuthorizationHeaderMalformed", "The authorization header is malformed."
+	}
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretKey), scopeParts[0])
+
+	for _, part := range scopeParts[1:] {
+		key = hmacSHA256(key, part)
+	}
+
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hashHex([]byte(canonicalRequest))
+
+	if !hmac.Equal([]byte(hex.EncodeToString(hmacSHA256(key, stringToSign))), []byte(params["Signature"])) {
+		return "SignatureDoesNotMatch",
+			"The request signature we calculated does not match the signature you provided."
+	}
+
+	return "", ""
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<Error><Code>%s</Code><Message>%s</Message></Error>`, code, message)
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+)
+
+//go:generate mockgen -source=storage.go -destination=mocks/mock.go
+
+var (
+	ErrBlobNotFound   = errors.New("blob doesn't exists")
+	ErrBlobKeyInvalid = errors.New("blob key is invalid")
+)
+
+// BlobStore keeps binary files by keys like "transactions/1/2f6c.jpg"
+type BlobStore interface {
+	// Put writes size bytes read from r under key, replacing existing blob
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens blob for reading, returns ErrBlobNotFound if there is no blob with key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes blob, missing blob is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// validateKey checks that key is relative slash separated path without empty, "." or ".." segments
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, `\`) {
+		return ErrBlobKeyInvalid
+	}
+
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return ErrBlobKeyInvalid
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package thumbnail
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: 200, G: 20, B: 20, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, png.Encode(&buf, img))
+
+	return buf.Bytes()
+}
+
+func TestCreate(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		width, height  int
+		expectedWidth  int
+		expectedHeight int
+	}{
+		{name: "landscape", width: 400, height: 200, expectedWidth: 256, expectedHeight: 128},
+		{name: "portrait", width: 300, height: 600, expectedWidth: 128, expectedHeight: 256},
+		{name: "thin", width: 1000, height: 2, expectedWidth: 256, expectedHeight: 1},
+		{name: "small", width: 100, height: 50, expectedWidth: 100, expectedHeight: 50},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Create(testPNG(t, tt.width, tt.height), 256)
+
+			require.NoError(t, err)
+
+			img, err := jpeg.Decode(bytes.NewReader(data))
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedWidth, img.Bounds().Dx())
+			require.Equal(t, tt.expectedHeight, img.Bounds().Dy())
+
+			r, g, b, _ := img.At(0, 0).RGBA()
+
+			require.InDelta(t, 200, r>>8, 10)
+			require.InDelta(t, 20, g>>8, 10)
+			require.InDelta(t, 20, b>>8, 10)
+		})
+	}
+}
+
+func TestCreate_Transparent(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 10, 10))))
+
+	data, err := Create(buf.Bytes(), 256)
+
+	require.NoError(t, err)
+
+	img, err := jpeg.Decode(bytes.NewReader(data))
+
+	require.NoError(t, err)
+
+	r, _, _, _ := img.At(5, 5).RGBA()
+
+	require.Greater(t, r>>8, uint32(240))
+}
+
+func TestCreate_Err(t *testing.T) {
+	_, err := Create([]byte("%PDF-1.4"), 256)
+
+	require.ErrorIs(t, err, image.ErrFormat)
+}
+
+func TestSupported(t *testing.T) {
+	require.True(t, Supported("image/png"))
+	require.False(t, Supported("application/pdf"))
+}
@@ -0,0 +1,197 @@
+// Package xlsx writes Office Open XML spreadsheets with plain string and number cells
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Workbook is set of sheets
+type Workbook struct {
+	sheets []*Sheet
+}
+
+// Sheet is list of rows. Cells of rows are strings, numbers, dates or nil for empty cell
+type Sheet struct {
+	name string
+	rows []row
+}
+
+type row struct {
+	bold   bool
+	values []interface{}
+}
+
+func New() *Workbook {
+	return &Workbook{}
+}
+
+// AddSheet adds sheet with given name. Names must be unique and not longer than 31 characters
+func (w *Workbook) AddSheet(name string) *Sheet {
+	s := &Sheet{name: name}
+	w.sheets = append(w.sheets, s)
+
+	return s
+}
+
+// AddRow appends row of values
+func (s *Sheet) AddRow(values ...interface{}) {
+	s.rows = append(s.rows, row{values: values})
+}
+
+// AddHeader appends row of values in bold
+func (s *Sheet) AddHeader(values ...interface{}) {
+	s.rows = append(s.rows, row{bold: true, values: values})
+}
+
+// WriteTo writes workbook as xlsx file
+func (w *Workbook) WriteTo(out io.Writer) (int64, error) {
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+
+	sheets := w.sheets
+
+	if len(sheets) == 0 {
+		sheets = []*Sheet{{name: "Sheet1"}}
+	}
+
+	var sheetTypes, workbookSheets, workbookRels string
+
+	for i, s := range sheets {
+		sheetTypes += fmt.Sprintf(`<Override PartName="/xl/worksheets/sheet%d.xml" `+
+			`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+		workbookSheets += fmt.Sprintf(`<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(s.name), i+1, i+1)
+		workbookRels += fmt.Sprintf(`<Relationship Id="rId%d" `+
+			`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" `+
+			`Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+
+	workbookRels += fmt.Sprintf(`<Relationship Id="rId%d" `+
+		`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`,
+		len(sheets)+1)
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ` +
+			`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/styles.xml" ` +
+			`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+			sheetTypes + `</Types>`},
+		{"_rels/.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" ` +
+			`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" ` +
+			`Target="xl/workbook.xml"/></Relationships>`},
+		{"xl/workbook.xml", `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets>` + workbookSheets + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			workbookRels + `</Relationships>`},
+		// Style 0 is default, style 1 is bold
+		{"xl/styles.xml", `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font>` +
+			`<font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+			`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+			`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+			`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+			`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+			`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+			`</styleSheet>`},
+	}
+
+	for i, s := range sheets {
+		files = append(files, struct {
+			name    string
+			content string
+		}{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), s.xml()})
+	}
+
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+
+		if err != nil {
+			return 0, err
+		}
+
+		if _, err = io.WriteString(fw, xml.Header+f.content); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return 0, err
+	}
+
+	return buf.WriteTo(out)
+}
+
+func (s *Sheet) xml() string {
+	var b bytes.Buffer
+
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	for i, r := range s.rows {
+		_, _ = fmt.Fprintf(&b, `<row r="%d">`, i+1)
+
+		style := ""
+
+		if r.bold {
+			style = ` s="1"`
+		}
+
+		for j, v := range r.values {
+			ref := column(j) + strconv.Itoa(i+1)
+
+			switch value := v.(type) {
+			case nil:
+				continue
+			case float64:
+				_, _ = fmt.Fprintf(&b, `<c r="%s"%s><v>%s</v></c>`, ref, style, strconv.FormatFloat(value, 'f', -1, 64))
+			case int:
+				_, _ = fmt.Fprintf(&b, `<c r="%s"%s><v>%d</v></c>`, ref, style, value)
+			case int64:
+				_, _ = fmt.Fprintf(&b, `<c r="%s"%s><v>%d</v></c>`, ref, style, value)
+			case time.Time:
+				_, _ = fmt.Fprintf(&b, `<c r="%s"%s t="inlineStr"><is><t>%s</t></is></c>`, ref, style,
+					value.Format("2006-01-02"))
+			default:
+				_, _ = fmt.Fprintf(&b, `<c r="%s"%s t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, style,
+					escape(fmt.Sprint(value)))
+			}
+		}
+
+		b.WriteString(`</row>`)
+	}
+
+	b.WriteString(`</sheetData></worksheet>`)
+
+	return b.String()
+}
+
+// column returns name of column by zero-based index: A, B, ..., Z, AA, AB, ...
+func column(i int) string {
+	name := ""
+
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+
+	return name
+}
+
+func escape(s string) string {
+	var b bytes.Buffer
+	_ = xml.EscapeText(&b, []byte(s))
+
+	return b.String()
+}
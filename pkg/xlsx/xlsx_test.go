@@ -0,0 +1,74 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"github.com/stretchr/testify/require"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestWorkbook_WriteTo(t *testing.T) {
+	w := New()
+	s := w.AddSheet("Statement")
+	s.AddHeader("Date", "Amount")
+	s.AddRow(time.Date(2022, 1, 15, 0, 0, 0, 0, time.UTC), 12.5, nil, "<tom & jerry>")
+
+	var buf bytes.Buffer
+
+	n, err := w.WriteTo(&buf)
+
+	require.NoError(t, err)
+	require.Equal(t, int64(buf.Len()), n)
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+
+	require.NoError(t, err)
+
+	files := make(map[string]string)
+
+	for _, f := range zr.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+
+		// Every part is well-formed XML
+		d := xml.NewDecoder(bytes.NewReader(content))
+
+		for {
+			if _, err = d.Token(); err != nil {
+				break
+			}
+		}
+
+		require.ErrorIs(t, err, io.EOF, f.Name)
+
+		files[f.Name] = string(content)
+	}
+
+	require.Contains(t, files, "[Content_Types].xml")
+	require.Contains(t, files, "_rels/.rels")
+	require.Contains(t, files, "xl/_rels/workbook.xml.rels")
+	require.Contains(t, files, "xl/styles.xml")
+	require.Contains(t, files["xl/workbook.xml"], `<sheet name="Statement" sheetId="1" r:id="rId1"/>`)
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+
+	require.Contains(t, sheet, `<c r="A1" s="1" t="inlineStr"><is><t xml:space="preserve">Date</t></is></c>`)
+	require.Contains(t, sheet, `<c r="A2" t="inlineStr"><is><t>2022-01-15</t></is></c>`)
+	require.Contains(t, sheet, `<c r="B2"><v>12.5</v></c>`)
+	require.NotContains(t, sheet, `r="C2"`)
+	require.Contains(t, sheet, `&lt;tom &amp; jerry&gt;`)
+}
+
+func TestColumn(t *testing.T) {
+	require.Equal(t, "A", column(0))
+	require.Equal(t, "Z", column(25))
+	require.Equal(t, "AA", column(26))
+	require.Equal(t, "AZ", column(51))
+	require.Equal(t, "BA", column(52))
+}